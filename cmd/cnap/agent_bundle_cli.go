@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cnap-oss/app/internal/common"
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func buildAgentExportCommand(logger *zap.Logger) *cobra.Command {
+	var all, noFiles, includeSecrets bool
+	var output string
+
+	exportCmd := &cobra.Command{
+		Use:   "export [agent-name...]",
+		Short: "Agent를 YAML 번들로 내보내기",
+		Long: `Agent 설정(프로바이더, 모델, 프롬프트, 이미지, 권한 정책), MCP 서버 설정,
+작업 공간 시드 파일을 하나의 YAML 번들로 내보냅니다.
+시드 파일은 agent import로 가져온 파일만 포함하며, 실행 중에 생긴 파일과 1MiB를 넘는 파일은 건너뜁니다.
+MCP 서버 환경 변수 값은 기본적으로 <redacted>로 내보내며, 가져올 때 대상 Agent의 현재 값을 유지합니다.`,
+		Example: `  cnap agent export my-agent -o my-agent.yaml
+  cnap agent export --all -o agents.yaml
+  cnap agent export my-agent --include-secrets -o my-agent.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) > 0) {
+				return fmt.Errorf("agent 이름 또는 --all 중 하나를 지정하세요")
+			}
+			return runAgentExport(logger, args, output, !noFiles, includeSecrets)
+		},
+	}
+	exportCmd.Flags().BoolVar(&all, "all", false, "활성 Agent 모두 내보내기")
+	exportCmd.Flags().StringVarP(&output, "output", "o", "-", "번들 파일 경로 (-이면 표준 출력)")
+	exportCmd.Flags().BoolVar(&noFiles, "no-files", false, "작업 공간 시드 파일 제외")
+	exportCmd.Flags().BoolVar(&includeSecrets, "include-secrets", false, "MCP 서버 환경 변수 값을 그대로 포함")
+
+	return exportCmd
+}
+
+func buildAgentImportCommand(logger *zap.Logger) *cobra.Command {
+	var upsert, dryRun, yes bool
+
+	importCmd := &cobra.Command{
+		Use:   "import <bundle.yaml>",
+		Short: "YAML 번들에서 Agent 가져오기",
+		Long: `번들을 검증하고 현재 Agent와 비교한 변경 사항을 표시한 뒤 적용합니다.
+이미 존재하는 Agent가 번들과 다르면 --upsert를 지정해야 갱신합니다.
+MCP 서버 설정은 번들 내용으로 교체되고, 번들에 없는 작업 공간 파일은 그대로 유지됩니다.`,
+		Example: `  cnap agent import agents.yaml --dry-run
+  cnap agent import agents.yaml --upsert --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentImport(logger, args[0], upsert, dryRun, yes)
+		},
+	}
+	importCmd.Flags().BoolVar(&upsert, "upsert", false, "이미 존재하는 Agent도 번들 내용으로 갱신")
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "변경 사항만 표시하고 적용하지 않음")
+	importCmd.Flags().BoolVarP(&yes, "yes", "y", false, "확인 없이 적용")
+
+	return importCmd
+}
+
+func buildAgentPlanCommand(logger *zap.Logger) *cobra.Command {
+	var dir string
+
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "선언형 Agent 설정과 DB 비교 (dry-run)",
+		Long: `설정 디렉토리(CNAP_AGENT_CONFIG_DIR)의 *.yaml 번들을 읽어, 서버 시작 시나 파일 변경 시
+반영될 변경 사항을 적용하지 않고 표시합니다. 설정 파일과 달라진 Agent(드리프트)를 확인할 때 사용합니다.`,
+		Example: `  cnap agent plan
+  cnap agent plan --dir ./configs/agents`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir == "" {
+				dir = common.GetConfig().Directory.AgentConfigDir
+			}
+			if dir == "" {
+				return fmt.Errorf("설정 디렉토리를 지정하세요 (--dir 또는 CNAP_AGENT_CONFIG_DIR)")
+			}
+			return runAgentPlan(logger, dir)
+		},
+	}
+	planCmd.Flags().StringVar(&dir, "dir", "", "선언형 Agent 설정 디렉토리 (기본값: CNAP_AGENT_CONFIG_DIR)")
+
+	return planCmd
+}
+
+func runAgentExport(logger *zap.Logger, names []string, output string, includeFiles, includeSecrets bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	bundle, err := ctrl.ExportAgents(ctx, names, includeFiles, includeSecrets)
+	if err != nil {
+		return fmt.Errorf("agent 내보내기 실패: %w", err)
+	}
+	data, err := bundle.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if output == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("번들 파일 저장 실패: %w", err)
+	}
+	fmt.Printf("✓ Agent %d개를 %s에 내보냈습니다.\n", len(bundle.Agents), output)
+	return nil
+}
+
+func runAgentImport(logger *zap.Logger, path string, upsert, dryRun, yes bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("번들 파일 읽기 실패: %w", err)
+	}
+	bundle, err := controller.ParseAgentBundle(data)
+	if err != nil {
+		return fmt.Errorf("번들 검증 실패: %w", err)
+	}
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	plan, err := ctrl.PlanAgentImport(ctx, bundle, upsert)
+	if err != nil {
+		return fmt.Errorf("변경 사항 계산 실패: %w", err)
+	}
+	printAgentImportPlan(os.Stdout, plan)
+
+	if plan.HasConflicts() {
+		return fmt.Errorf("이미 존재하는 Agent와 내용이 다릅니다. --upsert를 지정하면 갱신합니다")
+	}
+	pending := 0
+	for _, item := range plan.Items {
+		if item.Action == controller.AgentImportCreate || item.Action == controller.AgentImportUpdate {
+			pending++
+		}
+	}
+	if pending == 0 {
+		fmt.Println("\n변경 사항이 없습니다.")
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("\n--dry-run: Agent %d개의 변경 사항을 적용하지 않았습니다.\n", pending)
+		return nil
+	}
+
+	if !yes {
+		fmt.Printf("\nAgent %d개에 변경 사항을 적용하시겠습니까? (y/N): ", pending)
+		reader := bufio.NewReader(os.Stdin)
+		confirm, _ := reader.ReadString('\n')
+		confirm = strings.TrimSpace(strings.ToLower(confirm))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println("취소되었습니다.")
+			return nil
+		}
+	}
+
+	if err := ctrl.ApplyAgentImport(ctx, plan); err != nil {
+		return fmt.Errorf("agent 가져오기 실패: %w", err)
+	}
+	fmt.Printf("✓ Agent %d개 가져오기 완료\n", pending)
+	return nil
+}
+
+func runAgentPlan(logger *zap.Logger, dir string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	plan, err := ctrl.PlanAgentConfig(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("설정 비교 실패: %w", err)
+	}
+	printAgentConfigPlan(os.Stdout, plan)
+	return nil
+}
+
+// printAgentConfigPlan은 선언형 설정 반영 계획과 관리 해제될 Agent, 요약을 출력합니다.
+func printAgentConfigPlan(out io.Writer, plan *controller.AgentConfigPlan) {
+	if len(plan.Items) == 0 && len(plan.Released) == 0 {
+		_, _ = fmt.Fprintln(out, "선언된 Agent가 없습니다.")
+		return
+	}
+	printAgentImportPlan(out, &plan.AgentImportPlan)
+	for _, name := range plan.Released {
+		_, _ = fmt.Fprintf(out, "? %s (설정 파일에서 제거됨, 관리 해제)\n", name)
+	}
+
+	changed := len(plan.Released)
+	for _, item := range plan.Items {
+		if item.Action != controller.AgentImportUnchanged {
+			changed++
+		}
+	}
+	if changed == 0 {
+		_, _ = fmt.Fprintln(out, "\n설정 파일과 DB가 일치합니다.")
+	} else {
+		_, _ = fmt.Fprintf(out, "\nAgent %d개가 설정 파일과 다릅니다. 서버 시작 시 또는 파일 변경 시 반영됩니다.\n", changed)
+	}
+}
+
+// printAgentImportPlan은 Agent별 동작과 필드 변경 사항을 출력합니다.
+func printAgentImportPlan(out io.Writer, plan *controller.AgentImportPlan) {
+	for _, item := range plan.Items {
+		switch item.Action {
+		case controller.AgentImportCreate:
+			_, _ = fmt.Fprintf(out, "+ %s (생성)\n", item.Spec.Name)
+		case controller.AgentImportUpdate:
+			_, _ = fmt.Fprintf(out, "~ %s (갱신)\n", item.Spec.Name)
+		case controller.AgentImportUnchanged:
+			_, _ = fmt.Fprintf(out, "= %s (변경 없음)\n", item.Spec.Name)
+			continue
+		case controller.AgentImportConflict:
+			_, _ = fmt.Fprintf(out, "! %s (이미 존재함, --upsert 필요)\n", item.Spec.Name)
+		}
+
+		for _, change := range item.Changes {
+			switch {
+			case change.Old == "":
+				_, _ = fmt.Fprintf(out, "    + %s: %s\n", change.Field, planValue(change.New))
+			case change.New == "":
+				_, _ = fmt.Fprintf(out, "    - %s: %s\n", change.Field, planValue(change.Old))
+			default:
+				_, _ = fmt.Fprintf(out, "    ~ %s: %s → %s\n", change.Field, planValue(change.Old), planValue(change.New))
+			}
+		}
+	}
+}
+
+// planValue는 긴 값(프롬프트 등)을 한 줄로 줄여 표시합니다.
+func planValue(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > 60 {
+		return string(r[:57]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/controller"
+)
+
+func TestPrintAgentImportPlan(t *testing.T) {
+	plan := &controller.AgentImportPlan{Items: []controller.AgentImportItem{
+		{Spec: controller.AgentSpec{Name: "new-agent"}, Action: controller.AgentImportCreate, Changes: []controller.AgentImportChange{
+			{Field: "model", New: "gpt-4"},
+		}},
+		{Spec: controller.AgentSpec{Name: "same-agent"}, Action: controller.AgentImportUnchanged},
+		{Spec: controller.AgentSpec{Name: "old-agent"}, Action: controller.AgentImportConflict, Changes: []controller.AgentImportChange{
+			{Field: "prompt", Old: "short", New: "line one\nline two that is long enough to be truncated in the plan output"},
+			{Field: "mcp:github", Old: "npx github-mcp"},
+		}},
+	}}
+
+	var buf bytes.Buffer
+	printAgentImportPlan(&buf, plan)
+
+	want := "+ new-agent (생성)\n" +
+		"    + model: gpt-4\n" +
+		"= same-agent (변경 없음)\n" +
+		"! old-agent (이미 존재함, --upsert 필요)\n" +
+		"    ~ prompt: short → line one line two that is long enough to be truncated in ...\n" +
+		"    - mcp:github: npx github-mcp\n"
+	if got := buf.String(); got != want {
+		t.Errorf("printAgentImportPlan() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"text/tabwriter"
 	"time"
 
+	"github.com/cnap-oss/app/internal/controller"
+	taskrunner "github.com/cnap-oss/app/internal/runner"
+	"github.com/cnap-oss/app/internal/storage"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"golang.org/x/text/unicode/norm"
@@ -95,11 +98,32 @@ func buildAgentCommands(logger *zap.Logger) *cobra.Command {
 		},
 	}
 
+	// agent permission
+	var permTimeout time.Duration
+	var permOnTimeout string
+	agentPermissionCmd := &cobra.Command{
+		Use:   "permission <agent-name>",
+		Short: "Agent 도구 권한 승인 정책 설정",
+		Long:  "도구 실행 권한 요청의 응답 대기 시간과, 시간 초과 시 적용할 응답(reject 또는 once)을 설정합니다.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentPermission(logger, args[0], permTimeout, permOnTimeout)
+		},
+	}
+	agentPermissionCmd.Flags().DurationVar(&permTimeout, "timeout", 0, "응답 대기 시간 (0이면 기본값 5m)")
+	agentPermissionCmd.Flags().StringVar(&permOnTimeout, "on-timeout", storage.PermissionResponseReject, "시간 초과 시 적용할 응답 (reject, once)")
+
 	agentCmd.AddCommand(agentCreateCmd)
 	agentCmd.AddCommand(agentListCmd)
 	agentCmd.AddCommand(agentViewCmd)
 	agentCmd.AddCommand(agentDeleteCmd)
 	agentCmd.AddCommand(agentEditCmd)
+	agentCmd.AddCommand(agentPermissionCmd)
+	agentCmd.AddCommand(buildAgentRetentionCommand(logger))
+	agentCmd.AddCommand(buildAgentOwnerCommand(logger))
+	agentCmd.AddCommand(buildAgentExportCommand(logger))
+	agentCmd.AddCommand(buildAgentImportCommand(logger))
+	agentCmd.AddCommand(buildAgentPlanCommand(logger))
 
 	return agentCmd
 }
@@ -149,6 +173,10 @@ func runAgentCreate(logger *zap.Logger) error {
 	prompt, _ := reader.ReadString('\n')
 	prompt = normalizeInput(strings.TrimSpace(prompt))
 
+	fmt.Print("Runner 이미지 (Enter: 기본 이미지): ")
+	image, _ := reader.ReadString('\n')
+	image = strings.TrimSpace(image)
+
 	// 입력 검증
 	if err := ctrl.ValidateAgent(name); err != nil {
 		return fmt.Errorf("유효하지 않은 Agent 이름: %w", err)
@@ -159,6 +187,12 @@ func runAgentCreate(logger *zap.Logger) error {
 		return fmt.Errorf("agent 생성 실패: %w", err)
 	}
 
+	if image != "" {
+		if err := ctrl.SetAgentImage(ctx, name, image); err != nil {
+			return fmt.Errorf("runner 이미지 설정 실패: %w", err)
+		}
+	}
+
 	fmt.Printf("✓ Agent '%s' 생성 완료 (Model: %s)\n", name, model)
 	return nil
 }
@@ -216,18 +250,21 @@ func runAgentList(logger *zap.Logger) error {
 
 	// 테이블 형식 출력
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "NAME\tSTATUS\tMODEL\tDESCRIPTION\tCREATED")
-	_, _ = fmt.Fprintln(w, "----\t------\t-----\t-----------\t-------")
+	_, _ = fmt.Fprintln(w, "NAME\tID\tSTATUS\tMODEL\tTENANT\tVISIBILITY\tDESCRIPTION\tCREATED")
+	_, _ = fmt.Fprintln(w, "----\t--\t------\t-----\t------\t----------\t-----------\t-------")
 
 	for _, agent := range agents {
 		desc := agent.Description
 		if len(desc) > 40 {
 			desc = desc[:37] + "..."
 		}
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			agent.Name,
+			agent.ID,
 			agent.Status,
 			agent.Model,
+			agent.Tenant,
+			agent.Visibility,
 			desc,
 			agent.CreatedAt.Format("2006-01-02 15:04"),
 		)
@@ -255,9 +292,31 @@ func runAgentView(logger *zap.Logger, agentName string) error {
 	// 상세 정보 출력
 	fmt.Printf("=== Agent 정보: %s ===\n\n", agent.Name)
 	fmt.Printf("이름:        %s\n", agent.Name)
+	fmt.Printf("ID:          %s\n", agent.ID)
 	fmt.Printf("상태:        %s\n", agent.Status)
 	fmt.Printf("프로바이더:  %s\n", agent.Provider)
 	fmt.Printf("모델:        %s\n", agent.Model)
+	if agent.Image != "" {
+		fmt.Printf("이미지:      %s\n", agent.Image)
+	} else {
+		fmt.Printf("이미지:      (기본) %s\n", taskrunner.DefaultRunnerImage())
+	}
+	fmt.Printf("권한 정책:   응답 대기 %s, 시간 초과 시 %s\n", agent.PermissionTimeout, agent.PermissionOnTimeout)
+	if agent.Retention != (controller.RetentionPolicy{}) {
+		fmt.Printf("보관 정책:   완료 %s, 실패 %s, 작업 공간 %s\n",
+			retentionDays(agent.Retention.CompletedTaskDays),
+			retentionDays(agent.Retention.FailedTaskDays),
+			retentionDays(agent.Retention.WorkspaceIdleDays))
+	}
+	fmt.Printf("테넌트:      %s (%s)\n", agent.Tenant, agent.Visibility)
+	if agent.Owner != "" {
+		fmt.Printf("소유자:      %s\n", agent.Owner)
+	} else {
+		fmt.Printf("소유자:      (테넌트 공용)\n")
+	}
+	if agent.ConfigSource != "" {
+		fmt.Printf("관리:        설정 파일 %s\n", agent.ConfigSource)
+	}
 	fmt.Printf("설명:        %s\n", agent.Description)
 	fmt.Printf("프롬프트:\n%s\n\n", agent.Prompt)
 	fmt.Printf("생성일:      %s\n", agent.CreatedAt.Format("2006-01-02 15:04:05"))
@@ -266,6 +325,24 @@ func runAgentView(logger *zap.Logger, agentName string) error {
 	return nil
 }
 
+func runAgentPermission(logger *zap.Logger, agentName string, timeout time.Duration, onTimeout string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	if err := ctrl.SetAgentPermissionPolicy(ctx, agentName, timeout, onTimeout); err != nil {
+		return fmt.Errorf("권한 정책 설정 실패: %w", err)
+	}
+
+	fmt.Printf("✓ Agent '%s'의 권한 정책이 설정되었습니다.\n", agentName)
+	return nil
+}
+
 func runAgentDelete(logger *zap.Logger, agentName string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
@@ -335,11 +412,26 @@ func runAgentEdit(logger *zap.Logger, agentName string) error {
 		prompt = agent.Prompt
 	}
 
+	fmt.Printf("Runner 이미지 (현재: %s, '-' 입력 시 기본 이미지): ", agent.Image)
+	image, _ := reader.ReadString('\n')
+	image = strings.TrimSpace(image)
+
 	// Agent 수정 (provider는 항상 opencode)
 	if err := ctrl.UpdateAgent(ctx, agentName, description, "opencode", model, prompt); err != nil {
 		return fmt.Errorf("agent 수정 실패: %w", err)
 	}
 
+	if image == "-" {
+		image = ""
+	} else if image == "" {
+		image = agent.Image
+	}
+	if image != agent.Image {
+		if err := ctrl.SetAgentImage(ctx, agentName, image); err != nil {
+			return fmt.Errorf("runner 이미지 설정 실패: %w", err)
+		}
+	}
+
 	fmt.Printf("✓ Agent '%s' 수정 완료\n", agentName)
 	return nil
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func buildAgentOwnerCommand(logger *zap.Logger) *cobra.Command {
+	var tenant, owner, visibility string
+
+	ownerCmd := &cobra.Command{
+		Use:   "owner <agent-name>",
+		Short: "Agent 소유 테넌트, 소유자, 공개 범위 설정",
+		Long: `Agent의 소유 테넌트와 소유자, 공개 범위를 설정합니다. 옵션이 없으면 현재 설정을 표시합니다.
+테넌트는 Discord 서버이면 guild:{서버 ID}, 기본 테넌트는 default입니다.
+소유자를 빈 문자열로 지정하면 같은 테넌트의 모든 사용자가 수정할 수 있는 공용 Agent가 됩니다.
+공개 범위는 private(소유자만), guild(같은 테넌트), public(모든 테넌트에서 조회/호출) 중 하나입니다.`,
+		Example: `  cnap agent owner my-agent
+  cnap agent owner my-agent --tenant guild:123456789012345678 --owner discord:234567890123456789
+  cnap agent owner my-agent --owner "" --visibility guild`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			return runAgentOwner(logger, args[0],
+				flagValue(flags.Changed("tenant"), tenant),
+				flagValue(flags.Changed("owner"), owner),
+				flagValue(flags.Changed("visibility"), visibility))
+		},
+	}
+	ownerCmd.Flags().StringVar(&tenant, "tenant", "", "소유 테넌트 (예: default, guild:{서버 ID})")
+	ownerCmd.Flags().StringVar(&owner, "owner", "", "소유자 ({connector}:{id}, 숫자만 입력하면 Discord 사용자 ID)")
+	ownerCmd.Flags().StringVar(&visibility, "visibility", "", "공개 범위 (private, guild, public)")
+
+	return ownerCmd
+}
+
+// flagValue는 지정된 플래그 값의 포인터를, 지정되지 않았으면 nil을 반환합니다.
+func flagValue(changed bool, value string) *string {
+	if !changed {
+		return nil
+	}
+	return &value
+}
+
+func runAgentOwner(logger *zap.Logger, agentName string, tenant, owner, visibility *string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	agent, err := ctrl.GetAgentInfo(ctx, agentName)
+	if err != nil {
+		return fmt.Errorf("agent 조회 실패: %w", err)
+	}
+	agentName = agent.ID
+
+	if tenant != nil || owner != nil {
+		newTenant, newOwner := agent.Tenant, agent.Owner
+		if tenant != nil {
+			newTenant = *tenant
+		}
+		if owner != nil {
+			newOwner = *owner
+		}
+		if err := ctrl.TransferAgent(ctx, agentName, newTenant, newOwner); err != nil {
+			return fmt.Errorf("소유자 변경 실패: %w", err)
+		}
+	}
+	if visibility != nil {
+		if err := ctrl.SetAgentVisibility(ctx, agentName, *visibility); err != nil {
+			return fmt.Errorf("공개 범위 변경 실패: %w", err)
+		}
+	}
+
+	if agent, err = ctrl.GetAgentInfo(ctx, agentName); err != nil {
+		return fmt.Errorf("agent 조회 실패: %w", err)
+	}
+	owned := agent.Owner
+	if owned == "" {
+		owned = "(테넌트 공용)"
+	}
+	fmt.Printf("테넌트:    %s\n", agent.Tenant)
+	fmt.Printf("소유자:    %s\n", owned)
+	fmt.Printf("공개 범위: %s\n", agent.Visibility)
+	return nil
+}
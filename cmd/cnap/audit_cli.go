@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func buildAuditCommand(logger *zap.Logger) *cobra.Command {
+	var actor, action, target, since string
+	var limit int
+	var showChanges bool
+
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "감사 기록 조회",
+		Long: `Agent와 Task를 변경한 작업의 감사 기록을 최신순으로 조회합니다.
+수행자는 {connector}:{id} 형식입니다. (예: discord:1234, cli:alice, system:retention)`,
+		Example: `  cnap audit --since 7d
+  cnap audit --action agent --target agent/my-agent --changes
+  cnap audit --actor discord:123456789012345678`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sinceTime, err := controller.ParseSince(since, time.Now())
+			if err != nil {
+				return err
+			}
+			q := storage.AuditQuery{Actor: actor, Action: action, Since: sinceTime, Limit: limit}
+			if target != "" {
+				targetType, targetID, ok := strings.Cut(target, "/")
+				if !ok {
+					return fmt.Errorf("--target은 {종류}/{ID} 형식이어야 합니다 (예: agent/my-agent)")
+				}
+				q.TargetType, q.TargetID = targetType, targetID
+			}
+			return runAudit(logger, q, showChanges)
+		},
+	}
+	auditCmd.Flags().StringVar(&actor, "actor", "", "수행자 ({connector}:{id})")
+	auditCmd.Flags().StringVar(&action, "action", "", "동작 (예: agent.update, 대상 종류만 지정하면 agent.* 전체)")
+	auditCmd.Flags().StringVar(&target, "target", "", "대상 ({종류}/{ID}, 예: agent/my-agent, task/1234)")
+	auditCmd.Flags().StringVar(&since, "since", "", "이 기간 이후의 기록만 조회 (예: 7d, 12h, 2025-01-02)")
+	auditCmd.Flags().IntVar(&limit, "limit", 50, "최대 결과 개수")
+	auditCmd.Flags().BoolVar(&showChanges, "changes", false, "필드별 변경 전후 값 표시")
+
+	return auditCmd
+}
+
+func runAudit(logger *zap.Logger, q storage.AuditQuery, showChanges bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	events, err := ctrl.QueryAuditEvents(ctx, q)
+	if err != nil {
+		return fmt.Errorf("감사 기록 조회 실패: %w", err)
+	}
+	printAuditEvents(os.Stdout, events, showChanges)
+	return nil
+}
+
+// printAuditEvents는 감사 기록을 테이블로 출력합니다. showChanges이면 기록마다 필드별 변경 사항을 함께 출력합니다.
+func printAuditEvents(out io.Writer, events []storage.AuditEvent, showChanges bool) {
+	if len(events) == 0 {
+		_, _ = fmt.Fprintln(out, "감사 기록이 없습니다.")
+		return
+	}
+
+	if showChanges {
+		for _, e := range events {
+			_, _ = fmt.Fprintf(out, "%s  %s  %s/%s  %s\n",
+				e.CreatedAt.Local().Format("2006-01-02 15:04:05"), e.Action, e.TargetType, e.TargetID, e.Actor)
+			for _, change := range controller.AuditChanges(e) {
+				switch {
+				case change.Old == "":
+					_, _ = fmt.Fprintf(out, "    + %s: %s\n", change.Field, planValue(change.New))
+				case change.New == "":
+					_, _ = fmt.Fprintf(out, "    - %s: %s\n", change.Field, planValue(change.Old))
+				default:
+					_, _ = fmt.Fprintf(out, "    ~ %s: %s → %s\n", change.Field, planValue(change.Old), planValue(change.New))
+				}
+			}
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tACTION\tTARGET\tACTOR\tCHANGED")
+	for _, e := range events {
+		changes := controller.AuditChanges(e)
+		fields := make([]string, 0, len(changes))
+		for _, change := range changes {
+			if change.Field != "" {
+				fields = append(fields, change.Field)
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s/%s\t%s\t%s\n",
+			e.CreatedAt.Local().Format("2006-01-02 15:04:05"), e.Action, e.TargetType, e.TargetID, e.Actor,
+			planValue(strings.Join(fields, ",")))
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cnap-oss/app/internal/common"
+	taskrunner "github.com/cnap-oss/app/internal/runner"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func buildBackupCommands(logger *zap.Logger) *cobra.Command {
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "백업 및 복원 명령어",
+		Long: `DB, 메시지, 설정 디렉토리와 (선택) 작업 공간을 하나의 tar.zst 아카이브로 백업하고 복원합니다.
+DB는 테이블별 JSONL 논리 덤프로 저장되므로 SQLite 백업을 PostgreSQL로 복원할 수도 있습니다.`,
+	}
+
+	// backup create
+	var includeWorkspaces bool
+	var output string
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "백업 아카이브 생성",
+		Long: `DB 덤프와 메시지, 설정 파일은 메시지 쓰기를 멈춘 상태에서 같은 시점으로 기록하여 서로 일치합니다.
+서버가 실행 중이어도 백업할 수 있습니다. PostgreSQL은 덤프하는 동안 서버의 DB 쓰기가 대기하고,
+SQLite는 VACUUM INTO로 만든 스냅샷 사본을 덤프합니다. 서버의 메시지 파일 쓰기는 백업하는 동안 대기합니다.
+작업 공간은 --include-workspaces를 지정했을 때만 포함하며, 잠금을 푼 뒤 기록합니다.`,
+		Example: `  cnap backup create
+  cnap backup create --include-workspaces -o cnap-backup.tar.zst`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output == "" {
+				output = fmt.Sprintf("cnap-backup-%s.tar.zst", time.Now().Format("20060102-150405"))
+			}
+			return runBackupCreate(logger, output, includeWorkspaces)
+		},
+	}
+	createCmd.Flags().BoolVar(&includeWorkspaces, "include-workspaces", false, "Agent 작업 공간 포함")
+	createCmd.Flags().StringVarP(&output, "output", "o", "", "아카이브 파일 경로 (기본값: cnap-backup-{시각}.tar.zst)")
+
+	// backup verify
+	verifyCmd := &cobra.Command{
+		Use:   "verify <file>",
+		Short: "백업 아카이브 검증",
+		Long:  "아카이브의 형식 버전과 모든 파일의 크기, 체크섬을 확인하고 내용을 요약합니다.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := verifyBackupFile(args[0])
+			if err != nil {
+				return err
+			}
+			printBackupManifest(os.Stdout, manifest)
+			fmt.Println("✓ 체크섬이 모두 일치합니다.")
+			return nil
+		},
+	}
+
+	// backup restore
+	var yes bool
+	restoreCmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "백업 아카이브 복원",
+		Long: `아카이브를 검증한 뒤 DB의 모든 테이블을 백업 내용으로 교체하고, 아카이브에 포함된 디렉토리를 교체합니다.
+기존 디렉토리는 {디렉토리}.pre-restore-{시각}으로 옮겨 두며, 복원을 확인한 뒤 직접 삭제하세요.
+검색 색인은 복원 후 다시 만듭니다. 서버를 중지한 상태에서 실행하세요.`,
+		Example: `  cnap backup restore cnap-backup-20250102-030405.tar.zst`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBackupRestore(logger, args[0], yes)
+		},
+	}
+	restoreCmd.Flags().BoolVarP(&yes, "yes", "y", false, "확인 없이 복원")
+
+	backupCmd.AddCommand(createCmd)
+	backupCmd.AddCommand(verifyCmd)
+	backupCmd.AddCommand(restoreCmd)
+	return backupCmd
+}
+
+// backupDirs는 현재 설정의 메시지, 설정, 작업 공간 디렉토리를 반환합니다.
+func backupDirs(includeWorkspaces bool) (storage.BackupDirs, error) {
+	dirs := storage.BackupDirs{
+		Messages: common.GetMessagesDir(),
+		Configs:  common.GetConfigsDir(),
+	}
+	if includeWorkspaces {
+		ws, err := taskrunner.AgentWorkspacePath("")
+		if err != nil {
+			return dirs, err
+		}
+		dirs.Workspaces = ws
+	}
+	return dirs, nil
+}
+
+func runBackupCreate(logger *zap.Logger, output string, includeWorkspaces bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	dirs, err := backupDirs(includeWorkspaces)
+	if err != nil {
+		return err
+	}
+	repo, cleanup, err := initStorage(logger)
+	if err != nil {
+		return fmt.Errorf("저장소 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("아카이브 파일 생성 실패: %w", err)
+	}
+	manifest, err := storage.CreateBackup(ctx, repo.DB(), f, dirs, Version)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(output)
+		return fmt.Errorf("백업 실패: %w", err)
+	}
+
+	printBackupManifest(os.Stdout, manifest)
+	fmt.Printf("✓ %s에 백업했습니다.\n", output)
+	return nil
+}
+
+func verifyBackupFile(path string) (*storage.BackupManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("아카이브 파일 열기 실패: %w", err)
+	}
+	defer f.Close()
+
+	manifest, err := storage.VerifyBackup(f)
+	if err != nil {
+		return nil, fmt.Errorf("아카이브 검증 실패: %w", err)
+	}
+	return manifest, nil
+}
+
+func runBackupRestore(logger *zap.Logger, path string, yes bool) error {
+	manifest, err := verifyBackupFile(path)
+	if err != nil {
+		return err
+	}
+	printBackupManifest(os.Stdout, manifest)
+	if manifest.AppVersion != Version {
+		fmt.Printf("\n⚠️  백업한 cnap 버전(%s)과 현재 버전(%s)이 다릅니다.\n", manifest.AppVersion, Version)
+	}
+
+	dirs, err := backupDirs(true)
+	if err != nil {
+		return err
+	}
+
+	if !yes {
+		fmt.Print("\nDB와 위 디렉토리를 백업 내용으로 교체합니다. 계속하시겠습니까? (y/N): ")
+		reader := bufio.NewReader(os.Stdin)
+		confirm, _ := reader.ReadString('\n')
+		confirm = strings.TrimSpace(strings.ToLower(confirm))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println("취소되었습니다.")
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	repo, cleanup, err := initStorage(logger)
+	if err != nil {
+		return fmt.Errorf("저장소 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("아카이브 파일 열기 실패: %w", err)
+	}
+	defer f.Close()
+
+	result, err := storage.RestoreBackup(ctx, repo.DB(), f, dirs)
+	if err != nil {
+		return fmt.Errorf("복원 실패: %w", err)
+	}
+	fmt.Println("✓ 복원 완료")
+	for _, prev := range result.Previous {
+		fmt.Printf("  기존 디렉토리: %s\n", prev)
+	}
+
+	ctrl, ctrlCleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer ctrlCleanup()
+	count, err := ctrl.ReindexSearch(ctx)
+	if err != nil {
+		return fmt.Errorf("검색 색인 재구성 실패 (%d개 완료): %w", count, err)
+	}
+	fmt.Printf("✓ %d개 메시지를 검색 색인에 추가했습니다.\n", count)
+	return nil
+}
+
+// printBackupManifest는 백업 시각, 버전, 테이블별 행 수, 포함된 디렉토리를 출력합니다.
+func printBackupManifest(out io.Writer, m *storage.BackupManifest) {
+	_, _ = fmt.Fprintf(out, "백업 시각: %s\n", m.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	_, _ = fmt.Fprintf(out, "cnap 버전: %s (형식 %d, DB %s)\n", m.AppVersion, m.FormatVersion, m.Dialect)
+
+	tables := make([]string, 0, len(m.Tables))
+	for name := range m.Tables {
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+	for _, name := range tables {
+		_, _ = fmt.Fprintf(out, "  %-16s %d행\n", name, m.Tables[name])
+	}
+
+	files := make(map[string]int)
+	for _, f := range m.Files {
+		section, _, _ := strings.Cut(f.Path, "/")
+		files[section]++
+	}
+	for _, section := range m.Sections {
+		_, _ = fmt.Fprintf(out, "  %-16s 파일 %d개\n", section+"/", files[section])
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cnap-oss/app/internal/common"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func buildDBCommands(logger *zap.Logger) *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "데이터 저장소 관리 명령어",
+		Long:  "데이터베이스와 메시지 저장소를 관리합니다.",
+	}
+
+	// db migrate-messages
+	var from, to string
+	var dryRun bool
+	migrateMessagesCmd := &cobra.Command{
+		Use:   "migrate-messages",
+		Short: "메시지 저장소 간 메시지 이동",
+		Long: `모든 Task의 대화 메시지를 --from 저장소에서 --to 저장소로 복사하고, 메시지 인덱스를 새 저장소 키로 갱신합니다.
+저장소 종류: file ($CNAP_DIR/messages), db (message_blobs 테이블)
+이동 후에는 CNAP_MESSAGE_STORE를 --to 저장소로 설정하세요.`,
+		Example: `  cnap db migrate-messages --from file --to db
+  cnap db migrate-messages --from db --to file --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateMessages(logger, from, to, dryRun)
+		},
+	}
+	migrateMessagesCmd.Flags().StringVar(&from, "from", storage.MessageStoreFile, "원본 메시지 저장소 (file, db)")
+	migrateMessagesCmd.Flags().StringVar(&to, "to", storage.MessageStoreDB, "대상 메시지 저장소 (file, db)")
+	migrateMessagesCmd.Flags().BoolVar(&dryRun, "dry-run", false, "복사하지 않고 읽기만 확인")
+
+	// db reindex-search
+	reindexSearchCmd := &cobra.Command{
+		Use:   "reindex-search",
+		Short: "메시지 검색 색인 재구성",
+		Long: `검색 색인을 비우고 메시지 저장소의 모든 대화 메시지로 다시 채웁니다.
+메시지는 추가될 때 자동으로 색인되므로, 이전 버전에서 저장된 메시지를 검색하려면 한 번 실행하세요.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReindexSearch(logger)
+		},
+	}
+
+	dbCmd.AddCommand(migrateMessagesCmd)
+	dbCmd.AddCommand(reindexSearchCmd)
+	return dbCmd
+}
+
+// openMessageStore는 종류에 맞는 메시지 저장소를 생성합니다. 파일 저장소는 {DataDir}/messages를 사용합니다.
+func openMessageStore(repo *storage.Repository, kind string) (storage.MessageStore, error) {
+	return storage.NewMessageStore(kind, repo.DB(), common.GetMessagesDir())
+}
+
+func runMigrateMessages(logger *zap.Logger, from, to string, dryRun bool) error {
+	if from == to {
+		return fmt.Errorf("원본과 대상 저장소가 같습니다: %s", from)
+	}
+
+	repo, cleanup, err := initStorage(logger)
+	if err != nil {
+		return fmt.Errorf("저장소 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	src, err := openMessageStore(repo, from)
+	if err != nil {
+		return err
+	}
+	dst, err := openMessageStore(repo, to)
+	if err != nil {
+		return err
+	}
+
+	count, err := storage.MigrateMessages(context.Background(), repo, src, dst, dryRun)
+	if err != nil {
+		return fmt.Errorf("메시지 이동 실패 (%d개 완료): %w", count, err)
+	}
+
+	if dryRun {
+		fmt.Printf("✓ %d개 메시지를 %s에서 읽을 수 있습니다 (dry-run)\n", count, from)
+		return nil
+	}
+	fmt.Printf("✓ %d개 메시지를 %s에서 %s로 이동했습니다.\n", count, from, to)
+	return nil
+}
+
+func runReindexSearch(logger *zap.Logger) error {
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	count, err := ctrl.ReindexSearch(context.Background())
+	if err != nil {
+		return fmt.Errorf("검색 색인 재구성 실패 (%d개 완료): %w", count, err)
+	}
+	fmt.Printf("✓ %d개 메시지를 검색 색인에 추가했습니다.\n", count)
+	return nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func buildGCCommand(logger *zap.Logger) *cobra.Command {
+	var dryRun, yes bool
+
+	gcCmd := &cobra.Command{
+		Use:   "gc",
+		Short: "보관 기간이 지난 Task와 작업 공간 정리",
+		Long: `보관 정책에 따라 마지막 활동 후 기간이 지난 Task(메시지, 실행 단계, 로그 포함)를 삭제하고, 유휴 작업 공간의 Task 산출물(project, logs)을 비웁니다.
+기본 정책은 CNAP_RETENTION_* 환경 변수로, 에이전트별 정책은 'cnap agent retention'으로 설정합니다.
+CNAP_RETENTION_ARCHIVE_DIR을 지정하면 Task를 삭제하기 전에 대화 기록(JSONL)을 보관합니다.
+서버는 CNAP_RETENTION_INTERVAL 주기로 같은 정리 작업을 실행합니다.`,
+		Example: `  cnap gc --dry-run
+  cnap gc --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGC(logger, dryRun, yes)
+		},
+	}
+	gcCmd.Flags().BoolVar(&dryRun, "dry-run", false, "삭제할 항목만 표시하고 삭제하지 않음")
+	gcCmd.Flags().BoolVarP(&yes, "yes", "y", false, "확인 없이 삭제")
+
+	return gcCmd
+}
+
+func buildAgentRetentionCommand(logger *zap.Logger) *cobra.Command {
+	var policy controller.RetentionPolicy
+
+	retentionCmd := &cobra.Command{
+		Use:   "retention <agent-name>",
+		Short: "Agent 보관 정책 설정",
+		Long: `Agent의 Task와 작업 공간을 마지막 활동 후 보관할 일수를 설정합니다.
+0이면 기본 정책(CNAP_RETENTION_*)을 따르고, -1이면 삭제하지 않습니다.`,
+		Example: `  cnap agent retention my-agent --completed-days 30 --failed-days 7
+  cnap agent retention my-agent --workspace-idle-days -1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentRetention(logger, args[0], policy)
+		},
+	}
+	retentionCmd.Flags().IntVar(&policy.CompletedTaskDays, "completed-days", 0, "완료, 취소, 응답 대기 Task 보관 일수")
+	retentionCmd.Flags().IntVar(&policy.FailedTaskDays, "failed-days", 0, "실패한 Task 보관 일수")
+	retentionCmd.Flags().IntVar(&policy.WorkspaceIdleDays, "workspace-idle-days", 0, "유휴 작업 공간 보관 일수")
+
+	return retentionCmd
+}
+
+func runAgentRetention(logger *zap.Logger, agentName string, policy controller.RetentionPolicy) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	if err := ctrl.SetAgentRetention(ctx, agentName, policy); err != nil {
+		return fmt.Errorf("보관 정책 설정 실패: %w", err)
+	}
+
+	fmt.Printf("✓ Agent '%s'의 보관 정책이 설정되었습니다.\n", agentName)
+	return nil
+}
+
+func runGC(logger *zap.Logger, dryRun, yes bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger, retentionOption(0))
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	plan, err := ctrl.PlanGC(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("정리 대상 계산 실패: %w", err)
+	}
+	printGCPlan(os.Stdout, plan)
+	if len(plan.Tasks) == 0 && len(plan.Workspaces) == 0 {
+		return nil
+	}
+	if dryRun {
+		fmt.Println("\n--dry-run: 삭제하지 않았습니다.")
+		return nil
+	}
+
+	if !yes {
+		fmt.Print("\n위 항목을 삭제하시겠습니까? (y/N): ")
+		reader := bufio.NewReader(os.Stdin)
+		confirm, _ := reader.ReadString('\n')
+		confirm = strings.TrimSpace(strings.ToLower(confirm))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println("취소되었습니다.")
+			return nil
+		}
+	}
+
+	result, err := ctrl.RunGC(ctx, plan)
+	if result != nil {
+		fmt.Printf("✓ Task %d개(메시지 %d개), 작업 공간 %d개를 정리했습니다.", result.Tasks, result.Messages, result.Workspaces)
+		if result.Archived > 0 {
+			fmt.Printf(" 대화 기록 %d개를 보관했습니다.", result.Archived)
+		}
+		if result.Skipped > 0 {
+			fmt.Printf(" 다시 활동한 %d개는 건너뛰었습니다.", result.Skipped)
+		}
+		fmt.Println()
+	}
+	if err != nil {
+		return fmt.Errorf("일부 항목 정리 실패: %w", err)
+	}
+	return nil
+}
+
+// printGCPlan은 삭제할 Task와 작업 공간을 표로 출력합니다.
+func printGCPlan(out io.Writer, plan *controller.GCPlan) {
+	if len(plan.Tasks) == 0 && len(plan.Workspaces) == 0 {
+		_, _ = fmt.Fprintln(out, "정리할 항목이 없습니다.")
+		return
+	}
+
+	if len(plan.Tasks) > 0 {
+		messages := 0
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "TASK ID\tAGENT\tSTATUS\tLAST ACTIVITY\tMESSAGES\tRETENTION")
+		for _, t := range plan.Tasks {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%dd\n",
+				t.TaskID, t.AgentID, t.Status, t.UpdatedAt.Local().Format("2006-01-02 15:04"), t.Messages, t.RetentionDays)
+			messages += t.Messages
+		}
+		_ = w.Flush()
+		_, _ = fmt.Fprintf(out, "Task %d개, 메시지 %d개\n", len(plan.Tasks), messages)
+	}
+
+	if len(plan.Workspaces) > 0 {
+		if len(plan.Tasks) > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "WORKSPACE\tLAST ACTIVITY\tIDLE LIMIT\tPATH")
+		for _, ws := range plan.Workspaces {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%dd\t%s\n",
+				ws.AgentID, ws.LastActivity.Local().Format("2006-01-02 15:04"), ws.IdleDays, ws.Path)
+		}
+		_ = w.Flush()
+		_, _ = fmt.Fprintf(out, "작업 공간 %d개\n", len(plan.Workspaces))
+	}
+}
+
+// retentionDays는 에이전트별 보관 일수를 표시합니다.
+func retentionDays(days int) string {
+	switch {
+	case days == storage.RetentionKeepForever:
+		return "삭제 안 함"
+	case days > 0:
+		return fmt.Sprintf("%d일", days)
+	default:
+		return "기본값"
+	}
+}
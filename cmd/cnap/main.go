@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"os/user"
 	"sync"
 	"syscall"
 	"time"
@@ -75,6 +76,13 @@ func main() {
 	rootCmd.AddCommand(healthCmd)
 	rootCmd.AddCommand(buildAgentCommands(logger))
 	rootCmd.AddCommand(buildTaskCommands(logger))
+	rootCmd.AddCommand(buildRunnerCommands(logger))
+	rootCmd.AddCommand(buildSearchCommand(logger))
+	rootCmd.AddCommand(buildDBCommands(logger))
+	rootCmd.AddCommand(buildBackupCommands(logger))
+	rootCmd.AddCommand(buildGCCommand(logger))
+	rootCmd.AddCommand(buildPrivacyCommands(logger))
+	rootCmd.AddCommand(buildAuditCommand(logger))
 
 	if err := rootCmd.Execute(); err != nil {
 		logger.Error("Command execution failed", zap.Error(err))
@@ -113,8 +121,18 @@ func runStart(logger *zap.Logger) error {
 	connectorEventChan := make(chan controller.ConnectorEvent, 100)
 	controllerEventChan := make(chan controller.ControllerEvent, 100)
 
+	messageStore, err := openMessageStore(repo, common.GetConfig().Database.MessageStore)
+	if err != nil {
+		logger.Error("Failed to initialize message store", zap.Error(err))
+		return err
+	}
+
 	// 서버 인스턴스 생성
-	controllerServer := controller.NewController(logger, repo, connectorEventChan, controllerEventChan)
+	controllerServer := controller.NewController(logger, repo, connectorEventChan, controllerEventChan,
+		controller.WithMessageStore(messageStore),
+		controller.WithAgentConfigDir(common.GetConfig().Directory.AgentConfigDir),
+		retentionOption(common.GetConfig().Retention.Interval),
+	)
 	connectorServer := connector.NewServer(logger, controllerServer, connectorEventChan, controllerEventChan)
 
 	// 에러 채널
@@ -212,16 +230,56 @@ func initStorage(logger *zap.Logger) (*storage.Repository, func(), error) {
 	return repo, cleanup, nil
 }
 
-func newController(logger *zap.Logger) (*controller.Controller, func(), error) {
+// newController는 이벤트를 구독하지 않는 CLI 명령용 Controller를 생성합니다.
+// 이벤트 전송이 버퍼에서 막히지 않도록 이벤트는 읽어서 버립니다.
+func newController(logger *zap.Logger, opts ...controller.Option) (*controller.Controller, func(), error) {
+	ctrl, events, cleanup, err := newControllerWithEvents(logger, opts...)
+	if err == nil {
+		go func() {
+			for range events {
+			}
+		}()
+	}
+	return ctrl, cleanup, err
+}
+
+// retentionOption은 설정의 기본 보관 정책으로 Controller 옵션을 만듭니다. interval이 0이면 정리 작업을 주기적으로 실행하지 않습니다.
+func retentionOption(interval time.Duration) controller.Option {
+	cfg := common.GetConfig().Retention
+	return controller.WithRetention(controller.RetentionPolicy{
+		CompletedTaskDays: cfg.CompletedTaskDays,
+		FailedTaskDays:    cfg.FailedTaskDays,
+		WorkspaceIdleDays: cfg.WorkspaceIdleDays,
+	}, cfg.ArchiveDir, interval)
+}
+
+// newControllerWithEvents는 Task 이벤트를 CLI에서 직접 구독할 수 있도록 이벤트 채널과 함께 Controller를 생성합니다.
+func newControllerWithEvents(logger *zap.Logger, opts ...controller.Option) (*controller.Controller, <-chan controller.ControllerEvent, func(), error) {
 	repo, cleanup, err := initStorage(logger)
 	if err != nil {
-		return nil, func() {}, err
+		return nil, nil, func() {}, err
+	}
+
+	messageStore, err := openMessageStore(repo, common.GetConfig().Database.MessageStore)
+	if err != nil {
+		cleanup()
+		return nil, nil, func() {}, err
 	}
 
 	// CLI 단일 실행용으로 채널 생성 (버퍼 크기: 10)
 	connectorEventChan := make(chan controller.ConnectorEvent, 10)
 	controllerEventChan := make(chan controller.ControllerEvent, 10)
 
-	ctrl := controller.NewController(logger.Named("controller"), repo, connectorEventChan, controllerEventChan)
-	return ctrl, cleanup, nil
+	opts = append([]controller.Option{controller.WithMessageStore(messageStore), controller.WithDefaultActor(cliActor())}, opts...)
+	ctrl := controller.NewController(logger.Named("controller"), repo, connectorEventChan, controllerEventChan, opts...)
+	return ctrl, controllerEventChan, cleanup, nil
+}
+
+// cliActor는 CLI를 실행한 OS 사용자의 식별자(cli:{username})를 반환합니다.
+func cliActor() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	return controller.ConnectorActor(controller.ActorConnectorCLI, name)
 }
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func buildPrivacyCommands(logger *zap.Logger) *cobra.Command {
+	privacyCmd := &cobra.Command{
+		Use:   "privacy",
+		Short: "사용자 데이터 내보내기 및 삭제",
+		Long: `사용자가 작성한 Task 프롬프트와 메시지를 찾아 내보내거나 삭제합니다.
+사용자는 커넥터 식별자({connector}:{id})로 지정하며, 접두사가 없으면 Discord 사용자 ID로 간주합니다.
+작성자 기록은 이 기능이 추가된 이후의 메시지에만 남아 있습니다.`,
+	}
+
+	// privacy export
+	var output string
+	exportCmd := &cobra.Command{
+		Use:   "export <user>",
+		Short: "사용자가 작성한 데이터를 JSON으로 내보내기",
+		Example: `  cnap privacy export 123456789012345678 -o user.json
+  cnap privacy export discord:123456789012345678`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPrivacyExport(logger, controller.ParseActor(args[0]), output)
+		},
+	}
+	exportCmd.Flags().StringVarP(&output, "output", "o", "-", "출력 파일 경로 (-이면 표준 출력)")
+
+	// privacy delete
+	var yes bool
+	deleteCmd := &cobra.Command{
+		Use:   "delete <user>",
+		Short: "사용자가 작성한 메시지와 프롬프트 삭제",
+		Long: `사용자가 작성한 메시지 내용과 Task 프롬프트를 지우고 검색 색인에서 제거한 뒤 삭제 기록을 남깁니다.
+대화 순서를 유지하기 위해 메시지 자리에는 삭제 표시만 남습니다.
+어시스턴트 응답, 보관 정책 아카이브(CNAP_RETENTION_ARCHIVE_DIR), 백업 파일은 변경하지 않습니다.`,
+		Example: `  cnap privacy delete 123456789012345678
+  cnap privacy delete discord:123456789012345678 --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPrivacyDelete(logger, controller.ParseActor(args[0]), yes)
+		},
+	}
+	deleteCmd.Flags().BoolVarP(&yes, "yes", "y", false, "확인 없이 삭제")
+
+	// privacy log
+	logCmd := &cobra.Command{
+		Use:   "log [user]",
+		Short: "데이터 삭제 기록 조회",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			author := ""
+			if len(args) == 1 {
+				author = controller.ParseActor(args[0])
+			}
+			return runPrivacyLog(logger, author)
+		},
+	}
+
+	privacyCmd.AddCommand(exportCmd, deleteCmd, logCmd)
+	return privacyCmd
+}
+
+func runPrivacyExport(logger *zap.Logger, author, output string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	data, err := ctrl.ExportAuthorData(ctx, author)
+	if err != nil {
+		return fmt.Errorf("사용자 데이터 조회 실패: %w", err)
+	}
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	encoded = append(encoded, '\n')
+
+	if output == "-" {
+		_, err = os.Stdout.Write(encoded)
+		return err
+	}
+	if err := os.WriteFile(output, encoded, 0600); err != nil {
+		return fmt.Errorf("파일 저장 실패: %w", err)
+	}
+	fmt.Printf("✓ %s의 Task %d개, 메시지 %d개를 %s에 내보냈습니다.\n", author, len(data.Tasks), len(data.Messages), output)
+	return nil
+}
+
+func runPrivacyDelete(logger *zap.Logger, author string, yes bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	data, err := ctrl.ExportAuthorData(ctx, author)
+	if err != nil {
+		return fmt.Errorf("사용자 데이터 조회 실패: %w", err)
+	}
+	if len(data.Tasks) == 0 && len(data.Messages) == 0 {
+		fmt.Printf("%s가 작성한 데이터가 없습니다.\n", author)
+		return nil
+	}
+
+	if !yes {
+		fmt.Printf("%s가 작성한 Task 프롬프트 %d개와 메시지 %d개를 삭제하시겠습니까? (y/N): ", author, len(data.Tasks), len(data.Messages))
+		reader := bufio.NewReader(os.Stdin)
+		confirm, _ := reader.ReadString('\n')
+		confirm = strings.TrimSpace(strings.ToLower(confirm))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println("취소되었습니다.")
+			return nil
+		}
+	}
+
+	record, err := ctrl.DeleteAuthorData(ctx, author)
+	if err != nil {
+		return fmt.Errorf("사용자 데이터 삭제 실패: %w", err)
+	}
+	fmt.Printf("✓ %s의 Task 프롬프트 %d개, 메시지 %d개를 삭제했습니다.\n", author, record.Tasks, record.Messages)
+	return nil
+}
+
+func runPrivacyLog(logger *zap.Logger, author string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	records, err := ctrl.ListPrivacyDeletions(ctx, author)
+	if err != nil {
+		return fmt.Errorf("삭제 기록 조회 실패: %w", err)
+	}
+	printPrivacyDeletions(os.Stdout, records)
+	return nil
+}
+
+// printPrivacyDeletions는 데이터 삭제 기록을 테이블로 출력합니다.
+func printPrivacyDeletions(out io.Writer, records []storage.PrivacyDeletion) {
+	if len(records) == 0 {
+		_, _ = fmt.Fprintln(out, "삭제 기록이 없습니다.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DELETED AT\tUSER\tREQUESTED BY\tTASKS\tMESSAGES")
+	for _, r := range records {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\n",
+			r.CreatedAt.Local().Format("2006-01-02 15:04:05"), r.Author, r.RequestedBy, r.Tasks, r.Messages)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	taskrunner "github.com/cnap-oss/app/internal/runner"
+	"github.com/cnap-oss/app/internal/runner/docker"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func buildRunnerCommands(logger *zap.Logger) *cobra.Command {
+	runnerCmd := &cobra.Command{
+		Use:   "runner",
+		Short: "Runner 관리 명령어",
+		Long:  "Runner Container와 이미지를 관리합니다.",
+	}
+
+	imagesCmd := &cobra.Command{
+		Use:   "images",
+		Short: "Runner 이미지 관리",
+		Long:  "기본 이미지와 Agent별 이미지, Task에 고정된 이미지 다이제스트를 조회하고 정리합니다.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRunnerImagesList(logger)
+		},
+	}
+
+	// runner images list
+	imagesListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Runner 이미지 목록 조회",
+		Long:  "Runner가 사용하는 로컬 이미지 목록과 사용 여부를 조회합니다.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRunnerImagesList(logger)
+		},
+	}
+
+	// runner images prune
+	var dryRun bool
+	imagesPruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "사용하지 않는 Runner 이미지 삭제",
+		Long:  "Agent 설정이나 Task에 고정된 이미지로 참조되지 않는 Runner 이미지를 삭제합니다.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRunnerImagesPrune(logger, dryRun)
+		},
+	}
+	imagesPruneCmd.Flags().BoolVar(&dryRun, "dry-run", false, "삭제하지 않고 대상만 출력")
+
+	imagesCmd.AddCommand(imagesListCmd)
+	imagesCmd.AddCommand(imagesPruneCmd)
+	runnerCmd.AddCommand(imagesCmd)
+
+	return runnerCmd
+}
+
+// runnerImageUsage는 Runner 이미지의 사용 현황입니다.
+type runnerImageUsage struct {
+	repositories map[string]bool // 관리 대상 repository
+	references   map[string]bool // 사용 중인 이미지 참조 (태그 또는 다이제스트)
+}
+
+// inUse는 이미지가 Agent 설정 또는 Task에서 참조되는지 확인합니다.
+func (u *runnerImageUsage) inUse(img docker.ImageInfo) bool {
+	if u.references[img.ID] {
+		return true
+	}
+	for _, ref := range append(append([]string{}, img.RepoTags...), img.RepoDigests...) {
+		if u.references[ref] {
+			return true
+		}
+	}
+	return false
+}
+
+// collectRunnerImageUsage는 기본 이미지, Agent 이미지, Task에 고정된 이미지를 수집합니다.
+func collectRunnerImageUsage(ctx context.Context, repo *storage.Repository) (*runnerImageUsage, error) {
+	usage := &runnerImageUsage{
+		repositories: make(map[string]bool),
+		references:   make(map[string]bool),
+	}
+
+	add := func(ref string) {
+		usage.references[ref] = true
+		if !strings.HasPrefix(ref, "sha256:") {
+			usage.repositories[docker.RepositoryOf(ref)] = true
+		}
+	}
+
+	add(taskrunner.DefaultRunnerImage())
+
+	agents, err := repo.ListAgents(ctx, storage.AgentStatusActive, storage.AgentStatusIdle, storage.AgentStatusBusy)
+	if err != nil {
+		return nil, fmt.Errorf("agent 목록 조회 실패: %w", err)
+	}
+	for _, agent := range agents {
+		if agent.Image != "" {
+			add(agent.Image)
+		}
+	}
+
+	// 종료된 Task도 다시 실행하면 고정된 이미지가 필요하고, 로컬 빌드 이미지(sha256:)는
+	// 다시 pull할 수 없으므로 Task에 기록된 모든 다이제스트를 보호
+	digests, err := repo.ListTaskImageDigests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("task 이미지 조회 실패: %w", err)
+	}
+	for _, digest := range digests {
+		add(digest)
+	}
+
+	return usage, nil
+}
+
+// listRunnerImages는 관리 대상 repository의 로컬 이미지를 ID 기준으로 중복 없이 반환합니다.
+func listRunnerImages(ctx context.Context, client docker.DockerClient, usage *runnerImageUsage) ([]docker.ImageInfo, error) {
+	repos := make([]string, 0, len(usage.repositories))
+	for repo := range usage.repositories {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	seen := make(map[string]bool)
+	images := make([]docker.ImageInfo, 0)
+	for _, repo := range repos {
+		found, err := client.ListImages(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		for _, img := range found {
+			if seen[img.ID] {
+				continue
+			}
+			seen[img.ID] = true
+			images = append(images, img)
+		}
+	}
+	return images, nil
+}
+
+func runRunnerImagesList(logger *zap.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	repo, cleanup, err := initStorage(logger)
+	if err != nil {
+		return fmt.Errorf("저장소 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	usage, err := collectRunnerImageUsage(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	images, err := listRunnerImages(ctx, client, usage)
+	if err != nil {
+		return fmt.Errorf("이미지 목록 조회 실패: %w", err)
+	}
+
+	if len(images) == 0 {
+		fmt.Println("로컬에 Runner 이미지가 없습니다.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TAGS\tDIGEST\tIMAGE ID\tSIZE\tIN USE")
+	_, _ = fmt.Fprintln(w, "----\t------\t--------\t----\t------")
+
+	for _, img := range images {
+		tags := strings.Join(img.RepoTags, ",")
+		if tags == "" {
+			tags = "<none>"
+		}
+		digest := "<none>"
+		if len(img.RepoDigests) > 0 {
+			digest = truncateString(img.RepoDigests[0][strings.Index(img.RepoDigests[0], "@")+1:], 19)
+		}
+		inUse := "-"
+		if usage.inUse(img) {
+			inUse = "yes"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			tags,
+			digest,
+			truncateString(strings.TrimPrefix(img.ID, "sha256:"), 12),
+			formatBytes(img.Size),
+			inUse,
+		)
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+func runRunnerImagesPrune(logger *zap.Logger, dryRun bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	repo, cleanup, err := initStorage(logger)
+	if err != nil {
+		return fmt.Errorf("저장소 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	usage, err := collectRunnerImageUsage(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	images, err := listRunnerImages(ctx, client, usage)
+	if err != nil {
+		return fmt.Errorf("이미지 목록 조회 실패: %w", err)
+	}
+
+	removed := 0
+	var reclaimed int64
+	for _, img := range images {
+		if usage.inUse(img) {
+			continue
+		}
+
+		name := strings.Join(img.RepoTags, ",")
+		if name == "" && len(img.RepoDigests) > 0 {
+			name = img.RepoDigests[0]
+		}
+
+		if dryRun {
+			fmt.Printf("삭제 대상: %s (%s)\n", name, formatBytes(img.Size))
+		} else {
+			// 실행 중인 Container가 사용하는 이미지는 Docker가 삭제를 거부하므로 강제하지 않음
+			if err := client.RemoveImage(ctx, img.ID, false); err != nil {
+				fmt.Printf("⚠ %s 삭제 실패: %v\n", name, err)
+				continue
+			}
+			fmt.Printf("✓ %s 삭제\n", name)
+		}
+		removed++
+		reclaimed += img.Size
+	}
+
+	if removed == 0 {
+		fmt.Println("정리할 이미지가 없습니다.")
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("\n총 %d개 이미지 (%s) 삭제 예정\n", removed, formatBytes(reclaimed))
+	} else {
+		fmt.Printf("\n총 %d개 이미지 삭제 완료 (%s 확보)\n", removed, formatBytes(reclaimed))
+	}
+	return nil
+}
+
+// formatBytes는 바이트 수를 사람이 읽기 쉬운 형태로 변환합니다.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func buildSearchCommand(logger *zap.Logger) *cobra.Command {
+	var agentName, since string
+	var limit int
+
+	searchCmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "모든 대화에서 메시지 검색",
+		Long: `모든 Task의 대화 메시지와 도구 출력에서 검색어를 포함하는 메시지를 최신순으로 찾습니다.
+공백으로 구분된 모든 단어를 포함하는 메시지가 검색됩니다.
+Discord에서 생성된 Task의 ID는 스레드 ID입니다.`,
+		Example: `  cnap search "nginx 설정"
+  cnap search timeout --agent my-agent --since 7d`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSearch(logger, strings.Join(args, " "), agentName, since, limit)
+		},
+	}
+	searchCmd.Flags().StringVar(&agentName, "agent", "", "특정 에이전트의 Task만 검색")
+	searchCmd.Flags().StringVar(&since, "since", "", "이 기간 이후의 메시지만 검색 (예: 7d, 12h, 2025-01-02)")
+	searchCmd.Flags().IntVar(&limit, "limit", 20, "최대 결과 개수")
+
+	return searchCmd
+}
+
+func runSearch(logger *zap.Logger, query, agentName, since string, limit int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	sinceTime, err := controller.ParseSince(since, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	results, err := ctrl.SearchMessages(ctx, query, agentName, sinceTime, limit)
+	if err != nil {
+		return fmt.Errorf("검색 실패: %w", err)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("'%s'에 대한 검색 결과가 없습니다.\n", query)
+		return nil
+	}
+	printSearchResults(os.Stdout, results)
+	return nil
+}
+
+// printSearchResults는 검색 결과를 테이블 형식으로 출력합니다.
+func printSearchResults(out io.Writer, results []storage.SearchResult) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TASK ID\tINDEX\tROLE\tAGENT\tCREATED\tSNIPPET")
+	_, _ = fmt.Fprintln(w, "-------\t-----\t----\t-----\t-------\t-------")
+
+	for _, r := range results {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n",
+			r.TaskID,
+			r.ConversationIndex,
+			r.Role,
+			r.AgentID,
+			r.CreatedAt.Local().Format("2006-01-02 15:04"),
+			strings.Join(strings.Fields(r.Snippet), " "),
+		)
+	}
+	_ = w.Flush()
+}
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"text/tabwriter"
 	"time"
 
+	"github.com/cnap-oss/app/internal/controller"
+	taskrunner "github.com/cnap-oss/app/internal/runner"
 	"github.com/cnap-oss/app/internal/storage"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -39,15 +47,40 @@ func buildTaskCommands(logger *zap.Logger) *cobra.Command {
 	taskCreateCmd.Flags().BoolVarP(&forceCreate, "force", "f", false, "기존 Task가 있으면 삭제 후 생성")
 
 	// task list
+	var listOpts taskListOptions
 	taskListCmd := &cobra.Command{
-		Use:   "list <agent-name>",
+		Use:   "list [agent-name]",
 		Short: "Task 목록 조회",
-		Long:  "특정 Agent의 모든 Task 목록을 조회합니다.",
-		Args:  cobra.ExactArgs(1),
+		Long: `모든 Agent의 Task 목록을 조건에 맞게 조회합니다. agent-name을 지정하면 해당 Agent의 Task만 조회합니다.
+--status에는 쉼표로 여러 상태를 지정할 수 있으며, active는 pending, running, waiting을 의미합니다.
+시각 조건은 7d, 12h 같은 기간이나 2025-01-02, RFC3339 시각을 지원합니다.
+결과가 --limit보다 많으면 마지막에 출력되는 --cursor 값으로 다음 페이지를 조회합니다.`,
+		Example: `  cnap task list my-agent
+  cnap task list --status active
+  cnap task list --status failed --updated-since 24h --sort updated
+  cnap task list --prompt deploy --limit 20 --cursor <cursor>`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runTaskList(logger, args[0])
+			if len(args) == 1 {
+				if listOpts.agent != "" && listOpts.agent != args[0] {
+					return fmt.Errorf("agent-name과 --agent가 다릅니다: %s, %s", args[0], listOpts.agent)
+				}
+				listOpts.agent = args[0]
+			}
+			return runTaskList(logger, listOpts)
 		},
 	}
+	taskListCmd.Flags().StringVar(&listOpts.agent, "agent", "", "특정 Agent의 Task만 조회")
+	taskListCmd.Flags().StringSliceVar(&listOpts.statuses, "status", nil, "상태 필터 (pending, running, waiting, completed, failed, canceled, active)")
+	taskListCmd.Flags().StringVar(&listOpts.createdSince, "created-since", "", "이 시각 이후 생성된 Task")
+	taskListCmd.Flags().StringVar(&listOpts.createdUntil, "created-until", "", "이 시각 이전 생성된 Task")
+	taskListCmd.Flags().StringVar(&listOpts.updatedSince, "updated-since", "", "이 시각 이후 수정된 Task")
+	taskListCmd.Flags().StringVar(&listOpts.updatedUntil, "updated-until", "", "이 시각 이전 수정된 Task")
+	taskListCmd.Flags().StringVar(&listOpts.prompt, "prompt", "", "프롬프트에 포함된 문자열 (대소문자 무시)")
+	taskListCmd.Flags().StringVar(&listOpts.sort, "sort", "created", "정렬 기준 (created, updated)")
+	taskListCmd.Flags().BoolVar(&listOpts.asc, "asc", false, "오래된 순으로 정렬 (기본값: 최신순)")
+	taskListCmd.Flags().IntVar(&listOpts.limit, "limit", storage.DefaultTaskPageSize, "페이지 크기")
+	taskListCmd.Flags().StringVar(&listOpts.cursor, "cursor", "", "이전 조회에서 출력된 다음 페이지 커서")
 
 	// task view
 	taskViewCmd := &cobra.Command{
@@ -83,26 +116,30 @@ func buildTaskCommands(logger *zap.Logger) *cobra.Command {
 	}
 
 	// task run
+	var runWait bool
 	taskRunCmd := &cobra.Command{
 		Use:   "run <task-id>",
 		Short: "Task 실행",
-		Long:  "생성된 Pending 상태의 Task를 실행합니다.",
+		Long:  "생성된 Pending 상태의 Task를 실행합니다. --wait를 지정하면 완료될 때까지 응답을 출력하고 도구 권한 요청에 응답합니다.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runTaskRun(logger, args[0])
+			return runTaskRun(logger, args[0], runWait)
 		},
 	}
+	taskRunCmd.Flags().BoolVarP(&runWait, "wait", "w", false, "완료될 때까지 대기하며 권한 요청에 응답")
 
 	// task send
+	var sendWait bool
 	taskSendCmd := &cobra.Command{
 		Use:   "send <task-id>",
 		Short: "Task 실행 트리거",
-		Long:  "Task의 메시지를 전송하고 실행을 트리거합니다.",
+		Long:  "Task의 메시지를 전송하고 실행을 트리거합니다. --wait를 지정하면 완료될 때까지 응답을 출력하고 도구 권한 요청에 응답합니다.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runTaskSend(logger, args[0])
+			return runTaskSend(logger, args[0], sendWait)
 		},
 	}
+	taskSendCmd.Flags().BoolVarP(&sendWait, "wait", "w", false, "완료될 때까지 대기하며 권한 요청에 응답")
 
 	// task add-message
 	taskAddMessageCmd := &cobra.Command{
@@ -126,6 +163,58 @@ func buildTaskCommands(logger *zap.Logger) *cobra.Command {
 		},
 	}
 
+	// task events
+	var eventsSince int64
+	var eventsJSON bool
+	taskEventsCmd := &cobra.Command{
+		Use:   "events <task-id>",
+		Short: "Task 이벤트 로그 조회",
+		Long: `Task에서 발생한 Controller 이벤트(응답 파트, 도구 호출, 권한 요청, 에러 등)를 순번 순으로 조회합니다.
+--since를 지정하면 해당 순번 이후의 이벤트만 조회합니다. 완료된 파트의 스트리밍 델타는 정리되어 표시되지 않습니다.
+--json을 지정하면 이벤트 직렬화 형식(api/proto/cnap/v1/controller_event.proto)의 JSON을 한 줄에 하나씩 출력합니다.`,
+		Example: `  cnap task events 1234567890
+  cnap task events 1234567890 --since 42 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskEvents(logger, args[0], eventsSince, eventsJSON)
+		},
+	}
+	taskEventsCmd.Flags().Int64Var(&eventsSince, "since", 0, "이 순번 이후의 이벤트만 조회")
+	taskEventsCmd.Flags().BoolVar(&eventsJSON, "json", false, "JSON Lines 형식으로 출력")
+
+	// task logs
+	var followLogs bool
+	var tailLines int
+	taskLogsCmd := &cobra.Command{
+		Use:   "logs <task-id>",
+		Short: "Task Runner 로그 조회",
+		Long:  "Task의 Runner Container 로그를 조회합니다. --follow를 지정하면 새 로그를 계속 출력합니다.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskLogs(logger, args[0], tailLines, followLogs)
+		},
+	}
+	taskLogsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "새 로그를 계속 출력")
+	taskLogsCmd.Flags().IntVarP(&tailLines, "tail", "n", 100, "출력할 마지막 로그 줄 수")
+
+	// task export
+	var exportFormat, exportOutput string
+	taskExportCmd := &cobra.Command{
+		Use:   "export <task-id>",
+		Short: "Task 대화 기록 내보내기",
+		Long: `Task의 대화 기록을 Markdown, HTML, JSONL 형식으로 내보냅니다.
+메시지 본문과 추론, 도구 호출(입력, 출력), 실행 단계와 함께 에이전트, 모델, 리비전, 토큰 사용량, 시각을 포함합니다.
+HTML은 외부 리소스 없이 열 수 있으며 도구 호출은 접혀 있습니다.`,
+		Example: `  cnap task export 1234567890 --format md
+  cnap task export 1234567890 --format html -o transcript.html`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskExport(logger, args[0], exportFormat, exportOutput)
+		},
+	}
+	taskExportCmd.Flags().StringVar(&exportFormat, "format", controller.TranscriptFormatMarkdown, "내보내기 형식 (md, html, jsonl)")
+	taskExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "-", "저장할 파일 경로 (-이면 표준 출력)")
+
 	taskCmd.AddCommand(taskCreateCmd)
 	taskCmd.AddCommand(taskListCmd)
 	taskCmd.AddCommand(taskViewCmd)
@@ -135,6 +224,9 @@ func buildTaskCommands(logger *zap.Logger) *cobra.Command {
 	taskCmd.AddCommand(taskSendCmd)
 	taskCmd.AddCommand(taskAddMessageCmd)
 	taskCmd.AddCommand(taskMessagesCmd)
+	taskCmd.AddCommand(taskEventsCmd)
+	taskCmd.AddCommand(taskLogsCmd)
+	taskCmd.AddCommand(taskExportCmd)
 
 	return taskCmd
 }
@@ -143,12 +235,21 @@ func runTaskCreate(logger *zap.Logger, agentName, taskID, prompt string, force b
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
 
-	ctrl, cleanup, err := newController(logger)
+	ctrl, events, cleanup, err := newControllerWithEvents(logger)
 	if err != nil {
 		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
 	}
 	defer cleanup()
 
+	// Runner 시작 중 이미지를 pull하면 진행 상황 출력
+	go func() {
+		for event := range events {
+			if event.EventType == controller.EventTypeImagePull {
+				printImagePull(os.Stdout, event)
+			}
+		}
+	}()
+
 	// Task ID가 없으면 자동 생성
 	if taskID == "" {
 		taskID = generateTaskID()
@@ -224,42 +325,115 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-func runTaskList(logger *zap.Logger, agentName string) error {
+// taskListOptions는 `cnap task list` 플래그 값입니다.
+type taskListOptions struct {
+	agent        string
+	statuses     []string
+	createdSince string
+	createdUntil string
+	updatedSince string
+	updatedUntil string
+	prompt       string
+	sort         string
+	asc          bool
+	limit        int
+	cursor       string
+}
+
+// query는 플래그 값을 storage.TaskQuery로 변환합니다. 시각은 now 기준으로 해석합니다.
+func (o taskListOptions) query(now time.Time) (storage.TaskQuery, error) {
+	statuses, err := controller.ParseTaskStatuses(o.statuses...)
+	if err != nil {
+		return storage.TaskQuery{}, err
+	}
+
+	q := storage.TaskQuery{
+		AgentID:        o.agent,
+		Statuses:       statuses,
+		PromptContains: o.prompt,
+		Ascending:      o.asc,
+		Limit:          o.limit,
+		Cursor:         o.cursor,
+	}
+	switch o.sort {
+	case "", "created":
+		q.SortBy = storage.TaskSortCreated
+	case "updated":
+		q.SortBy = storage.TaskSortUpdated
+	default:
+		return storage.TaskQuery{}, fmt.Errorf("알 수 없는 정렬 기준: %s (created, updated)", o.sort)
+	}
+
+	for _, t := range []struct {
+		value string
+		dst   *time.Time
+	}{
+		{o.createdSince, &q.CreatedAfter},
+		{o.createdUntil, &q.CreatedBefore},
+		{o.updatedSince, &q.UpdatedAfter},
+		{o.updatedUntil, &q.UpdatedBefore},
+	} {
+		if *t.dst, err = controller.ParseSince(t.value, now); err != nil {
+			return storage.TaskQuery{}, err
+		}
+	}
+	return q, nil
+}
+
+func runTaskList(logger *zap.Logger, opts taskListOptions) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
 
+	query, err := opts.query(time.Now())
+	if err != nil {
+		return err
+	}
+
 	ctrl, cleanup, err := newController(logger)
 	if err != nil {
 		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
 	}
 	defer cleanup()
 
-	tasks, err := ctrl.ListTasksByAgent(ctx, agentName)
+	page, err := ctrl.ListTasks(ctx, query)
 	if err != nil {
 		return fmt.Errorf("task 목록 조회 실패: %w", err)
 	}
 
-	if len(tasks) == 0 {
-		fmt.Printf("Agent '%s'에 등록된 Task가 없습니다.\n", agentName)
+	if len(page.Tasks) == 0 {
+		if opts.agent != "" && opts.cursor == "" && len(query.Statuses) == 0 {
+			fmt.Printf("Agent '%s'에 등록된 Task가 없습니다.\n", opts.agent)
+		} else {
+			fmt.Println("조건에 맞는 Task가 없습니다.")
+		}
 		return nil
 	}
 
-	// 테이블 형식 출력
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "TASK ID\tSTATUS\tCREATED\tUPDATED")
-	_, _ = fmt.Fprintln(w, "-------\t------\t-------\t-------")
+	printTaskPage(os.Stdout, page)
+	return nil
+}
+
+// printTaskPage는 Task 목록을 테이블 형식으로 출력하고, 다음 페이지가 있으면 커서를 안내합니다.
+func printTaskPage(out io.Writer, page *storage.TaskPage) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TASK ID\tAGENT\tSTATUS\tCREATED\tUPDATED\tPROMPT")
+	_, _ = fmt.Fprintln(w, "-------\t-----\t------\t-------\t-------\t------")
 
-	for _, task := range tasks {
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+	for _, task := range page.Tasks {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
 			task.TaskID,
+			task.AgentID,
 			task.Status,
 			task.CreatedAt.Format("2006-01-02 15:04"),
 			task.UpdatedAt.Format("2006-01-02 15:04"),
+			truncateString(strings.Join(strings.Fields(task.Prompt), " "), 40),
 		)
 	}
 	_ = w.Flush()
 
-	return nil
+	if page.NextCursor != "" {
+		_, _ = fmt.Fprintf(out, "\n다음 페이지: --cursor %s\n", page.NextCursor)
+	}
 }
 
 func runTaskView(logger *zap.Logger, taskID string) error {
@@ -348,11 +522,11 @@ func runTaskCancel(logger *zap.Logger, taskID string) error {
 	return nil
 }
 
-func runTaskRun(logger *zap.Logger, taskID string) error {
+func runTaskRun(logger *zap.Logger, taskID string, wait bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	ctrl, cleanup, err := newController(logger)
+	ctrl, events, cleanup, err := newControllerWithEvents(logger)
 	if err != nil {
 		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
 	}
@@ -363,15 +537,18 @@ func runTaskRun(logger *zap.Logger, taskID string) error {
 	}
 
 	fmt.Printf("✓ Task '%s' 실행 시작\n", taskID)
+	if wait {
+		return waitTaskEvents(ctrl, events, taskID)
+	}
 	fmt.Printf("  상태 확인: cnap task view %s\n", taskID)
 	return nil
 }
 
-func runTaskSend(logger *zap.Logger, taskID string) error {
+func runTaskSend(logger *zap.Logger, taskID string, wait bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
 
-	ctrl, cleanup, err := newController(logger)
+	ctrl, events, cleanup, err := newControllerWithEvents(logger)
 	if err != nil {
 		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
 	}
@@ -382,9 +559,118 @@ func runTaskSend(logger *zap.Logger, taskID string) error {
 	}
 
 	fmt.Printf("✓ Task '%s' 실행이 트리거되었습니다.\n", taskID)
+	if wait {
+		return waitTaskEvents(ctrl, events, taskID)
+	}
 	return nil
 }
 
+// waitTaskEvents는 Task가 끝날 때까지 이벤트를 출력하고, 도구 권한 요청은 터미널에서 응답을 받습니다.
+func waitTaskEvents(ctrl *controller.Controller, events <-chan controller.ControllerEvent, taskID string) error {
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return watchTaskEvents(sigCtx, ctrl, events, taskID, bufio.NewReader(os.Stdin), os.Stdout)
+}
+
+// watchTaskEvents는 taskID의 이벤트를 out에 출력하고, 권한 요청이 오면 in에서 응답을 읽어 전달합니다.
+// 턴이 완료되거나 Task가 실패/취소되면 반환합니다.
+func watchTaskEvents(ctx context.Context, ctrl *controller.Controller, events <-chan controller.ControllerEvent, taskID string, in *bufio.Reader, out io.Writer) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-events:
+			if event.TaskID != taskID {
+				continue
+			}
+
+			switch event.EventType {
+			case controller.EventTypePartComplete:
+				if event.PartType == controller.PartTypeText && event.Role == "assistant" {
+					_, _ = fmt.Fprintf(out, "%s\n", event.Content)
+				}
+			case controller.EventTypeToolStart:
+				if event.ToolInfo != nil {
+					_, _ = fmt.Fprintf(out, "🔧 %s\n", event.ToolInfo.ToolName)
+				}
+			case controller.EventTypePermissionRequest:
+				if event.Permission == nil {
+					continue
+				}
+				response, err := promptPermission(in, out, event.Permission)
+				if err != nil {
+					return fmt.Errorf("권한 응답 입력 실패: %w", err)
+				}
+				if err := ctrl.RespondPermission(ctx, taskID, event.Permission.ID, response); err != nil {
+					_, _ = fmt.Fprintf(out, "✗ 권한 응답 실패: %v\n", err)
+				}
+			case controller.EventTypePermissionResolved:
+				if event.Permission != nil && event.Permission.TimedOut {
+					_, _ = fmt.Fprintf(out, "⏱ 권한 요청 시간 초과: '%s' 응답이 적용되었습니다.\n", event.Permission.Response)
+				}
+			case controller.EventTypeImagePull:
+				printImagePull(out, event)
+			case controller.EventTypeStreamStatus:
+				switch event.Status {
+				case "reconnecting":
+					_, _ = fmt.Fprintf(out, "⚠ Runner 연결이 끊겨 재연결하는 중입니다: %s\n", event.Content)
+				case "connected":
+					_, _ = fmt.Fprintln(out, "✓ Runner 연결이 복구되었습니다.")
+				}
+			case controller.EventTypeTurnComplete:
+				_, _ = fmt.Fprintf(out, "✓ Task '%s' 응답 완료\n", taskID)
+				return nil
+			case controller.EventTypeError:
+				return fmt.Errorf("task 실행 에러: %w", event.Error)
+			}
+
+			switch event.Status {
+			case "completed":
+				_, _ = fmt.Fprintf(out, "✓ Task '%s' 완료\n", taskID)
+				return nil
+			case "failed":
+				return fmt.Errorf("task 실행 실패: %v", event.Error)
+			case "canceled":
+				_, _ = fmt.Fprintf(out, "✓ Task '%s' 취소됨\n", taskID)
+				return nil
+			}
+		}
+	}
+}
+
+// printImagePull은 Runner 이미지 pull 진행 상황을 한 줄로 출력합니다.
+func printImagePull(out io.Writer, event controller.ControllerEvent) {
+	_, _ = fmt.Fprintf(out, "📦 %s %s\n", event.Content, event.Delta)
+}
+
+// promptPermission은 도구 권한 요청을 출력하고 사용자 응답(once, always, reject)을 읽습니다.
+// 빈 입력이나 알 수 없는 입력은 거부로 처리합니다.
+func promptPermission(in *bufio.Reader, out io.Writer, perm *controller.PermissionEventInfo) (string, error) {
+	_, _ = fmt.Fprintf(out, "🔐 도구 실행 권한 요청 [%s] %s\n", perm.Type, perm.Title)
+	for _, pattern := range perm.Patterns {
+		_, _ = fmt.Fprintf(out, "   대상: %s\n", pattern)
+	}
+	_, _ = fmt.Fprintf(out, "   승인하시겠습니까? [y]승인 / [a]항상 허용 / [N]거부 (기한: %s): ", perm.ExpiresAt.Format("15:04:05"))
+
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return parsePermissionAnswer(line), nil
+}
+
+// parsePermissionAnswer는 터미널 입력을 OpenCode 권한 응답으로 변환합니다.
+func parsePermissionAnswer(answer string) string {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "once":
+		return storage.PermissionResponseOnce
+	case "a", "always":
+		return storage.PermissionResponseAlways
+	default:
+		return storage.PermissionResponseReject
+	}
+}
+
 func runTaskAddMessage(logger *zap.Logger, taskID, message string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
@@ -403,6 +689,35 @@ func runTaskAddMessage(logger *zap.Logger, taskID, message string) error {
 	return nil
 }
 
+func runTaskExport(logger *zap.Logger, taskID, format, output string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	transcript, err := ctrl.BuildTaskTranscript(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("대화 기록 조회 실패: %w", err)
+	}
+
+	if output == "-" {
+		return controller.WriteTranscript(os.Stdout, transcript, format)
+	}
+	var buf strings.Builder
+	if err := controller.WriteTranscript(&buf, transcript, format); err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, []byte(buf.String()), 0600); err != nil {
+		return fmt.Errorf("파일 저장 실패: %w", err)
+	}
+	fmt.Printf("✓ 메시지 %d개를 %s에 내보냈습니다.\n", len(transcript.Messages), output)
+	return nil
+}
+
 func runTaskMessages(logger *zap.Logger, taskID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
@@ -440,3 +755,144 @@ func runTaskMessages(logger *zap.Logger, taskID string) error {
 
 	return nil
 }
+
+func runTaskEvents(logger *zap.Logger, taskID string, since int64, asJSON bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	ctrl, cleanup, err := newController(logger)
+	if err != nil {
+		return fmt.Errorf("컨트롤러 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	events, err := ctrl.ReplayEvents(ctx, taskID, since)
+	if err != nil {
+		return fmt.Errorf("이벤트 조회 실패: %w", err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range events {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(events) == 0 {
+		fmt.Printf("Task '%s'에 조회할 이벤트가 없습니다.\n", taskID)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SEQ\tTYPE\tSTATUS\tDETAIL")
+	_, _ = fmt.Fprintln(w, "---\t----\t------\t------")
+	for _, e := range events {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", e.Seq, e.EventType, e.Status, truncateString(eventDetail(e), 60))
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// eventDetail은 이벤트 목록에 표시할 한 줄 요약을 반환합니다.
+func eventDetail(e controller.ControllerEvent) string {
+	switch {
+	case e.Error != nil:
+		return e.Error.Error()
+	case e.ToolInfo != nil:
+		return e.ToolInfo.ToolName
+	case e.Permission != nil:
+		return e.Permission.Title
+	case e.Delta != "":
+		return strings.ReplaceAll(e.Delta, "\n", " ")
+	default:
+		return strings.ReplaceAll(e.Content, "\n", " ")
+	}
+}
+
+func runTaskLogs(logger *zap.Logger, taskID string, tail int, follow bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	repo, cleanup, err := initStorage(logger)
+	if err != nil {
+		return fmt.Errorf("저장소 초기화 실패: %w", err)
+	}
+	defer cleanup()
+
+	task, err := repo.GetTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("task 조회 실패: %w", err)
+	}
+
+	workspacePath, err := taskrunner.AgentWorkspacePath(task.AgentID)
+	if err != nil {
+		return err
+	}
+	logPath := taskrunner.TaskLogPath(workspacePath, taskID)
+
+	lines, err := taskrunner.ReadLogTail(logPath, tail)
+	if err != nil {
+		return fmt.Errorf("로그 조회 실패: %w", err)
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+
+	if !follow {
+		if len(lines) == 0 {
+			fmt.Printf("Task '%s'의 Runner 로그가 없습니다.\n", taskID)
+		}
+		return nil
+	}
+
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return followLogFile(sigCtx, logPath, os.Stdout)
+}
+
+// followLogFile은 로그 파일 끝에서부터 새로 추가되는 내용을 ctx가 종료될 때까지 출력합니다.
+// 파일 크기가 줄어들면 회전된 것으로 보고 새 파일의 처음부터 다시 읽습니다.
+func followLogFile(ctx context.Context, path string, out io.Writer) error {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("로그 파일 조회 실패: %w", err)
+		}
+		if info.Size() < offset {
+			offset = 0
+		}
+		if info.Size() == offset {
+			continue
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("로그 파일 열기 실패: %w", err)
+		}
+		if _, err := f.Seek(offset, io.SeekStart); err == nil {
+			n, _ := io.Copy(out, f)
+			offset += n
+		}
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+)
+
+func TestPromptPermission(t *testing.T) {
+	perm := &controller.PermissionEventInfo{
+		ID:        "per_1",
+		Type:      "bash",
+		Title:     "rm -rf build",
+		Patterns:  []string{"rm *"},
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	cases := map[string]string{
+		"y\n":      storage.PermissionResponseOnce,
+		"always\n": storage.PermissionResponseAlways,
+		" A \n":    storage.PermissionResponseAlways,
+		"\n":       storage.PermissionResponseReject,
+		"nope\n":   storage.PermissionResponseReject,
+		"yes":      storage.PermissionResponseOnce, // 개행 없이 입력이 끝난 경우
+	}
+	for input, want := range cases {
+		var out bytes.Buffer
+		got, err := promptPermission(bufio.NewReader(strings.NewReader(input)), &out, perm)
+		if err != nil {
+			t.Fatalf("promptPermission(%q) 에러: %v", input, err)
+		}
+		if got != want {
+			t.Errorf("promptPermission(%q) = %q, want %q", input, got, want)
+		}
+		if !strings.Contains(out.String(), "rm -rf build") || !strings.Contains(out.String(), "rm *") {
+			t.Errorf("권한 요청 정보가 출력되지 않음: %q", out.String())
+		}
+	}
+
+	if _, err := promptPermission(bufio.NewReader(strings.NewReader("")), &bytes.Buffer{}, perm); err == nil {
+		t.Error("입력이 없으면 에러를 반환해야 함")
+	}
+}
+
+func TestTaskListOptionsQuery(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	q, err := taskListOptions{
+		agent:        "my-agent",
+		statuses:     []string{"active", "failed"},
+		updatedSince: "24h",
+		createdUntil: "2025-03-01",
+		sort:         "updated",
+		limit:        20,
+	}.query(now)
+	if err != nil {
+		t.Fatalf("query 에러: %v", err)
+	}
+	wantStatuses := []string{storage.TaskStatusPending, storage.TaskStatusRunning, storage.TaskStatusWaiting, storage.TaskStatusFailed}
+	if strings.Join(q.Statuses, ",") != strings.Join(wantStatuses, ",") {
+		t.Errorf("Statuses = %v, want %v", q.Statuses, wantStatuses)
+	}
+	if q.AgentID != "my-agent" || q.SortBy != storage.TaskSortUpdated || q.Limit != 20 {
+		t.Errorf("예상과 다른 조건: %+v", q)
+	}
+	if !q.UpdatedAfter.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("UpdatedAfter = %s", q.UpdatedAfter)
+	}
+	if !q.CreatedBefore.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedBefore = %s", q.CreatedBefore)
+	}
+	if !q.CreatedAfter.IsZero() || !q.UpdatedBefore.IsZero() {
+		t.Errorf("지정하지 않은 시각 조건이 설정됨: %+v", q)
+	}
+
+	for _, opts := range []taskListOptions{
+		{statuses: []string{"done"}},
+		{sort: "prompt"},
+		{createdSince: "yesterday"},
+	} {
+		if _, err := opts.query(now); err == nil {
+			t.Errorf("%+v: 에러를 반환해야 함", opts)
+		}
+	}
+}
@@ -20,6 +20,7 @@ type Config struct {
 	APIKeys   APIKeysConfig   `yaml:"api_keys"`
 	Runner    RunnerConfig    `yaml:"runner"`
 	Directory DirectoryConfig `yaml:"directory"`
+	Retention RetentionConfig `yaml:"retention"`
 }
 
 // AppConfig는 애플리케이션 기본 설정입니다.
@@ -48,12 +49,18 @@ type DatabaseConfig struct {
 	PrepareStmt bool `yaml:"prepare_stmt"`
 	// DisableAutomaticPing은 자동 ping을 비활성화할지 여부입니다
 	DisableAutomaticPing bool `yaml:"disable_automatic_ping"`
+	// MessageStore는 대화 메시지 내용 저장소입니다 (file, db)
+	MessageStore string `yaml:"message_store"`
 }
 
 // DiscordConfig는 Discord 봇 설정입니다.
 type DiscordConfig struct {
 	// Token은 Discord 봇 토큰입니다
 	Token string `yaml:"token"`
+	// AuditChannelID는 감사 기록을 함께 게시할 채널 ID입니다 (비어 있으면 게시하지 않음)
+	AuditChannelID string `yaml:"audit_channel_id"`
+	// DefaultGuildID는 기본 테넌트(테넌트 도입 이전 에이전트, CLI로 만든 에이전트)에 대응하는 Discord 서버 ID입니다
+	DefaultGuildID string `yaml:"default_guild_id"`
 }
 
 // APIKeysConfig는 외부 API 키 설정입니다.
@@ -82,6 +89,23 @@ type DirectoryConfig struct {
 	WorkspaceBaseDir string `yaml:"workspace_base_dir"`
 	// SQLiteDatabase는 SQLite 데이터베이스 파일 경로입니다
 	SQLiteDatabase string `yaml:"sqlite_database"`
+	// AgentConfigDir은 선언형 에이전트 설정(*.yaml) 디렉토리입니다 (비어 있으면 사용하지 않음)
+	AgentConfigDir string `yaml:"agent_config_dir"`
+}
+
+// RetentionConfig는 오래된 Task, 메시지, 작업 공간의 기본 보관 정책입니다. 일수가 0이면 삭제하지 않습니다.
+// 에이전트별 보관 정책이 설정되어 있으면 그 값이 우선합니다.
+type RetentionConfig struct {
+	// CompletedTaskDays는 완료(또는 취소)된 Task를 마지막 활동 후 보관할 일수입니다
+	CompletedTaskDays int `yaml:"completed_task_days"`
+	// FailedTaskDays는 실패한 Task를 마지막 활동 후 보관할 일수입니다
+	FailedTaskDays int `yaml:"failed_task_days"`
+	// WorkspaceIdleDays는 에이전트 작업 공간을 마지막 활동 후 보관할 일수입니다
+	WorkspaceIdleDays int `yaml:"workspace_idle_days"`
+	// ArchiveDir을 지정하면 Task를 삭제하기 전에 대화 기록(JSONL)을 이 디렉토리에 보관합니다
+	ArchiveDir string `yaml:"archive_dir"`
+	// Interval은 서버에서 정리 작업을 실행하는 주기입니다 (0이면 실행하지 않음)
+	Interval time.Duration `yaml:"interval"`
 }
 
 var (
@@ -156,6 +180,7 @@ func LoadConfigFromEnv() (*Config, error) {
 		APIKeys:   loadAPIKeysConfig(),
 		Runner:    loadRunnerConfig(),
 		Directory: loadDirectoryConfig(),
+		Retention: loadRetentionConfig(),
 	}
 
 	return cfg, nil
@@ -193,11 +218,20 @@ func mergeWithEnv(cfg *Config) *Config {
 	if prepStmt := os.Getenv("CNAP_DB_PREPARE_STMT"); prepStmt != "" {
 		cfg.Database.PrepareStmt = parseBoolWithDefault(prepStmt, cfg.Database.PrepareStmt)
 	}
+	if messageStore := os.Getenv("CNAP_MESSAGE_STORE"); messageStore != "" {
+		cfg.Database.MessageStore = messageStore
+	}
 
 	// Discord
 	if token := os.Getenv("CNAP_DISCORD_TOKEN"); token != "" {
 		cfg.Discord.Token = token
 	}
+	if channelID := os.Getenv("CNAP_DISCORD_AUDIT_CHANNEL"); channelID != "" {
+		cfg.Discord.AuditChannelID = channelID
+	}
+	if guildID := os.Getenv("CNAP_DISCORD_DEFAULT_GUILD"); guildID != "" {
+		cfg.Discord.DefaultGuildID = guildID
+	}
 
 	// API Keys
 	if apiKey := os.Getenv("CNAP_OPENCODE_API_KEY"); apiKey != "" {
@@ -228,6 +262,26 @@ func mergeWithEnv(cfg *Config) *Config {
 	if sqliteDB := os.Getenv("CNAP_SQLITE_DATABASE"); sqliteDB != "" {
 		cfg.Directory.SQLiteDatabase = sqliteDB
 	}
+	if agentConfigDir := os.Getenv("CNAP_AGENT_CONFIG_DIR"); agentConfigDir != "" {
+		cfg.Directory.AgentConfigDir = agentConfigDir
+	}
+
+	// Retention
+	if days := os.Getenv("CNAP_RETENTION_COMPLETED_DAYS"); days != "" {
+		cfg.Retention.CompletedTaskDays = parseIntWithDefault(days, cfg.Retention.CompletedTaskDays)
+	}
+	if days := os.Getenv("CNAP_RETENTION_FAILED_DAYS"); days != "" {
+		cfg.Retention.FailedTaskDays = parseIntWithDefault(days, cfg.Retention.FailedTaskDays)
+	}
+	if days := os.Getenv("CNAP_RETENTION_WORKSPACE_IDLE_DAYS"); days != "" {
+		cfg.Retention.WorkspaceIdleDays = parseIntWithDefault(days, cfg.Retention.WorkspaceIdleDays)
+	}
+	if archiveDir := os.Getenv("CNAP_RETENTION_ARCHIVE_DIR"); archiveDir != "" {
+		cfg.Retention.ArchiveDir = archiveDir
+	}
+	if interval := os.Getenv("CNAP_RETENTION_INTERVAL"); interval != "" {
+		cfg.Retention.Interval = parseDurationWithDefault(interval, cfg.Retention.Interval)
+	}
 
 	return cfg
 }
@@ -260,6 +314,7 @@ func loadDatabaseConfig() DatabaseConfig {
 		ConnMaxLifetime: parseDurationWithDefault(os.Getenv("CNAP_DB_CONN_LIFETIME"), 30*time.Minute),
 		SkipDefaultTxn:  parseBoolWithDefault(os.Getenv("CNAP_DB_SKIP_DEFAULT_TXN"), true),
 		PrepareStmt:     parseBoolWithDefault(os.Getenv("CNAP_DB_PREPARE_STMT"), false),
+		MessageStore:    getEnvOrDefault("CNAP_MESSAGE_STORE", "file"),
 	}
 
 	if v, ok := lookupEnvBool("CNAP_DB_DISABLE_AUTO_PING"); ok {
@@ -271,7 +326,9 @@ func loadDatabaseConfig() DatabaseConfig {
 
 func loadDiscordConfig() DiscordConfig {
 	return DiscordConfig{
-		Token: os.Getenv("CNAP_DISCORD_TOKEN"),
+		Token:          os.Getenv("CNAP_DISCORD_TOKEN"),
+		AuditChannelID: os.Getenv("CNAP_DISCORD_AUDIT_CHANNEL"),
+		DefaultGuildID: os.Getenv("CNAP_DISCORD_DEFAULT_GUILD"),
 	}
 }
 
@@ -307,6 +364,17 @@ func loadDirectoryConfig() DirectoryConfig {
 		CNAPDir:          getCNAPDir(),
 		WorkspaceBaseDir: os.Getenv("CNAP_WORKSPACE_BASE_DIR"),
 		SQLiteDatabase:   os.Getenv("CNAP_SQLITE_DATABASE"),
+		AgentConfigDir:   os.Getenv("CNAP_AGENT_CONFIG_DIR"),
+	}
+}
+
+func loadRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		CompletedTaskDays: parseIntWithDefault(os.Getenv("CNAP_RETENTION_COMPLETED_DAYS"), 0),
+		FailedTaskDays:    parseIntWithDefault(os.Getenv("CNAP_RETENTION_FAILED_DAYS"), 0),
+		WorkspaceIdleDays: parseIntWithDefault(os.Getenv("CNAP_RETENTION_WORKSPACE_IDLE_DAYS"), 0),
+		ArchiveDir:        os.Getenv("CNAP_RETENTION_ARCHIVE_DIR"),
+		Interval:          parseDurationWithDefault(os.Getenv("CNAP_RETENTION_INTERVAL"), time.Hour),
 	}
 }
 
@@ -26,12 +26,18 @@ func GetDataDir() string {
 }
 
 // GetWorkspaceDir returns the workspace directory path.
-// Default: {DataDir}/workspace
+// Priority:
+// 1. CNAP_WORKSPACE_BASE_DIR from config
+// 2. CNAP_RUNNER_WORKSPACE_DIR from config (legacy)
+// 3. {DataDir}/workspace (default)
 func GetWorkspaceDir() string {
 	cfg, err := LoadConfig()
 	if err == nil && cfg.Directory.WorkspaceBaseDir != "" {
 		return cfg.Directory.WorkspaceBaseDir
 	}
+	if err == nil && cfg.Runner.WorkspaceDir != "" {
+		return cfg.Runner.WorkspaceDir
+	}
 	return filepath.Join(GetDataDir(), "workspace")
 }
 
@@ -62,11 +62,12 @@ func (s *Connector) Start(ctx context.Context) error {
 	s.session.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages
 
 	// 핸들러 초기화
-	s.discordHandler = handlers.NewDiscordHandler(s.logger, s.session, s.controller, s.connectorEventChan)
-	s.controllerHandler = handlers.NewControllerHandler(s.logger, s.session)
+	s.discordHandler = handlers.NewDiscordHandler(s.logger, s.session, s.controller, s.connectorEventChan, cfg.Discord.DefaultGuildID)
+	s.controllerHandler = handlers.NewControllerHandler(s.logger, s.session, s.controller)
 
 	// Discord 이벤트 핸들러 등록
 	s.discordHandler.RegisterHandlers()
+	s.controllerHandler.RegisterHandlers()
 
 	if err := s.session.Open(); err != nil {
 		return fmt.Errorf("error opening connection: %w", err)
@@ -77,6 +78,11 @@ func (s *Connector) Start(ctx context.Context) error {
 	// Controller 이벤트 핸들러 goroutine 시작
 	go s.controllerHandler.Start(ctx, s.controllerEventChan)
 
+	// 감사 기록 채널이 지정되어 있으면 감사 기록을 함께 게시
+	if cfg.Discord.AuditChannelID != "" {
+		go s.controllerHandler.StartAuditMirror(ctx, s.controller.SubscribeAudit(100), cfg.Discord.AuditChannelID)
+	}
+
 	// 컨텍스트가 취소될 때까지 대기
 	<-ctx.Done()
 	s.logger.Info("Connector server shutting down")
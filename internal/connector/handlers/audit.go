@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// auditChangeLimit는 감사 기록 임베드에 표시할 변경 값의 최대 길이입니다.
+const auditChangeLimit = 200
+
+// StartAuditMirror는 Controller의 감사 기록을 지정한 Discord 채널에 게시합니다.
+func (h *ControllerHandler) StartAuditMirror(ctx context.Context, events <-chan storage.AuditEvent, channelID string) {
+	h.logger.Info("Audit mirror started", zap.String("channel_id", channelID))
+	defer h.logger.Info("Audit mirror stopped")
+
+	for {
+		select {
+		case event := <-events:
+			if _, err := h.session.ChannelMessageSendEmbed(channelID, auditEmbed(event)); err != nil {
+				h.logger.Warn("Failed to mirror audit event",
+					zap.String("action", event.Action),
+					zap.String("channel_id", channelID),
+					zap.Error(err),
+				)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// auditEmbed는 감사 기록을 Discord 임베드로 변환합니다.
+func auditEmbed(event storage.AuditEvent) *discordgo.MessageEmbed {
+	actor := event.Actor
+	if id, ok := strings.CutPrefix(actor, controller.ActorConnectorDiscord+":"); ok {
+		actor = fmt.Sprintf("<@%s>", id)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:     event.Action,
+		Color:     0x808080, // Gray
+		Timestamp: event.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "대상", Value: fmt.Sprintf("%s `%s`", event.TargetType, event.TargetID), Inline: true},
+			{Name: "수행자", Value: actor, Inline: true},
+		},
+	}
+
+	var lines []string
+	for _, change := range controller.AuditChanges(event) {
+		field := change.Field
+		if field == "" {
+			field = "값"
+		}
+		lines = append(lines, fmt.Sprintf("**%s**: %s → %s", field,
+			auditChangeValue(change.Old), auditChangeValue(change.New)))
+	}
+	if len(lines) > 0 {
+		embed.Description = truncateRunes(strings.Join(lines, "\n"), 4000)
+	}
+	return embed
+}
+
+// auditChangeValue는 변경 값을 한 줄로 줄여 표시합니다.
+func auditChangeValue(s string) string {
+	if s == "" {
+		return "(없음)"
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	return "`" + truncateRunes(s, auditChangeLimit) + "`"
+}
+
+// truncateRunes는 문자열을 최대 글자 수로 자르고 "..."을 추가합니다. 한글이 깨지지 않도록 rune 단위로 자릅니다.
+func truncateRunes(s string, maxLen int) string {
+	if r := []rune(s); len(r) > maxLen {
+		return string(r[:maxLen]) + "..."
+	}
+	return s
+}
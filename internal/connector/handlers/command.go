@@ -1,25 +1,47 @@
 package handlers
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"context"
 
-// handleSlashCommand는 '/agent' 슬래시 명령어를 처리합니다.
+	"github.com/bwmarrin/discordgo"
+)
+
+// handleSlashCommand는 사용자의 역할을 확인한 뒤 슬래시 명령어를 명령어별 핸들러로 라우팅합니다.
 func (h *DiscordHandler) handleSlashCommand(i *discordgo.InteractionCreate) {
-	if i.ApplicationCommandData().Name != cmdAgent {
+	data := i.ApplicationCommandData()
+	ctx, ok := h.authorize(i, commandRole(data))
+	if !ok {
 		return
 	}
+	switch data.Name {
+	case cmdAgent:
+		h.handleAgentCommand(ctx, i)
+	case cmdTask:
+		h.handleTaskCommand(ctx, i)
+	case cmdSearch:
+		h.handleSearchCommand(ctx, i)
+	case cmdCNAP:
+		h.handleCNAPCommand(ctx, i)
+	}
+}
+
+// handleAgentCommand는 '/agent' 슬래시 명령어를 처리합니다.
+func (h *DiscordHandler) handleAgentCommand(ctx context.Context, i *discordgo.InteractionCreate) {
 	subCommand := i.ApplicationCommandData().Options[0]
 	switch subCommand.Name {
 	case subCmdCreate:
 		h.showCreateOrEditModal(i, "", nil)
 	case subCmdList:
-		h.showAgentList(i)
+		h.showAgentList(ctx, i)
 	case subCmdView:
-		h.showAgentDetails(i, subCommand.Options[0].StringValue())
+		h.showAgentDetails(ctx, i, subCommand.Options[0].StringValue())
 	case subCmdDelete:
-		h.deleteAgent(i, subCommand.Options[0].StringValue())
+		h.deleteAgent(ctx, i, subCommand.Options[0].StringValue())
 	case subCmdEdit:
-		h.showEditUI(i, subCommand.Options[0].StringValue())
+		h.showEditUI(ctx, i, subCommand.Options[0].StringValue())
 	case subCmdCall:
-		h.startAgentThread(i, subCommand.Options[0].StringValue())
+		h.startAgentThread(ctx, i, subCommand.Options[0].StringValue())
+	case subCmdVisibility:
+		h.setAgentVisibility(ctx, i, subCommand.Options[0].StringValue(), subCommand.Options[1].StringValue())
 	}
 }
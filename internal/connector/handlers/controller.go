@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/cnap-oss/app/internal/controller"
@@ -12,21 +14,51 @@ import (
 
 // ControllerHandler는 Controller로부터의 이벤트를 처리합니다.
 type ControllerHandler struct {
-	logger            *zap.Logger
-	session           *discordgo.Session
-	toolMessagesMutex sync.RWMutex
-	toolMessages      map[string]string // key: taskID:callID, value: Discord messageID
+	logger              *zap.Logger
+	session             *discordgo.Session
+	controller          *controller.Controller
+	toolMessagesMutex   sync.RWMutex
+	toolMessages        map[string]string // key: taskID:callID, value: Discord messageID
+	permMessagesMutex   sync.Mutex
+	permMessages        map[string]string // key: taskID:permissionID, value: Discord messageID
+	statusMessagesMutex sync.Mutex
+	statusMessages      map[string]string // key: taskID[:stream], value: Runner 상태(이미지 pull, 스트림 재연결) Discord messageID
+
+	// Discord 연결이 끊긴 동안 받은 이벤트는 재연결 후 이벤트 로그에서 다시 재생
+	deliverMu sync.Mutex
+	connected bool
+	lastSeq   map[string]int64 // key: taskID, value: 마지막으로 처리한 이벤트 순번
+	missed    map[string]bool  // 연결이 끊긴 동안 이벤트를 놓친 Task
 }
 
 // NewControllerHandler는 새로운 ControllerHandler를 생성합니다.
-func NewControllerHandler(logger *zap.Logger, session *discordgo.Session) *ControllerHandler {
+func NewControllerHandler(logger *zap.Logger, session *discordgo.Session, ctrl *controller.Controller) *ControllerHandler {
 	return &ControllerHandler{
-		logger:       logger.With(zap.String("handler", "controller")),
-		session:      session,
-		toolMessages: make(map[string]string),
+		logger:         logger.With(zap.String("handler", "controller")),
+		session:        session,
+		controller:     ctrl,
+		toolMessages:   make(map[string]string),
+		permMessages:   make(map[string]string),
+		statusMessages: make(map[string]string),
+		connected:      true,
+		lastSeq:        make(map[string]int64),
+		missed:         make(map[string]bool),
 	}
 }
 
+// RegisterHandlers는 Discord 연결 끊김과 재연결을 감지하는 핸들러를 등록합니다.
+func (h *ControllerHandler) RegisterHandlers() {
+	h.session.AddHandler(func(s *discordgo.Session, _ *discordgo.Disconnect) {
+		h.setDisconnected()
+	})
+	h.session.AddHandler(func(s *discordgo.Session, _ *discordgo.Resumed) {
+		h.replayMissed()
+	})
+	h.session.AddHandler(func(s *discordgo.Session, _ *discordgo.Ready) {
+		h.replayMissed()
+	})
+}
+
 // Start는 Controller 이벤트를 처리하는 goroutine을 시작합니다.
 func (h *ControllerHandler) Start(ctx context.Context, eventChan <-chan controller.ControllerEvent) {
 	h.logger.Info("Controller event handler started")
@@ -35,7 +67,7 @@ func (h *ControllerHandler) Start(ctx context.Context, eventChan <-chan controll
 	for {
 		select {
 		case event := <-eventChan:
-			h.handleControllerEvent(event)
+			h.deliver(event)
 
 		case <-ctx.Done():
 			h.logger.Info("Controller event handler shutting down")
@@ -44,6 +76,66 @@ func (h *ControllerHandler) Start(ctx context.Context, eventChan <-chan controll
 	}
 }
 
+// deliver는 이벤트를 Task별 순번 순서로 한 번씩만 처리합니다.
+// 연결이 끊긴 동안에는 처리하지 않고 Task를 기록해 두었다가 재연결 후 replayMissed에서 재생합니다.
+// 이벤트 로그에 기록되지 않은 이벤트(Seq 0)는 그대로 처리합니다.
+func (h *ControllerHandler) deliver(event controller.ControllerEvent) {
+	h.deliverMu.Lock()
+	defer h.deliverMu.Unlock()
+
+	if event.Seq > 0 {
+		if event.Seq <= h.lastSeq[event.TaskID] {
+			return // 재생으로 이미 처리한 이벤트
+		}
+		if !h.connected || h.missed[event.TaskID] {
+			h.missed[event.TaskID] = true
+			return
+		}
+		h.lastSeq[event.TaskID] = event.Seq
+	}
+	h.handleControllerEvent(event)
+}
+
+// setDisconnected는 Discord 연결이 끊겼음을 기록합니다.
+func (h *ControllerHandler) setDisconnected() {
+	h.deliverMu.Lock()
+	defer h.deliverMu.Unlock()
+	if h.connected {
+		h.logger.Warn("Discord connection lost, buffering controller events in event log")
+	}
+	h.connected = false
+}
+
+// replayMissed는 재연결 후 연결이 끊긴 동안 놓친 Task 이벤트를 이벤트 로그에서 순서대로 다시 처리합니다.
+func (h *ControllerHandler) replayMissed() {
+	h.deliverMu.Lock()
+	defer h.deliverMu.Unlock()
+	h.connected = true
+	if len(h.missed) == 0 || h.controller == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for taskID := range h.missed {
+		events, err := h.controller.ReplayEvents(ctx, taskID, h.lastSeq[taskID])
+		delete(h.missed, taskID)
+		if err != nil {
+			h.logger.Error("Failed to replay task events", zap.String("task_id", taskID), zap.Error(err))
+			continue
+		}
+		h.logger.Info("Replaying missed task events",
+			zap.String("task_id", taskID),
+			zap.Int64("after_seq", h.lastSeq[taskID]),
+			zap.Int("count", len(events)),
+		)
+		for _, event := range events {
+			h.lastSeq[taskID] = event.Seq
+			h.handleControllerEvent(event)
+		}
+	}
+}
+
 // handleControllerEvent는 ControllerEvent를 EventType에 따라 분기 처리합니다.
 func (h *ControllerHandler) handleControllerEvent(event controller.ControllerEvent) {
 	// 새로운 EventType 기반 처리
@@ -62,6 +154,16 @@ func (h *ControllerHandler) handleControllerEvent(event controller.ControllerEve
 		h.handleToolError(event)
 	case controller.EventTypeMessageComplete:
 		h.handleMessageComplete(event)
+	case controller.EventTypeTurnComplete:
+		h.handleTurnComplete(event)
+	case controller.EventTypePermissionRequest:
+		h.handlePermissionRequest(event)
+	case controller.EventTypePermissionResolved:
+		h.handlePermissionResolved(event)
+	case controller.EventTypeImagePull:
+		h.handleImagePull(event)
+	case controller.EventTypeStreamStatus:
+		h.handleStreamStatus(event)
 
 	case controller.EventTypeError:
 		h.handleError(event)
@@ -296,6 +398,15 @@ func (h *ControllerHandler) handleMessageComplete(event controller.ControllerEve
 	// h.sendMessageToDiscord(event)
 }
 
+// handleTurnComplete는 턴 완료를 처리합니다.
+// 어시스턴트 텍스트는 PartComplete에서 이미 전송되었으므로 로그만 남깁니다.
+func (h *ControllerHandler) handleTurnComplete(event controller.ControllerEvent) {
+	h.logger.Info("[TurnComplete]",
+		zap.String("task_id", event.TaskID),
+		zap.String("content", truncate(event.Content, 100)),
+	)
+}
+
 // handleError는 에러 이벤트를 처리합니다.
 func (h *ControllerHandler) handleError(event controller.ControllerEvent) {
 	h.logger.Error("[Error]",
@@ -425,6 +536,13 @@ func (h *ControllerHandler) sendResultToDiscord(result controller.ControllerEven
 				Value: result.Error.Error(),
 			})
 		}
+
+		if len(result.LogTail) > 0 {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:  "Runner 로그",
+				Value: formatLogTailField(result.LogTail),
+			})
+		}
 	} else if result.Status == "canceled" {
 		// 취소 시 노란색
 		embed = &discordgo.MessageEmbed{
@@ -478,8 +596,11 @@ func (h *ControllerHandler) sendResultToDiscord(result controller.ControllerEven
 		return
 	}
 
-	// Discord에 메시지 전송
-	_, err := h.session.ChannelMessageSendEmbed(result.TaskID, embed)
+	// Discord에 메시지 전송 (대화 기록 내보내기 버튼 포함)
+	_, err := h.session.ChannelMessageSendComplex(result.TaskID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.MessageComponent{exportTranscriptButton(result.TaskID)},
+	})
 	if err != nil {
 		h.logger.Error("Failed to send result to Discord",
 			zap.String("task_id", result.TaskID),
@@ -545,3 +666,21 @@ func formatToolMessage(toolName, status, output string, input map[string]any) st
 
 	return message
 }
+
+// formatLogTailField는 Runner 로그를 Discord Embed 필드 길이 제한(1024자)에 맞는 코드 블록으로 변환합니다.
+// 제한을 넘으면 앞쪽 줄부터 잘라내 최근 로그를 우선 보여줍니다.
+func formatLogTailField(lines []string) string {
+	const maxFieldLength = 1024
+	const fence = "```"
+	budget := maxFieldLength - len(fence)*2 - 2
+
+	// 로그 안의 코드 블록 구분자가 Embed 서식을 깨지 않도록 치환
+	body := strings.ReplaceAll(strings.Join(lines, "\n"), fence, "'''")
+	if len(body) > budget {
+		body = body[len(body)-budget:]
+		if idx := strings.Index(body, "\n"); idx >= 0 {
+			body = body[idx+1:]
+		}
+	}
+	return fence + "\n" + body + "\n" + fence
+}
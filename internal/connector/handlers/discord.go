@@ -8,21 +8,27 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
 	"go.uber.org/zap"
 )
 
 // Discord 명령어 및 UI 요소에 사용될 상수들을 정의합니다.
 const (
-	cmdAgent          = "agent"
-	subCmdCreate      = "create"
-	subCmdList        = "list"
-	subCmdView        = "view"
-	subCmdDelete      = "delete"
-	subCmdEdit        = "edit"
-	subCmdCall        = "call"
-	prefixModalCreate = "modal_agent_create"
-	prefixModalEdit   = "modal_agent_edit_"
-	prefixButtonEdit  = "edit_agent_"
+	cmdAgent           = "agent"
+	cmdSearch          = "search"
+	cmdTask            = "task"
+	subCmdCreate       = "create"
+	subCmdList         = "list"
+	subCmdView         = "view"
+	subCmdDelete       = "delete"
+	subCmdEdit         = "edit"
+	subCmdCall         = "call"
+	subCmdVisibility   = "visibility"
+	prefixModalCreate  = "modal_agent_create"
+	prefixModalEdit    = "modal_agent_edit_"
+	prefixButtonEdit   = "edit_agent_"
+	prefixButtonPerm   = "perm_"
+	prefixButtonExport = "export_task_"
 )
 
 // DiscordHandler는 Discord 이벤트 및 상호작용을 처리합니다.
@@ -33,6 +39,7 @@ type DiscordHandler struct {
 	connectorEventChan chan controller.ConnectorEvent
 	threadsMutex       sync.RWMutex
 	activeThreads      map[string]string
+	defaultGuildID     string // 기본 테넌트에 대응하는 Discord 서버 ID
 }
 
 // NewDiscordHandler는 새로운 DiscordHandler를 생성합니다.
@@ -41,6 +48,7 @@ func NewDiscordHandler(
 	session *discordgo.Session,
 	ctrl *controller.Controller,
 	eventChan chan controller.ConnectorEvent,
+	defaultGuildID string,
 ) *DiscordHandler {
 	return &DiscordHandler{
 		logger:             logger.With(zap.String("handler", "discord")),
@@ -48,6 +56,7 @@ func NewDiscordHandler(
 		controller:         ctrl,
 		connectorEventChan: eventChan,
 		activeThreads:      make(map[string]string),
+		defaultGuildID:     defaultGuildID,
 	}
 }
 
@@ -74,8 +83,19 @@ func (h *DiscordHandler) readyHandler(_ *discordgo.Session, r *discordgo.Ready)
 				{Type: discordgo.ApplicationCommandOptionSubCommand, Name: subCmdDelete, Description: "특정 에이전트를 삭제합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "삭제할 에이전트의 이름", Required: true, Autocomplete: true}}},
 				{Type: discordgo.ApplicationCommandOptionSubCommand, Name: subCmdEdit, Description: "특정 에이전트의 정보를 수정합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "수정할 에이전트의 이름", Required: true, Autocomplete: true}}},
 				{Type: discordgo.ApplicationCommandOptionSubCommand, Name: subCmdCall, Description: "에이전트와의 대화 스레드를 시작합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "호출할 에이전트의 이름", Required: true, Autocomplete: true}}},
+				{Type: discordgo.ApplicationCommandOptionSubCommand, Name: subCmdVisibility, Description: "에이전트의 공개 범위를 변경합니다.", Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "공개 범위를 변경할 에이전트의 이름", Required: true, Autocomplete: true},
+					{Type: discordgo.ApplicationCommandOptionString, Name: "scope", Description: "공개 범위", Required: true, Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "나만 사용 (private)", Value: storage.AgentVisibilityPrivate},
+						{Name: "이 서버 (guild)", Value: storage.AgentVisibilityGuild},
+						{Name: "모든 서버 (public)", Value: storage.AgentVisibilityPublic},
+					}},
+				}},
 			},
 		},
+		taskCommand(),
+		searchCommand(),
+		cnapCommand(),
 	}
 
 	_, err := h.session.ApplicationCommandBulkOverwrite(h.session.State.User.ID, "", commands)
@@ -103,16 +123,26 @@ func (h *DiscordHandler) interactionRouter(_ *discordgo.Session, i *discordgo.In
 // handleButton은 버튼 클릭 상호작용을 처리합니다.
 func (h *DiscordHandler) handleButton(i *discordgo.InteractionCreate) {
 	customID := i.MessageComponentData().CustomID
+	ctx, ok := h.authorize(i, buttonRole(customID))
+	if !ok {
+		return
+	}
 	if strings.HasPrefix(customID, prefixButtonEdit) {
 		agentName := strings.TrimPrefix(customID, prefixButtonEdit)
-		ctx := context.Background()
 		agent, err := h.controller.GetAgentInfo(ctx, agentName)
 		if err != nil {
 			h.logger.Error("Failed to get agent info from controller for edit button", zap.Error(err), zap.String("agent_id", agentName))
 			h.respondEphemeral(i, "오류: 에이전트의 정보를 가져오는 데 실패했어요.")
 			return
 		}
+		if h.rejectManagedAgent(i, agent) || h.rejectUnownedAgent(ctx, i, agent) {
+			return
+		}
 		h.showCreateOrEditModal(i, agentName, agent)
+	} else if strings.HasPrefix(customID, prefixButtonPerm) {
+		h.handlePermissionButton(i, customID)
+	} else if strings.HasPrefix(customID, prefixButtonExport) {
+		h.exportTranscript(ctx, i, strings.TrimPrefix(customID, prefixButtonExport))
 	}
 }
 
@@ -120,8 +150,11 @@ func (h *DiscordHandler) handleButton(i *discordgo.InteractionCreate) {
 func (h *DiscordHandler) handleAutocomplete(i *discordgo.InteractionCreate) {
 	options := i.ApplicationCommandData().Options[0].Options[0]
 	if options.Focused {
-		ctx := context.Background()
-		agents, err := h.controller.ListAgentsWithInfo(ctx)
+		ctx, err := h.roleContext(i)
+		var agents []*controller.AgentInfo
+		if err == nil {
+			agents, err = h.controller.ListAgentsWithInfo(ctx)
+		}
 		if err != nil {
 			h.logger.Error("Failed to list agents from controller for autocomplete", zap.Error(err))
 			// Can't respond with an ephemeral message here, so we just log and return empty choices
@@ -132,7 +165,8 @@ func (h *DiscordHandler) handleAutocomplete(i *discordgo.InteractionCreate) {
 		var choices []*discordgo.ApplicationCommandOptionChoice
 		for _, agent := range agents {
 			if strings.HasPrefix(strings.ToLower(agent.Name), strings.ToLower(options.StringValue())) {
-				choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: agent.Name, Value: agent.Name})
+				// 다른 테넌트의 public 에이전트와 이름이 겹칠 수 있어 값은 식별자를 사용
+				choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: agent.Name, Value: agent.ID})
 			}
 		}
 
@@ -148,8 +182,7 @@ func (h *DiscordHandler) handleAutocomplete(i *discordgo.InteractionCreate) {
 }
 
 // showAgentList는 현재 등록된 모든 에이전트의 목록을 Discord에 표시합니다.
-func (h *DiscordHandler) showAgentList(i *discordgo.InteractionCreate) {
-	ctx := context.Background()
+func (h *DiscordHandler) showAgentList(ctx context.Context, i *discordgo.InteractionCreate) {
 	agents, err := h.controller.ListAgentsWithInfo(ctx)
 	if err != nil {
 		h.logger.Error("Failed to list agents from controller", zap.Error(err))
@@ -181,8 +214,7 @@ func (h *DiscordHandler) showAgentList(i *discordgo.InteractionCreate) {
 }
 
 // showAgentDetails는 특정 에이전트의 상세 정보를 Discord에 표시합니다.
-func (h *DiscordHandler) showAgentDetails(i *discordgo.InteractionCreate, name string) {
-	ctx := context.Background()
+func (h *DiscordHandler) showAgentDetails(ctx context.Context, i *discordgo.InteractionCreate, name string) {
 	agent, err := h.controller.GetAgentInfo(ctx, name)
 	if err != nil {
 		h.logger.Error("Failed to get agent info from controller", zap.Error(err), zap.String("agent_id", name))
@@ -199,6 +231,17 @@ func (h *DiscordHandler) showAgentDetails(i *discordgo.InteractionCreate, name s
 			{Name: "실행한 작업 목록", Value: "(아직 구현되지 않은 기능이에요)"},
 		},
 	}
+	owner := "서버 공용"
+	if agent.Owner != "" {
+		owner = actorMention(agent.Owner)
+	}
+	embed.Fields = append(embed.Fields,
+		&discordgo.MessageEmbedField{Name: "소유자", Value: owner, Inline: true},
+		&discordgo.MessageEmbedField{Name: "공개 범위", Value: agent.Visibility, Inline: true},
+	)
+	if agent.ConfigSource != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "관리", Value: fmt.Sprintf("설정 파일 `%s` (Discord에서 수정 불가)", agent.ConfigSource)})
+	}
 	err = h.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}}})
 	if err != nil {
 		h.logger.Error("Failed to show agent details", zap.Error(err), zap.String("agent", name))
@@ -206,9 +249,17 @@ func (h *DiscordHandler) showAgentDetails(i *discordgo.InteractionCreate, name s
 }
 
 // deleteAgent는 지정된 이름의 에이전트를 삭제합니다.
-func (h *DiscordHandler) deleteAgent(i *discordgo.InteractionCreate, name string) {
-	ctx := context.Background()
-	if err := h.controller.DeleteAgent(ctx, name); err != nil {
+func (h *DiscordHandler) deleteAgent(ctx context.Context, i *discordgo.InteractionCreate, name string) {
+	agent, err := h.controller.GetAgentInfo(ctx, name)
+	if err != nil {
+		h.logger.Error("Failed to get agent info from controller", zap.Error(err), zap.String("agent_id", name))
+		h.respondEphemeral(i, fmt.Sprintf("오류: 에이전트 '**%s**'의 정보를 가져오는 데 실패했어요. 에러: %v", name, err))
+		return
+	}
+	if h.rejectManagedAgent(i, agent) || h.rejectUnownedAgent(ctx, i, agent) {
+		return
+	}
+	if err := h.controller.DeleteAgent(ctx, agent.ID); err != nil {
 		h.logger.Error("Failed to delete agent from controller", zap.Error(err), zap.String("agent_id", name))
 		h.respondEphemeral(i, fmt.Sprintf("오류: 에이전트 '**%s**'을(를) 삭제하는 데 실패했어요. 에러: %v", name, err))
 		return
@@ -217,23 +268,44 @@ func (h *DiscordHandler) deleteAgent(i *discordgo.InteractionCreate, name string
 	// activeThreads 맵에서 삭제된 에이전트와 연결된 스레드 정리
 	h.threadsMutex.Lock()
 	defer h.threadsMutex.Unlock()
-	for threadID, agentName := range h.activeThreads {
-		if agentName == name {
+	for threadID, agentID := range h.activeThreads {
+		if agentID == agent.ID {
 			delete(h.activeThreads, threadID)
 		}
 	}
-	h.respondEphemeral(i, fmt.Sprintf("에이전트 '**%s**'이(가) 성공적으로 삭제되었어요.", name))
+	h.respondEphemeral(i, fmt.Sprintf("에이전트 '**%s**'이(가) 성공적으로 삭제되었어요.", agent.Name))
+}
+
+// setAgentVisibility는 에이전트의 공개 범위를 변경합니다.
+func (h *DiscordHandler) setAgentVisibility(ctx context.Context, i *discordgo.InteractionCreate, name, visibility string) {
+	agent, err := h.controller.GetAgentInfo(ctx, name)
+	if err != nil {
+		h.logger.Error("Failed to get agent info from controller", zap.Error(err), zap.String("agent_id", name))
+		h.respondEphemeral(i, fmt.Sprintf("오류: 에이전트 '**%s**'의 정보를 가져오는 데 실패했어요. 에러: %v", name, err))
+		return
+	}
+	if h.rejectUnownedAgent(ctx, i, agent) {
+		return
+	}
+	if err := h.controller.SetAgentVisibility(ctx, agent.ID, visibility); err != nil {
+		h.logger.Error("Failed to set agent visibility", zap.Error(err), zap.String("agent_id", name))
+		h.respondEphemeral(i, fmt.Sprintf("오류: 에이전트 '**%s**'의 공개 범위를 변경하는 데 실패했어요. 에러: %v", name, err))
+		return
+	}
+	h.respondEphemeral(i, fmt.Sprintf("에이전트 '**%s**'의 공개 범위를 `%s`(으)로 변경했어요.", agent.Name, visibility))
 }
 
 // showEditUI는 특정 에이전트의 현재 정보를 임베드 메시지로 표시하고, 수정 모달을 열기 위한 버튼을 제공합니다.
-func (h *DiscordHandler) showEditUI(i *discordgo.InteractionCreate, name string) {
-	ctx := context.Background()
+func (h *DiscordHandler) showEditUI(ctx context.Context, i *discordgo.InteractionCreate, name string) {
 	agent, err := h.controller.GetAgentInfo(ctx, name)
 	if err != nil {
 		h.logger.Error("Failed to get agent info from controller", zap.Error(err), zap.String("agent_id", name))
 		h.respondEphemeral(i, fmt.Sprintf("오류: 에이전트 '**%s**'의 정보를 가져오는 데 실패했어요. 에러: %v", name, err))
 		return
 	}
+	if h.rejectManagedAgent(i, agent) || h.rejectUnownedAgent(ctx, i, agent) {
+		return
+	}
 
 	embed := &discordgo.MessageEmbed{
 		Title: "에이전트 수정: " + agent.Name, Description: "아래는 현재 정보예요. 수정하려면 버튼을 눌러주세요.", Color: 0xffaa00,
@@ -246,7 +318,7 @@ func (h *DiscordHandler) showEditUI(i *discordgo.InteractionCreate, name string)
 	err = h.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{
 		Embeds: []*discordgo.MessageEmbed{embed},
 		Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
-			discordgo.Button{Label: "정보 수정하기", Style: discordgo.PrimaryButton, CustomID: prefixButtonEdit + name},
+			discordgo.Button{Label: "정보 수정하기", Style: discordgo.PrimaryButton, CustomID: prefixButtonEdit + agent.ID},
 		}}},
 	}})
 	if err != nil {
@@ -261,14 +333,14 @@ func (h *DiscordHandler) messageCreateHandler(_ *discordgo.Session, m *discordgo
 	}
 
 	h.threadsMutex.RLock()
-	agentName, ok := h.activeThreads[m.ChannelID]
+	agentID, ok := h.activeThreads[m.ChannelID]
 	h.threadsMutex.RUnlock()
 
+	ctx := h.messageContext(m.Message)
 	if ok {
-		ctx := context.Background()
-		agent, err := h.controller.GetAgentInfo(ctx, agentName)
+		agent, err := h.controller.GetAgentInfo(ctx, agentID)
 		if err != nil {
-			h.logger.Error("Failed to get agent info from controller for message handler", zap.Error(err), zap.String("agent_id", agentName))
+			h.logger.Error("Failed to get agent info from controller for message handler", zap.Error(err), zap.String("agent_id", agentID))
 			if _, sendErr := h.session.ChannelMessageSend(m.ChannelID, "오류: 이 스레드에 연결된 에이전트를 찾을 수 없습니다."); sendErr != nil {
 				h.logger.Error("Failed to send error message to channel", zap.Error(sendErr), zap.String("channel_id", m.ChannelID))
 			}
@@ -276,12 +348,11 @@ func (h *DiscordHandler) messageCreateHandler(_ *discordgo.Session, m *discordgo
 		}
 		h.callAgentInThread(m.Message, agent)
 	} else {
-		task, err := h.controller.GetTask(context.Background(), m.ChannelID)
+		task, err := h.controller.GetTask(ctx, m.ChannelID)
 		if err == nil && task.AgentID != "" {
 			h.threadsMutex.Lock()
 			h.activeThreads[m.ChannelID] = task.AgentID
 			h.threadsMutex.Unlock()
-			ctx := context.Background()
 			agent, err := h.controller.GetAgentInfo(ctx, task.AgentID)
 			if err != nil {
 				h.logger.Error("Failed to get agent info from controller for existing task", zap.Error(err), zap.String("agent_id", task.AgentID))
@@ -292,9 +363,13 @@ func (h *DiscordHandler) messageCreateHandler(_ *discordgo.Session, m *discordgo
 	}
 }
 
+// messageEventID는 메시지로 발생한 Connector 이벤트의 ID입니다. Discord가 같은 메시지를 다시 전달해도 한 번만 처리됩니다.
+func messageEventID(m *discordgo.Message) string {
+	return "discord:message:" + m.ID
+}
+
 // startAgentThread는 지정된 에이전트와의 새로운 대화 스레드를 시작합니다.
-func (h *DiscordHandler) startAgentThread(i *discordgo.InteractionCreate, agentName string) {
-	ctx := context.Background()
+func (h *DiscordHandler) startAgentThread(ctx context.Context, i *discordgo.InteractionCreate, agentName string) {
 	agent, err := h.controller.GetAgentInfo(ctx, agentName)
 	if err != nil {
 		h.logger.Error("Failed to get agent info from controller", zap.Error(err), zap.String("agent_id", agentName))
@@ -302,7 +377,7 @@ func (h *DiscordHandler) startAgentThread(i *discordgo.InteractionCreate, agentN
 		return
 	}
 
-	h.respondEphemeral(i, fmt.Sprintf("'**%s**'와의 대화 스레드를 생성 중...", agentName))
+	h.respondEphemeral(i, fmt.Sprintf("'**%s**'와의 대화 스레드를 생성 중...", agent.Name))
 
 	thread, err := h.session.ThreadStart(i.ChannelID, fmt.Sprintf("[%s] 대화방", agent.Name), discordgo.ChannelTypeGuildPublicThread, 60)
 	if err != nil {
@@ -311,7 +386,7 @@ func (h *DiscordHandler) startAgentThread(i *discordgo.InteractionCreate, agentN
 	}
 
 	h.threadsMutex.Lock()
-	h.activeThreads[thread.ID] = agent.Name
+	h.activeThreads[thread.ID] = agent.ID
 	h.threadsMutex.Unlock()
 
 	embed := &discordgo.MessageEmbed{
@@ -331,7 +406,10 @@ func (h *DiscordHandler) startAgentThread(i *discordgo.InteractionCreate, agentN
 // callAgentInThread는 활성화된 에이전트 스레드 내에서 메시지를 처리합니다.
 // Thread ID를 Task ID로 사용하여 하나의 Thread 내 모든 대화가 동일한 Task에서 처리됩니다.
 func (h *DiscordHandler) callAgentInThread(m *discordgo.Message, agent *controller.AgentInfo) {
-	ctx := context.Background()
+	ctx, ok := h.authorizeMessage(m, storage.RoleUser)
+	if !ok {
+		return
+	}
 
 	// Thread ID를 Task ID로 사용 (Thread-Task 1:1 매핑)
 	taskID := m.ChannelID
@@ -349,9 +427,12 @@ func (h *DiscordHandler) callAgentInThread(m *discordgo.Message, agent *controll
 		// Task 실행 이벤트 전송 (새 Task, 비동기, 논블로킹)
 		h.connectorEventChan <- controller.ConnectorEvent{
 			Type:      "execute",
+			ID:        messageEventID(m),
 			TaskID:    taskID,
-			AgentName: agent.Name,
+			AgentName: agent.ID,
 			Prompt:    m.Content,
+			Actor:     controller.ConnectorActor(controller.ActorConnectorDiscord, m.Author.ID),
+			Tenant:    h.tenant(m.GuildID, m.Author.ID),
 		}
 	} else {
 		// "처리 중" 메시지 전송
@@ -368,9 +449,12 @@ func (h *DiscordHandler) callAgentInThread(m *discordgo.Message, agent *controll
 		// continue 이벤트 전송 (기존 Task 실행 계속)
 		h.connectorEventChan <- controller.ConnectorEvent{
 			Type:      "continue",
+			ID:        messageEventID(m),
 			TaskID:    taskID,
-			AgentName: agent.Name,
+			AgentName: agent.ID,
 			Prompt:    m.Content,
+			Actor:     controller.ConnectorActor(controller.ActorConnectorDiscord, m.Author.ID),
+			Tenant:    h.tenant(m.GuildID, m.Author.ID),
 		}
 
 		h.logger.Info("Task continue event sent",
@@ -397,6 +481,28 @@ func (h *DiscordHandler) callAgentInThread(m *discordgo.Message, agent *controll
 	)
 }
 
+// rejectManagedAgent는 설정 파일로 관리되는 에이전트이면 수정할 수 없다고 응답하고 true를 반환합니다.
+func (h *DiscordHandler) rejectManagedAgent(i *discordgo.InteractionCreate, agent *controller.AgentInfo) bool {
+	if agent.ConfigSource == "" {
+		return false
+	}
+	h.respondEphemeral(i, fmt.Sprintf("에이전트 '**%s**'은(는) 설정 파일 `%s`로 관리되고 있어 Discord에서 수정하거나 삭제할 수 없어요.", agent.Name, agent.ConfigSource))
+	return true
+}
+
+// rejectUnownedAgent는 사용자가 수정할 수 없는 에이전트(다른 사용자 소유 또는 다른 서버 소속)이면 안내하고 true를 반환합니다.
+func (h *DiscordHandler) rejectUnownedAgent(ctx context.Context, i *discordgo.InteractionCreate, agent *controller.AgentInfo) bool {
+	if controller.CanModifyAgent(ctx, agent) {
+		return false
+	}
+	if agent.Owner != "" && agent.Tenant == controller.TenantFromContext(ctx) {
+		h.respondEphemeral(i, fmt.Sprintf("에이전트 '**%s**'은(는) %s님의 에이전트라 소유자만 수정하거나 삭제할 수 있어요.", agent.Name, actorMention(agent.Owner)))
+	} else {
+		h.respondEphemeral(i, fmt.Sprintf("에이전트 '**%s**'은(는) 다른 서버의 에이전트라 수정하거나 삭제할 수 없어요.", agent.Name))
+	}
+	return true
+}
+
 // respondEphemeral은 사용자에게만 보이는 임시 메시지를 전송합니다.
 func (h *DiscordHandler) respondEphemeral(i *discordgo.InteractionCreate, content string) {
 	err := h.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Content: content, Flags: discordgo.MessageFlagsEphemeral}})
@@ -404,3 +510,54 @@ func (h *DiscordHandler) respondEphemeral(i *discordgo.InteractionCreate, conten
 		h.logger.Error("Failed to send ephemeral message", zap.Error(err))
 	}
 }
+
+// interactionActor는 상호작용을 보낸 사용자의 식별자(discord:{사용자 ID})를 반환합니다.
+// 서버에서는 Member.User, DM에서는 User에 사용자 정보가 있습니다.
+func interactionActor(i *discordgo.InteractionCreate) string {
+	switch {
+	case i.Member != nil && i.Member.User != nil:
+		return controller.ConnectorActor(controller.ActorConnectorDiscord, i.Member.User.ID)
+	case i.User != nil:
+		return controller.ConnectorActor(controller.ActorConnectorDiscord, i.User.ID)
+	default:
+		return ""
+	}
+}
+
+// tenant는 Discord 서버 ID로 테넌트를 결정합니다.
+// 기본 서버(CNAP_DISCORD_DEFAULT_GUILD)는 기본 테넌트를, DM은 사용자별 테넌트(dm:{사용자 ID})를 사용합니다.
+func (h *DiscordHandler) tenant(guildID, userID string) string {
+	switch {
+	case guildID == "":
+		return "dm:" + userID
+	case guildID == h.defaultGuildID:
+		return storage.DefaultTenantID
+	default:
+		return "guild:" + guildID
+	}
+}
+
+// interactionTenant는 상호작용이 발생한 서버의 테넌트를 반환합니다.
+func (h *DiscordHandler) interactionTenant(i *discordgo.InteractionCreate) string {
+	_, userID, _ := strings.Cut(interactionActor(i), ":")
+	return h.tenant(i.GuildID, userID)
+}
+
+// interactionContext는 상호작용을 보낸 사용자와 서버의 테넌트를 담은 context를 반환합니다.
+func (h *DiscordHandler) interactionContext(i *discordgo.InteractionCreate) context.Context {
+	return controller.WithTenant(controller.WithActor(context.Background(), interactionActor(i)), h.interactionTenant(i))
+}
+
+// messageContext는 메시지 작성자와 서버의 테넌트를 담은 context를 반환합니다.
+func (h *DiscordHandler) messageContext(m *discordgo.Message) context.Context {
+	actor := controller.ConnectorActor(controller.ActorConnectorDiscord, m.Author.ID)
+	return controller.WithTenant(controller.WithActor(context.Background(), actor), h.tenant(m.GuildID, m.Author.ID))
+}
+
+// actorMention은 Discord 사용자 식별자를 멘션으로, 그 외 식별자는 그대로 표시합니다.
+func actorMention(actor string) string {
+	if id, ok := strings.CutPrefix(actor, controller.ActorConnectorDiscord+":"); ok {
+		return "<@" + id + ">"
+	}
+	return "`" + actor + "`"
+}
@@ -1,18 +1,21 @@
 package handlers
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
 	"go.uber.org/zap"
 )
 
 // handleModal은 모달 제출 상호작용을 처리합니다.
 func (h *DiscordHandler) handleModal(i *discordgo.InteractionCreate) {
-	ctx := context.Background()
+	ctx, ok := h.authorize(i, storage.RoleAgentAuthor)
+	if !ok {
+		return
+	}
 	customID := i.ModalSubmitData().CustomID
 	data := i.ModalSubmitData().Components
 	name := data[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
@@ -31,6 +34,10 @@ func (h *DiscordHandler) handleModal(i *discordgo.InteractionCreate) {
 		h.respondEphemeral(i, fmt.Sprintf("에이전트 '**%s**'이(가) 성공적으로 생성되었어요!", name))
 	case strings.HasPrefix(customID, prefixModalEdit):
 		originalName := strings.TrimPrefix(customID, prefixModalEdit)
+		// 모달을 연 뒤 설정 파일 관리 대상이 되었을 수 있음
+		if agent, err := h.controller.GetAgentInfo(ctx, originalName); err == nil && (h.rejectManagedAgent(i, agent) || h.rejectUnownedAgent(ctx, i, agent)) {
+			return
+		}
 		// Discord 봇에서는 기본 provider로 "opencode" 사용
 		if err := h.controller.UpdateAgent(ctx, originalName, desc, "opencode", model, prompt); err != nil {
 			h.logger.Error("Failed to update agent via controller", zap.Error(err), zap.String("original_agent_id", originalName))
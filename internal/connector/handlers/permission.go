@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// permissionButtonID는 권한 응답 버튼의 CustomID를 생성합니다. (예: perm_once_per_123)
+func permissionButtonID(response, permissionID string) string {
+	return prefixButtonPerm + response + "_" + permissionID
+}
+
+// parsePermissionButtonID는 권한 응답 버튼 CustomID에서 응답과 권한 요청 ID를 추출합니다.
+func parsePermissionButtonID(customID string) (response, permissionID string, ok bool) {
+	rest := strings.TrimPrefix(customID, prefixButtonPerm)
+	response, permissionID, ok = strings.Cut(rest, "_")
+	if !ok || permissionID == "" {
+		return "", "", false
+	}
+	return response, permissionID, true
+}
+
+// permissionResponseLabel은 권한 응답을 사용자에게 보여줄 문구로 변환합니다.
+func permissionResponseLabel(response string) string {
+	switch response {
+	case storage.PermissionResponseOnce:
+		return "✅ 승인"
+	case storage.PermissionResponseAlways:
+		return "♾️ 항상 허용"
+	case storage.PermissionResponseReject:
+		return "⛔ 거부"
+	default:
+		return response
+	}
+}
+
+// permissionEmbed는 권한 요청 정보를 Discord Embed로 변환합니다.
+func permissionEmbed(info *controller.PermissionEventInfo, resolved bool) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "🔐 도구 실행 권한 요청",
+		Description: info.Title,
+		Color:       0xffa500, // 주황색
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "권한", Value: "`" + info.Type + "`", Inline: true},
+		},
+	}
+
+	if len(info.Patterns) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "대상",
+			Value: truncate("`"+strings.Join(info.Patterns, "`, `")+"`", 1000),
+		})
+	}
+
+	if !resolved {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "응답 기한",
+			Value:  fmt.Sprintf("<t:%d:R>", info.ExpiresAt.Unix()),
+			Inline: true,
+		})
+		return embed
+	}
+
+	result := permissionResponseLabel(info.Response)
+	if info.TimedOut {
+		result += " (시간 초과로 자동 적용)"
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "응답", Value: result, Inline: true})
+	if info.Response == storage.PermissionResponseReject {
+		embed.Color = 0xff0000 // 빨간색
+	} else {
+		embed.Color = 0x00ff00 // 초록색
+	}
+	return embed
+}
+
+// permissionButtons는 권한 요청에 응답할 버튼 목록을 생성합니다.
+func permissionButtons(permissionID string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "승인", Style: discordgo.SuccessButton, CustomID: permissionButtonID(storage.PermissionResponseOnce, permissionID)},
+		discordgo.Button{Label: "거부", Style: discordgo.DangerButton, CustomID: permissionButtonID(storage.PermissionResponseReject, permissionID)},
+		discordgo.Button{Label: "항상 허용", Style: discordgo.SecondaryButton, CustomID: permissionButtonID(storage.PermissionResponseAlways, permissionID)},
+	}}}
+}
+
+// handlePermissionRequest는 도구 권한 요청을 버튼이 포함된 메시지로 Thread에 전송합니다.
+func (h *ControllerHandler) handlePermissionRequest(event controller.ControllerEvent) {
+	if event.Permission == nil {
+		return
+	}
+	h.logger.Info("[PermissionRequest]",
+		zap.String("task_id", event.TaskID),
+		zap.String("permission_id", event.Permission.ID),
+		zap.String("type", event.Permission.Type),
+	)
+
+	msg, err := h.session.ChannelMessageSendComplex(event.TaskID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{permissionEmbed(event.Permission, false)},
+		Components: permissionButtons(event.Permission.ID),
+	})
+	if err != nil {
+		h.logger.Error("Failed to send permission request message",
+			zap.String("task_id", event.TaskID),
+			zap.String("permission_id", event.Permission.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	h.permMessagesMutex.Lock()
+	h.permMessages[event.TaskID+":"+event.Permission.ID] = msg.ID
+	h.permMessagesMutex.Unlock()
+}
+
+// handlePermissionResolved는 권한 요청 메시지의 버튼을 제거하고 적용된 응답을 표시합니다.
+func (h *ControllerHandler) handlePermissionResolved(event controller.ControllerEvent) {
+	if event.Permission == nil {
+		return
+	}
+	h.logger.Info("[PermissionResolved]",
+		zap.String("task_id", event.TaskID),
+		zap.String("permission_id", event.Permission.ID),
+		zap.String("response", event.Permission.Response),
+		zap.Bool("timed_out", event.Permission.TimedOut),
+	)
+
+	messageKey := event.TaskID + ":" + event.Permission.ID
+	h.permMessagesMutex.Lock()
+	messageID, exists := h.permMessages[messageKey]
+	delete(h.permMessages, messageKey)
+	h.permMessagesMutex.Unlock()
+
+	if !exists {
+		h.logger.Warn("Permission message not found for resolved update",
+			zap.String("task_id", event.TaskID),
+			zap.String("permission_id", event.Permission.ID),
+		)
+		return
+	}
+
+	embeds := []*discordgo.MessageEmbed{permissionEmbed(event.Permission, true)}
+	components := []discordgo.MessageComponent{}
+	_, err := h.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    event.TaskID,
+		ID:         messageID,
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		h.logger.Error("Failed to update permission message",
+			zap.String("task_id", event.TaskID),
+			zap.String("message_id", messageID),
+			zap.Error(err),
+		)
+	}
+}
+
+// handlePermissionButton은 권한 응답 버튼 클릭을 Controller에 전달합니다.
+// 메시지 갱신은 Controller가 응답을 적용한 뒤 보내는 permission_resolved 이벤트에서 처리합니다.
+func (h *DiscordHandler) handlePermissionButton(i *discordgo.InteractionCreate, customID string) {
+	response, permissionID, ok := parsePermissionButtonID(customID)
+	if !ok {
+		h.respondEphemeral(i, "오류: 잘못된 권한 응답이에요.")
+		return
+	}
+
+	// Discord는 3초 안에 인터랙션 응답을 받아야 하므로 Controller에 전달하기 전에 먼저 응답
+	err := h.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
+	if err != nil {
+		h.logger.Error("Failed to acknowledge permission button", zap.Error(err))
+	}
+
+	// 권한 요청에는 한 번만 응답할 수 있으므로 버튼을 여러 번 눌러도 하나의 이벤트로 처리
+	h.connectorEventChan <- controller.ConnectorEvent{
+		Type:         "permission",
+		ID:           "discord:permission:" + i.ChannelID + ":" + permissionID,
+		TaskID:       i.ChannelID,
+		PermissionID: permissionID,
+		Response:     response,
+		Actor:        interactionActor(i),
+		Tenant:       h.interactionTenant(i),
+	}
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	cmdCNAP                = "cnap"
+	subCmdGroupPermissions = "permissions"
+	subCmdSet              = "set"
+	subCmdRemove           = "remove"
+)
+
+// agentCommandRoles는 '/agent' 하위 명령어별로 필요한 최소 역할입니다.
+// 수정, 삭제, 공개 범위 변경은 이 역할에 더해 에이전트 소유자 또는 admin만 할 수 있습니다.
+var agentCommandRoles = map[string]string{
+	subCmdList:       storage.RoleViewer,
+	subCmdView:       storage.RoleViewer,
+	subCmdCall:       storage.RoleUser,
+	subCmdCreate:     storage.RoleAgentAuthor,
+	subCmdEdit:       storage.RoleAgentAuthor,
+	subCmdDelete:     storage.RoleAgentAuthor,
+	subCmdVisibility: storage.RoleAgentAuthor,
+}
+
+// commandRole은 슬래시 명령어에 필요한 최소 역할을 반환합니다. 알 수 없는 명령어는 admin만 사용할 수 있습니다.
+func commandRole(data discordgo.ApplicationCommandInteractionData) string {
+	switch data.Name {
+	case cmdAgent:
+		if role, ok := agentCommandRoles[data.Options[0].Name]; ok {
+			return role
+		}
+	case cmdTask, cmdSearch:
+		return storage.RoleViewer
+	case cmdCNAP:
+		// 역할 매핑 조회는 자신의 역할을 확인할 수 있도록 모두에게 허용
+		if group := data.Options[0]; group.Name == subCmdGroupPermissions && group.Options[0].Name == subCmdList {
+			return storage.RoleViewer
+		}
+	}
+	return storage.RoleAdmin
+}
+
+// buttonRole은 버튼에 필요한 최소 역할을 반환합니다.
+func buttonRole(customID string) string {
+	switch {
+	case strings.HasPrefix(customID, prefixButtonEdit):
+		return storage.RoleAgentAuthor
+	case strings.HasPrefix(customID, prefixButtonPerm):
+		return storage.RoleUser
+	case strings.HasPrefix(customID, prefixButtonExport):
+		return storage.RoleViewer
+	default:
+		return storage.RoleAdmin
+	}
+}
+
+// cnapCommand는 '/cnap' 슬래시 명령어 정의입니다.
+func cnapCommand() *discordgo.ApplicationCommand {
+	roleOption := &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionRole, Name: "role", Description: "Discord 역할 (@everyone이면 모든 멤버의 기본 역할)", Required: true}
+	var levelChoices []*discordgo.ApplicationCommandOptionChoice
+	for _, role := range []string{storage.RoleAdmin, storage.RoleAgentAuthor, storage.RoleUser, storage.RoleViewer} {
+		levelChoices = append(levelChoices, &discordgo.ApplicationCommandOptionChoice{Name: role, Value: role})
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:        cmdCNAP,
+		Description: "CNAP 설정 명령어",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Name:        subCmdGroupPermissions,
+				Description: "Discord 역할별 CNAP 권한을 설정합니다.",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionSubCommand, Name: subCmdList, Description: "역할 매핑과 내 역할을 봅니다."},
+					{Type: discordgo.ApplicationCommandOptionSubCommand, Name: subCmdSet, Description: "Discord 역할에 CNAP 역할을 부여합니다.", Options: []*discordgo.ApplicationCommandOption{
+						roleOption,
+						{Type: discordgo.ApplicationCommandOptionString, Name: "level", Description: "CNAP 역할", Required: true, Choices: levelChoices},
+					}},
+					{Type: discordgo.ApplicationCommandOptionSubCommand, Name: subCmdRemove, Description: "Discord 역할의 CNAP 역할 부여를 취소합니다.", Options: []*discordgo.ApplicationCommandOption{roleOption}},
+				},
+			},
+		},
+	}
+}
+
+// handleCNAPCommand는 '/cnap' 슬래시 명령어를 처리합니다.
+func (h *DiscordHandler) handleCNAPCommand(ctx context.Context, i *discordgo.InteractionCreate) {
+	group := i.ApplicationCommandData().Options[0]
+	if group.Name != subCmdGroupPermissions {
+		return
+	}
+	if i.GuildID == "" {
+		h.respondEphemeral(i, "역할 매핑은 서버에서만 설정할 수 있어요.")
+		return
+	}
+
+	subCommand := group.Options[0]
+	tenant := controller.TenantFromContext(ctx)
+	switch subCommand.Name {
+	case subCmdList:
+		h.showRoleBindings(ctx, i)
+	case subCmdSet:
+		roleID, level := fmt.Sprint(subCommand.Options[0].Value), subCommand.Options[1].StringValue()
+		if err := h.controller.SetRoleBinding(ctx, tenant, roleID, level); err != nil {
+			h.logger.Error("Failed to set role binding", zap.Error(err), zap.String("role_id", roleID))
+			h.respondEphemeral(i, fmt.Sprintf("오류: 역할 매핑을 설정하는 데 실패했어요. 에러: %v", err))
+			return
+		}
+		h.respondEphemeral(i, fmt.Sprintf("%s 역할에 `%s` 권한을 부여했어요.", roleMention(i.GuildID, roleID), level))
+	case subCmdRemove:
+		roleID := fmt.Sprint(subCommand.Options[0].Value)
+		if err := h.controller.RemoveRoleBinding(ctx, tenant, roleID); err != nil {
+			h.logger.Error("Failed to remove role binding", zap.Error(err), zap.String("role_id", roleID))
+			h.respondEphemeral(i, fmt.Sprintf("오류: 역할 매핑을 삭제하는 데 실패했어요. 에러: %v", err))
+			return
+		}
+		h.respondEphemeral(i, fmt.Sprintf("%s 역할의 CNAP 권한 부여를 취소했어요.", roleMention(i.GuildID, roleID)))
+	}
+}
+
+// showRoleBindings는 서버의 역할 매핑과 요청한 사용자의 역할을 표시합니다.
+func (h *DiscordHandler) showRoleBindings(ctx context.Context, i *discordgo.InteractionCreate) {
+	bindings, err := h.controller.ListRoleBindings(ctx, controller.TenantFromContext(ctx))
+	if err != nil {
+		h.logger.Error("Failed to list role bindings", zap.Error(err))
+		h.respondEphemeral(i, fmt.Sprintf("오류: 역할 매핑을 불러오는 데 실패했어요. 에러: %v", err))
+		return
+	}
+
+	lines := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		lines = append(lines, fmt.Sprintf("%s → `%s`", roleMention(i.GuildID, b.ExternalRole), b.Role))
+	}
+	mapping := "(설정된 매핑이 없어요)"
+	if len(lines) > 0 {
+		mapping = strings.Join(lines, "\n")
+	}
+	embed := &discordgo.MessageEmbed{
+		Title: "CNAP 역할 매핑",
+		Description: fmt.Sprintf("매핑된 역할이 없는 멤버는 `%s`, 서버 관리자 권한이 있는 멤버는 `%s`예요. 여러 역할이 매핑되어 있으면 가장 높은 역할을 사용해요.",
+			controller.DefaultRole, storage.RoleAdmin),
+		Color: 0x0099ff,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "매핑", Value: mapping},
+			{Name: "내 역할", Value: "`" + controller.RoleFromContext(ctx) + "`", Inline: true},
+		},
+	}
+	err = h.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}, Flags: discordgo.MessageFlagsEphemeral},
+	})
+	if err != nil {
+		h.logger.Error("Failed to show role bindings", zap.Error(err))
+	}
+}
+
+// authorize는 상호작용을 보낸 사용자의 역할을 확인하고, 사용자와 테넌트, 역할을 담은 context를 반환합니다.
+// 역할이 min보다 낮으면 사용자에게만 보이는 거부 메시지를 보내고 false를 반환합니다.
+func (h *DiscordHandler) authorize(i *discordgo.InteractionCreate, min string) (context.Context, bool) {
+	ctx, err := h.roleContext(i)
+	if err != nil {
+		h.logger.Error("Failed to resolve member role", zap.Error(err), zap.String("guild_id", i.GuildID))
+		h.respondEphemeral(i, "오류: 권한을 확인하는 데 실패했어요.")
+		return nil, false
+	}
+	if role := controller.RoleFromContext(ctx); !controller.RoleAtLeast(role, min) {
+		h.respondEphemeral(i, fmt.Sprintf("권한이 없어요. 이 작업에는 `%s` 이상의 역할이 필요해요. (현재 역할: `%s`)", min, role))
+		return nil, false
+	}
+	return ctx, true
+}
+
+// roleContext는 상호작용을 보낸 사용자와 테넌트, 역할을 담은 context를 반환합니다.
+func (h *DiscordHandler) roleContext(i *discordgo.InteractionCreate) (context.Context, error) {
+	ctx := h.interactionContext(i)
+	var roles []string
+	administrator := false
+	if i.Member != nil {
+		roles = i.Member.Roles
+		administrator = i.Member.Permissions&discordgo.PermissionAdministrator != 0
+	}
+	role, err := h.memberRole(ctx, i.GuildID, roles, administrator)
+	if err != nil {
+		return nil, err
+	}
+	return controller.WithRole(ctx, role), nil
+}
+
+// authorizeMessage는 메시지 작성자의 역할을 확인하고, 작성자와 테넌트, 역할을 담은 context를 반환합니다.
+// 역할이 min보다 낮으면 메시지에 답장으로 안내하고 false를 반환합니다.
+func (h *DiscordHandler) authorizeMessage(m *discordgo.Message, min string) (context.Context, bool) {
+	ctx := h.messageContext(m)
+	var roles []string
+	if m.Member != nil {
+		roles = m.Member.Roles
+	}
+	// 메시지 이벤트에는 권한 정보가 없으므로 캐시된 서버 정보로 관리자 권한을 확인
+	perms, err := h.session.State.UserChannelPermissions(m.Author.ID, m.ChannelID)
+	administrator := err == nil && perms&discordgo.PermissionAdministrator != 0
+
+	role, err := h.memberRole(ctx, m.GuildID, roles, administrator)
+	if err != nil {
+		h.logger.Error("Failed to resolve member role", zap.Error(err), zap.String("guild_id", m.GuildID))
+		return nil, false
+	}
+	if !controller.RoleAtLeast(role, min) {
+		content := fmt.Sprintf("권한이 없어요. 에이전트와 대화하려면 `%s` 이상의 역할이 필요해요. (현재 역할: `%s`)", min, role)
+		if _, err := h.session.ChannelMessageSendReply(m.ChannelID, content, m.Reference()); err != nil {
+			h.logger.Error("Failed to send permission denied reply", zap.Error(err), zap.String("channel_id", m.ChannelID))
+		}
+		return nil, false
+	}
+	return controller.WithRole(ctx, role), true
+}
+
+// memberRole은 서버 멤버의 CNAP 역할을 결정합니다.
+// DM은 사용자 개인 테넌트이므로, 서버 관리자 권한이 있으면 항상 admin입니다.
+// 그 외에는 멤버의 Discord 역할과 @everyone 역할(ID가 서버 ID와 같음)의 매핑 중 가장 높은 역할입니다.
+func (h *DiscordHandler) memberRole(ctx context.Context, guildID string, roles []string, administrator bool) (string, error) {
+	if guildID == "" || administrator {
+		return storage.RoleAdmin, nil
+	}
+	return h.controller.ResolveRole(ctx, controller.TenantFromContext(ctx), append([]string{guildID}, roles...))
+}
+
+// roleMention은 Discord 역할 멘션을 반환합니다. @everyone 역할은 ID가 서버 ID와 같습니다.
+func roleMention(guildID, roleID string) string {
+	if roleID == guildID {
+		return "@everyone"
+	}
+	return "<@&" + roleID + ">"
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"go.uber.org/zap"
+)
+
+// handleImagePull은 Runner 이미지 pull 진행 상황을 스레드의 상태 메시지 하나로 표시합니다.
+// 레이어마다 메시지를 보내면 스레드가 어지러워지므로 pull 시작과 완료만 알립니다.
+func (h *ControllerHandler) handleImagePull(event controller.ControllerEvent) {
+	h.logger.Debug("[ImagePull]",
+		zap.String("task_id", event.TaskID),
+		zap.String("image", event.Content),
+		zap.String("status", event.Delta),
+	)
+
+	h.statusMessagesMutex.Lock()
+	messageID, exists := h.statusMessages[event.TaskID]
+	h.statusMessagesMutex.Unlock()
+
+	if !exists {
+		msg, err := h.session.ChannelMessageSend(event.TaskID, fmt.Sprintf("📦 Runner 이미지를 받는 중입니다: `%s`", event.Content))
+		if err != nil {
+			h.logger.Error("Failed to send image pull message",
+				zap.String("task_id", event.TaskID),
+				zap.Error(err),
+			)
+			return
+		}
+		h.statusMessagesMutex.Lock()
+		h.statusMessages[event.TaskID] = msg.ID
+		h.statusMessagesMutex.Unlock()
+		return
+	}
+
+	// Docker는 pull이 끝나면 "Status: Downloaded newer image for ..."를 보냄
+	if !strings.HasPrefix(event.Delta, "Status:") {
+		return
+	}
+	h.statusMessagesMutex.Lock()
+	delete(h.statusMessages, event.TaskID)
+	h.statusMessagesMutex.Unlock()
+
+	if _, err := h.session.ChannelMessageEdit(event.TaskID, messageID, fmt.Sprintf("📦 Runner 이미지 준비 완료: `%s`", event.Content)); err != nil {
+		h.logger.Error("Failed to update image pull message",
+			zap.String("task_id", event.TaskID),
+			zap.Error(err),
+		)
+	}
+}
+
+// handleStreamStatus는 Runner 이벤트 스트림이 끊겨 재연결 중이면 알리고, 복구되면 같은 메시지를 갱신합니다.
+func (h *ControllerHandler) handleStreamStatus(event controller.ControllerEvent) {
+	h.logger.Info("[StreamStatus]",
+		zap.String("task_id", event.TaskID),
+		zap.String("status", event.Status),
+		zap.String("cause", event.Content),
+	)
+
+	messageKey := event.TaskID + ":stream"
+	switch event.Status {
+	case "reconnecting":
+		msg, err := h.session.ChannelMessageSend(event.TaskID, "⚠️ Runner 연결이 끊겨 재연결하는 중입니다. 응답이 지연될 수 있습니다.")
+		if err != nil {
+			h.logger.Error("Failed to send stream status message",
+				zap.String("task_id", event.TaskID),
+				zap.Error(err),
+			)
+			return
+		}
+		h.statusMessagesMutex.Lock()
+		h.statusMessages[messageKey] = msg.ID
+		h.statusMessagesMutex.Unlock()
+
+	case "connected":
+		h.statusMessagesMutex.Lock()
+		messageID, exists := h.statusMessages[messageKey]
+		delete(h.statusMessages, messageKey)
+		h.statusMessagesMutex.Unlock()
+		if !exists {
+			return
+		}
+		if _, err := h.session.ChannelMessageEdit(event.TaskID, messageID, "✅ Runner 연결이 복구되었습니다."); err != nil {
+			h.logger.Error("Failed to update stream status message",
+				zap.String("task_id", event.TaskID),
+				zap.Error(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// searchResultLimit은 '/search' 응답에 표시할 최대 결과 개수입니다.
+const searchResultLimit = 10
+
+// searchCommand는 '/search' 슬래시 명령어 정의입니다.
+func searchCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        cmdSearch,
+		Description: "모든 대화에서 이전 답변을 검색합니다.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "query", Description: "검색어 (모든 단어를 포함하는 메시지를 찾아요)", Required: true},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "agent", Description: "특정 에이전트의 대화만 검색"},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "since", Description: "이 기간 이후의 메시지만 검색 (예: 7d, 12h, 2025-01-02)"},
+		},
+	}
+}
+
+// handleSearchCommand는 '/search' 명령어를 처리하고 결과를 스레드 링크와 함께 표시합니다.
+func (h *DiscordHandler) handleSearchCommand(ctx context.Context, i *discordgo.InteractionCreate) {
+	var query, agentName, since string
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "query":
+			query = opt.StringValue()
+		case "agent":
+			agentName = opt.StringValue()
+		case "since":
+			since = opt.StringValue()
+		}
+	}
+
+	sinceTime, err := controller.ParseSince(since, time.Now())
+	if err != nil {
+		h.respondEphemeral(i, fmt.Sprintf("오류: 기간 형식이 올바르지 않아요. 에러: %v", err))
+		return
+	}
+
+	results, err := h.controller.SearchMessages(ctx, query, agentName, sinceTime, searchResultLimit)
+	if err != nil {
+		h.logger.Error("Failed to search messages", zap.Error(err), zap.String("query", query))
+		h.respondEphemeral(i, fmt.Sprintf("오류: 검색에 실패했어요. 에러: %v", err))
+		return
+	}
+	if len(results) == 0 {
+		h.respondEphemeral(i, fmt.Sprintf("'**%s**'에 대한 검색 결과가 없어요.", query))
+		return
+	}
+
+	err = h.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{searchResultEmbed(query, results)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		h.logger.Error("Failed to send search results", zap.Error(err))
+	}
+}
+
+// searchResultEmbed는 검색 결과 임베드를 생성합니다. Task ID는 스레드 ID이므로 채널 멘션으로 링크합니다.
+func searchResultEmbed(query string, results []storage.SearchResult) *discordgo.MessageEmbed {
+	fields := make([]*discordgo.MessageEmbedField, 0, len(results))
+	for _, r := range results {
+		snippet := strings.NewReplacer(storage.SearchMatchStart, "**", storage.SearchMatchEnd, "**").
+			Replace(strings.Join(strings.Fields(r.Snippet), " "))
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s · #%d %s · %s", r.AgentID, r.ConversationIndex, r.Role, r.CreatedAt.Local().Format("2006-01-02 15:04")),
+			Value: truncate(fmt.Sprintf("<#%s>\n%s", r.TaskID, snippet), 1000),
+		})
+	}
+	return &discordgo.MessageEmbed{
+		Title:  fmt.Sprintf("검색 결과: %s", query),
+		Color:  0x0099ff,
+		Fields: fields,
+	}
+}
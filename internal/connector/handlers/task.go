@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// '/task list' 응답에 표시할 Task 개수 (임베드 필드는 최대 25개)
+const (
+	taskListDefaultLimit = 10
+	taskListMaxLimit     = 25
+)
+
+// taskCommand는 '/task' 슬래시 명령어 정의입니다.
+func taskCommand() *discordgo.ApplicationCommand {
+	statusChoices := []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "진행 중 (pending, running, waiting)", Value: controller.TaskStatusActive},
+	}
+	for _, status := range []string{
+		storage.TaskStatusPending, storage.TaskStatusRunning, storage.TaskStatusWaiting,
+		storage.TaskStatusCompleted, storage.TaskStatusFailed, storage.TaskStatusCanceled,
+	} {
+		statusChoices = append(statusChoices, &discordgo.ApplicationCommandOptionChoice{Name: status, Value: status})
+	}
+	minLimit := float64(1)
+
+	return &discordgo.ApplicationCommand{
+		Name:        cmdTask,
+		Description: "Task 조회 명령어",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        subCmdList,
+				Description: "모든 에이전트의 Task 목록을 최신순으로 봅니다.",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionString, Name: "status", Description: "상태 필터", Choices: statusChoices},
+					{Type: discordgo.ApplicationCommandOptionString, Name: "agent", Description: "특정 에이전트의 Task만 조회"},
+					{Type: discordgo.ApplicationCommandOptionString, Name: "prompt", Description: "프롬프트에 포함된 문자열"},
+					{Type: discordgo.ApplicationCommandOptionInteger, Name: "limit", Description: "표시할 Task 개수 (최대 25)", MinValue: &minLimit, MaxValue: taskListMaxLimit},
+					{Type: discordgo.ApplicationCommandOptionString, Name: "cursor", Description: "이전 결과 하단에 표시된 다음 페이지 커서"},
+				},
+			},
+		},
+	}
+}
+
+// handleTaskCommand는 '/task' 슬래시 명령어를 처리합니다.
+func (h *DiscordHandler) handleTaskCommand(ctx context.Context, i *discordgo.InteractionCreate) {
+	subCommand := i.ApplicationCommandData().Options[0]
+	switch subCommand.Name {
+	case subCmdList:
+		h.showTaskList(ctx, i, subCommand.Options)
+	}
+}
+
+// showTaskList는 조건에 맞는 Task 목록을 표시합니다. 다음 페이지가 있으면 커서를 안내합니다.
+func (h *DiscordHandler) showTaskList(ctx context.Context, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	query := storage.TaskQuery{Limit: taskListDefaultLimit}
+	for _, opt := range options {
+		switch opt.Name {
+		case "status":
+			statuses, err := controller.ParseTaskStatuses(opt.StringValue())
+			if err != nil {
+				h.respondEphemeral(i, fmt.Sprintf("오류: %v", err))
+				return
+			}
+			query.Statuses = statuses
+		case "agent":
+			query.AgentID = opt.StringValue()
+		case "prompt":
+			query.PromptContains = opt.StringValue()
+		case "limit":
+			query.Limit = int(opt.IntValue())
+		case "cursor":
+			query.Cursor = strings.TrimSpace(opt.StringValue())
+		}
+	}
+	if query.Limit > taskListMaxLimit {
+		query.Limit = taskListMaxLimit
+	}
+
+	page, err := h.controller.ListTasks(ctx, query)
+	if err != nil {
+		h.logger.Error("Failed to list tasks from controller", zap.Error(err))
+		h.respondEphemeral(i, fmt.Sprintf("오류: Task 목록을 불러오는 데 실패했어요. 에러: %v", err))
+		return
+	}
+	if len(page.Tasks) == 0 {
+		h.respondEphemeral(i, "조건에 맞는 Task가 없어요.")
+		return
+	}
+
+	err = h.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{taskListEmbed(page)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		h.logger.Error("Failed to show task list", zap.Error(err))
+	}
+}
+
+// taskListEmbed는 Task 목록 임베드를 생성합니다. Discord 스레드에서 생성된 Task는 스레드로 링크합니다.
+func taskListEmbed(page *storage.TaskPage) *discordgo.MessageEmbed {
+	fields := make([]*discordgo.MessageEmbedField, 0, len(page.Tasks))
+	for _, task := range page.Tasks {
+		location := task.TaskID
+		if isSnowflake(task.TaskID) {
+			location = fmt.Sprintf("<#%s>", task.TaskID)
+		}
+		value := fmt.Sprintf("%s · %s · %s", location, task.AgentID, task.UpdatedAt.Local().Format("2006-01-02 15:04"))
+		if prompt := strings.Join(strings.Fields(task.Prompt), " "); prompt != "" {
+			value += "\n" + truncate(prompt, 200)
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s · %s", task.TaskID, task.Status),
+			Value: value,
+		})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:  "Task 목록",
+		Color:  0x0099ff,
+		Fields: fields,
+	}
+	if page.NextCursor != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "다음 페이지: /task list cursor:" + page.NextCursor}
+	}
+	return embed
+}
+
+// isSnowflake는 id가 Discord ID(숫자) 형식인지 확인합니다.
+func isSnowflake(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// exportTranscriptButton은 결과 메시지에 붙는 대화 기록 내보내기 버튼입니다.
+func exportTranscriptButton(taskID string) discordgo.MessageComponent {
+	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "대화 내보내기", Style: discordgo.SecondaryButton, Emoji: &discordgo.ComponentEmoji{Name: "📄"}, CustomID: prefixButtonExport + taskID},
+	}}
+}
+
+// exportTranscript는 Task의 대화 기록을 HTML 파일로 만들어 버튼을 누른 사용자에게만 보냅니다.
+func (h *DiscordHandler) exportTranscript(ctx context.Context, i *discordgo.InteractionCreate, taskID string) {
+	err := h.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+	if err != nil {
+		h.logger.Error("Failed to defer export response", zap.Error(err), zap.String("task_id", taskID))
+		return
+	}
+
+	followup := &discordgo.WebhookParams{Flags: discordgo.MessageFlagsEphemeral}
+	transcript, err := h.controller.BuildTaskTranscript(ctx, taskID)
+	var buf bytes.Buffer
+	if err == nil {
+		err = controller.WriteTranscript(&buf, transcript, controller.TranscriptFormatHTML)
+	}
+	if err != nil {
+		h.logger.Error("Failed to export transcript", zap.Error(err), zap.String("task_id", taskID))
+		followup.Content = "오류: 대화 기록을 내보내는 데 실패했어요."
+	} else {
+		followup.Content = fmt.Sprintf("메시지 %d개의 대화 기록이에요.", len(transcript.Messages))
+		followup.Files = []*discordgo.File{{
+			Name:        transcript.ExportFileName(controller.TranscriptFormatHTML),
+			ContentType: "text/html",
+			Reader:      &buf,
+		}}
+	}
+	if _, err := h.session.FollowupMessageCreate(i.Interaction, true, followup); err != nil {
+		h.logger.Error("Failed to send exported transcript", zap.Error(err), zap.String("task_id", taskID))
+	}
+}
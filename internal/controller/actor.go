@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"strings"
+)
+
+// 사용자 식별자의 커넥터 접두사
+const (
+	ActorConnectorDiscord = "discord"
+	ActorConnectorCLI     = "cli"
+	ActorConnectorSystem  = "system" // Controller 내부 작업 (보관 정책 정리, 선언형 설정 반영 등)
+)
+
+// actorKey는 context에 요청한 사용자 식별자를 담는 키입니다.
+type actorKey struct{}
+
+// ConnectorActor는 커넥터와 커넥터 안의 사용자 ID로 사용자 식별자({connector}:{id})를 만듭니다.
+func ConnectorActor(connector, id string) string {
+	return connector + ":" + id
+}
+
+// ParseActor는 사용자 식별자를 정규화합니다. 커넥터 접두사가 없으면 Discord 사용자 ID로 간주합니다.
+func ParseActor(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.Contains(s, ":") {
+		return s
+	}
+	return ConnectorActor(ActorConnectorDiscord, s)
+}
+
+// actorConnector는 사용자 식별자의 커넥터 접두사를 반환합니다.
+func actorConnector(actor string) string {
+	connector, _, _ := strings.Cut(actor, ":")
+	return connector
+}
+
+// WithDefaultActor는 context에 사용자 식별자가 없을 때 감사 기록에 사용할 식별자를 지정합니다.
+// CLI는 실행한 OS 사용자를 지정합니다. 지정하지 않으면 system:controller를 사용합니다.
+func WithDefaultActor(actor string) Option {
+	return func(c *Controller) {
+		c.defaultActor = actor
+	}
+}
+
+// actor는 ctx의 사용자 식별자를, 없으면 기본 식별자를 반환합니다.
+func (c *Controller) actor(ctx context.Context) string {
+	if actor := ActorFromContext(ctx); actor != "" {
+		return actor
+	}
+	if c.defaultActor != "" {
+		return c.defaultActor
+	}
+	return ConnectorActor(ActorConnectorSystem, "controller")
+}
+
+// WithActor는 요청한 사용자 식별자를 담은 context를 반환합니다.
+// 이 context로 추가된 Task 프롬프트와 메시지에는 작성자로 기록됩니다.
+func WithActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext는 ctx에 담긴 사용자 식별자를 반환합니다. 없으면 빈 문자열입니다.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
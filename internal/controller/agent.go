@@ -4,13 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cnap-oss/app/internal/storage"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-// CreateAgent는 새로운 에이전트를 생성합니다.
+// CreateAgent는 새로운 에이전트를 생성합니다. agentID는 ctx 테넌트 안의 이름이며,
+// 같은 테넌트에 같은 이름이 있으면 storage.ErrAgentExists를 반환합니다.
 func (c *Controller) CreateAgent(ctx context.Context, agentID, description, provider, model, prompt string) error {
 	c.logger.Info("Creating agent",
 		zap.String("agent_id", agentID),
@@ -23,12 +25,14 @@ func (c *Controller) CreateAgent(ctx context.Context, agentID, description, prov
 	}
 
 	payload := &storage.Agent{
-		AgentID:     agentID,
+		Name:        agentID,
 		Description: description,
 		Provider:    provider,
 		Model:       model,
 		Prompt:      prompt,
 		Status:      storage.AgentStatusActive,
+		TenantID:    TenantFromContext(ctx),
+		Owner:       ActorFromContext(ctx),
 	}
 
 	if err := c.repo.CreateAgent(ctx, payload); err != nil {
@@ -36,8 +40,10 @@ func (c *Controller) CreateAgent(ctx context.Context, agentID, description, prov
 		return err
 	}
 
+	c.audit(ctx, AuditAgentCreate, AuditTargetAgent, payload.AgentID, nil, c.agentAuditSnapshot(ctx, payload.AgentID))
+
 	c.logger.Info("Agent created successfully",
-		zap.String("agent", agentID),
+		zap.String("agent", payload.AgentID),
 		zap.Int64("id", payload.ID),
 	)
 	return nil
@@ -53,9 +59,25 @@ func (c *Controller) DeleteAgent(ctx context.Context, agent string) error {
 		return fmt.Errorf("controller: repository is not configured")
 	}
 
+	if TenantFromContext(ctx) != "" {
+		rec, err := c.getEditableAgent(ctx, agent)
+		if err != nil {
+			return err
+		}
+		agent = rec.AgentID
+	} else if rec, err := c.repo.GetAgent(ctx, agent); err == nil {
+		if err := rejectManagedAgent(ctx, rec); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	before := c.agentAuditSnapshot(ctx, agent)
 	if err := c.repo.UpsertAgentStatus(ctx, agent, storage.AgentStatusDeleted); err != nil {
 		return err
 	}
+	c.audit(ctx, AuditAgentDelete, AuditTargetAgent, agent, before, c.agentAuditSnapshot(ctx, agent))
 
 	c.logger.Info("Agent deleted successfully",
 		zap.String("agent", agent),
@@ -73,23 +95,29 @@ func (c *Controller) GetAgentInfo(ctx context.Context, agent string) (*AgentInfo
 		return nil, fmt.Errorf("controller: repository is not configured")
 	}
 
-	rec, err := c.repo.GetAgent(ctx, agent)
+	rec, err := c.getAgent(ctx, agent)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("agent not found: %s", agent)
-		}
 		return nil, err
 	}
 
 	info := &AgentInfo{
-		Name:        rec.AgentID,
-		Description: rec.Description,
-		Provider:    rec.Provider,
-		Model:       rec.Model,
-		Prompt:      rec.Prompt,
-		Status:      rec.Status,
-		CreatedAt:   rec.CreatedAt,
-		UpdatedAt:   rec.UpdatedAt,
+		ID:                  rec.AgentID,
+		Name:                rec.Name,
+		Description:         rec.Description,
+		Provider:            rec.Provider,
+		Model:               rec.Model,
+		Prompt:              rec.Prompt,
+		Image:               rec.Image,
+		Status:              rec.Status,
+		PermissionTimeout:   permissionTimeout(rec),
+		PermissionOnTimeout: permissionOnTimeout(rec),
+		ConfigSource:        rec.ConfigSource,
+		Retention:           agentRetention(rec),
+		Tenant:              rec.TenantID,
+		Owner:               rec.Owner,
+		Visibility:          rec.Visibility,
+		CreatedAt:           rec.CreatedAt,
+		UpdatedAt:           rec.UpdatedAt,
 	}
 
 	c.logger.Info("Retrieved agent info",
@@ -109,13 +137,12 @@ func (c *Controller) UpdateAgent(ctx context.Context, agentID, description, prov
 		return fmt.Errorf("controller: repository is not configured")
 	}
 
-	// Agent 존재 여부 확인
-	if _, err := c.repo.GetAgent(ctx, agentID); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("agent not found: %s", agentID)
-		}
+	// Agent 존재 여부와 수정 권한 확인 (설정 파일로 관리되는 Agent는 수정 불가)
+	rec, err := c.getEditableAgent(ctx, agentID)
+	if err != nil {
 		return err
 	}
+	agentID = rec.AgentID
 
 	agent := &storage.Agent{
 		AgentID:     agentID,
@@ -125,16 +152,78 @@ func (c *Controller) UpdateAgent(ctx context.Context, agentID, description, prov
 		Prompt:      prompt,
 	}
 
+	before := c.agentAuditSnapshot(ctx, agentID)
 	if err := c.repo.UpdateAgent(ctx, agent); err != nil {
 		c.logger.Error("Failed to update agent", zap.Error(err))
 		return err
 	}
+	c.audit(ctx, AuditAgentUpdate, AuditTargetAgent, agentID, before, c.agentAuditSnapshot(ctx, agentID))
 
 	c.logger.Info("Agent updated successfully", zap.String("agent", agentID))
 	return nil
 }
 
-// ListAgents는 모든 에이전트 목록을 반환합니다.
+// SetAgentImage는 에이전트 전용 Runner 이미지를 설정합니다.
+// image가 비어 있으면 기본 이미지를 사용합니다. 기존 Task는 고정된 다이제스트를 계속 사용합니다.
+func (c *Controller) SetAgentImage(ctx context.Context, agentID, image string) error {
+	c.logger.Info("Setting agent image",
+		zap.String("agent_id", agentID),
+		zap.String("image", image),
+	)
+
+	if c.repo == nil {
+		return fmt.Errorf("controller: repository is not configured")
+	}
+
+	rec, err := c.getEditableAgent(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	agentID = rec.AgentID
+
+	before := c.agentAuditSnapshot(ctx, agentID)
+	if err := c.repo.UpdateAgentImage(ctx, agentID, image); err != nil {
+		c.logger.Error("Failed to update agent image", zap.Error(err))
+		return err
+	}
+	c.audit(ctx, AuditAgentSetImage, AuditTargetAgent, agentID, before, c.agentAuditSnapshot(ctx, agentID))
+
+	c.logger.Info("Agent image updated successfully", zap.String("agent", agentID))
+	return nil
+}
+
+// SetAgentPermissionPolicy는 에이전트의 도구 권한 승인 정책을 설정합니다.
+// timeout이 0이면 기본 대기 시간(DefaultPermissionTimeout)을 사용하고,
+// onTimeout은 대기 시간 안에 응답이 없을 때 적용할 응답(once 또는 reject)입니다.
+func (c *Controller) SetAgentPermissionPolicy(ctx context.Context, agentID string, timeout time.Duration, onTimeout string) error {
+	c.logger.Info("Setting agent permission policy",
+		zap.String("agent_id", agentID),
+		zap.Duration("timeout", timeout),
+		zap.String("on_timeout", onTimeout),
+	)
+
+	if c.repo == nil {
+		return fmt.Errorf("controller: repository is not configured")
+	}
+
+	rec, err := c.getEditableAgent(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	agentID = rec.AgentID
+
+	before := c.agentAuditSnapshot(ctx, agentID)
+	if err := c.repo.UpdateAgentPermissionPolicy(ctx, agentID, int(timeout/time.Second), onTimeout); err != nil {
+		c.logger.Error("Failed to update agent permission policy", zap.Error(err))
+		return err
+	}
+	c.audit(ctx, AuditAgentSetPermission, AuditTargetAgent, agentID, before, c.agentAuditSnapshot(ctx, agentID))
+
+	c.logger.Info("Agent permission policy updated successfully", zap.String("agent", agentID))
+	return nil
+}
+
+// ListAgents는 모든 에이전트 이름 목록을 반환합니다. 테넌트 범위의 context에서는 보이는 에이전트만 반환합니다.
 func (c *Controller) ListAgents(ctx context.Context) ([]string, error) {
 	c.logger.Info("Listing agents")
 
@@ -142,14 +231,14 @@ func (c *Controller) ListAgents(ctx context.Context) ([]string, error) {
 		return nil, fmt.Errorf("controller: repository is not configured")
 	}
 
-	records, err := c.repo.ListAgents(ctx)
+	records, err := c.listAgents(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	agents := make([]string, 0, len(records))
 	for _, rec := range records {
-		agents = append(agents, rec.AgentID)
+		agents = append(agents, rec.Name)
 	}
 
 	c.logger.Info("Listed agents",
@@ -166,7 +255,7 @@ func (c *Controller) ListAgentsWithInfo(ctx context.Context) ([]*AgentInfo, erro
 		return nil, fmt.Errorf("controller: repository is not configured")
 	}
 
-	records, err := c.repo.ListAgents(ctx)
+	records, err := c.listAgents(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -174,13 +263,22 @@ func (c *Controller) ListAgentsWithInfo(ctx context.Context) ([]*AgentInfo, erro
 	agents := make([]*AgentInfo, 0, len(records))
 	for _, rec := range records {
 		agents = append(agents, &AgentInfo{
-			Name:        rec.AgentID,
-			Description: rec.Description,
-			Model:       rec.Model,
-			Prompt:      rec.Prompt,
-			Status:      rec.Status,
-			CreatedAt:   rec.CreatedAt,
-			UpdatedAt:   rec.UpdatedAt,
+			ID:                  rec.AgentID,
+			Name:                rec.Name,
+			Description:         rec.Description,
+			Model:               rec.Model,
+			Prompt:              rec.Prompt,
+			Image:               rec.Image,
+			Status:              rec.Status,
+			PermissionTimeout:   permissionTimeout(&rec),
+			PermissionOnTimeout: permissionOnTimeout(&rec),
+			ConfigSource:        rec.ConfigSource,
+			Retention:           agentRetention(&rec),
+			Tenant:              rec.TenantID,
+			Owner:               rec.Owner,
+			Visibility:          rec.Visibility,
+			CreatedAt:           rec.CreatedAt,
+			UpdatedAt:           rec.UpdatedAt,
 		})
 	}
 
@@ -190,8 +288,21 @@ func (c *Controller) ListAgentsWithInfo(ctx context.Context) ([]*AgentInfo, erro
 	return agents, nil
 }
 
+// listAgents는 ctx의 테넌트 범위에 보이는 에이전트 레코드를 반환합니다. 테넌트가 없으면 모든 에이전트입니다.
+func (c *Controller) listAgents(ctx context.Context) ([]storage.Agent, error) {
+	if scope, ok := agentScope(ctx); ok {
+		return c.repo.ListVisibleAgents(ctx, scope)
+	}
+	return c.repo.ListAgents(ctx)
+}
+
 // ValidateAgent는 에이전트 이름의 유효성을 검증합니다.
 func (c *Controller) ValidateAgent(agent string) error {
+	return validateAgentName(agent)
+}
+
+// validateAgentName은 에이전트 이름 규칙을 검사합니다. 번들 검증에서도 사용합니다.
+func validateAgentName(agent string) error {
 	if agent == "" {
 		return fmt.Errorf("agent name cannot be empty")
 	}
@@ -0,0 +1,839 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	taskrunner "github.com/cnap-oss/app/internal/runner"
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// 에이전트 번들 형식
+const (
+	AgentBundleAPIVersion = "cnap/v1"
+	AgentBundleKind       = "AgentBundle"
+)
+
+// SeedFileEncodingBase64는 UTF-8 텍스트가 아닌 시드 파일의 내용 인코딩입니다.
+const SeedFileEncodingBase64 = "base64"
+
+// 번들에 포함하는 작업 공간 시드 파일 크기 제한
+const (
+	maxSeedFileSize  = 1 << 20
+	maxSeedTotalSize = 16 << 20
+)
+
+// seedManifestName은 가져온 시드 파일 경로를 기록하는 파일 이름입니다 (.opencode/ 아래).
+// 내보내기는 이 목록의 파일만 포함하므로 실행 중에 생긴 산출물은 번들에 들어가지 않습니다.
+const seedManifestName = "seed-files.json"
+
+// RedactedSecret은 비밀 값을 제외하고 내보낸 MCP 서버 환경 변수 값입니다.
+// 가져올 때는 대상 에이전트의 현재 값을 그대로 유지합니다.
+const RedactedSecret = "<redacted>"
+
+// seedFileExcludes는 번들에 포함하지 않는 작업 공간 최상위 디렉토리입니다.
+// 실행 중에 생성되는 로그와 OpenCode 상태이며, MCP 설정은 mcpServers로 따로 내보냅니다.
+var seedFileExcludes = map[string]bool{"logs": true, ".opencode": true, ".git": true}
+
+// AgentBundle은 배포 환경 사이에서 에이전트를 옮기기 위한 YAML 문서입니다.
+type AgentBundle struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Agents     []AgentSpec `yaml:"agents"`
+}
+
+// AgentSpec은 번들에 담긴 에이전트 하나의 선언입니다.
+// Name은 테넌트 안의 에이전트 이름이며, Tenant가 비어 있으면 기본 테넌트의 에이전트입니다.
+type AgentSpec struct {
+	Name        string                          `yaml:"name"`
+	Tenant      string                          `yaml:"tenant,omitempty"`
+	Description string                          `yaml:"description,omitempty"`
+	Provider    string                          `yaml:"provider,omitempty"`
+	Model       string                          `yaml:"model,omitempty"`
+	Prompt      string                          `yaml:"prompt,omitempty"`
+	Image       string                          `yaml:"image,omitempty"`
+	Permission  *AgentPermissionSpec            `yaml:"permission,omitempty"`
+	MCPServers  map[string]taskrunner.MCPServer `yaml:"mcpServers,omitempty"`
+	Files       []AgentSeedFile                 `yaml:"files,omitempty"`
+}
+
+// AgentID는 선언이 가리키는 에이전트 식별자(테넌트와 이름으로 만든 storage.AgentKey)를 반환합니다.
+func (s AgentSpec) AgentID() string {
+	return storage.AgentKey(s.Tenant, s.Name)
+}
+
+// AgentPermissionSpec은 도구 권한 승인 정책입니다. 생략하면 기본 정책을 사용합니다.
+type AgentPermissionSpec struct {
+	TimeoutSeconds int    `yaml:"timeoutSeconds,omitempty"`
+	OnTimeout      string `yaml:"onTimeout,omitempty"`
+}
+
+// AgentSeedFile은 에이전트 작업 공간에 미리 넣어 둘 파일입니다. Path는 작업 공간 기준 상대 경로입니다.
+type AgentSeedFile struct {
+	Path     string `yaml:"path"`
+	Content  string `yaml:"content"`
+	Encoding string `yaml:"encoding,omitempty"` // 비어 있으면 UTF-8 텍스트, base64
+	Mode     string `yaml:"mode,omitempty"`     // 8진수 권한 (기본값 0644)
+}
+
+// 에이전트 가져오기 동작
+const (
+	AgentImportCreate    = "create"
+	AgentImportUpdate    = "update"
+	AgentImportUnchanged = "unchanged"
+	AgentImportConflict  = "conflict" // 이미 존재하고 upsert가 아니어서 적용할 수 없음
+)
+
+// AgentImportPlan은 번들을 적용했을 때 일어날 변경 사항입니다.
+type AgentImportPlan struct {
+	Items []AgentImportItem
+}
+
+// AgentImportItem은 에이전트 하나에 대한 가져오기 계획입니다.
+type AgentImportItem struct {
+	Spec    AgentSpec
+	Action  string
+	Changes []AgentImportChange
+}
+
+// AgentImportChange는 필드 하나의 변경입니다. Old가 비어 있으면 추가, New가 비어 있으면 제거입니다.
+// Field는 에이전트 필드 이름이거나 "mcp:<서버 이름>", "file:<경로>" 형식입니다.
+type AgentImportChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// HasConflicts는 upsert 없이 적용할 수 없는 에이전트가 있는지 확인합니다.
+func (p *AgentImportPlan) HasConflicts() bool {
+	for _, item := range p.Items {
+		if item.Action == AgentImportConflict {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAgentBundle은 YAML 번들을 읽고 검증합니다. 알 수 없는 필드가 있으면 오류를 반환합니다.
+func ParseAgentBundle(data []byte) (*AgentBundle, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var bundle AgentBundle
+	if err := dec.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("invalid agent bundle: %w", err)
+	}
+	if err := bundle.Validate(); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// Marshal은 번들을 YAML로 직렬화합니다.
+func (b *AgentBundle) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(b); err != nil {
+		return nil, fmt.Errorf("failed to encode agent bundle: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode agent bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Validate는 번들 형식과 각 에이전트 선언을 검증합니다.
+func (b *AgentBundle) Validate() error {
+	if b.APIVersion != AgentBundleAPIVersion {
+		return fmt.Errorf("unsupported agent bundle apiVersion %q (expected %s)", b.APIVersion, AgentBundleAPIVersion)
+	}
+	if b.Kind != AgentBundleKind {
+		return fmt.Errorf("unsupported agent bundle kind %q (expected %s)", b.Kind, AgentBundleKind)
+	}
+	if len(b.Agents) == 0 {
+		return fmt.Errorf("agent bundle has no agents")
+	}
+
+	seen := make(map[string]bool, len(b.Agents))
+	for _, spec := range b.Agents {
+		if err := spec.validate(); err != nil {
+			return fmt.Errorf("agent %q: %w", spec.Name, err)
+		}
+		if seen[spec.AgentID()] {
+			return fmt.Errorf("agent %q is declared more than once", spec.Name)
+		}
+		seen[spec.AgentID()] = true
+	}
+	return nil
+}
+
+func (s *AgentSpec) validate() error {
+	if err := validateAgentName(s.Name); err != nil {
+		return err
+	}
+	if s.Permission != nil {
+		if s.Permission.TimeoutSeconds < 0 {
+			return fmt.Errorf("permission timeoutSeconds must not be negative")
+		}
+		switch s.Permission.OnTimeout {
+		case "", storage.PermissionResponseOnce, storage.PermissionResponseReject:
+		default:
+			return fmt.Errorf("invalid permission onTimeout %q (expected %s or %s)",
+				s.Permission.OnTimeout, storage.PermissionResponseOnce, storage.PermissionResponseReject)
+		}
+	}
+	for name, server := range s.MCPServers {
+		if name == "" {
+			return fmt.Errorf("mcp server name cannot be empty")
+		}
+		if server.Command == "" {
+			return fmt.Errorf("mcp server %q has no command", name)
+		}
+	}
+
+	paths := make(map[string]bool, len(s.Files))
+	for _, f := range s.Files {
+		if err := validateSeedPath(f.Path); err != nil {
+			return err
+		}
+		if paths[f.Path] {
+			return fmt.Errorf("file %s is declared more than once", f.Path)
+		}
+		paths[f.Path] = true
+		if _, err := f.data(); err != nil {
+			return err
+		}
+		if _, err := f.perm(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSeedPath는 시드 파일 경로가 작업 공간 밖이나 제외 디렉토리를 가리키지 않는지 확인합니다.
+func validateSeedPath(p string) error {
+	if p == "" {
+		return fmt.Errorf("file path cannot be empty")
+	}
+	if path.IsAbs(p) || strings.Contains(p, "\\") || path.Clean(p) != p || p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return fmt.Errorf("invalid file path %q: must be a clean relative path inside the workspace", p)
+	}
+	if top, _, _ := strings.Cut(p, "/"); seedFileExcludes[top] {
+		return fmt.Errorf("invalid file path %q: %s/ is managed by the runner", p, top)
+	}
+	return nil
+}
+
+func newSeedFile(p string, data []byte, perm os.FileMode) AgentSeedFile {
+	f := AgentSeedFile{Path: p, Mode: fmt.Sprintf("%04o", perm)}
+	if utf8.Valid(data) {
+		f.Content = string(data)
+	} else {
+		f.Content = base64.StdEncoding.EncodeToString(data)
+		f.Encoding = SeedFileEncodingBase64
+	}
+	return f
+}
+
+// data는 디코딩된 파일 내용을 반환합니다.
+func (f AgentSeedFile) data() ([]byte, error) {
+	switch f.Encoding {
+	case "":
+		return []byte(f.Content), nil
+	case SeedFileEncodingBase64:
+		data, err := base64.StdEncoding.DecodeString(f.Content)
+		if err != nil {
+			return nil, fmt.Errorf("file %s: invalid base64 content: %w", f.Path, err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("file %s: unknown encoding %q", f.Path, f.Encoding)
+	}
+}
+
+// perm은 파일 권한을 반환합니다.
+func (f AgentSeedFile) perm() (os.FileMode, error) {
+	if f.Mode == "" {
+		return 0644, nil
+	}
+	mode, err := strconv.ParseUint(f.Mode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("file %s: invalid mode %q", f.Path, f.Mode)
+	}
+	return os.FileMode(mode), nil
+}
+
+// effective는 생략된 값을 기본값으로 채운 권한 정책을 반환합니다.
+func (p *AgentPermissionSpec) effective() (time.Duration, string) {
+	if p == nil {
+		return DefaultPermissionTimeout, storage.PermissionResponseReject
+	}
+	timeout := DefaultPermissionTimeout
+	if p.TimeoutSeconds > 0 {
+		timeout = time.Duration(p.TimeoutSeconds) * time.Second
+	}
+	onTimeout := p.OnTimeout
+	if onTimeout == "" {
+		onTimeout = storage.PermissionResponseReject
+	}
+	return timeout, onTimeout
+}
+
+// workspaceManager는 에이전트 작업 공간 관리자를 반환합니다.
+// 지정되지 않았으면 Runner가 /workspace로 마운트하는 디렉토리를 기준으로 생성합니다.
+func (c *Controller) workspaceManager() taskrunner.WorkspaceManager {
+	c.workspacesOnce.Do(func() {
+		if c.workspaces != nil {
+			return
+		}
+		cfg := taskrunner.DefaultWorkspaceConfig()
+		if baseDir, err := taskrunner.AgentWorkspacePath(""); err == nil {
+			cfg.BaseDir = baseDir
+		}
+		c.workspaces = taskrunner.NewWorkspaceManager(c.logger, cfg)
+	})
+	return c.workspaces
+}
+
+// ExportAgents는 에이전트를 번들로 내보냅니다. names가 비어 있으면 활성 에이전트를 모두 내보냅니다.
+// includeFiles가 false이면 작업 공간 시드 파일을 제외하고, includeSecrets가 false이면
+// MCP 서버 환경 변수 값을 RedactedSecret으로 바꿔 내보냅니다.
+func (c *Controller) ExportAgents(ctx context.Context, names []string, includeFiles, includeSecrets bool) (*AgentBundle, error) {
+	c.logger.Info("Exporting agents",
+		zap.Strings("agents", names),
+		zap.Bool("include_files", includeFiles),
+		zap.Bool("include_secrets", includeSecrets),
+	)
+
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+
+	var records []storage.Agent
+	if len(names) == 0 {
+		agents, err := c.repo.ListAgents(ctx, storage.AgentStatusActive)
+		if err != nil {
+			return nil, err
+		}
+		records = agents
+	} else {
+		for _, name := range names {
+			rec, err := c.getAgent(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, *rec)
+		}
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no agents to export")
+	}
+
+	bundle := &AgentBundle{APIVersion: AgentBundleAPIVersion, Kind: AgentBundleKind}
+	for i := range records {
+		spec, err := c.currentAgentSpec(&records[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to export agent %s: %w", records[i].AgentID, err)
+		}
+		if !includeSecrets {
+			spec.MCPServers = redactMCPSecrets(spec.MCPServers)
+		}
+		if includeFiles {
+			if spec.Files, err = c.readSeedFiles(records[i].AgentID); err != nil {
+				return nil, fmt.Errorf("failed to export agent %s: %w", records[i].AgentID, err)
+			}
+		}
+		bundle.Agents = append(bundle.Agents, spec)
+	}
+
+	c.logger.Info("Exported agents", zap.Int("count", len(bundle.Agents)))
+	return bundle, nil
+}
+
+// currentAgentSpec은 저장된 에이전트와 작업 공간의 MCP 설정으로 AgentSpec을 만듭니다. 시드 파일은 포함하지 않습니다.
+// 이름과 테넌트를 내보내므로 다른 환경에서 가져와도 같은 테넌트의 같은 이름으로 만들어집니다.
+func (c *Controller) currentAgentSpec(rec *storage.Agent) (AgentSpec, error) {
+	name := rec.Name
+	if name == "" {
+		name = rec.AgentID
+	}
+	tenant := rec.TenantID
+	if tenant == storage.DefaultTenantID {
+		tenant = ""
+	}
+	spec := AgentSpec{
+		Name:        name,
+		Tenant:      tenant,
+		Description: rec.Description,
+		Provider:    rec.Provider,
+		Model:       rec.Model,
+		Prompt:      rec.Prompt,
+		Image:       rec.Image,
+		Permission: &AgentPermissionSpec{
+			TimeoutSeconds: int(permissionTimeout(rec) / time.Second),
+			OnTimeout:      permissionOnTimeout(rec),
+		},
+	}
+
+	// 작업 공간이 아직 없으면 MCP 서버가 설정되지 않은 상태
+	if _, err := c.workspaceManager().GetWorkspace(rec.AgentID); err != nil {
+		return spec, nil
+	}
+	cfg, err := taskrunner.NewSettingsManager(c.workspaceManager(), c.logger).GetMCPConfig(rec.AgentID)
+	if err != nil {
+		return spec, err
+	}
+	if len(cfg.MCPServers) > 0 {
+		spec.MCPServers = cfg.MCPServers
+	}
+	return spec, nil
+}
+
+// redactMCPSecrets는 MCP 서버 환경 변수 값을 RedactedSecret으로 바꾼 복사본을 반환합니다.
+func redactMCPSecrets(servers map[string]taskrunner.MCPServer) map[string]taskrunner.MCPServer {
+	if len(servers) == 0 {
+		return servers
+	}
+	redacted := make(map[string]taskrunner.MCPServer, len(servers))
+	for name, server := range servers {
+		if len(server.Env) > 0 {
+			env := make(map[string]string, len(server.Env))
+			for k := range server.Env {
+				env[k] = RedactedSecret
+			}
+			server.Env = env
+		}
+		redacted[name] = server
+	}
+	return redacted
+}
+
+// resolveRedactedSecrets는 번들의 RedactedSecret 값을 현재 MCP 설정의 값으로 채운 복사본을 반환합니다.
+// 현재 값이 없으면 적용할 값을 알 수 없으므로 오류를 반환합니다.
+func resolveRedactedSecrets(servers, current map[string]taskrunner.MCPServer) (map[string]taskrunner.MCPServer, error) {
+	resolved := make(map[string]taskrunner.MCPServer, len(servers))
+	for name, server := range servers {
+		if len(server.Env) > 0 {
+			env := make(map[string]string, len(server.Env))
+			for k, v := range server.Env {
+				if v == RedactedSecret {
+					cur, ok := current[name].Env[k]
+					if !ok {
+						return nil, fmt.Errorf("mcp server %q env %s is redacted and has no current value; set it in the bundle", name, k)
+					}
+					v = cur
+				}
+				env[k] = v
+			}
+			server.Env = env
+		}
+		resolved[name] = server
+	}
+	return resolved, nil
+}
+
+// readSeedFiles는 가져오기로 기록된 시드 파일을 경로 순으로 읽습니다.
+// 사라졌거나 일반 파일이 아니거나 크기 제한을 넘는 파일은 경고를 남기고 건너뜁니다.
+func (c *Controller) readSeedFiles(agentID string) ([]AgentSeedFile, error) {
+	ws, err := c.workspaceManager().GetWorkspace(agentID)
+	if err != nil {
+		return nil, nil
+	}
+	paths, err := readSeedManifest(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []AgentSeedFile
+	var total int64
+	for _, rel := range paths {
+		if validateSeedPath(rel) != nil {
+			continue
+		}
+		p := filepath.Join(ws.BasePath, filepath.FromSlash(rel))
+		info, err := os.Lstat(p)
+		if err != nil || !info.Mode().IsRegular() {
+			c.logger.Warn("Skipping missing seed file",
+				zap.String("agent_id", agentID),
+				zap.String("path", rel),
+			)
+			continue
+		}
+		if info.Size() > maxSeedFileSize || total+info.Size() > maxSeedTotalSize {
+			c.logger.Warn("Skipping seed file over the bundle size limit",
+				zap.String("agent_id", agentID),
+				zap.String("path", rel),
+				zap.Int64("size", info.Size()),
+				zap.Int64("max_file_size", maxSeedFileSize),
+				zap.Int64("max_total_size", maxSeedTotalSize),
+			)
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		total += info.Size()
+		files = append(files, newSeedFile(rel, data, info.Mode().Perm()))
+	}
+	return files, nil
+}
+
+// readSeedManifest는 작업 공간에 기록된 시드 파일 경로를 정렬하여 반환합니다.
+func readSeedManifest(ws *taskrunner.Workspace) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(ws.OpenCodeDir, seedManifestName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return nil, fmt.Errorf("invalid seed file manifest: %w", err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// recordSeedFiles는 가져온 시드 파일 경로를 작업 공간의 시드 파일 목록에 추가합니다.
+func recordSeedFiles(ws *taskrunner.Workspace, files []AgentSeedFile) error {
+	paths, err := readSeedManifest(ws)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if !slices.Contains(paths, f.Path) {
+			paths = append(paths, f.Path)
+		}
+	}
+	sort.Strings(paths)
+	data, err := json.MarshalIndent(paths, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(ws.OpenCodeDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(ws.OpenCodeDir, seedManifestName), data, 0644)
+}
+
+// PlanAgentImport는 번들을 현재 에이전트와 비교하여 적용할 변경 사항을 계산합니다.
+// 이미 존재하는 에이전트가 달라졌을 때 upsert가 false이면 conflict로 표시합니다.
+func (c *Controller) PlanAgentImport(ctx context.Context, bundle *AgentBundle, upsert bool) (*AgentImportPlan, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+	if err := bundle.Validate(); err != nil {
+		return nil, err
+	}
+
+	plan := &AgentImportPlan{}
+	for _, spec := range bundle.Agents {
+		tenant, err := importTenant(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		spec.Tenant = tenant
+		rec, err := c.repo.GetAgent(ctx, spec.AgentID())
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		var current AgentSpec
+		if rec != nil && err == nil {
+			if current, err = c.currentAgentSpec(rec); err != nil {
+				return nil, fmt.Errorf("failed to read agent %s: %w", spec.Name, err)
+			}
+		}
+		if spec.MCPServers, err = resolveRedactedSecrets(spec.MCPServers, current.MCPServers); err != nil {
+			return nil, fmt.Errorf("agent %q: %w", spec.Name, err)
+		}
+		item := AgentImportItem{Spec: spec}
+
+		switch {
+		case current.Name == "":
+			item.Action = AgentImportCreate
+			item.Changes = diffAgentSpec(AgentSpec{}, spec)
+		default:
+			if rec.Status != storage.AgentStatusActive {
+				item.Changes = append(item.Changes, AgentImportChange{Field: "status", Old: rec.Status, New: storage.AgentStatusActive})
+			}
+			item.Changes = append(item.Changes, diffAgentSpec(current, spec)...)
+		}
+		item.Changes = append(item.Changes, c.diffSeedFiles(spec)...)
+
+		if item.Action == "" {
+			switch {
+			case len(item.Changes) == 0:
+				item.Action = AgentImportUnchanged
+			case upsert:
+				item.Action = AgentImportUpdate
+			default:
+				item.Action = AgentImportConflict
+			}
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	return plan, nil
+}
+
+// importTenant는 선언을 가져올 테넌트를 반환합니다.
+// 테넌트 범위의 context에서는 그 테넌트로만 가져올 수 있습니다.
+func importTenant(ctx context.Context, spec AgentSpec) (string, error) {
+	tenant := spec.Tenant
+	if tenant == storage.DefaultTenantID {
+		tenant = ""
+	}
+	scoped := TenantFromContext(ctx)
+	switch {
+	case scoped == "":
+		return tenant, nil
+	case tenant == "" || tenant == scoped:
+		return scoped, nil
+	default:
+		return "", fmt.Errorf("%w: cannot import agent %s into another tenant", ErrPermissionDenied, spec.Name)
+	}
+}
+
+// diffAgentSpec은 시드 파일을 제외한 에이전트 필드와 MCP 서버 변경을 계산합니다.
+func diffAgentSpec(cur, next AgentSpec) []AgentImportChange {
+	var changes []AgentImportChange
+	for _, f := range []struct{ field, old, new string }{
+		{"description", cur.Description, next.Description},
+		{"provider", cur.Provider, next.Provider},
+		{"model", cur.Model, next.Model},
+		{"prompt", cur.Prompt, next.Prompt},
+		{"image", cur.Image, next.Image},
+	} {
+		if f.old != f.new {
+			changes = append(changes, AgentImportChange{Field: f.field, Old: f.old, New: f.new})
+		}
+	}
+
+	// cur가 비어 있으면 새로 생성하는 에이전트
+	oldPermission, newPermission := "", permissionString(next.Permission)
+	if cur.Name != "" {
+		oldPermission = permissionString(cur.Permission)
+	}
+	if oldPermission != newPermission {
+		changes = append(changes, AgentImportChange{Field: "permission", Old: oldPermission, New: newPermission})
+	}
+
+	names := make([]string, 0, len(cur.MCPServers)+len(next.MCPServers))
+	for name := range cur.MCPServers {
+		names = append(names, name)
+	}
+	for name := range next.MCPServers {
+		if _, ok := cur.MCPServers[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		oldServer, hadOld := cur.MCPServers[name]
+		newServer, hasNew := next.MCPServers[name]
+		if hadOld && hasNew && mcpServerEqual(oldServer, newServer) {
+			continue
+		}
+		change := AgentImportChange{Field: "mcp:" + name}
+		if hadOld {
+			change.Old = mcpServerString(oldServer)
+		}
+		if hasNew {
+			change.New = mcpServerString(newServer)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// diffSeedFiles는 번들의 시드 파일을 작업 공간의 파일과 비교합니다. 번들에 없는 작업 공간 파일은 그대로 둡니다.
+func (c *Controller) diffSeedFiles(spec AgentSpec) []AgentImportChange {
+	basePath := ""
+	if ws, err := c.workspaceManager().GetWorkspace(spec.AgentID()); err == nil {
+		basePath = ws.BasePath
+	}
+
+	var changes []AgentImportChange
+	for _, f := range spec.Files {
+		data, _ := f.data()
+		perm, _ := f.perm()
+		change := AgentImportChange{Field: "file:" + f.Path, New: seedFileString(len(data), perm)}
+
+		if basePath != "" {
+			target := filepath.Join(basePath, filepath.FromSlash(f.Path))
+			if info, err := os.Stat(target); err == nil {
+				existing, err := os.ReadFile(target)
+				if err == nil && bytes.Equal(existing, data) && info.Mode().Perm() == perm {
+					continue
+				}
+				change.Old = seedFileString(int(info.Size()), info.Mode().Perm())
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func permissionString(p *AgentPermissionSpec) string {
+	timeout, onTimeout := p.effective()
+	return fmt.Sprintf("timeout=%s on_timeout=%s", timeout, onTimeout)
+}
+
+// mcpServerEqual은 비어 있는 Args, Env와 생략된 값을 같게 취급하여 비교합니다.
+func mcpServerEqual(a, b taskrunner.MCPServer) bool {
+	return a.Command == b.Command && a.Enabled == b.Enabled &&
+		slices.Equal(a.Args, b.Args) && maps.Equal(a.Env, b.Env)
+}
+
+func mcpServerString(s taskrunner.MCPServer) string {
+	out := strings.TrimSpace(s.Command + " " + strings.Join(s.Args, " "))
+	if len(s.Env) > 0 {
+		keys := make([]string, 0, len(s.Env))
+		for k := range s.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out += fmt.Sprintf(" (env: %s)", strings.Join(keys, ", "))
+	}
+	if !s.Enabled {
+		out += " (disabled)"
+	}
+	return out
+}
+
+func seedFileString(size int, perm os.FileMode) string {
+	return fmt.Sprintf("%d bytes, %04o", size, perm)
+}
+
+// ApplyAgentImport는 가져오기 계획의 create, update 항목을 적용합니다.
+// conflict 항목이 있으면 아무것도 변경하지 않고 오류를 반환합니다.
+func (c *Controller) ApplyAgentImport(ctx context.Context, plan *AgentImportPlan) error {
+	if plan.HasConflicts() {
+		return fmt.Errorf("agent import has conflicts with existing agents; use upsert to update them")
+	}
+
+	applied := 0
+	for _, item := range plan.Items {
+		if item.Action != AgentImportCreate && item.Action != AgentImportUpdate {
+			continue
+		}
+		if err := c.applyAgentSpec(ctx, item); err != nil {
+			return fmt.Errorf("failed to import agent %s: %w", item.Spec.Name, err)
+		}
+		applied++
+	}
+
+	c.logger.Info("Applied agent import", zap.Int("applied", applied), zap.Int("total", len(plan.Items)))
+	return nil
+}
+
+// applyAgentSpec은 선언 하나를 적용합니다. 저장소와 작업 공간은 선언의 이름이 아닌 에이전트 식별자로 접근합니다.
+func (c *Controller) applyAgentSpec(ctx context.Context, item AgentImportItem) error {
+	spec := item.Spec
+	if item.Action == AgentImportCreate {
+		if err := c.CreateAgent(WithTenant(ctx, spec.Tenant), spec.Name, spec.Description, spec.Provider, spec.Model, spec.Prompt); err != nil {
+			return err
+		}
+	}
+	rec, err := c.getAgent(ctx, spec.AgentID())
+	if err != nil {
+		return err
+	}
+	agentID := rec.AgentID
+	if item.Action != AgentImportCreate {
+		if err := c.UpdateAgent(ctx, agentID, spec.Description, spec.Provider, spec.Model, spec.Prompt); err != nil {
+			return err
+		}
+		before := c.agentAuditSnapshot(ctx, agentID)
+		if err := c.repo.UpsertAgentStatus(ctx, agentID, storage.AgentStatusActive); err != nil {
+			return err
+		}
+		c.audit(ctx, AuditAgentUpdate, AuditTargetAgent, agentID, before, c.agentAuditSnapshot(ctx, agentID))
+	}
+	if err := c.SetAgentImage(ctx, agentID, spec.Image); err != nil {
+		return err
+	}
+	timeout, onTimeout := spec.Permission.effective()
+	if err := c.SetAgentPermissionPolicy(ctx, agentID, timeout, onTimeout); err != nil {
+		return err
+	}
+
+	var mcpChanged, filesChanged bool
+	for _, change := range item.Changes {
+		mcpChanged = mcpChanged || strings.HasPrefix(change.Field, "mcp:")
+		filesChanged = filesChanged || strings.HasPrefix(change.Field, "file:")
+	}
+	if !mcpChanged && !filesChanged {
+		return nil
+	}
+
+	ws, err := c.workspaceManager().CreateWorkspace(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	if mcpChanged {
+		servers := spec.MCPServers
+		if servers == nil {
+			servers = make(map[string]taskrunner.MCPServer)
+		}
+		settings := taskrunner.NewSettingsManager(c.workspaceManager(), c.logger)
+		if err := settings.UpdateMCPConfig(agentID, &taskrunner.MCPConfig{MCPServers: servers}); err != nil {
+			return err
+		}
+	}
+	for _, f := range spec.Files {
+		data, err := f.data()
+		if err != nil {
+			return err
+		}
+		perm, err := f.perm()
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(ws.BasePath, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", f.Path, err)
+		}
+		if err := os.WriteFile(target, data, perm); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+		// 기존 파일은 WriteFile이 권한을 바꾸지 않음
+		if err := os.Chmod(target, perm); err != nil {
+			return fmt.Errorf("failed to set mode of %s: %w", f.Path, err)
+		}
+	}
+	if len(spec.Files) > 0 {
+		if err := recordSeedFiles(ws, spec.Files); err != nil {
+			return fmt.Errorf("failed to record seed files: %w", err)
+		}
+	}
+
+	before, after := make(map[string]string), make(map[string]string)
+	for _, change := range item.Changes {
+		if strings.HasPrefix(change.Field, "mcp:") || strings.HasPrefix(change.Field, "file:") {
+			before[change.Field], after[change.Field] = change.Old, change.New
+		}
+	}
+	c.audit(ctx, AuditAgentUpdateWorkspace, AuditTargetAgent, agentID, before, after)
+	return nil
+}
@@ -0,0 +1,235 @@
+package controller_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	taskrunner "github.com/cnap-oss/app/internal/runner"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newBundleController는 별도 DB와 작업 공간 디렉토리를 사용하는 Controller를 생성합니다.
+func newBundleController(t *testing.T, name string, opts ...controller.Option) (*controller.Controller, taskrunner.WorkspaceManager) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	logger := zaptest.NewLogger(t)
+	wm := taskrunner.NewWorkspaceManager(logger, taskrunner.WorkspaceConfig{BaseDir: t.TempDir()})
+	ctrl := controller.NewController(logger, repo, make(chan controller.ConnectorEvent, 10), make(chan controller.ControllerEvent, 10),
+		append([]controller.Option{controller.WithWorkspaceManager(wm)}, opts...)...)
+	return ctrl, wm
+}
+
+func TestAgentBundleRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src, srcWM := newBundleController(t, "agent_bundle_src")
+
+	// 시드 파일은 가져오기로 작업 공간에 기록된 파일만 내보냄
+	seed := &controller.AgentBundle{APIVersion: controller.AgentBundleAPIVersion, Kind: controller.AgentBundleKind, Agents: []controller.AgentSpec{{
+		Name:        "bundle-agent",
+		Description: "Bundle agent",
+		Provider:    "opencode",
+		Model:       "gpt-4",
+		Prompt:      "You are helpful.\nBe brief.",
+		Image:       "ghcr.io/example/runner:1",
+		Permission:  &controller.AgentPermissionSpec{TimeoutSeconds: 120, OnTimeout: storage.PermissionResponseOnce},
+		MCPServers: map[string]taskrunner.MCPServer{
+			"github": {Command: "npx", Args: []string{"-y", "github-mcp"}, Env: map[string]string{"GITHUB_TOKEN": "ghp_secret"}, Enabled: true},
+		},
+		Files: []controller.AgentSeedFile{
+			{Path: "project/README.md", Content: "# 프로젝트\n"},
+			{Path: "run.sh", Content: "#!/bin/sh\n", Mode: "0755"},
+			{Path: "blob.bin", Content: "/wD+", Encoding: controller.SeedFileEncodingBase64},
+			{Path: "data/large.bin", Content: "small"},
+		},
+	}}}
+	plan, err := src.PlanAgentImport(ctx, seed, false)
+	require.NoError(t, err)
+	require.NoError(t, src.ApplyAgentImport(ctx, plan))
+
+	// 실행 중에 생긴 산출물과 크기 제한을 넘는 파일은 건너뜀
+	ws, err := srcWM.GetWorkspace("bundle-agent")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(ws.ProjectDir, "output.txt"), []byte("artifact"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(ws.BasePath, "data", "large.bin"), make([]byte, 1<<20+1), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(ws.LogDir, "container.log"), []byte("log"), 0644))
+
+	// 비밀 값은 기본적으로 제외하고 내보냄
+	redacted, err := src.ExportAgents(ctx, nil, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"GITHUB_TOKEN": controller.RedactedSecret}, redacted.Agents[0].MCPServers["github"].Env)
+
+	bundle, err := src.ExportAgents(ctx, nil, true, true)
+	require.NoError(t, err)
+	data, err := bundle.Marshal()
+	require.NoError(t, err)
+
+	parsed, err := controller.ParseAgentBundle(data)
+	require.NoError(t, err)
+	require.Len(t, parsed.Agents, 1)
+	spec := parsed.Agents[0]
+	assert.Equal(t, "You are helpful.\nBe brief.", spec.Prompt)
+	assert.Equal(t, "ghcr.io/example/runner:1", spec.Image)
+	assert.Equal(t, &controller.AgentPermissionSpec{TimeoutSeconds: 120, OnTimeout: storage.PermissionResponseOnce}, spec.Permission)
+	assert.Equal(t, []string{"-y", "github-mcp"}, spec.MCPServers["github"].Args)
+
+	paths := make([]string, 0, len(spec.Files))
+	for _, f := range spec.Files {
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, []string{"blob.bin", "project/README.md", "run.sh"}, paths)
+	assert.Equal(t, controller.SeedFileEncodingBase64, spec.Files[0].Encoding)
+	assert.Equal(t, "0755", spec.Files[2].Mode)
+
+	// 다른 배포 환경으로 가져오기 (현재 값이 없는 비밀 값은 적용할 수 없음)
+	dst, dstWM := newBundleController(t, "agent_bundle_dst")
+	_, err = dst.PlanAgentImport(ctx, redacted, false)
+	assert.ErrorContains(t, err, "GITHUB_TOKEN")
+
+	plan, err = dst.PlanAgentImport(ctx, parsed, false)
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	assert.Equal(t, controller.AgentImportCreate, plan.Items[0].Action)
+	require.NoError(t, dst.ApplyAgentImport(ctx, plan))
+
+	info, err := dst.GetAgentInfo(ctx, "bundle-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4", info.Model)
+	assert.Equal(t, 2*time.Minute, info.PermissionTimeout)
+	assert.Equal(t, storage.PermissionResponseOnce, info.PermissionOnTimeout)
+
+	dstWS, err := dstWM.GetWorkspace("bundle-agent")
+	require.NoError(t, err)
+	blob, err := os.ReadFile(filepath.Join(dstWS.BasePath, "blob.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0x00, 0xfe}, blob)
+	st, err := os.Stat(filepath.Join(dstWS.BasePath, "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), st.Mode().Perm())
+	mcp, err := taskrunner.NewSettingsManager(dstWM, nil).GetMCPConfig("bundle-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "npx", mcp.MCPServers["github"].Command)
+	assert.Equal(t, "ghp_secret", mcp.MCPServers["github"].Env["GITHUB_TOKEN"])
+
+	// 같은 번들이나 비밀 값을 제외한 번들을 다시 가져오면 변경 없음
+	for _, b := range []*controller.AgentBundle{parsed, redacted} {
+		plan, err = dst.PlanAgentImport(ctx, b, false)
+		require.NoError(t, err)
+		assert.Equal(t, controller.AgentImportUnchanged, plan.Items[0].Action)
+		assert.Empty(t, plan.Items[0].Changes)
+	}
+
+	// 달라진 번들은 upsert 없이 충돌
+	parsed.Agents[0].Model = "gpt-4o"
+	parsed.Agents[0].MCPServers = nil
+	plan, err = dst.PlanAgentImport(ctx, parsed, false)
+	require.NoError(t, err)
+	assert.Equal(t, controller.AgentImportConflict, plan.Items[0].Action)
+	assert.Error(t, dst.ApplyAgentImport(ctx, plan))
+
+	plan, err = dst.PlanAgentImport(ctx, parsed, true)
+	require.NoError(t, err)
+	assert.Equal(t, controller.AgentImportUpdate, plan.Items[0].Action)
+	assert.Equal(t, []controller.AgentImportChange{
+		{Field: "model", Old: "gpt-4", New: "gpt-4o"},
+		{Field: "mcp:github", Old: "npx -y github-mcp (env: GITHUB_TOKEN)"},
+	}, plan.Items[0].Changes)
+	require.NoError(t, dst.ApplyAgentImport(ctx, plan))
+
+	info, err = dst.GetAgentInfo(ctx, "bundle-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o", info.Model)
+	mcp, err = taskrunner.NewSettingsManager(dstWM, nil).GetMCPConfig("bundle-agent")
+	require.NoError(t, err)
+	assert.Empty(t, mcp.MCPServers)
+}
+
+func TestAgentBundleTenantRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src, _ := newBundleController(t, "agent_bundle_tenant_src")
+	require.NoError(t, src.CreateAgent(ctx, "shared", "default agent", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, src.CreateAgent(controller.WithTenant(ctx, "guild-1"), "shared", "guild agent", "opencode", "gpt-4", "prompt"))
+
+	// 다른 테넌트의 같은 이름 에이전트는 식별자가 아닌 이름과 테넌트로 내보냄
+	bundle, err := src.ExportAgents(ctx, nil, false, true)
+	require.NoError(t, err)
+	require.Len(t, bundle.Agents, 2)
+	tenants := map[string]string{}
+	for _, spec := range bundle.Agents {
+		assert.Equal(t, "shared", spec.Name)
+		tenants[spec.Tenant] = spec.Description
+	}
+	assert.Equal(t, map[string]string{"": "default agent", "guild-1": "guild agent"}, tenants)
+
+	for i := range bundle.Agents {
+		bundle.Agents[i].MCPServers = map[string]taskrunner.MCPServer{"fs": {Command: "mcp-fs", Enabled: true}}
+	}
+	data, err := bundle.Marshal()
+	require.NoError(t, err)
+	parsed, err := controller.ParseAgentBundle(data)
+	require.NoError(t, err)
+
+	dst, dstWM := newBundleController(t, "agent_bundle_tenant_dst")
+	plan, err := dst.PlanAgentImport(ctx, parsed, false)
+	require.NoError(t, err)
+	require.NoError(t, dst.ApplyAgentImport(ctx, plan))
+
+	guildID := storage.AgentKey("guild-1", "shared")
+	info, err := dst.GetAgentInfo(ctx, guildID)
+	require.NoError(t, err)
+	assert.Equal(t, "shared", info.Name)
+	assert.Equal(t, "guild-1", info.Tenant)
+	assert.Equal(t, "guild agent", info.Description)
+	mcp, err := taskrunner.NewSettingsManager(dstWM, nil).GetMCPConfig(guildID)
+	require.NoError(t, err)
+	assert.Contains(t, mcp.MCPServers, "fs")
+
+	info, err = dst.GetAgentInfo(ctx, "shared")
+	require.NoError(t, err)
+	assert.Equal(t, storage.DefaultTenantID, info.Tenant)
+	assert.Equal(t, "default agent", info.Description)
+
+	// 다시 가져오면 변경 사항 없음
+	plan, err = dst.PlanAgentImport(ctx, parsed, false)
+	require.NoError(t, err)
+	for _, item := range plan.Items {
+		assert.Equal(t, controller.AgentImportUnchanged, item.Action, item.Spec.Tenant)
+	}
+}
+
+func TestParseAgentBundleValidation(t *testing.T) {
+	valid := "apiVersion: cnap/v1\nkind: AgentBundle\nagents:\n  - name: a\n    model: gpt-4\n"
+	_, err := controller.ParseAgentBundle([]byte(valid))
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"version":       "apiVersion: cnap/v2\nkind: AgentBundle\nagents:\n  - name: a\n",
+		"unknown field": valid + "    color: blue\n",
+		"no agents":     "apiVersion: cnap/v1\nkind: AgentBundle\nagents: []\n",
+		"duplicate":     valid + "  - name: a\n",
+		"on timeout":    valid + "    permission:\n      onTimeout: always\n",
+		"mcp command":   valid + "    mcpServers:\n      github: {}\n",
+		"escape":        valid + "    files:\n      - path: ../etc/passwd\n        content: x\n",
+		"absolute":      valid + "    files:\n      - path: /etc/passwd\n        content: x\n",
+		"runner dir":    valid + "    files:\n      - path: logs/x.log\n        content: x\n",
+		"base64":        valid + "    files:\n      - path: a.bin\n        content: '!!'\n        encoding: base64\n",
+		"mode":          valid + "    files:\n      - path: a.sh\n        content: x\n        mode: '999'\n",
+	}
+	for name, doc := range tests {
+		_, err := controller.ParseAgentBundle([]byte(doc))
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// ErrAgentManaged는 설정 파일로 관리되는 에이전트를 설정 반영 외의 경로로 수정하거나 삭제하려 할 때 반환됩니다.
+var ErrAgentManaged = errors.New("agent is managed by config file")
+
+// agentConfigApplyKey는 선언형 설정을 반영하는 중임을 context에 표시하는 키입니다.
+type agentConfigApplyKey struct{}
+
+// rejectManagedAgent는 설정 파일로 관리되는 에이전트이면 ErrAgentManaged를 반환합니다.
+// 설정 디렉토리를 반영하는 ReconcileAgentConfig만 관리되는 에이전트를 바꿀 수 있습니다.
+func rejectManagedAgent(ctx context.Context, rec *storage.Agent) error {
+	if rec == nil || rec.ConfigSource == "" || ctx.Value(agentConfigApplyKey{}) != nil {
+		return nil
+	}
+	return fmt.Errorf("%w: agent %s is declared in %s", ErrAgentManaged, rec.AgentID, rec.ConfigSource)
+}
+
+// getEditableAgent는 ctx의 사용자가 수정할 수 있고 설정 파일로 관리되지 않는 에이전트를 조회합니다.
+func (c *Controller) getEditableAgent(ctx context.Context, agentID string) (*storage.Agent, error) {
+	rec, err := c.getWritableAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := rejectManagedAgent(ctx, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// agentConfigPollInterval은 선언형 에이전트 설정 디렉토리의 변경을 확인하는 주기입니다.
+const agentConfigPollInterval = 10 * time.Second
+
+// AgentConfigPlan은 설정 디렉토리를 DB에 반영했을 때 일어날 변경 사항입니다.
+type AgentConfigPlan struct {
+	AgentImportPlan
+
+	// Sources는 에이전트 식별자(AgentSpec.AgentID)별 선언 파일 이름입니다.
+	Sources map[string]string
+	// Released는 설정 파일로 관리되다가 선언이 사라진 에이전트입니다.
+	// 삭제하지 않고 관리 대상에서만 제외하여 다시 Discord에서 수정할 수 있게 합니다.
+	Released []string
+}
+
+// WithAgentConfigDir은 선언형 에이전트 설정 디렉토리를 지정합니다.
+// 지정하면 Start에서 디렉토리의 에이전트를 DB에 반영하고, 이후 파일이 바뀔 때마다 다시 반영합니다.
+func WithAgentConfigDir(dir string) Option {
+	return func(c *Controller) {
+		c.agentConfigDir = dir
+	}
+}
+
+// agentConfigFiles는 디렉토리의 *.yaml, *.yml 파일 이름을 정렬하여 반환합니다.
+func agentConfigFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.Type().IsRegular() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadAgentConfigDir은 디렉토리의 번들 파일을 모두 읽어 하나의 번들로 합칩니다.
+// 반환하는 map은 에이전트 식별자(AgentSpec.AgentID)별 선언 파일 이름이며, 같은 에이전트를 여러 파일에서 선언하면 오류입니다.
+func LoadAgentConfigDir(dir string) (*AgentBundle, map[string]string, error) {
+	names, err := agentConfigFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read agent config dir: %w", err)
+	}
+
+	merged := &AgentBundle{APIVersion: AgentBundleAPIVersion, Kind: AgentBundleKind}
+	sources := make(map[string]string)
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read agent config %s: %w", name, err)
+		}
+		bundle, err := ParseAgentBundle(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, spec := range bundle.Agents {
+			if prev, ok := sources[spec.AgentID()]; ok {
+				return nil, nil, fmt.Errorf("agent %q is declared in both %s and %s", spec.Name, prev, name)
+			}
+			sources[spec.AgentID()] = name
+			merged.Agents = append(merged.Agents, spec)
+		}
+	}
+	return merged, sources, nil
+}
+
+// PlanAgentConfig는 설정 디렉토리와 DB를 비교합니다. 설정 파일이 기준이므로 기존 에이전트는 항상 갱신 대상입니다.
+func (c *Controller) PlanAgentConfig(ctx context.Context, dir string) (*AgentConfigPlan, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+
+	bundle, sources, err := LoadAgentConfigDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &AgentConfigPlan{Sources: sources}
+	if len(bundle.Agents) > 0 {
+		imports, err := c.PlanAgentImport(ctx, bundle, true)
+		if err != nil {
+			return nil, err
+		}
+		plan.AgentImportPlan = *imports
+	}
+
+	records, err := c.repo.ListAgents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]string, len(records))
+	for _, rec := range records {
+		current[rec.AgentID] = rec.ConfigSource
+		if _, declared := sources[rec.AgentID]; !declared && rec.ConfigSource != "" {
+			plan.Released = append(plan.Released, rec.AgentID)
+		}
+	}
+
+	for i := range plan.Items {
+		item := &plan.Items[i]
+		agentID := item.Spec.AgentID()
+		source := sources[agentID]
+		if item.Action == AgentImportCreate || current[agentID] == source {
+			continue
+		}
+		item.Changes = append(item.Changes, AgentImportChange{Field: "config_source", Old: current[agentID], New: source})
+		item.Action = AgentImportUpdate
+	}
+	return plan, nil
+}
+
+// ReconcileAgentConfig는 WithAgentConfigDir로 지정한 디렉토리의 선언을 DB와 작업 공간에 반영합니다.
+// 파일과 다른 기존 에이전트(드리프트)는 경고로 남긴 뒤 파일 내용으로 덮어씁니다.
+func (c *Controller) ReconcileAgentConfig(ctx context.Context) error {
+	if c.agentConfigDir == "" {
+		return nil
+	}
+	if ActorFromContext(ctx) == "" {
+		ctx = WithActor(ctx, ConnectorActor(ActorConnectorSystem, "agent-config"))
+	}
+	ctx = context.WithValue(ctx, agentConfigApplyKey{}, true)
+
+	plan, err := c.PlanAgentConfig(ctx, c.agentConfigDir)
+	if err != nil {
+		return fmt.Errorf("failed to plan agent config: %w", err)
+	}
+
+	for _, item := range plan.Items {
+		if item.Action != AgentImportUpdate {
+			continue
+		}
+		fields := make([]string, 0, len(item.Changes))
+		for _, change := range item.Changes {
+			fields = append(fields, change.Field)
+		}
+		c.logger.Warn("Agent differs from config file, applying file",
+			zap.String("agent", item.Spec.Name),
+			zap.String("file", plan.Sources[item.Spec.AgentID()]),
+			zap.Strings("fields", fields),
+		)
+	}
+
+	if err := c.ApplyAgentImport(ctx, &plan.AgentImportPlan); err != nil {
+		return err
+	}
+	for _, item := range plan.Items {
+		if item.Action != AgentImportCreate && item.Action != AgentImportUpdate {
+			continue
+		}
+		if err := c.setAgentConfigSource(ctx, item.Spec.AgentID(), plan.Sources[item.Spec.AgentID()]); err != nil {
+			return fmt.Errorf("failed to mark agent %s as managed: %w", item.Spec.Name, err)
+		}
+	}
+	for _, name := range plan.Released {
+		c.logger.Warn("Agent is no longer declared in config dir, releasing it from file management",
+			zap.String("agent", name),
+		)
+		if err := c.setAgentConfigSource(ctx, name, ""); err != nil {
+			return fmt.Errorf("failed to release agent %s: %w", name, err)
+		}
+	}
+
+	c.logger.Info("Reconciled agent config",
+		zap.String("dir", c.agentConfigDir),
+		zap.Int("agents", len(plan.Items)),
+		zap.Int("released", len(plan.Released)),
+	)
+	return nil
+}
+
+// setAgentConfigSource는 에이전트를 관리하는 설정 파일 이름을 기록합니다. 비어 있으면 관리 대상에서 제외합니다.
+func (c *Controller) setAgentConfigSource(ctx context.Context, agentID, source string) error {
+	before := c.agentAuditSnapshot(ctx, agentID)
+	if err := c.repo.SetAgentConfigSource(ctx, agentID, source); err != nil {
+		return err
+	}
+	c.audit(ctx, AuditAgentUpdate, AuditTargetAgent, agentID, before, c.agentAuditSnapshot(ctx, agentID))
+	return nil
+}
+
+// watchAgentConfig는 설정 디렉토리를 주기적으로 확인하여 파일이 바뀌면 다시 반영합니다.
+func (c *Controller) watchAgentConfig(ctx context.Context, last string) {
+	ticker := time.NewTicker(agentConfigPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fingerprint, err := agentConfigFingerprint(c.agentConfigDir)
+			if err != nil {
+				c.logger.Warn("Failed to check agent config dir", zap.Error(err))
+				continue
+			}
+			if fingerprint == last {
+				continue
+			}
+			c.logger.Info("Agent config changed, reconciling", zap.String("dir", c.agentConfigDir))
+			if err := c.ReconcileAgentConfig(ctx); err != nil {
+				// 잘못된 파일은 다음 변경 때까지 건너뜀
+				c.logger.Error("Failed to reconcile agent config", zap.Error(err))
+			}
+			last = fingerprint
+		}
+	}
+}
+
+// agentConfigFingerprint는 설정 파일의 이름, 크기, 수정 시각으로 변경 여부를 판단할 값을 만듭니다.
+func agentConfigFingerprint(dir string) (string, error) {
+	names, err := agentConfigFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
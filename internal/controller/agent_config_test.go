@@ -0,0 +1,99 @@
+package controller_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestReconcileAgentConfig(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeConfig := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	writeConfig("support.yaml", `apiVersion: cnap/v1
+kind: AgentBundle
+agents:
+  - name: support
+    provider: opencode
+    model: gpt-4
+    prompt: 고객 문의에 답합니다.
+`)
+	writeConfig("README.md", "yaml 파일만 읽음")
+
+	ctrl, _ := newBundleController(t, "agent_config_reconcile", controller.WithAgentConfigDir(dir))
+	require.NoError(t, ctrl.CreateAgent(ctx, "manual", "", "opencode", "gpt-4", ""))
+
+	plan, err := ctrl.PlanAgentConfig(ctx, dir)
+	require.NoError(t, err)
+	require.Len(t, plan.Items, 1)
+	assert.Equal(t, controller.AgentImportCreate, plan.Items[0].Action)
+	assert.Equal(t, "support.yaml", plan.Sources["support"])
+	assert.Empty(t, plan.Released)
+
+	require.NoError(t, ctrl.ReconcileAgentConfig(ctx))
+
+	info, err := ctrl.GetAgentInfo(ctx, "support")
+	require.NoError(t, err)
+	assert.Equal(t, "support.yaml", info.ConfigSource)
+	manual, err := ctrl.GetAgentInfo(ctx, "manual")
+	require.NoError(t, err)
+	assert.Empty(t, manual.ConfigSource)
+
+	// 반영 후에는 변경 없음
+	plan, err = ctrl.PlanAgentConfig(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, controller.AgentImportUnchanged, plan.Items[0].Action)
+
+	// 설정 파일로 관리되는 Agent는 Controller API로 수정하거나 삭제할 수 없음
+	assert.ErrorIs(t, ctrl.UpdateAgent(ctx, "support", "", "opencode", "gpt-4o", "고객 문의에 답합니다."), controller.ErrAgentManaged)
+	assert.ErrorIs(t, ctrl.SetAgentImage(ctx, "support", "ghcr.io/example/runner:2"), controller.ErrAgentManaged)
+	assert.ErrorIs(t, ctrl.DeleteAgent(ctx, "support"), controller.ErrAgentManaged)
+	assert.ErrorIs(t, ctrl.DeleteAgent(controller.WithRole(controller.WithTenant(ctx, storage.DefaultTenantID), storage.RoleAdmin), "support"), controller.ErrAgentManaged)
+
+	// DB에서 직접 바꾸면 드리프트로 표시되고, 반영하면 파일 내용으로 되돌림
+	db, err := gorm.Open(sqlite.Open("file:agent_config_reconcile?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&storage.Agent{}).Where("agent_id = ?", "support").Update("model", "gpt-4o").Error)
+	plan, err = ctrl.PlanAgentConfig(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, controller.AgentImportUpdate, plan.Items[0].Action)
+	assert.Equal(t, []controller.AgentImportChange{{Field: "model", Old: "gpt-4o", New: "gpt-4"}}, plan.Items[0].Changes)
+	require.NoError(t, ctrl.ReconcileAgentConfig(ctx))
+	info, err = ctrl.GetAgentInfo(ctx, "support")
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4", info.Model)
+
+	// 선언이 사라지면 삭제하지 않고 관리 대상에서만 제외
+	require.NoError(t, os.Remove(filepath.Join(dir, "support.yaml")))
+	plan, err = ctrl.PlanAgentConfig(ctx, dir)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Items)
+	assert.Equal(t, []string{"support"}, plan.Released)
+	require.NoError(t, ctrl.ReconcileAgentConfig(ctx))
+	info, err = ctrl.GetAgentInfo(ctx, "support")
+	require.NoError(t, err)
+	assert.Empty(t, info.ConfigSource)
+	assert.Equal(t, "active", info.Status)
+}
+
+func TestLoadAgentConfigDirDuplicate(t *testing.T) {
+	dir := t.TempDir()
+	doc := "apiVersion: cnap/v1\nkind: AgentBundle\nagents:\n  - name: dup\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte(doc), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte(doc), 0644))
+
+	_, _, err := controller.LoadAgentConfigDir(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a.yaml")
+	assert.Contains(t, err.Error(), "b.yml")
+}
@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// 감사 기록 대상 종류
+const (
+	AuditTargetAgent  = "agent"
+	AuditTargetTask   = "task"
+	AuditTargetUser   = "user"
+	AuditTargetTenant = "tenant"
+	AuditTargetSystem = "system"
+)
+
+// 감사 기록 동작 ({대상 종류}.{동작})
+const (
+	AuditAgentCreate          = "agent.create"
+	AuditAgentUpdate          = "agent.update"
+	AuditAgentDelete          = "agent.delete"
+	AuditAgentSetImage        = "agent.set_image"
+	AuditAgentSetPermission   = "agent.set_permission_policy"
+	AuditAgentSetRetention    = "agent.set_retention"
+	AuditAgentSetVisibility   = "agent.set_visibility"
+	AuditAgentTransfer        = "agent.transfer"
+	AuditAgentUpdateWorkspace = "agent.update_workspace"
+	AuditAgentDeleteWorkspace = "agent.delete_workspace"
+
+	AuditTaskCreate            = "task.create"
+	AuditTaskExecute           = "task.execute"
+	AuditTaskAddMessage        = "task.add_message"
+	AuditTaskUpdateStatus      = "task.update_status"
+	AuditTaskCancel            = "task.cancel"
+	AuditTaskComplete          = "task.complete"
+	AuditTaskDelete            = "task.delete"
+	AuditTaskPurge             = "task.purge"
+	AuditTaskRespondPermission = "task.respond_permission"
+
+	AuditUserDeleteData = "user.delete_data"
+
+	AuditTenantSetRole    = "tenant.set_role"
+	AuditTenantRemoveRole = "tenant.remove_role"
+
+	AuditSystemReindexSearch = "system.reindex_search"
+)
+
+// AuditChange는 감사 기록의 필드별 변경 전후 값입니다.
+type AuditChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// taskAuditState는 감사 기록에 남기는 Task 상태입니다.
+type taskAuditState struct {
+	AgentID string `json:"agent_id"`
+	Status  string `json:"status"`
+}
+
+// agentAuditState는 감사 기록에 남기는 에이전트 설정입니다.
+type agentAuditState struct {
+	Description                string `json:"description"`
+	Provider                   string `json:"provider"`
+	Model                      string `json:"model"`
+	Prompt                     string `json:"prompt"`
+	Image                      string `json:"image"`
+	Status                     string `json:"status"`
+	PermissionTimeoutSec       int    `json:"permission_timeout_sec"`
+	PermissionOnTimeout        string `json:"permission_on_timeout"`
+	RetentionCompletedDays     int    `json:"retention_completed_days"`
+	RetentionFailedDays        int    `json:"retention_failed_days"`
+	RetentionWorkspaceIdleDays int    `json:"retention_workspace_idle_days"`
+	ConfigSource               string `json:"config_source,omitempty"`
+	TenantID                   string `json:"tenant_id"`
+	Owner                      string `json:"owner"`
+	Visibility                 string `json:"visibility"`
+}
+
+// agentAuditSnapshot은 에이전트의 현재 설정을 조회합니다. 조회하지 못하면 nil입니다.
+func (c *Controller) agentAuditSnapshot(ctx context.Context, agentID string) *agentAuditState {
+	rec, err := c.repo.GetAgent(ctx, agentID)
+	if err != nil {
+		return nil
+	}
+	return &agentAuditState{
+		Description:                rec.Description,
+		Provider:                   rec.Provider,
+		Model:                      rec.Model,
+		Prompt:                     rec.Prompt,
+		Image:                      rec.Image,
+		Status:                     rec.Status,
+		PermissionTimeoutSec:       rec.PermissionTimeoutSec,
+		PermissionOnTimeout:        rec.PermissionOnTimeout,
+		RetentionCompletedDays:     rec.RetentionCompletedDays,
+		RetentionFailedDays:        rec.RetentionFailedDays,
+		RetentionWorkspaceIdleDays: rec.RetentionWorkspaceIdleDays,
+		ConfigSource:               rec.ConfigSource,
+		TenantID:                   rec.TenantID,
+		Owner:                      rec.Owner,
+		Visibility:                 rec.Visibility,
+	}
+}
+
+// audit는 ctx의 사용자가 수행한 작업을 감사 기록에 추가하고 구독자에게 전달합니다.
+// before와 after가 모두 있고 같으면 변경이 없으므로 기록하지 않습니다.
+// 작업은 이미 반영되었으므로 기록에 실패해도 오류를 반환하지 않고 로그만 남깁니다.
+func (c *Controller) audit(ctx context.Context, action, targetType, targetID string, before, after any) {
+	if c.repo == nil {
+		return
+	}
+	beforeJSON, afterJSON := auditJSON(before), auditJSON(after)
+	if beforeJSON != "" && beforeJSON == afterJSON {
+		return
+	}
+
+	actor := c.actor(ctx)
+	event := &storage.AuditEvent{
+		Actor:      actor,
+		Source:     actorConnector(actor),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+	}
+	// 작업 context가 취소되었어도 기록은 남김
+	if err := c.repo.AppendAuditEvent(context.WithoutCancel(ctx), event); err != nil {
+		c.logger.Error("Failed to append audit event",
+			zap.String("action", action),
+			zap.String("target", targetType+"/"+targetID),
+			zap.String("actor", actor),
+			zap.Error(err),
+		)
+		return
+	}
+	c.publishAudit(*event)
+}
+
+// auditJSON은 감사 기록 상태를 JSON으로 변환합니다. nil이면 빈 문자열입니다.
+func auditJSON(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return ""
+	}
+	return string(data)
+}
+
+// SubscribeAudit는 이후 기록되는 감사 이벤트를 받을 채널을 반환합니다.
+// 수신 측이 밀려 버퍼가 가득 차면 해당 이벤트는 채널로 전달하지 않습니다. (DB에는 기록됨)
+func (c *Controller) SubscribeAudit(buffer int) <-chan storage.AuditEvent {
+	ch := make(chan storage.AuditEvent, buffer)
+	c.auditMu.Lock()
+	c.auditSubscribers = append(c.auditSubscribers, ch)
+	c.auditMu.Unlock()
+	return ch
+}
+
+func (c *Controller) publishAudit(event storage.AuditEvent) {
+	c.auditMu.Lock()
+	defer c.auditMu.Unlock()
+	for _, ch := range c.auditSubscribers {
+		select {
+		case ch <- event:
+		default:
+			c.logger.Warn("Audit subscriber is full, dropping event", zap.String("action", event.Action))
+		}
+	}
+}
+
+// QueryAuditEvents는 조건에 맞는 감사 기록을 최신순으로 반환합니다.
+func (c *Controller) QueryAuditEvents(ctx context.Context, q storage.AuditQuery) ([]storage.AuditEvent, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+	return c.repo.QueryAuditEvents(ctx, q)
+}
+
+// AuditChanges는 감사 기록의 변경 전후 상태를 비교하여 달라진 필드를 이름순으로 반환합니다.
+// 상태가 JSON 객체가 아니면 전체 값을 Field가 빈 하나의 변경으로 반환합니다.
+func AuditChanges(event storage.AuditEvent) []AuditChange {
+	before, okBefore := auditFields(event.Before)
+	after, okAfter := auditFields(event.After)
+	if !okBefore || !okAfter {
+		if event.Before == event.After {
+			return nil
+		}
+		return []AuditChange{{Old: event.Before, New: event.After}}
+	}
+
+	fields := make(map[string]struct{}, len(before)+len(after))
+	for k := range before {
+		fields[k] = struct{}{}
+	}
+	for k := range after {
+		fields[k] = struct{}{}
+	}
+
+	var changes []AuditChange
+	for field := range fields {
+		old, cur := auditValue(before[field]), auditValue(after[field])
+		if old != cur {
+			changes = append(changes, AuditChange{Field: field, Old: old, New: cur})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// auditFields는 JSON 객체 상태를 필드별로 파싱합니다. 빈 상태는 필드가 없는 객체로 취급합니다.
+func auditFields(state string) (map[string]json.RawMessage, bool) {
+	fields := make(map[string]json.RawMessage)
+	if state == "" {
+		return fields, true
+	}
+	if err := json.Unmarshal([]byte(state), &fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// auditValue는 JSON 값을 표시용 문자열로 변환합니다. 문자열은 따옴표 없이 표시합니다.
+func auditValue(raw json.RawMessage) string {
+	if raw == nil {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,62 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEvents(t *testing.T) {
+	ctx := context.Background()
+	ctrl, _ := newBundleController(t, "audit_events", controller.WithDefaultActor("cli:tester"))
+	events := ctrl.SubscribeAudit(10)
+
+	discordCtx := controller.WithActor(ctx, "discord:42")
+	require.NoError(t, ctrl.CreateAgent(discordCtx, "audit-agent", "desc", "opencode", "gpt-4", "prompt"))
+	// 변경 없는 수정은 기록하지 않음
+	require.NoError(t, ctrl.UpdateAgent(discordCtx, "audit-agent", "desc", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.UpdateAgent(discordCtx, "audit-agent", "desc", "opencode", "gpt-4o", "prompt"))
+	require.NoError(t, ctrl.DeleteAgent(ctx, "audit-agent"))
+
+	all, err := ctrl.QueryAuditEvents(ctx, storage.AuditQuery{Action: "agent"})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	actions := []string{all[0].Action, all[1].Action, all[2].Action}
+	assert.ElementsMatch(t, []string{controller.AuditAgentCreate, controller.AuditAgentUpdate, controller.AuditAgentDelete}, actions)
+
+	updates, err := ctrl.QueryAuditEvents(ctx, storage.AuditQuery{Action: controller.AuditAgentUpdate, TargetType: controller.AuditTargetAgent, TargetID: "audit-agent"})
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	update := updates[0]
+	assert.Equal(t, "discord:42", update.Actor)
+	assert.Equal(t, "discord", update.Source)
+	assert.Equal(t, []controller.AuditChange{{Field: "model", Old: "gpt-4", New: "gpt-4o"}}, controller.AuditChanges(update))
+
+	deletes, err := ctrl.QueryAuditEvents(ctx, storage.AuditQuery{Actor: "cli:tester"})
+	require.NoError(t, err)
+	require.Len(t, deletes, 1)
+	assert.Equal(t, controller.AuditAgentDelete, deletes[0].Action)
+	assert.Equal(t, "cli", deletes[0].Source)
+	assert.Equal(t, []controller.AuditChange{{Field: "status", Old: storage.AgentStatusActive, New: storage.AgentStatusDeleted}}, controller.AuditChanges(deletes[0]))
+
+	// 생성 기록은 변경 전 상태가 없음
+	creates, err := ctrl.QueryAuditEvents(ctx, storage.AuditQuery{Action: controller.AuditAgentCreate})
+	require.NoError(t, err)
+	require.Len(t, creates, 1)
+	assert.Empty(t, creates[0].Before)
+	assert.Contains(t, controller.AuditChanges(creates[0]), controller.AuditChange{Field: "prompt", New: "prompt"})
+
+	// 구독자는 기록 순서대로 받음
+	for _, action := range []string{controller.AuditAgentCreate, controller.AuditAgentUpdate, controller.AuditAgentDelete} {
+		select {
+		case event := <-events:
+			assert.Equal(t, action, event.Action)
+		default:
+			t.Fatalf("missing audit event %s", action)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cnap-oss/app/internal/common"
 	taskrunner "github.com/cnap-oss/app/internal/runner"
 	"github.com/cnap-oss/app/internal/storage"
 	"go.uber.org/zap"
@@ -20,29 +21,104 @@ type Controller struct {
 	mu                  sync.RWMutex
 	connectorEventChan  chan ConnectorEvent
 	controllerEventChan chan ControllerEvent
+	eventLogsMu         sync.Mutex
+	eventLogs           map[string]*taskEventLog // Task ID → 이벤트 순번 예약 범위와 기록을 미룬 델타
+	permissions         *permissionRegistry
+	transcripts         *transcriptRegistry
+	messages            storage.MessageStore
+	messagesErr         error // 기본 메시지 저장소를 만들지 못한 이유 (Start에서 반환)
+	workspaces          taskrunner.WorkspaceManager
+	workspacesOnce      sync.Once
+	agentConfigDir      string
+	retention           RetentionPolicy
+	retentionArchiveDir string
+	retentionInterval   time.Duration
+	defaultActor        string
+	auditMu             sync.Mutex
+	auditSubscribers    []chan storage.AuditEvent
+	dedupWindow         time.Duration
+	dedupMu             sync.Mutex
+	seenEvents          map[string]time.Time // Connector 이벤트 ID → 처리 시각
+	queueMu             sync.Mutex
+	eventQueues         map[string][]ConnectorEvent // Task ID → 처리를 기다리는 Connector 이벤트
+}
+
+// Option은 Controller 생성 옵션입니다.
+type Option func(*Controller)
+
+// WithMessageStore는 대화 메시지 내용 저장소를 지정합니다.
+// 지정하지 않으면 {DataDir}/messages 파일 저장소를 사용합니다.
+func WithMessageStore(store storage.MessageStore) Option {
+	return func(c *Controller) {
+		c.messages = store
+	}
+}
+
+// WithWorkspaceManager는 에이전트 작업 공간 관리자를 지정합니다.
+// 지정하지 않으면 처음 사용할 때 Runner가 마운트하는 작업 공간 디렉토리로 생성합니다.
+func WithWorkspaceManager(wm taskrunner.WorkspaceManager) Option {
+	return func(c *Controller) {
+		c.workspaces = wm
+	}
 }
 
 // NewController는 새로운 Controller를 생성합니다.
-func NewController(logger *zap.Logger, repo *storage.Repository, eventChan chan ConnectorEvent, resultChan chan ControllerEvent) *Controller {
-	return &Controller{
+func NewController(logger *zap.Logger, repo *storage.Repository, eventChan chan ConnectorEvent, resultChan chan ControllerEvent, opts ...Option) *Controller {
+	c := &Controller{
 		logger:              logger,
 		repo:                repo,
 		runnerManager:       taskrunner.GetRunnerManager(taskrunner.WithLogger(logger)),
 		taskContexts:        make(map[string]*TaskContext),
 		connectorEventChan:  eventChan,
 		controllerEventChan: resultChan,
+		permissions:         newPermissionRegistry(),
+		transcripts:         newTranscriptRegistry(),
+		dedupWindow:         DefaultEventDedupWindow,
+		eventLogs:           make(map[string]*taskEventLog),
+		seenEvents:          make(map[string]time.Time),
+		eventQueues:         make(map[string][]ConnectorEvent),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.messages == nil {
+		store, err := storage.NewFileMessageStore(common.GetMessagesDir())
+		if err != nil {
+			c.messagesErr = fmt.Errorf("failed to open message store: %w", err)
+		} else {
+			c.messages = store
+		}
 	}
+	return c
 }
 
 // Start는 controller 서버를 시작합니다.
 func (c *Controller) Start(ctx context.Context) error {
 	c.logger.Info("Starting controller server")
 
+	if c.messagesErr != nil {
+		return c.messagesErr
+	}
+
 	// RunnerManager 시작
 	if err := c.runnerManager.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start runner manager: %w", err)
 	}
 
+	// 선언형 에이전트 설정 반영
+	if c.agentConfigDir != "" {
+		fingerprint, _ := agentConfigFingerprint(c.agentConfigDir)
+		if err := c.ReconcileAgentConfig(ctx); err != nil {
+			c.logger.Error("Failed to reconcile agent config", zap.Error(err))
+		}
+		go c.watchAgentConfig(ctx, fingerprint)
+	}
+
+	// 보관 기간이 지난 Task와 작업 공간 정리
+	if c.retentionInterval > 0 {
+		go c.runJanitor(ctx)
+	}
+
 	// 이벤트 루프 시작 (별도 goroutine)
 	go c.eventLoop(ctx)
 
@@ -65,6 +141,9 @@ func (c *Controller) Start(ctx context.Context) error {
 func (c *Controller) Stop(ctx context.Context) error {
 	c.logger.Info("Stopping controller server")
 
+	// 기록을 미룬 스트리밍 델타 기록
+	c.flushEventLogs()
+
 	// RunnerManager 종료 (모든 컨테이너 정리)
 	if err := c.runnerManager.Stop(ctx); err != nil {
 		c.logger.Error("Failed to stop runner manager", zap.Error(err))
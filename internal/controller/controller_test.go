@@ -389,3 +389,50 @@ func TestControllerSendMessage_RunnerAutoRecreation(t *testing.T) {
 	// 로그에 "Runner not found, recreating..." 메시지가 출력되어야 함
 	// 실제 프로덕션에서는 이 로직 덕분에 CLI 단일 실행도 정상 동작함
 }
+
+func TestControllerListTasks(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:list_tasks?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 1), make(chan controller.ControllerEvent, 1))
+
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+	for i, task := range []storage.Task{
+		{TaskID: "list-task-1", AgentID: "agent-a", Status: storage.TaskStatusPending},
+		{TaskID: "list-task-2", AgentID: "agent-b", Status: storage.TaskStatusRunning},
+		{TaskID: "list-task-3", AgentID: "agent-b", Status: storage.TaskStatusCompleted},
+	} {
+		task.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.CreateTask(ctx, &task))
+	}
+
+	// 모든 에이전트에서 아직 끝나지 않은 Task
+	statuses, err := controller.ParseTaskStatuses(controller.TaskStatusActive)
+	require.NoError(t, err)
+	page, err := ctrl.ListTasks(ctx, storage.TaskQuery{Statuses: statuses, Ascending: true})
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 2)
+	assert.Equal(t, "list-task-1", page.Tasks[0].TaskID)
+	assert.Equal(t, "list-task-2", page.Tasks[1].TaskID)
+
+	page, err = ctrl.ListTasks(ctx, storage.TaskQuery{AgentID: "agent-b", Statuses: []string{storage.TaskStatusCompleted}})
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 1)
+	assert.Equal(t, "list-task-3", page.Tasks[0].TaskID)
+}
+
+func TestParseTaskStatuses(t *testing.T) {
+	statuses, err := controller.ParseTaskStatuses("running, Failed", "active")
+	require.NoError(t, err)
+	assert.Equal(t, []string{storage.TaskStatusRunning, storage.TaskStatusFailed, storage.TaskStatusPending, storage.TaskStatusWaiting}, statuses)
+
+	statuses, err = controller.ParseTaskStatuses()
+	require.NoError(t, err)
+	assert.Empty(t, statuses)
+
+	_, err = controller.ParseTaskStatuses("running,done")
+	assert.Error(t, err)
+}
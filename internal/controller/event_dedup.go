@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// DefaultEventDedupWindow는 같은 ID의 Connector 이벤트를 중복으로 보고 무시하는 기본 기간입니다.
+const DefaultEventDedupWindow = 10 * time.Minute
+
+// processedEventRetention은 execute 이벤트 ID를 저장소에 보관하는 기간입니다.
+// 이벤트 루프가 processedEventPruneInterval마다 지난 기록을 삭제하며, Task 보관 정책(retention)과는 관계없습니다.
+const processedEventRetention = 7 * 24 * time.Hour
+
+// processedEventPruneInterval은 보관 기간이 지난 execute 이벤트 ID 기록을 삭제하는 주기입니다.
+const processedEventPruneInterval = time.Hour
+
+// WithEventDedupWindow는 같은 ID의 Connector 이벤트를 중복으로 보고 무시하는 기간을 지정합니다.
+// 0 이하이면 메모리 중복 검사를 하지 않으며, execute 이벤트는 기간과 관계없이 저장소로 중복을 검사합니다.
+func WithEventDedupWindow(window time.Duration) Option {
+	return func(c *Controller) {
+		c.dedupWindow = window
+	}
+}
+
+// isDuplicateEvent는 이미 처리했거나 처리 중인 Connector 이벤트인지 확인하고, 처음 보는 이벤트이면 처리 중으로 기록합니다.
+// 처리에 실패하면 forgetEvent로 기록을 지워 Connector가 다시 보낸 이벤트를 처리할 수 있게 합니다.
+// ID가 없는 이벤트는 중복 검사를 하지 않습니다.
+func (c *Controller) isDuplicateEvent(ctx context.Context, event ConnectorEvent) bool {
+	if event.ID == "" {
+		return false
+	}
+
+	if c.dedupWindow > 0 {
+		now := time.Now()
+		c.dedupMu.Lock()
+		seenAt, seen := c.seenEvents[event.ID]
+		if seen && now.Sub(seenAt) < c.dedupWindow {
+			c.dedupMu.Unlock()
+			return true
+		}
+		c.seenEvents[event.ID] = now
+		c.dedupMu.Unlock()
+	}
+
+	// execute는 Task를 만들므로 Controller가 재시작한 뒤 다시 전달되어도 한 번만 처리
+	if event.Type != "execute" || c.repo == nil {
+		return false
+	}
+	first, err := c.repo.MarkEventProcessed(ctx, &storage.ProcessedEvent{
+		EventID: event.ID,
+		Type:    event.Type,
+		TaskID:  event.TaskID,
+	})
+	if err != nil {
+		// 기록하지 못해도 이벤트는 처리 (Task ID 중복은 CreateTask에서 거부)
+		c.logger.Warn("Failed to record processed event",
+			zap.String("event_id", event.ID),
+			zap.String("task_id", event.TaskID),
+			zap.Error(err),
+		)
+		return false
+	}
+	return !first
+}
+
+// forgetEvent는 처리하지 못한 Connector 이벤트의 중복 검사 기록을 지웁니다.
+func (c *Controller) forgetEvent(ctx context.Context, event ConnectorEvent) {
+	if event.ID == "" {
+		return
+	}
+
+	c.dedupMu.Lock()
+	delete(c.seenEvents, event.ID)
+	c.dedupMu.Unlock()
+
+	if event.Type != "execute" || c.repo == nil {
+		return
+	}
+	if err := c.repo.DeleteProcessedEvent(ctx, event.ID); err != nil {
+		c.logger.Warn("Failed to clear processed event",
+			zap.String("event_id", event.ID),
+			zap.String("task_id", event.TaskID),
+			zap.Error(err),
+		)
+	}
+}
+
+// pruneSeenEvents는 중복 검사 기간이 지난 Connector 이벤트 ID를 메모리에서 삭제합니다.
+func (c *Controller) pruneSeenEvents(now time.Time) {
+	c.dedupMu.Lock()
+	defer c.dedupMu.Unlock()
+	for id, at := range c.seenEvents {
+		if now.Sub(at) >= c.dedupWindow {
+			delete(c.seenEvents, id)
+		}
+	}
+}
+
+// pruneProcessedEvents는 보관 기간이 지난 execute 이벤트 ID 기록을 삭제합니다.
+func (c *Controller) pruneProcessedEvents(ctx context.Context, now time.Time) {
+	if c.repo == nil {
+		return
+	}
+	n, err := c.repo.PruneProcessedEvents(ctx, now.Add(-processedEventRetention))
+	if err != nil {
+		c.logger.Warn("Failed to prune processed events", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		c.logger.Info("Pruned processed events", zap.Int64("count", n))
+	}
+}
@@ -0,0 +1,330 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// emit은 이벤트에 ID와 발생 시각을 부여하고 Task 이벤트 로그에 순번과 함께 기록한 뒤 Connector로 전달합니다.
+// 기록에 실패해도 실시간 전달은 계속하며, 이 경우 이벤트의 Seq는 0입니다.
+// 스트리밍 델타는 순번만 먼저 부여하고 기록은 모아서 하므로, 기록되기 전에 해당 Part가 완료되면 재생되지 않습니다.
+// 같은 Task의 이벤트는 여러 goroutine에서 발생해도 순번 순서대로 Connector에 전달됩니다.
+func (c *Controller) emit(event ControllerEvent) {
+	if event.ID == "" {
+		event.ID = newEventID()
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	l, ticket := c.recordEvent(&event)
+	if l == nil {
+		c.controllerEventChan <- event
+		return
+	}
+
+	// 앞선 순번의 이벤트가 전달될 때까지 기다림. 전달하는 동안에는 잠금을 잡지 않으므로
+	// Connector가 이벤트를 받다가 ReplayEvents를 호출해도 교착되지 않음
+	l.mu.Lock()
+	for l.sent+1 != ticket {
+		l.sendCond.Wait()
+	}
+	l.mu.Unlock()
+
+	c.controllerEventChan <- event
+
+	l.mu.Lock()
+	l.sent = ticket
+	l.sendCond.Broadcast()
+	if l.closed && l.sent == l.tickets {
+		// Task가 끝나고 전달할 이벤트도 없으면 상태 제거
+		l.removed = true
+		c.eventLogsMu.Lock()
+		if c.eventLogs[l.taskID] == l {
+			delete(c.eventLogs, l.taskID)
+		}
+		c.eventLogsMu.Unlock()
+	}
+	l.mu.Unlock()
+}
+
+const (
+	// eventSeqBlock은 Task 이벤트 순번을 저장소에서 한 번에 예약하는 개수입니다.
+	eventSeqBlock = 64
+	// deltaFlushSize는 모아 둔 스트리밍 델타를 바로 기록하는 개수 기준입니다.
+	deltaFlushSize = 64
+	// deltaFlushInterval은 스트리밍 델타를 모아 두는 최대 시간입니다.
+	deltaFlushInterval = 500 * time.Millisecond
+)
+
+// taskEventLog는 한 Task의 이벤트 순번 예약 범위와 아직 기록하지 않은 스트리밍 델타, 전달 순서를 보관합니다.
+// 잠금이 Task마다 따로 있으므로 서로 다른 Task의 이벤트 기록은 서로 기다리지 않습니다.
+type taskEventLog struct {
+	mu       sync.Mutex
+	sendCond *sync.Cond // mu를 사용하며 sent가 바뀌면 깨움
+	taskID   string
+	nextSeq  int64                // 다음에 부여할 순번
+	endSeq   int64                // 예약한 순번 범위의 끝 (미포함)
+	pending  []*storage.TaskEvent // 기록을 미룬 스트리밍 델타
+	written  map[string]bool      // 델타를 기록한 Part ID (Part 완료 시 정리 대상)
+	timer    *time.Timer
+	tickets  uint64 // 전달 순서를 정한 이벤트 수
+	sent     uint64 // Connector로 전달한 이벤트 수
+	closed   bool   // Task가 끝나 남은 이벤트를 전달하면 제거됨
+	removed  bool   // 맵에서 제거됨
+}
+
+// taskEventLog는 Task의 이벤트 로그 상태를 반환하고, 없으면 만듭니다.
+func (c *Controller) taskEventLog(taskID string) *taskEventLog {
+	c.eventLogsMu.Lock()
+	defer c.eventLogsMu.Unlock()
+	l, ok := c.eventLogs[taskID]
+	if !ok {
+		l = &taskEventLog{taskID: taskID, written: make(map[string]bool)}
+		l.sendCond = sync.NewCond(&l.mu)
+		c.eventLogs[taskID] = l
+	}
+	return l
+}
+
+// recordEvent는 이벤트를 Task 이벤트 로그에 추가하고 부여된 순번을 event.Seq에 채운 뒤, Task의 이벤트 로그 상태와 전달 순서를 반환합니다.
+// 순번은 묶음으로 예약해 둔 범위에서 부여하며, 스트리밍 델타는 모아 두었다가 한 트랜잭션으로 기록합니다.
+// Part가 완료되면 해당 Part의 스트리밍 델타는 완료 이벤트에 내용이 모두 담기므로 기록하지 않거나 정리합니다.
+// 이벤트 로그를 사용하지 않으면 nil을 반환합니다.
+func (c *Controller) recordEvent(event *ControllerEvent) (*taskEventLog, uint64) {
+	if c.repo == nil || event.TaskID == "" {
+		return nil, 0
+	}
+
+	// 직렬화 형식(WireEvent)으로 저장. 순번은 기록할 때 정해지므로 seq 컬럼에 저장
+	record := *event
+	// 사용자 메시지 내용은 메시지 저장소에만 보관 (사용자 데이터 삭제 대상)
+	if record.Role == "user" {
+		record.Content = ""
+	}
+	payload, err := json.Marshal(record)
+	if err != nil {
+		c.logger.Error("Failed to encode controller event", zap.String("task_id", event.TaskID), zap.Error(err))
+		return nil, 0
+	}
+
+	rec := &storage.TaskEvent{
+		TaskID:    event.TaskID,
+		EventType: string(event.EventType),
+		PartID:    event.PartID,
+		Payload:   string(payload),
+	}
+
+	for {
+		l := c.taskEventLog(event.TaskID)
+		l.mu.Lock()
+		if l.removed {
+			// 다른 goroutine이 Task 종료로 방금 제거함
+			l.mu.Unlock()
+			continue
+		}
+		l.closed = false
+		c.appendEvent(l, event, rec)
+		switch event.Status {
+		case "completed", "failed", "canceled":
+			c.closeEventLog(l)
+		}
+		l.tickets++
+		ticket := l.tickets
+		l.mu.Unlock()
+		return l, ticket
+	}
+}
+
+// appendEvent는 l.mu를 잡은 상태에서 이벤트에 순번을 부여하고 기록합니다.
+func (c *Controller) appendEvent(l *taskEventLog, event *ControllerEvent, rec *storage.TaskEvent) {
+	ctx := context.Background()
+	seq, err := c.nextEventSeq(ctx, l)
+	if err != nil {
+		c.logger.Error("Failed to reserve task event seq",
+			zap.String("task_id", event.TaskID),
+			zap.String("event_type", string(event.EventType)),
+			zap.Error(err),
+		)
+		return
+	}
+	rec.Seq = seq
+
+	if event.EventType == EventTypeStreamDelta {
+		event.Seq = seq
+		l.pending = append(l.pending, rec)
+		if len(l.pending) >= deltaFlushSize {
+			c.flushEventLog(ctx, l)
+		} else if l.timer == nil {
+			l.timer = time.AfterFunc(deltaFlushInterval, func() {
+				l.mu.Lock()
+				defer l.mu.Unlock()
+				c.flushEventLog(context.Background(), l)
+			})
+		}
+		return
+	}
+
+	partDone := event.EventType == EventTypePartComplete && event.PartID != ""
+	if partDone {
+		// 아직 기록하지 않은 델타는 완료 이벤트에 내용이 모두 담기므로 버림
+		kept := l.pending[:0]
+		for _, p := range l.pending {
+			if p.PartID != event.PartID || p.EventType != string(EventTypeStreamDelta) {
+				kept = append(kept, p)
+			}
+		}
+		l.pending = kept
+	}
+
+	if err := c.writeEvents(ctx, l, rec); err != nil {
+		c.logger.Error("Failed to append task event",
+			zap.String("task_id", event.TaskID),
+			zap.String("event_type", string(event.EventType)),
+			zap.Error(err),
+		)
+		return
+	}
+	event.Seq = seq
+
+	if partDone && l.written[event.PartID] {
+		delete(l.written, event.PartID)
+		if _, err := c.repo.CompactTaskEvents(ctx, event.TaskID, event.PartID, string(EventTypeStreamDelta)); err != nil {
+			c.logger.Warn("Failed to compact stream deltas",
+				zap.String("task_id", event.TaskID),
+				zap.String("part_id", event.PartID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// nextEventSeq는 예약한 범위에서 다음 순번을 부여하고, 범위를 다 쓰면 저장소에서 새로 예약합니다.
+func (c *Controller) nextEventSeq(ctx context.Context, l *taskEventLog) (int64, error) {
+	if l.nextSeq >= l.endSeq {
+		first, err := c.repo.ReserveTaskEventSeqs(ctx, l.taskID, eventSeqBlock)
+		if err != nil {
+			return 0, err
+		}
+		l.nextSeq, l.endSeq = first, first+eventSeqBlock
+	}
+	seq := l.nextSeq
+	l.nextSeq++
+	return seq, nil
+}
+
+// writeEvents는 l.mu를 잡은 상태에서 모아 둔 델타와 rec를 한 트랜잭션으로 기록합니다.
+// 기록에 실패한 델타는 버리며, 해당 Part가 완료되면 완료 이벤트로 대신합니다.
+func (c *Controller) writeEvents(ctx context.Context, l *taskEventLog, rec *storage.TaskEvent) error {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	batch := l.pending
+	l.pending = nil
+	if rec != nil {
+		batch = append(batch, rec)
+	}
+	if err := c.repo.AppendTaskEvents(ctx, batch); err != nil {
+		return err
+	}
+	for _, e := range batch {
+		if e.EventType == string(EventTypeStreamDelta) && e.PartID != "" {
+			l.written[e.PartID] = true
+		}
+	}
+	return nil
+}
+
+// flushEventLog는 l.mu를 잡은 상태에서 모아 둔 스트리밍 델타를 기록합니다.
+func (c *Controller) flushEventLog(ctx context.Context, l *taskEventLog) {
+	if len(l.pending) == 0 {
+		return
+	}
+	if err := c.writeEvents(ctx, l, nil); err != nil {
+		c.logger.Warn("Failed to append stream deltas", zap.String("task_id", l.taskID), zap.Error(err))
+	}
+}
+
+// closeEventLog는 l.mu를 잡은 상태에서 모아 둔 델타를 기록하고 남은 예약 범위를 버립니다.
+// 상태는 남은 이벤트를 모두 전달한 뒤 emit에서 제거합니다.
+// 이후 같은 Task에서 이벤트가 발생하면 순번을 새로 예약하므로, 남은 예약 범위만큼 순번을 건너뜁니다.
+func (c *Controller) closeEventLog(l *taskEventLog) {
+	c.flushEventLog(context.Background(), l)
+	l.nextSeq, l.endSeq = 0, 0
+	l.closed = true
+}
+
+// flushEventLogs는 모든 Task에서 모아 둔 스트리밍 델타를 기록합니다. taskIDs를 지정하면 해당 Task만 기록합니다.
+func (c *Controller) flushEventLogs(taskIDs ...string) {
+	c.eventLogsMu.Lock()
+	var logs []*taskEventLog
+	if len(taskIDs) == 0 {
+		for _, l := range c.eventLogs {
+			logs = append(logs, l)
+		}
+	} else {
+		for _, id := range taskIDs {
+			if l, ok := c.eventLogs[id]; ok {
+				logs = append(logs, l)
+			}
+		}
+	}
+	c.eventLogsMu.Unlock()
+
+	for _, l := range logs {
+		l.mu.Lock()
+		c.flushEventLog(context.Background(), l)
+		l.mu.Unlock()
+	}
+}
+
+// ReplayEvents는 Task 이벤트 로그에서 afterSeq 이후의 이벤트를 순번 순으로 반환합니다.
+// Connector가 재연결한 뒤 놓친 이벤트를 다시 처리하거나, 나중에 연결한 구독자가 기록을 받을 때 사용합니다.
+// 완료된 Part의 스트리밍 델타는 정리되어 있으므로 Part 완료 이벤트로 대신합니다.
+func (c *Controller) ReplayEvents(ctx context.Context, taskID string, afterSeq int64) ([]ControllerEvent, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+
+	task, err := c.repo.GetTask(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task not found: %s", taskID)
+		}
+		return nil, err
+	}
+	if tenant := TenantFromContext(ctx); tenant != "" && task.TenantID != tenant {
+		return nil, fmt.Errorf("task not found: %s", taskID)
+	}
+
+	// 아직 기록하지 않은 스트리밍 델타도 재생되도록 먼저 기록
+	c.flushEventLogs(taskID)
+
+	recs, err := c.repo.ListTaskEvents(ctx, taskID, afterSeq, 0)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]ControllerEvent, 0, len(recs))
+	for _, rec := range recs {
+		var event ControllerEvent
+		if err := json.Unmarshal([]byte(rec.Payload), &event); err != nil {
+			c.logger.Warn("Skipping undecodable task event",
+				zap.String("task_id", taskID),
+				zap.Int64("seq", rec.Seq),
+				zap.Error(err),
+			)
+			continue
+		}
+		event.Seq = rec.Seq
+		events = append(events, event)
+	}
+	return events, nil
+}
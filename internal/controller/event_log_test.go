@@ -0,0 +1,207 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestControllerEventLogReplay(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:event_log?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	controllerEventChan := make(chan controller.ControllerEvent, 10)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 10), controllerEventChan)
+
+	ctx := context.Background()
+	require.NoError(t, ctrl.CreateAgent(ctx, "event-agent", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "event-task", AgentID: "event-agent", Status: storage.TaskStatusRunning}))
+
+	text := func(delta, content string) *opencode.RunnerMessage {
+		return &opencode.RunnerMessage{Type: opencode.MessageTypeText, PartID: "prt_1", Delta: delta, IsPartial: delta != "", Content: content}
+	}
+	require.NoError(t, ctrl.OnEvent("event-task", text("Hel", "")))
+	require.NoError(t, ctrl.OnEvent("event-task", text("lo", "")))
+	require.NoError(t, ctrl.OnEvent("event-task", &opencode.RunnerMessage{
+		Type:     opencode.MessageTypeToolCall,
+		PartID:   "prt_2",
+		ToolCall: &opencode.ToolCallInfo{ToolID: "call_1", ToolName: "bash"},
+	}))
+	require.NoError(t, ctrl.OnEvent("event-task", text("", "Hello")))
+	require.NoError(t, ctrl.OnError("event-task", errors.New("runner crashed")))
+
+	// 실시간 이벤트에는 Task별 순번이 부여됨
+	for seq := int64(1); seq <= 5; seq++ {
+		assert.Equal(t, seq, (<-controllerEventChan).Seq)
+	}
+
+	// Part가 완료되면 스트리밍 델타는 정리되고, 에러 메시지는 보존됨
+	events, err := ctrl.ReplayEvents(ctx, "event-task", 0)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, int64(3), events[0].Seq)
+	assert.Equal(t, controller.EventTypeToolStart, events[0].EventType)
+	assert.Equal(t, "bash", events[0].ToolInfo.ToolName)
+	assert.Equal(t, controller.EventTypePartComplete, events[1].EventType)
+	assert.Equal(t, "Hello", events[1].Content)
+	assert.Equal(t, "failed", events[2].Status)
+	var eventErr *controller.EventError
+	require.ErrorAs(t, events[2].Error, &eventErr)
+	assert.Equal(t, controller.ErrorCodeRunner, eventErr.Code)
+	assert.Equal(t, "runner crashed", eventErr.Message)
+	assert.NotEmpty(t, events[2].ID)
+
+	events, err = ctrl.ReplayEvents(ctx, "event-task", 4)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(5), events[0].Seq)
+
+	// 다른 테넌트에서는 Task 이벤트를 조회할 수 없음
+	_, err = ctrl.ReplayEvents(controller.WithTenant(ctx, "guild:other"), "event-task", 0)
+	assert.EqualError(t, err, "task not found: event-task")
+
+	// Task를 정리하면 이벤트 로그도 삭제됨
+	_, err = repo.PurgeTask(ctx, "event-task")
+	require.NoError(t, err)
+	recs, err := repo.ListTaskEvents(ctx, "event-task", 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+}
+
+func TestControllerEventLogBuffersDeltas(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:event_log_deltas?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	controllerEventChan := make(chan controller.ControllerEvent, 20)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 10), controllerEventChan)
+	ctx := context.Background()
+	require.NoError(t, ctrl.CreateAgent(ctx, "delta-agent", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "delta-task", AgentID: "delta-agent", Status: storage.TaskStatusRunning}))
+
+	text := func(partID, delta, content string) *opencode.RunnerMessage {
+		return &opencode.RunnerMessage{Type: opencode.MessageTypeText, PartID: partID, Delta: delta, IsPartial: delta != "", Content: content}
+	}
+	require.NoError(t, ctrl.OnEvent("delta-task", text("prt_1", "Hel", "")))
+	require.NoError(t, ctrl.OnEvent("delta-task", text("prt_1", "lo", "")))
+
+	// 델타는 순번만 부여하고 기록은 미룸
+	assert.Equal(t, int64(1), (<-controllerEventChan).Seq)
+	assert.Equal(t, int64(2), (<-controllerEventChan).Seq)
+	recs, err := repo.ListTaskEvents(ctx, "delta-task", 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+
+	// 재생할 때는 모아 둔 델타를 먼저 기록
+	events, err := ctrl.ReplayEvents(ctx, "delta-task", 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "lo", events[1].Delta)
+
+	// 기록하기 전에 Part가 완료된 델타는 기록하지 않고, 기록한 델타는 정리
+	require.NoError(t, ctrl.OnEvent("delta-task", text("prt_1", "!", "")))
+	require.NoError(t, ctrl.OnEvent("delta-task", text("prt_1", "", "Hello!")))
+	require.NoError(t, ctrl.OnEvent("delta-task", text("prt_2", "Bye", "")))
+	for seq := int64(3); seq <= 5; seq++ {
+		assert.Equal(t, seq, (<-controllerEventChan).Seq)
+	}
+	recs, err = repo.ListTaskEvents(ctx, "delta-task", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	assert.Equal(t, int64(4), recs[0].Seq)
+	assert.Equal(t, string(controller.EventTypePartComplete), recs[0].EventType)
+
+	// 일정 시간이 지나면 델타를 기록
+	assert.Eventually(t, func() bool {
+		recs, err := repo.ListTaskEvents(ctx, "delta-task", 4, 0)
+		return err == nil && len(recs) == 1 && recs[0].Seq == 5
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// 다른 Controller(재시작)는 예약된 범위 다음부터 순번을 부여
+	other := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 10), controllerEventChan)
+	require.NoError(t, other.OnError("delta-task", errors.New("runner crashed")))
+	assert.Greater(t, (<-controllerEventChan).Seq, int64(5))
+}
+
+func TestControllerEventLogDeliversInSeqOrder(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:event_log_order?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	// 버퍼가 없어 전달이 막힌 동안에도 다른 goroutine이 이벤트를 발생시킴
+	controllerEventChan := make(chan controller.ControllerEvent)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 10), controllerEventChan)
+
+	const workers, perWorker = 8, 20
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				_ = ctrl.OnEvent("order-task", &opencode.RunnerMessage{Type: opencode.MessageTypeText, PartID: "prt_1", Delta: "x", IsPartial: true})
+			}
+		}()
+	}
+
+	// 같은 Task의 이벤트는 순번 순서대로 전달됨
+	var last int64
+	for i := 0; i < workers*perWorker; i++ {
+		select {
+		case event := <-controllerEventChan:
+			require.Greater(t, event.Seq, last)
+			last = event.Seq
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for controller event")
+		}
+	}
+	wg.Wait()
+}
+
+func TestControllerStreamStatusEvent(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:stream_status?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	controllerEventChan := make(chan controller.ControllerEvent, 10)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 10), controllerEventChan)
+
+	require.NoError(t, ctrl.OnEvent("stream-task", &opencode.RunnerMessage{
+		Type:   opencode.MessageTypeStreamStatus,
+		Status: opencode.StreamStatusReconnecting,
+		Error:  &opencode.MessageErrorInfo{Code: "stream_disconnected", Message: "EOF"},
+	}))
+	require.NoError(t, ctrl.OnEvent("stream-task", &opencode.RunnerMessage{
+		Type:   opencode.MessageTypeStreamStatus,
+		Status: opencode.StreamStatusConnected,
+	}))
+
+	// 재연결 중 상태를 Connector가 표시할 수 있도록 전달
+	event := <-controllerEventChan
+	assert.Equal(t, controller.EventTypeStreamStatus, event.EventType)
+	assert.Equal(t, "reconnecting", event.Status)
+	assert.Equal(t, "EOF", event.Content)
+	event = <-controllerEventChan
+	assert.Equal(t, controller.EventTypeStreamStatus, event.EventType)
+	assert.Equal(t, "connected", event.Status)
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cnap-oss/app/internal/storage"
 	"go.uber.org/zap"
@@ -13,11 +14,26 @@ func (c *Controller) eventLoop(ctx context.Context) {
 	c.logger.Info("Event loop started")
 	defer c.logger.Info("Event loop stopped")
 
+	// 중복 검사 기간이 지난 이벤트 ID는 주기적으로 정리
+	var prune <-chan time.Time
+	if c.dedupWindow > 0 {
+		ticker := time.NewTicker(c.dedupWindow)
+		defer ticker.Stop()
+		prune = ticker.C
+	}
+	pruneProcessed := time.NewTicker(processedEventPruneInterval)
+	defer pruneProcessed.Stop()
+
 	for {
 		select {
 		case event := <-c.connectorEventChan:
-			// 각 이벤트를 별도 goroutine으로 처리 (병렬 처리)
-			go c.handleConnectorEvent(ctx, event)
+			c.dispatchConnectorEvent(ctx, event)
+
+		case now := <-prune:
+			c.pruneSeenEvents(now)
+
+		case now := <-pruneProcessed.C:
+			c.pruneProcessedEvents(ctx, now)
 
 		case <-ctx.Done():
 			c.logger.Info("Event loop shutting down")
@@ -26,33 +42,109 @@ func (c *Controller) eventLoop(ctx context.Context) {
 	}
 }
 
-// handleConnectorEvent는 단일 Connector 이벤트를 처리합니다.
+// dispatchConnectorEvent는 이벤트를 Task별 큐에 넣고, 큐를 처리하는 goroutine이 없으면 시작합니다.
+// 같은 Task(스레드)의 이벤트는 도착한 순서대로 하나씩, 다른 Task의 이벤트는 병렬로 처리됩니다.
+// 턴을 시작한 이벤트(execute, continue) 다음 이벤트는 그 턴이 끝난 뒤에 처리됩니다.
+// 취소와 권한 응답은 Runner 준비 등 앞선 이벤트를 기다리지 않도록, Task가 없는 이벤트는 서로 묶이지 않도록 큐를 거치지 않고 바로 처리합니다.
+func (c *Controller) dispatchConnectorEvent(ctx context.Context, event ConnectorEvent) {
+	if event.TaskID == "" || event.Type == "cancel" || event.Type == "permission" {
+		go c.handleConnectorEvent(ctx, event)
+		return
+	}
+
+	c.queueMu.Lock()
+	queue, running := c.eventQueues[event.TaskID]
+	c.eventQueues[event.TaskID] = append(queue, event)
+	c.queueMu.Unlock()
+
+	if !running {
+		go c.drainConnectorEvents(ctx, event.TaskID)
+	}
+}
+
+// drainConnectorEvents는 Task의 큐가 빌 때까지 이벤트를 순서대로 처리합니다.
+func (c *Controller) drainConnectorEvents(ctx context.Context, taskID string) {
+	for {
+		c.queueMu.Lock()
+		queue := c.eventQueues[taskID]
+		if len(queue) == 0 {
+			delete(c.eventQueues, taskID)
+			c.queueMu.Unlock()
+			return
+		}
+		event := queue[0]
+		c.eventQueues[taskID] = queue[1:]
+		c.queueMu.Unlock()
+
+		c.handleConnectorEvent(ctx, event)
+		// 턴은 비동기로 실행되므로 끝날 때까지 같은 Task의 다음 이벤트를 처리하지 않음
+		if event.Type == "execute" || event.Type == "continue" {
+			c.waitTurn(ctx, taskID)
+		}
+	}
+}
+
+// waitTurn은 Task Runner의 진행 중인 턴이 끝날 때까지 기다립니다.
+// 턴 결과는 OnComplete/OnError 콜백이 처리하며, Runner가 없거나 진행 중인 턴이 없으면 바로 반환합니다.
+func (c *Controller) waitTurn(ctx context.Context, taskID string) {
+	runner := c.runnerManager.GetRunner(taskID)
+	if runner == nil {
+		return
+	}
+	if err := runner.WaitTurn(ctx); err != nil {
+		c.logger.Info("Stopped waiting for task turn",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+	}
+}
+
+// handleConnectorEvent는 단일 Connector 이벤트를 처리합니다. 이미 처리한 이벤트는 무시하며,
+// 처리에 실패한 이벤트는 중복 검사 기록을 지워 Connector가 다시 보내면 처리합니다.
 func (c *Controller) handleConnectorEvent(ctx context.Context, event ConnectorEvent) {
+	if c.isDuplicateEvent(ctx, event) {
+		c.logger.Info("Skipping duplicate connector event",
+			zap.String("type", event.Type),
+			zap.String("event_id", event.ID),
+			zap.String("task_id", event.TaskID),
+		)
+		return
+	}
+
 	c.logger.Info("Handling connector event",
 		zap.String("type", event.Type),
+		zap.String("event_id", event.ID),
 		zap.String("task_id", event.TaskID),
 		zap.String("agent_name", event.AgentName),
 	)
+	ctx = WithTenant(WithActor(ctx, event.Actor), event.Tenant)
 
+	var err error
 	switch event.Type {
 	case "execute":
-		c.handleExecuteEvent(ctx, event)
+		err = c.handleExecuteEvent(ctx, event)
 	case "continue":
-		c.handleContinueEvent(ctx, event)
+		err = c.handleContinueEvent(ctx, event)
 	case "cancel":
-		c.handleCancelEvent(ctx, event)
+		err = c.handleCancelEvent(ctx, event)
 	case "complete":
-		c.handleCompleteEvent(ctx, event)
+		err = c.handleCompleteEvent(ctx, event)
+	case "permission":
+		err = c.handlePermissionEvent(ctx, event)
 	default:
 		c.logger.Warn("Unknown event type",
 			zap.String("type", event.Type),
 			zap.String("task_id", event.TaskID),
 		)
 	}
+	if err != nil {
+		c.forgetEvent(ctx, event)
+	}
 }
 
 // handleExecuteEvent는 Task 실행 이벤트를 처리합니다.
-func (c *Controller) handleExecuteEvent(ctx context.Context, event ConnectorEvent) {
+// Task를 만들지 못했으면 에러를 반환하며, Task를 만든 뒤의 실행 실패는 Task 상태와 이벤트로 알립니다.
+func (c *Controller) handleExecuteEvent(ctx context.Context, event ConnectorEvent) error {
 	c.logger.Info("Creating new task for thread",
 		zap.String("task_id", event.TaskID),
 		zap.String("agent", event.AgentName),
@@ -60,31 +152,35 @@ func (c *Controller) handleExecuteEvent(ctx context.Context, event ConnectorEven
 
 	if err := c.CreateTask(ctx, event.AgentName, event.TaskID, event.Prompt); err != nil {
 		c.logger.Error("Failed to create task", zap.Error(err))
-		c.controllerEventChan <- ControllerEvent{
-			TaskID: event.TaskID,
-			Status: "failed",
-			Error:  fmt.Errorf("failed to create task: %w", err),
-		}
-		return
+		c.emit(ControllerEvent{
+			TaskID:  event.TaskID,
+			Status:  "failed",
+			Error:   fmt.Errorf("failed to create task: %w", err),
+			LogTail: c.runnerLogTail(event.TaskID),
+		})
+		return err
 	}
 
 	task, err := c.repo.GetTask(ctx, event.TaskID)
 	if err != nil {
 		c.logger.Error("Failed to get newly created task", zap.Error(err))
-		c.controllerEventChan <- ControllerEvent{
+		c.emit(ControllerEvent{
 			TaskID: event.TaskID,
 			Status: "failed",
 			Error:  fmt.Errorf("task not found after creation: %w", err),
-		}
-		return
+		})
+		return err
 	}
 
-	go c.executeTask(ctx, event.TaskID, task)
+	c.audit(ctx, AuditTaskExecute, AuditTargetTask, event.TaskID, nil, taskAuditState{AgentID: task.AgentID, Status: task.Status})
+	// Runner 준비가 끝난 뒤 같은 Task의 다음 이벤트(continue 등)를 처리하도록 큐 안에서 실행 (턴 종료는 큐에서 기다림)
+	c.executeTask(ctx, event.TaskID, task)
+	return nil
 }
 
 // handleContinueEvent는 기존 Task에 메시지 추가 후 실행 계속 이벤트를 처리합니다.
 // Thread 후속 메시지로 인해 기존 Task를 계속 실행해야 할 때 사용됩니다.
-func (c *Controller) handleContinueEvent(ctx context.Context, event ConnectorEvent) {
+func (c *Controller) handleContinueEvent(ctx context.Context, event ConnectorEvent) error {
 	taskID := event.TaskID
 
 	c.logger.Info("Handling continue event",
@@ -97,49 +193,59 @@ func (c *Controller) handleContinueEvent(ctx context.Context, event ConnectorEve
 			zap.String("task_id", taskID),
 			zap.Error(err),
 		)
-		c.controllerEventChan <- ControllerEvent{
-			TaskID: taskID,
-			Status: "failed",
-			Error:  fmt.Errorf("failed to send one message: %w", err),
-		}
+		c.emit(ControllerEvent{
+			TaskID:  taskID,
+			Status:  "failed",
+			Error:   fmt.Errorf("failed to send one message: %w", err),
+			LogTail: c.runnerLogTail(taskID),
+		})
+		return err
 	}
+	return nil
 }
 
 // handleCancelEvent는 Task 취소 이벤트를 처리합니다.
-func (c *Controller) handleCancelEvent(ctx context.Context, event ConnectorEvent) {
+// OpenCode 세션을 중단하고 중단이 확인되면 canceled 이벤트를 보냅니다.
+// CancelTask와 마찬가지로 running 상태가 아닌 Task는 상태를 바꾸지 않고 "not running"으로 응답합니다.
+func (c *Controller) handleCancelEvent(ctx context.Context, event ConnectorEvent) error {
 	c.logger.Info("Canceling task",
 		zap.String("task_id", event.TaskID),
 	)
 
-	// TaskContext에서 cancel 호출
-	c.mu.RLock()
-	taskCtx, ok := c.taskContexts[event.TaskID]
-	c.mu.RUnlock()
-
-	if !ok {
-		c.logger.Warn("Task context not found for cancellation",
-			zap.String("task_id", event.TaskID),
-		)
-		c.controllerEventChan <- ControllerEvent{
-			TaskID: event.TaskID,
-			Status: "failed",
-			Error:  fmt.Errorf("task not running"),
+	agentID := event.AgentName
+	if c.repo != nil {
+		if task, err := c.repo.GetTask(ctx, event.TaskID); err == nil {
+			if task.Status != storage.TaskStatusRunning {
+				err := fmt.Errorf("task is not running: %s (status: %s)", event.TaskID, task.Status)
+				c.reportCancelFailure(event.TaskID, err)
+				return err
+			}
+			agentID = task.AgentID
 		}
-		return
 	}
 
-	// Context 취소
-	taskCtx.cancel()
-
-	c.controllerEventChan <- ControllerEvent{
-		TaskID:  event.TaskID,
-		Status:  "canceled",
-		Content: "Task canceled by user",
+	if err := c.abortTask(ctx, event.TaskID, agentID); err != nil {
+		c.reportCancelFailure(event.TaskID, err)
+		return err
 	}
+	return nil
+}
+
+// reportCancelFailure는 취소하지 못한 Task를 "task not running" 이벤트로 알립니다.
+func (c *Controller) reportCancelFailure(taskID string, err error) {
+	c.logger.Warn("Failed to cancel task",
+		zap.String("task_id", taskID),
+		zap.Error(err),
+	)
+	c.emit(ControllerEvent{
+		TaskID: taskID,
+		Status: "failed",
+		Error:  fmt.Errorf("task not running: %w", err),
+	})
 }
 
 // handleCompleteEvent handles the explicit task completion event.
-func (c *Controller) handleCompleteEvent(ctx context.Context, event ConnectorEvent) {
+func (c *Controller) handleCompleteEvent(ctx context.Context, event ConnectorEvent) error {
 	taskID := event.TaskID
 
 	c.logger.Info("Handling complete event",
@@ -153,12 +259,12 @@ func (c *Controller) handleCompleteEvent(ctx context.Context, event ConnectorEve
 			zap.String("task_id", taskID),
 			zap.Error(err),
 		)
-		c.controllerEventChan <- ControllerEvent{
+		c.emit(ControllerEvent{
 			TaskID: taskID,
 			Status: "failed",
 			Error:  fmt.Errorf("task not found: %w", err),
-		}
-		return
+		})
+		return err
 	}
 
 	// 2. Task 상태를 completed로 변경
@@ -167,15 +273,20 @@ func (c *Controller) handleCompleteEvent(ctx context.Context, event ConnectorEve
 			zap.String("task_id", taskID),
 			zap.Error(err),
 		)
-		c.controllerEventChan <- ControllerEvent{
+		c.emit(ControllerEvent{
 			TaskID: taskID,
 			Status: "failed",
 			Error:  fmt.Errorf("failed to update status: %w", err),
-		}
-		return
+		})
+		return err
 	}
 
+	c.audit(ctx, AuditTaskComplete, AuditTargetTask, taskID,
+		taskAuditState{AgentID: task.AgentID, Status: task.Status}, taskAuditState{AgentID: task.AgentID, Status: storage.TaskStatusCompleted})
+
 	// 3. Runner 삭제 (명시적 완료 시에만)
+	c.dropTaskPermissions(taskID)
+	c.dropTranscript(taskID)
 	if err := c.runnerManager.DeleteRunner(ctx, taskID); err != nil {
 		c.logger.Warn("Failed to delete runner on complete",
 			zap.String("task_id", taskID),
@@ -187,13 +298,39 @@ func (c *Controller) handleCompleteEvent(ctx context.Context, event ConnectorEve
 	c.cleanupTaskContext(taskID)
 
 	// 4. completed 이벤트 전송
-	c.controllerEventChan <- ControllerEvent{
+	c.emit(ControllerEvent{
 		TaskID:  taskID,
 		Status:  "completed",
 		Content: "Task completed successfully",
-	}
+	})
 
 	c.logger.Info("Task completed explicitly",
 		zap.String("task_id", taskID),
 	)
+	return nil
+}
+
+// handlePermissionEvent는 도구 권한 요청에 대한 사용자 응답 이벤트를 처리합니다.
+func (c *Controller) handlePermissionEvent(ctx context.Context, event ConnectorEvent) error {
+	c.logger.Info("Responding to permission request",
+		zap.String("task_id", event.TaskID),
+		zap.String("permission_id", event.PermissionID),
+		zap.String("response", event.Response),
+	)
+
+	if err := c.RespondPermission(ctx, event.TaskID, event.PermissionID, event.Response); err != nil {
+		c.logger.Error("Failed to respond permission",
+			zap.String("task_id", event.TaskID),
+			zap.String("permission_id", event.PermissionID),
+			zap.Error(err),
+		)
+		c.emit(ControllerEvent{
+			TaskID:    event.TaskID,
+			EventType: EventTypeError,
+			Status:    "error",
+			Error:     fmt.Errorf("failed to respond permission: %w", err),
+		})
+		return err
+	}
+	return nil
 }
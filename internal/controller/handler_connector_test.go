@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestConnectorEventDedupAndOrdering(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:connector_dedup?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	events := make(chan ControllerEvent, 20)
+	newCtrl := func() *Controller {
+		return NewController(zaptest.NewLogger(t), repo, make(chan ConnectorEvent), events)
+	}
+	next := func() ControllerEvent {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for controller event")
+			return ControllerEvent{}
+		}
+	}
+	permission := func(id, permissionID string) ConnectorEvent {
+		return ConnectorEvent{Type: "permission", ID: id, TaskID: "thread-1", PermissionID: permissionID, Response: storage.PermissionResponseOnce}
+	}
+	idle := func(c *Controller) {
+		assert.Eventually(t, func() bool {
+			c.queueMu.Lock()
+			defer c.queueMu.Unlock()
+			return len(c.eventQueues) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	forgotten := func(c *Controller, id string) {
+		assert.Eventually(t, func() bool {
+			c.dedupMu.Lock()
+			defer c.dedupMu.Unlock()
+			_, seen := c.seenEvents[id]
+			return !seen
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	ctrl := newCtrl()
+	require.NoError(t, ctrl.CreateAgent(ctx, "dedup-agent", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "thread-3", AgentID: "dedup-agent", Status: storage.TaskStatusRunning}))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "thread-6", AgentID: "dedup-agent", Status: storage.TaskStatusCompleted}))
+
+	// 같은 Task의 이벤트는 도착 순서대로 처리됨
+	sendMessage := ConnectorEvent{Type: "continue", TaskID: "thread-1", Prompt: "hi"}
+	complete := ConnectorEvent{Type: "complete", TaskID: "thread-1"}
+	for _, e := range []ConnectorEvent{sendMessage, complete, sendMessage, complete} {
+		ctrl.dispatchConnectorEvent(ctx, e)
+	}
+	for _, want := range []string{"failed to send one message", "task not found", "failed to send one message", "task not found"} {
+		assert.True(t, strings.HasPrefix(next().Error.Error(), want))
+	}
+
+	// 같은 ID의 이벤트는 한 번만 처리됨
+	done := ConnectorEvent{Type: "complete", ID: "click-1", TaskID: "thread-3"}
+	ctrl.dispatchConnectorEvent(ctx, done)
+	ctrl.dispatchConnectorEvent(ctx, done)
+	assert.Equal(t, "completed", next().Status)
+	idle(ctrl)
+	assert.Empty(t, events)
+
+	// 처리에 실패한 이벤트는 같은 ID로 다시 보내면 다시 처리됨
+	for i := 0; i < 2; i++ {
+		ctrl.dispatchConnectorEvent(ctx, permission("click-2", "per_1"))
+		assert.Contains(t, next().Error.Error(), "per_1")
+		forgotten(ctrl, "click-2")
+	}
+
+	// 실패한 execute 이벤트 ID는 저장소에서도 지워져 재시작 뒤 다시 처리됨
+	execute := ConnectorEvent{Type: "execute", ID: "discord:message:1", TaskID: "thread-2", AgentName: "missing-agent", Prompt: "hi"}
+	ctrl.dispatchConnectorEvent(ctx, execute)
+	assert.Equal(t, "thread-2", next().TaskID)
+	assert.Eventually(t, func() bool {
+		var n int64
+		return db.Model(&storage.ProcessedEvent{}).Where("event_id = ?", execute.ID).Count(&n).Error == nil && n == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	restarted := newCtrl()
+	restarted.dispatchConnectorEvent(ctx, execute)
+	assert.Equal(t, "thread-2", next().TaskID)
+
+	// 처리한 execute 이벤트 ID는 재시작 뒤에도 다시 처리되지 않음
+	processed, err := repo.MarkEventProcessed(ctx, &storage.ProcessedEvent{EventID: "discord:message:2", Type: "execute", TaskID: "thread-4"})
+	require.NoError(t, err)
+	require.True(t, processed)
+	restarted.dispatchConnectorEvent(ctx, ConnectorEvent{Type: "execute", ID: "discord:message:2", TaskID: "thread-4", AgentName: "missing-agent"})
+	idle(restarted)
+	assert.Empty(t, events)
+
+	// 취소, 권한 응답, Task가 없는 이벤트는 앞선 이벤트의 처리를 기다리지 않음
+	ctrl.queueMu.Lock()
+	ctrl.eventQueues["thread-5"] = []ConnectorEvent{} // 처리 중인 이벤트가 끝나지 않은 상태
+	ctrl.eventQueues["thread-6"] = []ConnectorEvent{}
+	ctrl.eventQueues[""] = []ConnectorEvent{}
+	ctrl.queueMu.Unlock()
+	ctrl.dispatchConnectorEvent(ctx, ConnectorEvent{Type: "cancel", TaskID: "thread-5"})
+	assert.Contains(t, next().Error.Error(), "task not running")
+	ctrl.dispatchConnectorEvent(ctx, ConnectorEvent{Type: "cancel", TaskID: "thread-6"})
+	assert.Contains(t, next().Error.Error(), "status: completed")
+	notRunning, err := repo.GetTask(ctx, "thread-6")
+	require.NoError(t, err)
+	assert.Equal(t, storage.TaskStatusCompleted, notRunning.Status)
+	ctrl.dispatchConnectorEvent(ctx, ConnectorEvent{Type: "permission", TaskID: "thread-5", PermissionID: "per_2", Response: storage.PermissionResponseOnce})
+	assert.Contains(t, next().Error.Error(), "per_2")
+	ctrl.dispatchConnectorEvent(ctx, ConnectorEvent{Type: "complete"})
+	assert.Contains(t, next().Error.Error(), "task not found")
+	ctrl.queueMu.Lock()
+	delete(ctrl.eventQueues, "thread-5")
+	delete(ctrl.eventQueues, "thread-6")
+	delete(ctrl.eventQueues, "")
+	ctrl.queueMu.Unlock()
+
+	// 중복 검사 기간이 지나면 같은 ID도 다시 처리 (execute 제외)
+	expiring := NewController(zaptest.NewLogger(t), repo, make(chan ConnectorEvent), events, WithEventDedupWindow(time.Millisecond))
+	expiring.dispatchConnectorEvent(ctx, ConnectorEvent{Type: "complete", ID: "click-3", TaskID: "thread-3"})
+	assert.Equal(t, "completed", next().Status)
+	time.Sleep(5 * time.Millisecond)
+	expiring.dispatchConnectorEvent(ctx, ConnectorEvent{Type: "complete", ID: "click-3", TaskID: "thread-3"})
+	assert.Equal(t, "completed", next().Status)
+
+	// 기간이 지난 ID는 주기적으로 정리
+	expiring.pruneSeenEvents(time.Now().Add(time.Second))
+	assert.Empty(t, expiring.seenEvents)
+
+	// 처리가 끝난 Task 큐는 정리됨
+	idle(ctrl)
+	idle(expiring)
+}
+
+func TestConnectorCancelEventWithoutRepository(t *testing.T) {
+	events := make(chan ControllerEvent, 1)
+	ctrl := NewController(zaptest.NewLogger(t), nil, make(chan ConnectorEvent), events)
+
+	ctrl.dispatchConnectorEvent(context.Background(), ConnectorEvent{Type: "cancel", TaskID: "thread-1"})
+	select {
+	case e := <-events:
+		assert.Equal(t, "failed", e.Status)
+		assert.Contains(t, e.Error.Error(), "task not running")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for controller event")
+	}
+}
@@ -19,187 +19,151 @@ func (c *Controller) OnStarted(taskID string, sessionID string) error {
 		zap.String("session_id", sessionID),
 	)
 
-	// 세션 ID를 Task 메타데이터에 저장할 수 있음 (추후 Phase에서 구현)
+	// 어시스턴트 메시지 기록 시 하위 세션을 구분하기 위해 루트 세션 ID 저장
+	c.setTranscriptSession(taskID, sessionID)
 	return nil
 }
 
 // OnEvent는 Runner가 SSE 이벤트를 수신할 때 호출됩니다.
 // 이를 통해 Connector에 실시간으로 메시지를 전달합니다.
-func (c *Controller) OnEvent(taskID string, evt *opencode.Event) error {
+func (c *Controller) OnEvent(taskID string, msg *opencode.RunnerMessage) error {
 	c.logger.Debug("OnEvent callback",
 		zap.String("task_id", taskID),
-		zap.String("event_type", evt.Type),
-		zap.Any("properties", evt.Properties),
+		zap.String("message_type", string(msg.Type)),
+		zap.String("session_id", msg.SessionID),
 	)
 
+	// 완료된 어시스턴트 파트를 메시지 파일에 기록
+	c.recordTranscript(taskID, msg)
+
 	event := ControllerEvent{
-		TaskID: taskID,
+		TaskID:    taskID,
+		MessageID: msg.MessageID,
+		PartID:    msg.PartID,
 	}
 
-	switch evt.Type {
-	case "message.part.updated":
-		// 파트 정보 추출
-		if props, ok := evt.Properties["part"].(map[string]interface{}); ok {
-			partType, _ := props["type"].(string)
-			partID, _ := props["id"].(string)
-			messageID, _ := props["messageID"].(string)
-
-			event.PartID = partID
-			event.MessageID = messageID
-
-			switch partType {
-			case "text":
-				// delta 필드가 있으면 부분 업데이트
-				if delta, hasDelta := evt.Properties["delta"].(string); hasDelta {
-					event.EventType = EventTypeStreamDelta
-					event.Delta = delta
-					event.IsPartial = true
-				} else {
-					event.EventType = EventTypePartComplete
-					if text, ok := props["text"].(string); ok {
-						event.Content = text
-					}
-					event.IsPartial = false
-
-					// PartComplete 시 메시지 role 정보 가져오기
-					if messageID != "" {
-						c.logger.Debug("Fetching message role",
-							zap.String("task_id", taskID),
-							zap.String("message_id", messageID),
-						)
-						c.fetchMessageRole(taskID, messageID, &event)
-					}
-				}
-				event.PartType = PartTypeText
-				event.Status = "message" // 하위 호환성
-
-			case "reasoning":
-				// delta 필드가 있으면 부분 업데이트
-				if delta, hasDelta := evt.Properties["delta"].(string); hasDelta {
-					event.EventType = EventTypeStreamDelta
-					event.Delta = delta
-					event.IsPartial = true
-				} else {
-					event.EventType = EventTypePartComplete
-					if text, ok := props["text"].(string); ok {
-						event.Content = text
-					}
-					event.IsPartial = false
-					// PartComplete 시 메시지 role 정보 가져오기
-					if messageID != "" {
-						c.fetchMessageRole(taskID, messageID, &event)
-					}
-				}
-				event.PartType = PartTypeReasoning
-				event.Status = "reasoning"
-
-			case "tool":
-				// 도구 상태 확인
-				if state, ok := props["state"].(map[string]interface{}); ok {
-					status, _ := state["status"].(string)
-					callID, _ := props["callID"].(string)
-					tool, _ := props["tool"].(string)
-
-					if status == "running" || status == "pending" {
-						event.EventType = EventTypeToolStart
-						event.PartType = PartTypeTool
-						event.Status = "tool_start"
-						event.ToolInfo = &ToolEventInfo{
-							CallID:   callID,
-							ToolName: tool,
-						}
-						if input, ok := state["input"].(map[string]interface{}); ok {
-							event.ToolInfo.Input = input
-						}
-					} else {
-						event.PartType = PartTypeTool
-						isError := status == "error"
-						if isError {
-							event.EventType = EventTypeToolError
-							event.Status = "tool_error"
-						} else {
-							event.EventType = EventTypeToolComplete
-							event.Status = "tool_complete"
-						}
-						event.ToolInfo = &ToolEventInfo{
-							CallID:   callID,
-							ToolName: tool,
-						}
-						if output, ok := state["output"].(string); ok {
-							if isError {
-								event.ToolInfo.Error = output
-							} else {
-								event.ToolInfo.Output = output
-							}
-						}
-					}
-				}
-			default:
-				// 지원하지 않는 파트 타입은 무시
-				return nil
+	switch msg.Type {
+	case opencode.MessageTypeText, opencode.MessageTypeReasoning:
+		// delta 키가 있으면 (빈 값이어도) 부분 업데이트
+		if msg.IsPartial {
+			event.EventType = EventTypeStreamDelta
+			event.Delta = msg.Delta
+			event.IsPartial = true
+		} else {
+			event.EventType = EventTypePartComplete
+			event.Content = msg.Content
+
+			// PartComplete 시 메시지 role 정보 가져오기
+			if msg.MessageID != "" {
+				c.fetchMessageRole(taskID, msg.MessageID, &event)
 			}
 		}
+		if msg.Type == opencode.MessageTypeText {
+			event.PartType = PartTypeText
+			event.Status = "message" // 하위 호환성
+		} else {
+			event.PartType = PartTypeReasoning
+			event.Status = "reasoning"
+		}
+
+	case opencode.MessageTypeToolCall:
+		event.EventType = EventTypeToolStart
+		event.PartType = PartTypeTool
+		event.Status = "tool_start"
+		event.ToolInfo = &ToolEventInfo{
+			CallID:   msg.ToolCall.ToolID,
+			ToolName: msg.ToolCall.ToolName,
+			Input:    msg.ToolCall.Arguments,
+		}
+
+	case opencode.MessageTypeToolResult:
+		event.PartType = PartTypeTool
+		event.ToolInfo = &ToolEventInfo{
+			CallID:   msg.ToolResult.ToolID,
+			ToolName: msg.ToolResult.ToolName,
+		}
+		if msg.ToolResult.IsError {
+			event.EventType = EventTypeToolError
+			event.Status = "tool_error"
+			event.ToolInfo.Error = msg.ToolResult.Result
+		} else {
+			event.EventType = EventTypeToolComplete
+			event.Status = "tool_complete"
+			event.ToolInfo.Output = msg.ToolResult.Result
+		}
 
-	case "message.completed":
+	case opencode.MessageTypeComplete:
 		event.EventType = EventTypeMessageComplete
 		event.Status = "message_complete"
-		if messageID, ok := evt.Properties["messageID"].(string); ok {
-			event.MessageID = messageID
+
+	case opencode.MessageTypeStatus:
+		c.logger.Info("Session status changed",
+			zap.String("task_id", taskID),
+			zap.String("status_type", msg.Status),
+		)
+
+		// 턴 종료(idle)는 Runner가 OnComplete로 보고하므로 여기서는 상태를 변경하지 않음
+		return nil
+
+	case opencode.MessageTypeStreamStatus:
+		// 재연결 중에는 응답이 지연될 수 있으므로 Connector가 상태를 표시하도록 전달
+		c.logger.Warn("Runner event stream status changed",
+			zap.String("task_id", taskID),
+			zap.String("stream_status", msg.Status),
+		)
+		event.EventType = EventTypeStreamStatus
+		event.Status = msg.Status
+		if msg.Error != nil {
+			event.Content = msg.Error.Message
 		}
 
-	case "session.status":
-		// 세션 상태 변경 이벤트 처리
-		// status는 객체이고 type 필드를 가지고 있음
-		if statusObj, ok := evt.Properties["status"].(map[string]interface{}); ok {
-			if statusType, ok := statusObj["type"].(string); ok {
-				c.logger.Info("Session status changed",
-					zap.String("task_id", taskID),
-					zap.String("status_type", statusType),
-				)
-
-				// status.type이 idle이고 Runner status가 running이면 Task status를 waiting으로 변경
-				if statusType == "idle" {
-					runner := c.runnerManager.GetRunner(taskID)
-					if runner != nil && runner.Status == taskrunner.RunnerStatusRunning {
-						c.logger.Info("Changing task status to waiting",
-							zap.String("task_id", taskID),
-							zap.String("runner_status", runner.Status),
-						)
-						// Task 상태를 waiting으로 업데이트
-						if err := c.UpdateTaskStatus(context.Background(), taskID, storage.TaskStatusWaiting); err != nil {
-							c.logger.Error("Failed to update task status to waiting",
-								zap.String("task_id", taskID),
-								zap.Error(err),
-							)
-						}
-					}
-				}
-			}
+	case opencode.MessageTypePermission:
+		if msg.Permission == nil {
+			return nil
 		}
-		return nil
+		event.EventType = EventTypePermissionRequest
+		event.Status = "permission_request"
+		event.Permission = c.handlePermissionRequest(taskID, msg.Permission)
+
+	case opencode.MessageTypeSessionAborted:
+		// 사용자가 취소한 경우 canceled 이벤트는 abortTask에서 보냄
+		if runner := c.runnerManager.GetRunner(taskID); runner != nil && runner.IsAborting() {
+			return nil
+		}
+		event.EventType = EventTypeError
+		event.Status = "error"
+		event.Error = NewEventError(ErrorCodeSessionAborted, true, fmt.Errorf("session aborted"))
 
-	case "session.aborted":
+	case opencode.MessageTypeError:
 		event.EventType = EventTypeError
 		event.Status = "error"
-		event.Error = fmt.Errorf("session aborted")
+		event.Error = NewEventError(ErrorCodeSession, false, fmt.Errorf("session error: %s", describeMessageError(msg.Error)))
 
 	default:
-		// 알 수 없는 이벤트 타입은 무시
-		c.logger.Debug("Unknown event type, skipping",
-			zap.String("event_type", evt.Type),
+		// 처리하지 않는 메시지 타입은 무시
+		c.logger.Debug("Unhandled message type, skipping",
+			zap.String("message_type", string(msg.Type)),
 		)
 		return nil
 	}
 
-	// 이벤트 전송 (빈 이벤트는 무시)
-	if event.EventType != "" {
-		c.controllerEventChan <- event
-	}
-
+	c.emit(event)
 	return nil
 }
 
-// OnComplete는 Task가 완료될 때 호출됩니다.
+// describeMessageError는 OpenCode 에러 정보를 사람이 읽을 수 있는 문자열로 변환합니다.
+func describeMessageError(info *opencode.MessageErrorInfo) string {
+	if info == nil {
+		return "unknown error"
+	}
+	if info.Message != "" {
+		return fmt.Sprintf("%s: %s", info.Code, info.Message)
+	}
+	return info.Code
+}
+
+// OnComplete는 Runner의 한 턴이 끝날 때 호출됩니다.
+// 조립된 어시스턴트 메시지를 저장하고 Task를 다음 메시지를 기다리는 waiting 상태로 변경합니다.
 func (c *Controller) OnComplete(taskID string, result *taskrunner.RunResult) error {
 	c.logger.Debug("OnComplete callback",
 		zap.String("task_id", taskID),
@@ -207,29 +171,26 @@ func (c *Controller) OnComplete(taskID string, result *taskrunner.RunResult) err
 		zap.String("output", result.Output),
 	)
 
-	// 결과를 파일로 저장
+	// 턴의 메시지 파일을 최종 어시스턴트 메시지로 마무리 (파트는 완료될 때마다 이미 기록됨)
 	if result.Success {
-		filePath, err := c.saveMessageToFile(context.Background(), taskID, "assistant", result.Output)
-		if err != nil {
+		if err := c.finishTranscript(context.Background(), taskID, result.Output); err != nil {
 			c.logger.Error("Failed to save result to file", zap.Error(err))
 			return err
 		}
-
-		// MessageIndex에 추가
-		if _, err := c.repo.AppendMessageIndex(context.Background(), taskID, "assistant", filePath); err != nil {
-			c.logger.Error("Failed to append message index", zap.Error(err))
-			return err
-		}
 	}
 
-	c.controllerEventChan <- ControllerEvent{
-		TaskID:  taskID,
-		Status:  "completed",
-		Content: result.Output,
+	// 상태를 waiting으로 변경 (세션은 유지되며 다음 메시지로 이어서 실행 가능)
+	if err := c.UpdateTaskStatus(context.Background(), taskID, storage.TaskStatusWaiting); err != nil {
+		return err
 	}
 
-	// 상태를 completed로 변경
-	return c.UpdateTaskStatus(context.Background(), taskID, storage.TaskStatusCompleted)
+	c.emit(ControllerEvent{
+		TaskID:    taskID,
+		EventType: EventTypeTurnComplete,
+		Status:    "turn_complete",
+		Content:   result.Output,
+	})
+	return nil
 }
 
 // OnError는 Task 실행 중 에러가 발생할 때 호출됩니다.
@@ -239,16 +200,52 @@ func (c *Controller) OnError(taskID string, err error) error {
 		zap.Error(err),
 	)
 
-	c.controllerEventChan <- ControllerEvent{
-		TaskID: taskID,
-		Status: "failed",
-		Error:  err,
+	// 에러 전까지 기록된 파트는 유지
+	if saveErr := c.finishTranscript(context.Background(), taskID, ""); saveErr != nil {
+		c.logger.Error("Failed to save transcript", zap.Error(saveErr))
 	}
 
+	c.emit(ControllerEvent{
+		TaskID:  taskID,
+		Status:  "failed",
+		Error:   NewEventError(ErrorCodeRunner, true, err),
+		LogTail: c.runnerLogTail(taskID),
+	})
+
 	// 상태를 failed로 변경
 	return c.UpdateTaskStatus(context.Background(), taskID, storage.TaskStatusFailed)
 }
 
+// runnerLogTail은 Task의 Runner Container 로그 마지막 N줄을 반환합니다.
+// Runner가 이미 삭제된 경우에도 작업 공간에 남은 로그 파일에서 읽습니다.
+func (c *Controller) runnerLogTail(taskID string) []string {
+	if runner := c.runnerManager.GetRunner(taskID); runner != nil {
+		return runner.TailLogs(0)
+	}
+
+	if c.repo == nil {
+		return nil
+	}
+	task, err := c.repo.GetTask(context.Background(), taskID)
+	if err != nil {
+		return nil
+	}
+	workspacePath, err := taskrunner.AgentWorkspacePath(task.AgentID)
+	if err != nil {
+		return nil
+	}
+
+	lines, err := taskrunner.ReadLogTail(taskrunner.TaskLogPath(workspacePath, taskID), taskrunner.DefaultLogConfig().TailLines)
+	if err != nil {
+		c.logger.Warn("Failed to read runner logs",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return lines
+}
+
 // fetchMessageRole은 메시지 ID로부터 role 정보를 가져와 이벤트에 설정합니다.
 func (c *Controller) fetchMessageRole(taskID string, messageID string, event *ControllerEvent) {
 	// RunnerManager에서 Runner 가져오기
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/cnap-oss/app/internal/storage"
+)
+
+// MessageFileVersion은 현재 메시지 파일 형식 버전입니다.
+// 버전 1(필드 없음)은 role, content, timestamp만 저장하며, 버전 2부터 parts에 구조화된 파트를 저장합니다.
+const MessageFileVersion = 2
+
+// MessageFile은 메시지 저장소의 {taskID}/{index}.json 항목 내용입니다.
+type MessageFile struct {
+	Version    int           `json:"version,omitempty"`
+	Role       string        `json:"role"`
+	Author     string        `json:"author,omitempty"` // 작성자 ({connector}:{id})
+	Content    string        `json:"content"`          // 텍스트 파트를 합친 내용 (버전 1 호환)
+	Timestamp  string        `json:"timestamp"`
+	SessionID  string        `json:"session_id,omitempty"`  // OpenCode 세션 ID
+	MessageIDs []string      `json:"message_ids,omitempty"` // 턴에 포함된 OpenCode 메시지 ID (step마다 생성됨)
+	Model      string        `json:"model,omitempty"`       // 응답한 모델 ({provider}/{model})
+	Usage      *TokenUsage   `json:"usage,omitempty"`       // 턴의 토큰 사용량 합계
+	Parts      []MessagePart `json:"parts,omitempty"`
+	RedactedAt string        `json:"redacted_at,omitempty"` // 사용자 요청으로 내용을 지운 시각 (RFC3339)
+}
+
+// TokenUsage는 어시스턴트 턴에서 사용한 토큰 수와 비용입니다.
+type TokenUsage struct {
+	Input      int     `json:"input"`
+	Output     int     `json:"output"`
+	Reasoning  int     `json:"reasoning,omitempty"`
+	CacheRead  int     `json:"cache_read,omitempty"`
+	CacheWrite int     `json:"cache_write,omitempty"`
+	Cost       float64 `json:"cost,omitempty"`
+}
+
+// newTokenUsage는 OpenCode 어시스턴트 메시지의 토큰 정보를 변환합니다.
+func newTokenUsage(m opencode.AssistantMessage) TokenUsage {
+	return TokenUsage{
+		Input:      m.Tokens.Input,
+		Output:     m.Tokens.Output,
+		Reasoning:  m.Tokens.Reasoning,
+		CacheRead:  m.Tokens.Cache.Read,
+		CacheWrite: m.Tokens.Cache.Write,
+		Cost:       m.Cost,
+	}
+}
+
+// Add는 다른 사용량을 더합니다.
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.Input += other.Input
+	u.Output += other.Output
+	u.Reasoning += other.Reasoning
+	u.CacheRead += other.CacheRead
+	u.CacheWrite += other.CacheWrite
+	u.Cost += other.Cost
+}
+
+// Total은 입력, 출력, 추론 토큰의 합입니다.
+func (u TokenUsage) Total() int {
+	return u.Input + u.Output + u.Reasoning
+}
+
+// MessagePart는 메시지 파일에 저장되는 OpenCode 파트입니다.
+type MessagePart struct {
+	ID        string         `json:"id"`
+	MessageID string         `json:"message_id"`
+	Type      PartType       `json:"type"`
+	Text      string         `json:"text,omitempty"`    // text, reasoning
+	Tool      string         `json:"tool,omitempty"`    // tool
+	CallID    string         `json:"call_id,omitempty"` // tool
+	Status    string         `json:"status,omitempty"`  // tool: completed, error
+	Title     string         `json:"title,omitempty"`   // tool
+	Input     map[string]any `json:"input,omitempty"`   // tool
+	Output    string         `json:"output,omitempty"`  // tool
+	Error     string         `json:"error,omitempty"`   // tool
+	StartedAt *time.Time     `json:"started_at,omitempty"`
+	EndedAt   *time.Time     `json:"ended_at,omitempty"`
+}
+
+// newMessageFile은 현재 형식의 메시지 파일을 생성합니다.
+func newMessageFile(role, content string) *MessageFile {
+	return &MessageFile{
+		Version:   MessageFileVersion,
+		Role:      role,
+		Content:   content,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// newMessagePart는 완료된 OpenCode 파트를 메시지 파트로 변환합니다.
+// text, reasoning, tool 외의 파트는 저장하지 않습니다.
+func newMessagePart(part *opencode.Part) (MessagePart, bool) {
+	mp := MessagePart{
+		ID:        part.ID,
+		MessageID: part.MessageID,
+	}
+
+	switch part.Type {
+	case "text", "reasoning":
+		mp.Type = PartTypeText
+		if part.Type == "reasoning" {
+			mp.Type = PartTypeReasoning
+		}
+		mp.Text = part.Text
+		if part.Time != nil {
+			mp.StartedAt = unixMilliTime(part.Time.Start)
+			if part.Time.End != nil {
+				mp.EndedAt = unixMilliTime(*part.Time.End)
+			}
+		}
+	case "tool":
+		mp.Type = PartTypeTool
+		mp.Tool = part.Tool
+		mp.CallID = part.CallID
+		if state := part.State; state != nil {
+			mp.Status = state.Status
+			mp.Title = state.Title
+			mp.Input = state.Input
+			mp.Output = state.Output
+			mp.Error = state.Error
+			if state.Time != nil {
+				mp.StartedAt = unixMilliTime(state.Time.Start)
+				if state.Time.End != nil {
+					mp.EndedAt = unixMilliTime(*state.Time.End)
+				}
+			}
+		}
+	default:
+		return MessagePart{}, false
+	}
+	return mp, true
+}
+
+// unixMilliTime은 OpenCode의 밀리초 타임스탬프를 time.Time으로 변환합니다. 0이면 nil입니다.
+func unixMilliTime(ms int64) *time.Time {
+	if ms == 0 {
+		return nil
+	}
+	t := time.UnixMilli(ms).UTC()
+	return &t
+}
+
+// upsertPart는 같은 ID의 파트를 교체하거나 새 파트를 추가합니다.
+func (m *MessageFile) upsertPart(part MessagePart) {
+	for i := range m.Parts {
+		if m.Parts[i].ID == part.ID {
+			m.Parts[i] = part
+			return
+		}
+	}
+	m.Parts = append(m.Parts, part)
+}
+
+// addMessageID는 턴에 포함된 OpenCode 메시지 ID를 중복 없이 기록합니다.
+func (m *MessageFile) addMessageID(messageID string) {
+	for _, id := range m.MessageIDs {
+		if id == messageID {
+			return
+		}
+	}
+	m.MessageIDs = append(m.MessageIDs, messageID)
+}
+
+// partsText는 텍스트 파트를 순서대로 합칩니다.
+func (m *MessageFile) partsText() string {
+	texts := make([]string, 0, len(m.Parts))
+	for _, part := range m.Parts {
+		if part.Type == PartTypeText && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// Text는 메시지의 본문을 반환합니다. content가 비어 있으면 텍스트 파트로 구성합니다.
+func (m *MessageFile) Text() string {
+	if m.Content != "" {
+		return m.Content
+	}
+	return m.partsText()
+}
+
+// ParseMessageFile은 메시지 파일 내용을 파싱합니다. 버전 1 파일(role, content, timestamp)도 그대로 읽을 수 있습니다.
+func ParseMessageFile(data []byte) (*MessageFile, error) {
+	var msg MessageFile
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	// 버전 1 파일은 content가 필수
+	if msg.Version == 0 {
+		var legacy map[string]any
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		if _, ok := legacy["content"].(string); !ok {
+			return nil, fmt.Errorf("content field not found or not a string")
+		}
+	}
+	return &msg, nil
+}
+
+// messageStore는 메시지 저장소를 반환합니다. 기본 저장소를 만들지 못했으면 그 에러를 반환합니다.
+func (c *Controller) messageStore() (storage.MessageStore, error) {
+	if c.messages == nil {
+		if c.messagesErr != nil {
+			return nil, c.messagesErr
+		}
+		return nil, fmt.Errorf("controller: message store is not configured")
+	}
+	return c.messages, nil
+}
+
+// GetMessageFile은 메시지 저장소에서 key(MessageIndex.FilePath)의 메시지를 읽습니다.
+func (c *Controller) GetMessageFile(ctx context.Context, key string) (*MessageFile, error) {
+	store, err := c.messageStore()
+	if err != nil {
+		return nil, err
+	}
+	data, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMessageFile(data)
+}
+
+// putMessageFile은 메시지를 메시지 저장소의 key에 씁니다.
+func (c *Controller) putMessageFile(ctx context.Context, key string, msg *MessageFile) error {
+	data, err := json.MarshalIndent(msg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	store, err := c.messageStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Put(ctx, key, data); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
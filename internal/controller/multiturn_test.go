@@ -238,7 +238,9 @@ func TestOnEventCallback(t *testing.T) {
 			},
 		},
 	}
-	err = ctrl.OnEvent(taskID, event)
+	msg, err := opencode.DecodeEvent(event)
+	require.NoError(t, err)
+	err = ctrl.OnEvent(taskID, msg)
 	require.NoError(t, err)
 
 	// ControllerEvent 채널에서 이벤트 수신
@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// DefaultPermissionTimeout은 에이전트 정책이 없을 때 권한 요청 응답을 기다리는 시간입니다.
+const DefaultPermissionTimeout = 5 * time.Minute
+
+const (
+	// permissionTimeoutAttempts는 대기 시간 초과 정책 응답을 Runner에 전달하는 최대 시도 횟수입니다.
+	permissionTimeoutAttempts = 3
+	// permissionTimeoutRetryDelay는 정책 응답 전달에 실패한 뒤 다시 시도하기까지 기다리는 시간입니다.
+	permissionTimeoutRetryDelay = 10 * time.Second
+)
+
+// pendingPermission은 사용자 응답을 기다리는 도구 권한 요청입니다.
+type pendingPermission struct {
+	taskID    string
+	info      *PermissionEventInfo
+	onTimeout string
+	timer     *time.Timer
+	attempts  int // 대기 시간 초과 정책 응답을 전달하려고 시도한 횟수
+}
+
+// permissionRegistry는 응답 대기 중인 권한 요청을 권한 요청 ID로 관리합니다.
+type permissionRegistry struct {
+	mu      sync.Mutex
+	pending map[string]*pendingPermission
+}
+
+func newPermissionRegistry() *permissionRegistry {
+	return &permissionRegistry{pending: make(map[string]*pendingPermission)}
+}
+
+// add는 권한 요청을 등록합니다. 같은 ID의 요청이 다시 오면 이전 타이머를 멈추고 교체합니다.
+func (r *permissionRegistry) add(p *pendingPermission) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.pending[p.info.ID]; ok {
+		old.timer.Stop()
+	}
+	r.pending[p.info.ID] = p
+}
+
+// restore는 응답 전달에 실패한 권한 요청을 다시 등록하고 delay 뒤에 expire를 실행합니다.
+// 그 사이 같은 ID의 요청이 새로 등록되었으면 새 요청을 유지합니다.
+func (r *permissionRegistry) restore(p *pendingPermission, delay time.Duration, expire func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pending[p.info.ID]; ok {
+		return
+	}
+	p.timer = time.AfterFunc(delay, expire)
+	r.pending[p.info.ID] = p
+}
+
+// take는 권한 요청을 레지스트리에서 꺼내고 타이머를 멈춥니다.
+// 이미 처리되었거나 다른 Task의 요청이면 nil을 반환합니다.
+func (r *permissionRegistry) take(taskID, permissionID string) *pendingPermission {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pending[permissionID]
+	if !ok || (taskID != "" && p.taskID != taskID) {
+		return nil
+	}
+	delete(r.pending, permissionID)
+	p.timer.Stop()
+	return p
+}
+
+// dropTask는 Task의 대기 중인 권한 요청을 모두 제거합니다.
+func (r *permissionRegistry) dropTask(taskID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dropped := 0
+	for id, p := range r.pending {
+		if p.taskID == taskID {
+			p.timer.Stop()
+			delete(r.pending, id)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// permissionTimeout은 에이전트의 권한 요청 응답 대기 시간을 반환합니다.
+func permissionTimeout(agent *storage.Agent) time.Duration {
+	if agent == nil || agent.PermissionTimeoutSec <= 0 {
+		return DefaultPermissionTimeout
+	}
+	return time.Duration(agent.PermissionTimeoutSec) * time.Second
+}
+
+// permissionOnTimeout은 대기 시간 초과 시 적용할 응답을 반환합니다.
+func permissionOnTimeout(agent *storage.Agent) string {
+	if agent == nil || agent.PermissionOnTimeout == "" {
+		return storage.PermissionResponseReject
+	}
+	return agent.PermissionOnTimeout
+}
+
+// permissionPatterns는 OpenCode의 pattern 필드(문자열 또는 배열)를 문자열 목록으로 변환합니다.
+func permissionPatterns(pattern any) []string {
+	switch p := pattern.(type) {
+	case string:
+		if p == "" {
+			return nil
+		}
+		return []string{p}
+	case []any:
+		patterns := make([]string, 0, len(p))
+		for _, v := range p {
+			if s, ok := v.(string); ok && s != "" {
+				patterns = append(patterns, s)
+			}
+		}
+		return patterns
+	case []string:
+		return p
+	}
+	return nil
+}
+
+// handlePermissionRequest는 permission.updated 메시지를 대기 목록에 등록하고
+// 에이전트 정책의 대기 시간이 지나면 정책 응답을 자동으로 적용합니다.
+func (c *Controller) handlePermissionRequest(taskID string, perm *opencode.PermissionInfo) *PermissionEventInfo {
+	var agent *storage.Agent
+	if c.repo != nil {
+		if task, err := c.repo.GetTask(context.Background(), taskID); err == nil {
+			agent, _ = c.repo.GetAgent(context.Background(), task.AgentID)
+		}
+	}
+	timeout := permissionTimeout(agent)
+
+	info := &PermissionEventInfo{
+		ID:        perm.ID,
+		SessionID: perm.SessionID,
+		Type:      perm.Type,
+		Title:     perm.Title,
+		Patterns:  permissionPatterns(perm.Pattern),
+		CallID:    perm.CallID,
+		Metadata:  perm.Metadata,
+		ExpiresAt: time.Now().Add(timeout),
+	}
+	pending := &pendingPermission{
+		taskID:    taskID,
+		info:      info,
+		onTimeout: permissionOnTimeout(agent),
+	}
+	pending.timer = time.AfterFunc(timeout, func() {
+		c.expirePermission(taskID, info.ID)
+	})
+	c.permissions.add(pending)
+
+	c.logger.Info("Permission requested",
+		zap.String("task_id", taskID),
+		zap.String("permission_id", info.ID),
+		zap.String("type", info.Type),
+		zap.Duration("timeout", timeout),
+	)
+	return info
+}
+
+// RespondPermission은 사용자의 도구 권한 응답(once, always, reject)을 Runner에 전달합니다.
+func (c *Controller) RespondPermission(ctx context.Context, taskID, permissionID, response string) error {
+	if !opencode.PermissionResponse(response).Valid() {
+		return fmt.Errorf("invalid permission response: %s", response)
+	}
+
+	pending := c.permissions.take(taskID, permissionID)
+	if pending == nil {
+		return fmt.Errorf("permission request not found or already resolved: %s", permissionID)
+	}
+	if err := c.resolvePermission(ctx, pending, response, false); err != nil {
+		// 사용자가 다시 응답할 수 있도록 남은 대기 시간으로 되돌려 놓음
+		c.permissions.restore(pending, time.Until(pending.info.ExpiresAt), func() {
+			c.expirePermission(pending.taskID, permissionID)
+		})
+		return err
+	}
+	c.audit(ctx, AuditTaskRespondPermission, AuditTargetTask, taskID, nil, map[string]string{
+		"permission_id": permissionID,
+		"type":          pending.info.Type,
+		"response":      response,
+	})
+	return nil
+}
+
+// expirePermission은 대기 시간이 지난 권한 요청에 에이전트 정책 응답을 적용합니다.
+// 전달에 실패하면 요청을 대기 목록에 되돌려 다시 시도하고(그 사이 사용자 응답도 받음),
+// Runner가 없거나 모든 시도가 실패하면 에러 이벤트로 알려 턴이 말없이 멈추지 않게 합니다.
+func (c *Controller) expirePermission(taskID, permissionID string) {
+	pending := c.permissions.take(taskID, permissionID)
+	if pending == nil {
+		return
+	}
+
+	c.logger.Warn("Permission request timed out",
+		zap.String("task_id", taskID),
+		zap.String("permission_id", permissionID),
+		zap.String("on_timeout", pending.onTimeout),
+	)
+	ctx := WithActor(context.Background(), ConnectorActor(ActorConnectorSystem, "permission-timeout"))
+	if err := c.resolvePermission(ctx, pending, pending.onTimeout, true); err != nil {
+		pending.attempts++
+		runnerGone := c.runnerManager.GetRunner(taskID) == nil
+		if !runnerGone && pending.attempts < permissionTimeoutAttempts {
+			c.logger.Warn("Failed to apply permission timeout policy, retrying",
+				zap.String("task_id", taskID),
+				zap.String("permission_id", permissionID),
+				zap.Int("attempt", pending.attempts),
+				zap.Error(err),
+			)
+			c.permissions.restore(pending, permissionTimeoutRetryDelay, func() {
+				c.expirePermission(taskID, permissionID)
+			})
+			return
+		}
+
+		c.logger.Error("Failed to apply permission timeout policy",
+			zap.String("task_id", taskID),
+			zap.String("permission_id", permissionID),
+			zap.Int("attempts", pending.attempts),
+			zap.Error(err),
+		)
+		c.emit(ControllerEvent{
+			TaskID:     taskID,
+			EventType:  EventTypeError,
+			Status:     "error",
+			Permission: pending.info,
+			Error:      NewEventError(ErrorCodeRunner, false, fmt.Errorf("failed to apply permission timeout policy: %w", err)),
+		})
+		return
+	}
+	c.audit(ctx, AuditTaskRespondPermission, AuditTargetTask, taskID, nil, map[string]string{
+		"permission_id": permissionID,
+		"type":          pending.info.Type,
+		"response":      pending.onTimeout,
+	})
+}
+
+// resolvePermission은 응답을 OpenCode에 전달하고 처리 결과를 Connector에 알립니다.
+func (c *Controller) resolvePermission(ctx context.Context, pending *pendingPermission, response string, timedOut bool) error {
+	runner := c.runnerManager.GetRunner(pending.taskID)
+	if runner == nil {
+		return fmt.Errorf("runner not found for task: %s", pending.taskID)
+	}
+
+	if err := runner.RespondPermission(ctx, pending.info.SessionID, pending.info.ID, opencode.PermissionResponse(response)); err != nil {
+		return fmt.Errorf("failed to respond permission: %w", err)
+	}
+
+	resolved := *pending.info
+	resolved.Response = response
+	resolved.TimedOut = timedOut
+	c.emit(ControllerEvent{
+		TaskID:     pending.taskID,
+		EventType:  EventTypePermissionResolved,
+		Status:     "permission_resolved",
+		Permission: &resolved,
+	})
+	return nil
+}
+
+// dropTaskPermissions는 종료된 Task의 대기 중인 권한 요청을 정리합니다.
+func (c *Controller) dropTaskPermissions(taskID string) {
+	if n := c.permissions.dropTask(taskID); n > 0 {
+		c.logger.Info("Dropped pending permission requests",
+			zap.String("task_id", taskID),
+			zap.Int("count", n),
+		)
+	}
+}
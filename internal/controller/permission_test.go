@@ -0,0 +1,90 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestControllerPermissionRequest(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:permission?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	controllerEventChan := make(chan controller.ControllerEvent, 10)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 10), controllerEventChan)
+
+	ctx := context.Background()
+	require.NoError(t, ctrl.CreateAgent(ctx, "perm-agent", "Permission agent", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.SetAgentPermissionPolicy(ctx, "perm-agent", time.Second, storage.PermissionResponseOnce))
+	// Runner 없이 정책 동작만 확인하기 위해 Task 레코드를 직접 생성
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "perm-task", AgentID: "perm-agent", Status: storage.TaskStatusRunning}))
+
+	info, err := ctrl.GetAgentInfo(ctx, "perm-agent")
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, info.PermissionTimeout)
+	assert.Equal(t, storage.PermissionResponseOnce, info.PermissionOnTimeout)
+
+	require.NoError(t, ctrl.OnEvent("perm-task", &opencode.RunnerMessage{
+		Type:      opencode.MessageTypePermission,
+		SessionID: "ses_1",
+		Permission: &opencode.PermissionInfo{
+			ID:        "per_1",
+			Type:      "bash",
+			Pattern:   []any{"git push", "git push *"},
+			SessionID: "ses_1",
+			Title:     "git push origin main",
+			Metadata:  map[string]any{"command": "git push origin main"},
+		},
+	}))
+
+	event := <-controllerEventChan
+	require.Equal(t, controller.EventTypePermissionRequest, event.EventType)
+	require.True(t, event.IsPermissionEvent())
+	require.NotNil(t, event.Permission)
+	assert.Equal(t, "per_1", event.Permission.ID)
+	assert.Equal(t, "bash", event.Permission.Type)
+	assert.Equal(t, []string{"git push", "git push *"}, event.Permission.Patterns)
+	assert.WithinDuration(t, time.Now().Add(time.Second), event.Permission.ExpiresAt, 500*time.Millisecond)
+
+	// 잘못된 응답, 다른 Task의 응답은 거부
+	assert.Error(t, ctrl.RespondPermission(ctx, "perm-task", "per_1", "maybe"))
+	assert.Error(t, ctrl.RespondPermission(ctx, "other-task", "per_1", storage.PermissionResponseOnce))
+
+	// Runner에 전달하지 못한 응답은 대기 목록에 남아 다시 응답할 수 있음
+	for i := 0; i < 2; i++ {
+		err = ctrl.RespondPermission(ctx, "perm-task", "per_1", storage.PermissionResponseOnce)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "runner not found")
+	}
+
+	// 대기 시간이 지났는데 정책 응답을 전달할 Runner가 없으면 에러 이벤트를 보내고 대기 목록에서 제거됨
+	select {
+	case event = <-controllerEventChan:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for permission timeout error")
+	}
+	assert.Equal(t, controller.EventTypeError, event.EventType)
+	require.NotNil(t, event.Permission)
+	assert.Equal(t, "per_1", event.Permission.ID)
+	var eventErr *controller.EventError
+	require.ErrorAs(t, event.Error, &eventErr)
+	assert.Equal(t, controller.ErrorCodeRunner, eventErr.Code)
+	assert.Contains(t, eventErr.Message, "runner not found")
+	assert.Error(t, ctrl.RespondPermission(ctx, "perm-task", "per_1", storage.PermissionResponseOnce))
+
+	// 알 수 없는 정책 응답은 저장하지 않음
+	assert.Error(t, ctrl.SetAgentPermissionPolicy(ctx, "perm-agent", time.Second, "maybe"))
+}
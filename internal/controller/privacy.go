@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// AuthorData는 한 사용자가 작성한 Task 프롬프트와 메시지입니다.
+type AuthorData struct {
+	Author     string          `json:"author"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Tasks      []AuthorTask    `json:"tasks"`
+	Messages   []AuthorMessage `json:"messages"`
+}
+
+// AuthorTask는 사용자가 첫 메시지(프롬프트)로 시작한 Task입니다.
+type AuthorTask struct {
+	TaskID    string    `json:"task_id"`
+	AgentID   string    `json:"agent_id"`
+	Prompt    string    `json:"prompt"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuthorMessage는 사용자가 Task에 추가한 메시지입니다.
+// 메시지 파일을 읽지 못하면 Unavailable이 true이고 Content는 비어 있습니다.
+type AuthorMessage struct {
+	TaskID      string    `json:"task_id"`
+	AgentID     string    `json:"agent_id"`
+	Index       int       `json:"index"`
+	CreatedAt   time.Time `json:"created_at"`
+	Content     string    `json:"content"`
+	Unavailable bool      `json:"unavailable,omitempty"`
+}
+
+// ExportAuthorData는 author가 작성한 모든 Task 프롬프트와 메시지를 모읍니다.
+func (c *Controller) ExportAuthorData(ctx context.Context, author string) (*AuthorData, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+	if author == "" {
+		return nil, fmt.Errorf("author is required")
+	}
+
+	tasks, err := c.repo.ListTasksByAuthor(ctx, author)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	rows, err := c.repo.ListMessageIndexByAuthor(ctx, author)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	data := &AuthorData{
+		Author:     author,
+		ExportedAt: time.Now(),
+		Tasks:      make([]AuthorTask, 0, len(tasks)),
+		Messages:   make([]AuthorMessage, 0, len(rows)),
+	}
+	for _, task := range tasks {
+		data.Tasks = append(data.Tasks, AuthorTask{
+			TaskID:    task.TaskID,
+			AgentID:   task.AgentID,
+			Prompt:    task.Prompt,
+			CreatedAt: task.CreatedAt,
+		})
+	}
+
+	agents := make(map[string]string)
+	for _, row := range rows {
+		agentID, ok := agents[row.TaskID]
+		if !ok {
+			if task, err := c.repo.GetTask(ctx, row.TaskID); err == nil {
+				agentID = task.AgentID
+			}
+			agents[row.TaskID] = agentID
+		}
+
+		msg := AuthorMessage{
+			TaskID:    row.TaskID,
+			AgentID:   agentID,
+			Index:     row.ConversationIndex,
+			CreatedAt: row.CreatedAt,
+		}
+		if file, err := c.GetMessageFile(ctx, row.FilePath); err != nil {
+			c.logger.Warn("Failed to read message for export",
+				zap.String("task_id", row.TaskID),
+				zap.String("key", row.FilePath),
+				zap.Error(err),
+			)
+			msg.Unavailable = true
+		} else {
+			msg.Content = file.Text()
+		}
+		data.Messages = append(data.Messages, msg)
+	}
+	return data, nil
+}
+
+// DeleteAuthorData는 author가 작성한 메시지 내용과 Task 프롬프트를 지우고 삭제 기록을 남깁니다.
+// 메시지는 대화 순서를 유지하도록 삭제 표시(RedactedAt)만 남긴 파일로 덮어쓰고, 검색 색인에서도 제거합니다.
+// 어시스턴트 응답, 보관 정책 아카이브, 백업 파일은 변경하지 않습니다.
+// ctx의 사용자 식별자(WithActor, 없으면 WithDefaultActor)는 삭제 요청자로 기록됩니다.
+func (c *Controller) DeleteAuthorData(ctx context.Context, author string) (*storage.PrivacyDeletion, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+	if author == "" {
+		return nil, fmt.Errorf("author is required")
+	}
+
+	rows, err := c.repo.ListMessageIndexByAuthor(ctx, author)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	// 메시지 내용을 먼저 지우고 DB의 작성자 정보는 마지막에 지워, 중간에 실패해도 다시 시도할 수 있게 함
+	redactedAt := time.Now().Format(time.RFC3339)
+	for _, row := range rows {
+		file, err := c.GetMessageFile(ctx, row.FilePath)
+		if errors.Is(err, storage.ErrMessageNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message %s: %w", row.FilePath, err)
+		}
+		redacted := &MessageFile{
+			Version:    MessageFileVersion,
+			Role:       file.Role,
+			Timestamp:  file.Timestamp,
+			RedactedAt: redactedAt,
+		}
+		if err := c.putMessageFile(ctx, row.FilePath, redacted); err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := c.repo.RedactAuthor(ctx, author, c.actor(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to redact author: %w", err)
+	}
+	c.audit(ctx, AuditUserDeleteData, AuditTargetUser, author, nil, map[string]int{"tasks": record.Tasks, "messages": record.Messages})
+
+	c.logger.Info("Author data deleted",
+		zap.String("author", author),
+		zap.String("requested_by", record.RequestedBy),
+		zap.Int("tasks", record.Tasks),
+		zap.Int("messages", record.Messages),
+	)
+	return record, nil
+}
+
+// ListPrivacyDeletions는 데이터 삭제 기록을 반환합니다. author가 비어 있으면 모든 기록을 반환합니다.
+func (c *Controller) ListPrivacyDeletions(ctx context.Context, author string) ([]storage.PrivacyDeletion, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+	return c.repo.ListPrivacyDeletions(ctx, author)
+}
@@ -0,0 +1,95 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAuthorDataExportAndDelete(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewFileMessageStore(t.TempDir())
+	require.NoError(t, err)
+	ctrl, _ := newBundleController(t, "privacy_author", controller.WithMessageStore(store))
+	db, err := gorm.Open(sqlite.Open("file:privacy_author?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	alice := controller.ParseActor("111")
+	bob := controller.ConnectorActor(controller.ActorConnectorDiscord, "222")
+	assert.Equal(t, "discord:111", alice)
+
+	require.NoError(t, ctrl.CreateAgent(ctx, "privacy-agent", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "thread-1", AgentID: "privacy-agent", Prompt: "alice secret prompt", Status: storage.TaskStatusWaiting, Author: alice}))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "thread-2", AgentID: "privacy-agent", Prompt: "bob prompt", Status: storage.TaskStatusWaiting, Author: bob}))
+
+	require.NoError(t, ctrl.AddMessage(controller.WithActor(ctx, alice), "thread-1", storage.MessageRoleUser, "alice banana message"))
+	// 사용자 요청으로 추가한 어시스턴트 메시지도 작성자를 기록하지 않음
+	require.NoError(t, ctrl.AddMessage(controller.WithActor(ctx, alice), "thread-1", storage.MessageRoleAssistant, "assistant banana reply"))
+	require.NoError(t, ctrl.AddMessage(controller.WithActor(ctx, bob), "thread-1", storage.MessageRoleUser, "bob banana message"))
+	require.NoError(t, ctrl.AddMessage(controller.WithActor(ctx, alice), "thread-2", storage.MessageRoleUser, "alice cherry message"))
+
+	data, err := ctrl.ExportAuthorData(ctx, alice)
+	require.NoError(t, err)
+	require.Len(t, data.Tasks, 1)
+	assert.Equal(t, "alice secret prompt", data.Tasks[0].Prompt)
+	require.Len(t, data.Messages, 2)
+	assert.Equal(t, controller.AuthorMessage{TaskID: "thread-1", AgentID: "privacy-agent", Index: 0, CreatedAt: data.Messages[0].CreatedAt, Content: "alice banana message"}, data.Messages[0])
+	assert.Equal(t, "alice cherry message", data.Messages[1].Content)
+
+	record, err := ctrl.DeleteAuthorData(controller.WithActor(ctx, "cli:admin"), alice)
+	require.NoError(t, err)
+	assert.Equal(t, 1, record.Tasks)
+	assert.Equal(t, 2, record.Messages)
+	assert.Equal(t, "cli:admin", record.RequestedBy)
+
+	// 메시지 자리에는 삭제 표시만 남고, 다른 사용자와 어시스턴트 메시지는 유지
+	messages, err := ctrl.ListMessages(ctx, "thread-1")
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	redacted, err := ctrl.GetMessageFile(ctx, messages[0].FilePath)
+	require.NoError(t, err)
+	assert.Empty(t, redacted.Text())
+	assert.Empty(t, redacted.Author)
+	assert.NotEmpty(t, redacted.RedactedAt)
+	assert.Empty(t, messages[0].Author)
+	assert.Empty(t, messages[1].Author)
+	reply, err := ctrl.GetMessageFile(ctx, messages[1].FilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "assistant banana reply", reply.Text())
+	assert.Empty(t, reply.Author)
+	assert.Empty(t, reply.RedactedAt)
+	assert.Equal(t, bob, messages[2].Author)
+
+	task, err := repo.GetTask(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Empty(t, task.Prompt)
+	task, err = repo.GetTask(ctx, "thread-2")
+	require.NoError(t, err)
+	assert.Equal(t, "bob prompt", task.Prompt)
+
+	results, err := ctrl.SearchMessages(ctx, "banana", "", time.Time{}, 10)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	results, err = ctrl.SearchMessages(ctx, "cherry", "", time.Time{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	data, err = ctrl.ExportAuthorData(ctx, alice)
+	require.NoError(t, err)
+	assert.Empty(t, data.Tasks)
+	assert.Empty(t, data.Messages)
+
+	records, err := ctrl.ListPrivacyDeletions(ctx, alice)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, 2, records[0].Messages)
+}
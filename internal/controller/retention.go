@@ -0,0 +1,451 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	taskrunner "github.com/cnap-oss/app/internal/runner"
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RetentionPolicy는 Task와 작업 공간을 마지막 활동 후 보관할 일수입니다. 0이면 삭제하지 않습니다.
+type RetentionPolicy struct {
+	CompletedTaskDays int // 완료, 취소, 응답 대기(waiting) 상태의 Task
+	FailedTaskDays    int // 실패한 Task
+	WorkspaceIdleDays int // 에이전트 작업 공간
+}
+
+// retentionCompletedStatuses는 완료 보관 기간이 적용되는 상태입니다.
+// waiting은 마지막 턴이 끝난 뒤 사용자 입력을 기다리는 대화로, 오래 방치되면 완료된 대화와 같이 취급합니다.
+var retentionCompletedStatuses = []string{storage.TaskStatusCompleted, storage.TaskStatusCanceled, storage.TaskStatusWaiting}
+
+// WithRetention은 기본 보관 정책과 정리 작업 주기를 지정합니다.
+// archiveDir을 지정하면 Task를 삭제하기 전에 대화 기록을 {archiveDir}/{agent}/{task}.jsonl로 보관합니다.
+// interval이 0보다 크면 Start에서 주기적으로 정리 작업을 실행합니다.
+func WithRetention(defaults RetentionPolicy, archiveDir string, interval time.Duration) Option {
+	return func(c *Controller) {
+		c.retention = defaults
+		c.retentionArchiveDir = archiveDir
+		c.retentionInterval = interval
+	}
+}
+
+// agentRetention은 에이전트 레코드에 저장된 보관 정책(재정의 값)을 반환합니다.
+func agentRetention(rec *storage.Agent) RetentionPolicy {
+	return RetentionPolicy{
+		CompletedTaskDays: rec.RetentionCompletedDays,
+		FailedTaskDays:    rec.RetentionFailedDays,
+		WorkspaceIdleDays: rec.RetentionWorkspaceIdleDays,
+	}
+}
+
+// Override는 에이전트별 재정의 값을 적용한 정책을 반환합니다.
+// 재정의 값이 0이면 기본값을, storage.RetentionKeepForever이면 삭제하지 않음(0)을 사용합니다.
+func (p RetentionPolicy) Override(agent RetentionPolicy) RetentionPolicy {
+	pick := func(def, override int) int {
+		switch {
+		case override == storage.RetentionKeepForever:
+			return 0
+		case override > 0:
+			return override
+		default:
+			return def
+		}
+	}
+	return RetentionPolicy{
+		CompletedTaskDays: pick(p.CompletedTaskDays, agent.CompletedTaskDays),
+		FailedTaskDays:    pick(p.FailedTaskDays, agent.FailedTaskDays),
+		WorkspaceIdleDays: pick(p.WorkspaceIdleDays, agent.WorkspaceIdleDays),
+	}
+}
+
+// SetAgentRetention은 에이전트별 보관 정책을 갱신합니다.
+func (c *Controller) SetAgentRetention(ctx context.Context, agentID string, policy RetentionPolicy) error {
+	c.logger.Info("Setting agent retention policy",
+		zap.String("agent_id", agentID),
+		zap.Int("completed_days", policy.CompletedTaskDays),
+		zap.Int("failed_days", policy.FailedTaskDays),
+		zap.Int("workspace_idle_days", policy.WorkspaceIdleDays),
+	)
+
+	if c.repo == nil {
+		return fmt.Errorf("controller: repository is not configured")
+	}
+
+	rec, err := c.getWritableAgent(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	agentID = rec.AgentID
+
+	before := c.agentAuditSnapshot(ctx, agentID)
+	if err := c.repo.UpdateAgentRetention(ctx, agentID, policy.CompletedTaskDays, policy.FailedTaskDays, policy.WorkspaceIdleDays); err != nil {
+		c.logger.Error("Failed to update agent retention policy", zap.Error(err))
+		return err
+	}
+	c.audit(ctx, AuditAgentSetRetention, AuditTargetAgent, agentID, before, c.agentAuditSnapshot(ctx, agentID))
+	return nil
+}
+
+// GCPlan은 보관 기간이 지나 정리할 Task와 작업 공간입니다.
+type GCPlan struct {
+	Tasks      []GCTask
+	Workspaces []GCWorkspace
+}
+
+// GCTask는 정리할 Task입니다.
+type GCTask struct {
+	TaskID        string
+	AgentID       string
+	Status        string
+	UpdatedAt     time.Time
+	Messages      int
+	RetentionDays int
+}
+
+// GCWorkspace는 정리할 에이전트 작업 공간입니다.
+type GCWorkspace struct {
+	AgentID      string
+	Path         string
+	LastActivity time.Time
+	IdleDays     int
+}
+
+// GCResult는 정리 작업 결과입니다.
+type GCResult struct {
+	Tasks      int
+	Messages   int
+	Archived   int
+	Workspaces int
+	Skipped    int // 계획 이후 다시 활동하여 건너뛴 항목
+}
+
+// PlanGC는 에이전트별 보관 정책에 따라 정리할 Task와 작업 공간을 계산합니다. 아무것도 삭제하지 않습니다.
+func (c *Controller) PlanGC(ctx context.Context, now time.Time) (*GCPlan, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+
+	agents, err := c.repo.ListAgents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &GCPlan{}
+	for i := range agents {
+		agent := &agents[i]
+		policy := c.retention.Override(agentRetention(agent))
+
+		for _, rule := range []struct {
+			days     int
+			statuses []string
+		}{
+			{policy.CompletedTaskDays, retentionCompletedStatuses},
+			{policy.FailedTaskDays, []string{storage.TaskStatusFailed}},
+		} {
+			if rule.days <= 0 {
+				continue
+			}
+			tasks, err := c.repo.ListInactiveTasks(ctx, agent.AgentID, rule.statuses, now.AddDate(0, 0, -rule.days))
+			if err != nil {
+				return nil, err
+			}
+			for _, task := range tasks {
+				messages, err := c.repo.ListMessageIndexByTask(ctx, task.TaskID)
+				if err != nil {
+					return nil, err
+				}
+				plan.Tasks = append(plan.Tasks, GCTask{
+					TaskID:        task.TaskID,
+					AgentID:       task.AgentID,
+					Status:        task.Status,
+					UpdatedAt:     task.UpdatedAt,
+					Messages:      len(messages),
+					RetentionDays: rule.days,
+				})
+			}
+		}
+
+		if policy.WorkspaceIdleDays > 0 {
+			ws, last, err := c.idleWorkspace(ctx, agent, now.AddDate(0, 0, -policy.WorkspaceIdleDays))
+			if err != nil {
+				return nil, err
+			}
+			if ws != nil {
+				plan.Workspaces = append(plan.Workspaces, GCWorkspace{
+					AgentID:      agent.AgentID,
+					Path:         ws.BasePath,
+					LastActivity: last,
+					IdleDays:     policy.WorkspaceIdleDays,
+				})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// idleWorkspace는 에이전트의 마지막 활동이 cutoff 이전이면 작업 공간과 마지막 활동 시각을 반환합니다.
+// 마지막 활동은 에이전트 설정 변경, Task 갱신, 작업 공간 파일 수정 중 가장 늦은 시각입니다.
+// 진행 중(pending, running)인 Task가 하나라도 있거나 정리할 Task 산출물(시드 파일 제외)이 없으면 정리하지 않습니다.
+func (c *Controller) idleWorkspace(ctx context.Context, agent *storage.Agent, cutoff time.Time) (*taskrunner.Workspace, time.Time, error) {
+	ws, err := c.workspaceManager().GetWorkspace(agent.AgentID)
+	if err != nil {
+		// 작업 공간이 없음
+		return nil, time.Time{}, nil
+	}
+
+	active, err := c.repo.HasActiveTaskByAgent(ctx, agent.AgentID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if active {
+		return nil, time.Time{}, nil
+	}
+
+	last := agent.UpdatedAt
+	latest, err := c.repo.LatestTaskByAgent(ctx, agent.AgentID)
+	switch {
+	case err == nil:
+		if latest.UpdatedAt.After(last) {
+			last = latest.UpdatedAt
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, time.Time{}, err
+	}
+	if last.After(cutoff) {
+		return nil, time.Time{}, nil
+	}
+
+	seeds, err := readSeedManifest(ws)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read seed files of %s: %w", agent.AgentID, err)
+	}
+	hasData, err := taskrunner.WorkspaceHasTaskData(ws, seeds)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to check workspace %s: %w", agent.AgentID, err)
+	}
+	if !hasData {
+		return nil, time.Time{}, nil
+	}
+
+	// DB 기준으로 유휴 상태일 때만 파일 수정 시각을 확인 (작업 공간 전체를 순회하므로)
+	modified, err := taskrunner.WorkspaceLastModified(ws)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to check workspace %s: %w", agent.AgentID, err)
+	}
+	if modified.After(last) {
+		last = modified
+	}
+	if last.After(cutoff) {
+		return nil, time.Time{}, nil
+	}
+	return ws, last, nil
+}
+
+// RunGC는 계획한 Task와 작업 공간을 정리합니다.
+// 계획 이후 다시 활동한 Task와 작업 공간은 건너뛰고, 일부 항목이 실패해도 나머지를 계속 정리합니다.
+func (c *Controller) RunGC(ctx context.Context, plan *GCPlan) (*GCResult, error) {
+	result := &GCResult{}
+	var errs []error
+
+	for _, item := range plan.Tasks {
+		task, err := c.repo.GetTask(ctx, item.TaskID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !task.UpdatedAt.Equal(item.UpdatedAt) || task.Status != item.Status {
+			result.Skipped++
+			continue
+		}
+
+		if c.retentionArchiveDir != "" {
+			if err := c.archiveTask(ctx, task); err != nil {
+				// 보관에 실패한 Task는 삭제하지 않음
+				errs = append(errs, fmt.Errorf("failed to archive task %s: %w", task.TaskID, err))
+				continue
+			}
+			result.Archived++
+		}
+
+		deleted, err := c.purgeTask(ctx, task)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to purge task %s: %w", task.TaskID, err))
+			continue
+		}
+		c.audit(ctx, AuditTaskPurge, AuditTargetTask, task.TaskID, taskAuditState{AgentID: task.AgentID, Status: task.Status}, nil)
+		result.Tasks++
+		result.Messages += deleted
+	}
+
+	for _, item := range plan.Workspaces {
+		agent, err := c.repo.GetAgent(ctx, item.AgentID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ws, _, err := c.idleWorkspace(ctx, agent, item.LastActivity.Add(time.Second))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ws == nil {
+			result.Skipped++
+			continue
+		}
+		// 에이전트 설정(.opencode의 mcp.json, 시드 파일 목록 등)과 가져온 시드 파일은 유지하고 Task 산출물만 비움
+		seeds, err := readSeedManifest(ws)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read seed files of %s: %w", item.AgentID, err))
+			continue
+		}
+		if err := taskrunner.ClearWorkspaceTaskData(ws, seeds); err != nil {
+			errs = append(errs, fmt.Errorf("failed to clear workspace %s: %w", item.AgentID, err))
+			continue
+		}
+		c.logger.Info("Cleared idle workspace",
+			zap.String("agent_id", item.AgentID),
+			zap.Time("last_activity", item.LastActivity),
+		)
+		c.audit(ctx, AuditAgentDeleteWorkspace, AuditTargetAgent, item.AgentID, map[string]string{"path": item.Path}, nil)
+		result.Workspaces++
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// archiveTask는 Task의 대화 기록을 JSONL로 보관 디렉토리에 씁니다.
+func (c *Controller) archiveTask(ctx context.Context, task *storage.Task) error {
+	transcript, err := c.BuildTaskTranscript(ctx, task.TaskID)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(c.retentionArchiveDir, task.AgentID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, transcript.ExportFileName(TranscriptFormatJSONL))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := WriteTranscript(f, transcript, TranscriptFormatJSONL); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// purgeTask는 Task의 DB 레코드와 메시지 파일, Runner 로그, 메모리 상태를 삭제하고 삭제한 메시지 수를 반환합니다.
+// 메시지를 DB 레코드보다 먼저 지우며, 하나라도 지우지 못하면 DB 레코드를 남겨 두어 다음 정리 때 다시 시도합니다.
+func (c *Controller) purgeTask(ctx context.Context, task *storage.Task) (int, error) {
+	store, err := c.messageStore()
+	if err != nil {
+		return 0, err
+	}
+	c.dropTaskPermissions(task.TaskID)
+	c.dropTranscript(task.TaskID)
+	if c.runnerManager.GetRunner(task.TaskID) != nil {
+		if err := c.runnerManager.DeleteRunner(ctx, task.TaskID); err != nil {
+			c.logger.Warn("Failed to delete runner on purge",
+				zap.String("task_id", task.TaskID),
+				zap.Error(err),
+			)
+		}
+	}
+	c.cleanupTaskContext(task.TaskID)
+
+	// 참조를 잃은 메시지가 남지 않도록 DB 레코드를 지우기 전에 메시지를 삭제
+	messages, err := c.repo.ListMessageIndexByTask(ctx, task.TaskID)
+	if err != nil {
+		return 0, err
+	}
+	deleted := make(map[string]bool, len(messages))
+	for _, msg := range messages {
+		// 마이그레이션 이전의 절대 경로는 저장소가 삭제하지 않으므로 같은 파일을 가리키는 키로 삭제
+		key := msg.FilePath
+		if filepath.IsAbs(key) {
+			key = storage.MessageKey(msg.TaskID, msg.ConversationIndex)
+		}
+		if err := store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrMessageNotFound) {
+			return 0, fmt.Errorf("failed to delete message %s: %w", msg.FilePath, err)
+		}
+		deleted[msg.FilePath] = true
+	}
+
+	keys, err := c.repo.PurgeTask(ctx, task.TaskID)
+	if err != nil {
+		return 0, err
+	}
+	// 목록 조회 이후에 추가된 메시지 (DB 레코드가 이미 없으므로 실패는 기록만 함)
+	for _, key := range keys {
+		if deleted[key] {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrMessageNotFound) {
+			c.logger.Warn("Failed to delete message on purge",
+				zap.String("task_id", task.TaskID),
+				zap.String("key", key),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if ws, err := c.workspaceManager().GetWorkspace(task.AgentID); err == nil {
+		_ = os.RemoveAll(filepath.Dir(taskrunner.TaskLogPath(ws.BasePath, task.TaskID)))
+	}
+
+	c.logger.Info("Purged task",
+		zap.String("task_id", task.TaskID),
+		zap.String("agent_id", task.AgentID),
+		zap.String("status", task.Status),
+		zap.Int("messages", len(keys)),
+	)
+	return len(keys), nil
+}
+
+// runJanitor는 주기적으로 보관 기간이 지난 Task와 작업 공간을 정리합니다.
+func (c *Controller) runJanitor(ctx context.Context) {
+	ctx = WithActor(ctx, ConnectorActor(ActorConnectorSystem, "retention"))
+	ticker := time.NewTicker(c.retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			plan, err := c.PlanGC(ctx, time.Now())
+			if err != nil {
+				c.logger.Error("Failed to plan retention cleanup", zap.Error(err))
+				continue
+			}
+			if len(plan.Tasks) == 0 && len(plan.Workspaces) == 0 {
+				continue
+			}
+			result, err := c.RunGC(ctx, plan)
+			if err != nil {
+				c.logger.Error("Retention cleanup finished with errors", zap.Error(err))
+			}
+			if result != nil {
+				c.logger.Info("Retention cleanup finished",
+					zap.Int("tasks", result.Tasks),
+					zap.Int("messages", result.Messages),
+					zap.Int("archived", result.Archived),
+					zap.Int("workspaces", result.Workspaces),
+					zap.Int("skipped", result.Skipped),
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRetentionGC(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewFileMessageStore(t.TempDir())
+	require.NoError(t, err)
+	archiveDir := t.TempDir()
+	ctrl, wm := newBundleController(t, "retention_gc",
+		controller.WithMessageStore(store),
+		controller.WithRetention(controller.RetentionPolicy{CompletedTaskDays: 30, FailedTaskDays: 7, WorkspaceIdleDays: 10}, archiveDir, 0))
+	db, err := gorm.Open(sqlite.Open("file:retention_gc?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	require.NoError(t, ctrl.CreateAgent(ctx, "gc-agent", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.CreateAgent(ctx, "keep-agent", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.CreateAgent(ctx, "idle-agent", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.CreateAgent(ctx, "busy-agent", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.SetAgentRetention(ctx, "keep-agent", controller.RetentionPolicy{CompletedTaskDays: storage.RetentionKeepForever}))
+
+	tasks := []struct {
+		id, agent, status string
+		updated           time.Time
+	}{
+		{"old-completed", "gc-agent", storage.TaskStatusCompleted, daysAgo(40)},
+		{"old-waiting", "gc-agent", storage.TaskStatusWaiting, daysAgo(40)},
+		{"recent-completed", "gc-agent", storage.TaskStatusCompleted, daysAgo(5)},
+		{"old-failed", "gc-agent", storage.TaskStatusFailed, daysAgo(10)},
+		{"old-running", "gc-agent", storage.TaskStatusRunning, daysAgo(40)},
+		{"kept-completed", "keep-agent", storage.TaskStatusCompleted, daysAgo(400)},
+		// 마지막으로 갱신된 Task가 아니어도 진행 중인 Task가 있으면 작업 공간을 정리하지 않음
+		{"busy-running", "busy-agent", storage.TaskStatusRunning, daysAgo(25)},
+		{"busy-completed", "busy-agent", storage.TaskStatusCompleted, daysAgo(12)},
+	}
+	for _, task := range tasks {
+		require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: task.id, AgentID: task.agent, Status: task.status}))
+		require.NoError(t, ctrl.AddMessage(ctx, task.id, storage.MessageRoleUser, "hello "+task.id))
+		require.NoError(t, db.Model(&storage.Task{}).Where("task_id = ?", task.id).UpdateColumn("updated_at", task.updated).Error)
+	}
+
+	// 설정 변경과 파일 수정이 모두 오래된 작업 공간만 유휴 상태
+	for _, agent := range []string{"gc-agent", "idle-agent", "busy-agent"} {
+		ws, err := wm.CreateWorkspace(ctx, agent)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(ws.ProjectDir, "out.txt"), []byte("out"), 0644))
+		require.NoError(t, os.MkdirAll(filepath.Join(ws.LogDir, "task"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(ws.LogDir, "task", "container.log"), []byte("log"), 0644))
+		require.NoError(t, db.Model(&storage.Agent{}).Where("agent_id = ?", agent).UpdateColumn("updated_at", daysAgo(20)).Error)
+		if agent != "gc-agent" {
+			require.NoError(t, filepath.WalkDir(ws.BasePath, func(path string, d fs.DirEntry, err error) error {
+				require.NoError(t, err)
+				return os.Chtimes(path, daysAgo(20), daysAgo(20))
+			}))
+		}
+	}
+
+	plan, err := ctrl.PlanGC(ctx, now)
+	require.NoError(t, err)
+	planned := make([]string, 0, len(plan.Tasks))
+	for _, task := range plan.Tasks {
+		planned = append(planned, task.TaskID)
+		assert.Equal(t, 1, task.Messages)
+	}
+	assert.ElementsMatch(t, []string{"old-completed", "old-waiting", "old-failed"}, planned)
+	require.Len(t, plan.Workspaces, 1)
+	assert.Equal(t, "idle-agent", plan.Workspaces[0].AgentID)
+
+	// 계획 이후 다시 활동한 Task는 건너뜀
+	require.NoError(t, repo.UpsertTaskStatus(ctx, "old-waiting", "gc-agent", storage.TaskStatusRunning))
+
+	result, err := ctrl.RunGC(ctx, plan)
+	require.NoError(t, err)
+	assert.Equal(t, &controller.GCResult{Tasks: 2, Messages: 2, Archived: 2, Workspaces: 1, Skipped: 1}, result)
+
+	for _, id := range []string{"old-completed", "old-failed"} {
+		_, err := repo.GetTask(ctx, id)
+		assert.Error(t, err, id)
+		messages, err := repo.ListMessageIndexByTask(ctx, id)
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+		_, err = store.Get(ctx, storage.MessageKey(id, 0))
+		assert.ErrorIs(t, err, storage.ErrMessageNotFound)
+		assert.FileExists(t, filepath.Join(archiveDir, "gc-agent", "task-"+id+".jsonl"))
+	}
+	for _, id := range []string{"old-waiting", "recent-completed", "old-running", "kept-completed"} {
+		_, err := repo.GetTask(ctx, id)
+		assert.NoError(t, err, id)
+	}
+
+	// 유휴 작업 공간은 Task 산출물만 비우고 에이전트 설정은 유지
+	ws, err := wm.GetWorkspace("idle-agent")
+	require.NoError(t, err)
+	assert.FileExists(t, ws.MCPConfigPath)
+	assert.FileExists(t, ws.ConfigPath)
+	assert.DirExists(t, ws.ProjectDir)
+	assert.NoFileExists(t, filepath.Join(ws.ProjectDir, "out.txt"))
+	assert.NoDirExists(t, filepath.Join(ws.LogDir, "task"))
+	for _, agent := range []string{"gc-agent", "busy-agent"} {
+		ws, err := wm.GetWorkspace(agent)
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(ws.ProjectDir, "out.txt"), agent)
+	}
+
+	// 비운 작업 공간은 다시 정리 대상이 되지 않음
+	plan, err = ctrl.PlanGC(ctx, now.AddDate(0, 0, 30))
+	require.NoError(t, err)
+	for _, w := range plan.Workspaces {
+		assert.NotEqual(t, "idle-agent", w.AgentID)
+	}
+}
+
+func TestRetentionPolicyOverride(t *testing.T) {
+	defaults := controller.RetentionPolicy{CompletedTaskDays: 30, FailedTaskDays: 7, WorkspaceIdleDays: 10}
+	assert.Equal(t, defaults, defaults.Override(controller.RetentionPolicy{}))
+	assert.Equal(t,
+		controller.RetentionPolicy{CompletedTaskDays: 90, FailedTaskDays: 0, WorkspaceIdleDays: 10},
+		defaults.Override(controller.RetentionPolicy{CompletedTaskDays: 90, FailedTaskDays: storage.RetentionKeepForever}))
+}
+
+// failingDeleteStore는 failing이 true인 동안 메시지 삭제에 실패하는 메시지 저장소입니다.
+type failingDeleteStore struct {
+	storage.MessageStore
+	failing bool
+}
+
+func (s *failingDeleteStore) Delete(ctx context.Context, key string) error {
+	if s.failing {
+		return errors.New("disk unavailable")
+	}
+	return s.MessageStore.Delete(ctx, key)
+}
+
+func TestRetentionGCKeepsTaskWhenMessageDeleteFails(t *testing.T) {
+	ctx := context.Background()
+	files, err := storage.NewFileMessageStore(t.TempDir())
+	require.NoError(t, err)
+	store := &failingDeleteStore{MessageStore: files, failing: true}
+	ctrl, _ := newBundleController(t, "retention_gc_delete_fail",
+		controller.WithMessageStore(store),
+		controller.WithRetention(controller.RetentionPolicy{CompletedTaskDays: 30}, "", 0))
+	db, err := gorm.Open(sqlite.Open("file:retention_gc_delete_fail?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	require.NoError(t, ctrl.CreateAgent(ctx, "gc-agent", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "old-completed", AgentID: "gc-agent", Status: storage.TaskStatusCompleted}))
+	require.NoError(t, ctrl.AddMessage(ctx, "old-completed", storage.MessageRoleUser, "hello"))
+	require.NoError(t, db.Model(&storage.Task{}).Where("task_id = ?", "old-completed").UpdateColumn("updated_at", time.Now().AddDate(0, 0, -40)).Error)
+
+	// 메시지를 지우지 못하면 DB 레코드를 남겨 다음 정리 때 다시 시도
+	plan, err := ctrl.PlanGC(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, plan.Tasks, 1)
+	result, err := ctrl.RunGC(ctx, plan)
+	assert.Error(t, err)
+	assert.Zero(t, result.Tasks)
+	messages, err := repo.ListMessageIndexByTask(ctx, "old-completed")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	store.failing = false
+	plan, err = ctrl.PlanGC(ctx, time.Now())
+	require.NoError(t, err)
+	result, err = ctrl.RunGC(ctx, plan)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Tasks)
+	_, err = files.Get(ctx, messages[0].FilePath)
+	assert.ErrorIs(t, err, storage.ErrMessageNotFound)
+}
+
+func TestRetentionGCKeepsSeedFiles(t *testing.T) {
+	ctx := context.Background()
+	ctrl, wm := newBundleController(t, "retention_gc_seed",
+		controller.WithRetention(controller.RetentionPolicy{WorkspaceIdleDays: 10}, "", 0))
+	db, err := gorm.Open(sqlite.Open("file:retention_gc_seed?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	daysAgo := time.Now().AddDate(0, 0, -20)
+
+	bundle := &controller.AgentBundle{APIVersion: controller.AgentBundleAPIVersion, Kind: controller.AgentBundleKind, Agents: []controller.AgentSpec{{
+		Name:     "seed-agent",
+		Provider: "opencode",
+		Model:    "gpt-4",
+		Prompt:   "prompt",
+		Files:    []controller.AgentSeedFile{{Path: "project/docs/README.md", Content: "# 프로젝트\n"}},
+	}}}
+	plan, err := ctrl.PlanAgentImport(ctx, bundle, false)
+	require.NoError(t, err)
+	require.NoError(t, ctrl.ApplyAgentImport(ctx, plan))
+
+	ws, err := wm.GetWorkspace("seed-agent")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(ws.ProjectDir, "out.txt"), []byte("out"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(ws.ProjectDir, "docs", "notes.txt"), []byte("notes"), 0644))
+	require.NoError(t, db.Model(&storage.Agent{}).Where("agent_id = ?", "seed-agent").UpdateColumn("updated_at", daysAgo).Error)
+	require.NoError(t, filepath.WalkDir(ws.BasePath, func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		return os.Chtimes(path, daysAgo, daysAgo)
+	}))
+
+	gcPlan, err := ctrl.PlanGC(ctx, time.Now())
+	require.NoError(t, err)
+	require.Len(t, gcPlan.Workspaces, 1)
+	result, err := ctrl.RunGC(ctx, gcPlan)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Workspaces)
+
+	// 가져온 시드 파일은 남기고 Task 산출물만 지움
+	data, err := os.ReadFile(filepath.Join(ws.ProjectDir, "docs", "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# 프로젝트\n", string(data))
+	assert.NoFileExists(t, filepath.Join(ws.ProjectDir, "out.txt"))
+	assert.NoFileExists(t, filepath.Join(ws.ProjectDir, "docs", "notes.txt"))
+
+	// 시드 파일만 남은 작업 공간은 다시 정리 대상이 되지 않음
+	gcPlan, err = ctrl.PlanGC(ctx, time.Now().AddDate(0, 0, 30))
+	require.NoError(t, err)
+	assert.Empty(t, gcPlan.Workspaces)
+}
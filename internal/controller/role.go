@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// DefaultRole은 역할 매핑이 없는 사용자의 역할입니다.
+// 역할 매핑을 설정하기 전에는 기존처럼 누구나 에이전트를 만들고 자신의 에이전트를 관리할 수 있습니다.
+const DefaultRole = storage.RoleAgentAuthor
+
+// roleRanks는 역할별 권한 순위입니다. 높을수록 더 많은 작업을 할 수 있습니다.
+var roleRanks = map[string]int{
+	storage.RoleViewer:      1,
+	storage.RoleUser:        2,
+	storage.RoleAgentAuthor: 3,
+	storage.RoleAdmin:       4,
+}
+
+// roleKey는 context에 요청한 사용자의 역할을 담는 키입니다.
+type roleKey struct{}
+
+// WithRole은 요청한 사용자의 테넌트 안 역할을 담은 context를 반환합니다.
+// admin 역할이면 같은 테넌트의 모든 에이전트를 조회하고 수정할 수 있습니다.
+func WithRole(ctx context.Context, role string) context.Context {
+	if role == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// RoleFromContext는 ctx에 담긴 역할을 반환합니다. 없으면 빈 문자열입니다.
+func RoleFromContext(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey{}).(string)
+	return role
+}
+
+// ParseRole은 역할 이름을 검증합니다.
+func ParseRole(s string) (string, error) {
+	role := strings.ToLower(strings.TrimSpace(s))
+	if _, ok := roleRanks[role]; !ok {
+		return "", fmt.Errorf("unknown role: %s (admin, agent-author, user, viewer)", s)
+	}
+	return role, nil
+}
+
+// RoleAtLeast는 role이 min 이상의 권한을 가지는지 확인합니다.
+func RoleAtLeast(role, min string) bool {
+	return roleRanks[role] >= roleRanks[min]
+}
+
+// ResolveRole은 사용자가 가진 커넥터 역할(Discord 역할 ID 등) 중 매핑된 가장 높은 역할을 반환합니다.
+// 매핑된 역할이 하나도 없으면 DefaultRole입니다.
+func (c *Controller) ResolveRole(ctx context.Context, tenant string, externalRoles []string) (string, error) {
+	if c.repo == nil {
+		return "", fmt.Errorf("controller: repository is not configured")
+	}
+
+	bindings, err := c.repo.ListRoleBindings(ctx, tenant)
+	if err != nil {
+		return "", err
+	}
+	held := make(map[string]bool, len(externalRoles))
+	for _, r := range externalRoles {
+		held[r] = true
+	}
+	role := ""
+	for _, b := range bindings {
+		if held[b.ExternalRole] && roleRanks[b.Role] > roleRanks[role] {
+			role = b.Role
+		}
+	}
+	if role == "" {
+		role = DefaultRole
+	}
+	return role, nil
+}
+
+// ListRoleBindings는 테넌트의 역할 매핑을 반환합니다.
+func (c *Controller) ListRoleBindings(ctx context.Context, tenant string) ([]storage.RoleBinding, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+	return c.repo.ListRoleBindings(ctx, tenant)
+}
+
+// SetRoleBinding은 테넌트의 커넥터 역할에 CNAP 역할을 부여합니다.
+// 테넌트 범위의 context에서는 같은 테넌트의 admin만 설정할 수 있습니다.
+func (c *Controller) SetRoleBinding(ctx context.Context, tenant, externalRole, role string) error {
+	c.logger.Info("Setting role binding",
+		zap.String("tenant", tenant),
+		zap.String("external_role", externalRole),
+		zap.String("role", role),
+	)
+
+	if c.repo == nil {
+		return fmt.Errorf("controller: repository is not configured")
+	}
+
+	role, err := ParseRole(role)
+	if err != nil {
+		return err
+	}
+	if err := authorizeRoleAdmin(ctx, tenant); err != nil {
+		return err
+	}
+
+	before := c.roleBindingSnapshot(ctx, tenant, externalRole)
+	if err := c.repo.SetRoleBinding(ctx, &storage.RoleBinding{
+		TenantID:     tenant,
+		ExternalRole: externalRole,
+		Role:         role,
+		UpdatedBy:    c.actor(ctx),
+	}); err != nil {
+		c.logger.Error("Failed to set role binding", zap.Error(err))
+		return err
+	}
+	c.audit(ctx, AuditTenantSetRole, AuditTargetTenant, tenant, before, c.roleBindingSnapshot(ctx, tenant, externalRole))
+	return nil
+}
+
+// RemoveRoleBinding은 테넌트의 커넥터 역할에 부여한 CNAP 역할을 취소합니다. 매핑이 없으면 오류입니다.
+func (c *Controller) RemoveRoleBinding(ctx context.Context, tenant, externalRole string) error {
+	c.logger.Info("Removing role binding",
+		zap.String("tenant", tenant),
+		zap.String("external_role", externalRole),
+	)
+
+	if c.repo == nil {
+		return fmt.Errorf("controller: repository is not configured")
+	}
+
+	if err := authorizeRoleAdmin(ctx, tenant); err != nil {
+		return err
+	}
+
+	before := c.roleBindingSnapshot(ctx, tenant, externalRole)
+	removed, err := c.repo.DeleteRoleBinding(ctx, tenant, externalRole)
+	if err != nil {
+		c.logger.Error("Failed to remove role binding", zap.Error(err))
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("role binding not found: %s", externalRole)
+	}
+	c.audit(ctx, AuditTenantRemoveRole, AuditTargetTenant, tenant, before, roleBindingAuditState{ExternalRole: externalRole})
+	return nil
+}
+
+// authorizeRoleAdmin은 테넌트 범위의 context이면 같은 테넌트의 admin인지 확인합니다.
+func authorizeRoleAdmin(ctx context.Context, tenant string) error {
+	scoped := TenantFromContext(ctx)
+	if scoped == "" {
+		return nil
+	}
+	if scoped != tenant || RoleFromContext(ctx) != storage.RoleAdmin {
+		return fmt.Errorf("%w: role bindings require admin role", ErrPermissionDenied)
+	}
+	return nil
+}
+
+// roleBindingAuditState는 감사 기록에 남기는 역할 매핑입니다.
+type roleBindingAuditState struct {
+	ExternalRole string `json:"external_role"`
+	Role         string `json:"role,omitempty"`
+}
+
+// roleBindingSnapshot은 커넥터 역할의 현재 매핑을 조회합니다. 매핑이 없으면 역할이 빈 상태입니다.
+func (c *Controller) roleBindingSnapshot(ctx context.Context, tenant, externalRole string) roleBindingAuditState {
+	state := roleBindingAuditState{ExternalRole: externalRole}
+	bindings, err := c.repo.ListRoleBindings(ctx, tenant)
+	if err != nil {
+		return state
+	}
+	for _, b := range bindings {
+		if b.ExternalRole == externalRole {
+			state.Role = b.Role
+		}
+	}
+	return state
+}
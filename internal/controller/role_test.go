@@ -0,0 +1,78 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleBindings(t *testing.T) {
+	ctx := context.Background()
+	ctrl, _ := newBundleController(t, "role_bindings")
+
+	member := func(id, role string) context.Context {
+		return controller.WithRole(controller.WithTenant(controller.WithActor(ctx, "discord:"+id), "guild:a"), role)
+	}
+	resolve := func(roles ...string) string {
+		role, err := ctrl.ResolveRole(ctx, "guild:a", roles)
+		require.NoError(t, err)
+		return role
+	}
+
+	// 매핑이 없으면 기본 역할
+	assert.Equal(t, controller.DefaultRole, resolve("r1"))
+
+	// 매핑 변경은 같은 테넌트의 admin만 가능
+	assert.ErrorIs(t, ctrl.SetRoleBinding(member("1", storage.RoleAgentAuthor), "guild:a", "r1", storage.RoleAdmin), controller.ErrPermissionDenied)
+	assert.ErrorIs(t, ctrl.SetRoleBinding(controller.WithRole(controller.WithTenant(ctx, "guild:b"), storage.RoleAdmin), "guild:a", "r1", storage.RoleAdmin), controller.ErrPermissionDenied)
+	assert.Error(t, ctrl.SetRoleBinding(ctx, "guild:a", "r1", "owner"))
+
+	admin := member("9", storage.RoleAdmin)
+	require.NoError(t, ctrl.SetRoleBinding(admin, "guild:a", "guild:a", storage.RoleViewer))
+	require.NoError(t, ctrl.SetRoleBinding(admin, "guild:a", "r1", storage.RoleUser))
+	require.NoError(t, ctrl.SetRoleBinding(ctx, "guild:a", "r2", storage.RoleAdmin))
+
+	// @everyone(서버 ID) 매핑이 기본 역할을 대신하고, 여러 역할 중 가장 높은 역할을 사용
+	assert.Equal(t, storage.RoleViewer, resolve("guild:a"))
+	assert.Equal(t, storage.RoleUser, resolve("guild:a", "r1"))
+	assert.Equal(t, storage.RoleAdmin, resolve("guild:a", "r1", "r2"))
+	other, err := ctrl.ResolveRole(ctx, "guild:b", []string{"guild:b", "r2"})
+	require.NoError(t, err)
+	assert.Equal(t, controller.DefaultRole, other)
+
+	require.NoError(t, ctrl.SetRoleBinding(admin, "guild:a", "r1", storage.RoleAgentAuthor))
+	bindings, err := ctrl.ListRoleBindings(ctx, "guild:a")
+	require.NoError(t, err)
+	assert.Len(t, bindings, 3)
+	assert.Equal(t, storage.RoleAgentAuthor, resolve("r1"))
+
+	require.NoError(t, ctrl.RemoveRoleBinding(admin, "guild:a", "r1"))
+	assert.Error(t, ctrl.RemoveRoleBinding(admin, "guild:a", "r1"))
+	assert.Equal(t, storage.RoleViewer, resolve("guild:a", "r1"))
+
+	// admin은 같은 테넌트의 다른 사용자 private 에이전트를 조회하고 수정 가능
+	alice := member("1", storage.RoleAgentAuthor)
+	require.NoError(t, ctrl.CreateAgent(alice, "private-bot", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.SetAgentVisibility(alice, "private-bot", storage.AgentVisibilityPrivate))
+	_, err = ctrl.GetAgentInfo(member("2", storage.RoleAgentAuthor), "private-bot")
+	assert.EqualError(t, err, "agent not found: private-bot")
+	agents, err := ctrl.ListAgents(admin)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"private-bot"}, agents)
+	require.NoError(t, ctrl.UpdateAgent(admin, "private-bot", "moderated", "opencode", "gpt-4", "prompt"))
+
+	events, err := ctrl.QueryAuditEvents(ctx, storage.AuditQuery{TargetType: controller.AuditTargetTenant, TargetID: "guild:a"})
+	require.NoError(t, err)
+	actions := make([]string, 0, len(events))
+	for _, e := range events {
+		actions = append(actions, e.Action)
+	}
+	assert.ElementsMatch(t, []string{
+		controller.AuditTenantSetRole, controller.AuditTenantSetRole, controller.AuditTenantSetRole,
+		controller.AuditTenantSetRole, controller.AuditTenantRemoveRole,
+	}, actions)
+}
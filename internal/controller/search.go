@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// searchBody는 메시지에서 검색 색인에 넣을 본문을 구성합니다. 텍스트와 도구 출력을 포함합니다.
+func searchBody(msg *MessageFile) string {
+	parts := []string{msg.Text()}
+	for _, part := range msg.Parts {
+		if part.Type != PartTypeTool {
+			continue
+		}
+		for _, s := range []string{part.Title, part.Output, part.Error} {
+			if s != "" {
+				parts = append(parts, s)
+			}
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n\n"))
+}
+
+// indexMessage는 메시지를 검색 색인에 추가합니다.
+// 색인 실패는 메시지 저장을 실패시키지 않으며, `cnap db reindex-search`로 다시 채울 수 있습니다.
+func (c *Controller) indexMessage(ctx context.Context, taskID string, conversationIndex int, msg *MessageFile) {
+	if err := c.repo.IndexMessage(ctx, taskID, conversationIndex, msg.Role, searchBody(msg)); err != nil {
+		c.logger.Warn("Failed to index message for search",
+			zap.String("task_id", taskID),
+			zap.Int("conversation_index", conversationIndex),
+			zap.Error(err),
+		)
+	}
+}
+
+// SearchMessages는 모든 Task의 대화에서 query를 포함하는 메시지를 최신순으로 찾습니다.
+// 테넌트 범위의 context에서는 해당 테넌트에서 시작된 Task만 검색합니다.
+// agentName이 비어 있지 않으면 해당 에이전트의 Task만, since가 zero가 아니면 그 이후의 메시지만 검색합니다.
+func (c *Controller) SearchMessages(ctx context.Context, query, agentName string, since time.Time, limit int) ([]storage.SearchResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+	return c.repo.SearchMessages(ctx, storage.SearchQuery{
+		Query:    query,
+		TenantID: TenantFromContext(ctx),
+		AgentID:  c.agentFilterID(ctx, agentName),
+		Since:    since,
+		Limit:    limit,
+	})
+}
+
+// ReindexSearch는 메시지 저장소의 모든 메시지로 검색 색인을 다시 만듭니다.
+// 메시지를 모두 읽은 뒤 한 번에 교체하므로, 재색인 중에도 검색은 이전 색인을 사용합니다.
+// 색인한 메시지 개수를 반환합니다. 읽을 수 없는 메시지는 건너뜁니다.
+func (c *Controller) ReindexSearch(ctx context.Context) (int, error) {
+	if c.repo == nil {
+		return 0, fmt.Errorf("controller: repository is not configured")
+	}
+	rows, err := c.repo.ListAllMessageIndex(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	entries := make([]storage.SearchEntry, 0, len(rows))
+	for _, row := range rows {
+		msg, err := c.GetMessageFile(ctx, row.FilePath)
+		if err != nil {
+			c.logger.Warn("Skipping unreadable message",
+				zap.String("task_id", row.TaskID),
+				zap.Int("conversation_index", row.ConversationIndex),
+				zap.Error(err),
+			)
+			continue
+		}
+		entries = append(entries, storage.SearchEntry{
+			TaskID:            row.TaskID,
+			ConversationIndex: row.ConversationIndex,
+			Role:              row.Role,
+			Body:              searchBody(msg),
+		})
+	}
+	if err := c.repo.ReplaceSearchIndex(ctx, entries); err != nil {
+		return 0, fmt.Errorf("failed to rebuild search index: %w", err)
+	}
+
+	indexed := len(entries)
+	c.audit(ctx, AuditSystemReindexSearch, AuditTargetSystem, "search", nil, map[string]int{"messages": indexed})
+	return indexed, nil
+}
+
+// ParseSince는 검색 기간 값을 시각으로 변환합니다.
+// "7d"처럼 일 단위, "12h"처럼 Go duration, "2025-01-02" 날짜, RFC3339 시각을 지원합니다. 빈 문자열은 zero 시각입니다.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid since value %q (e.g. 7d, 12h, 2025-01-02)", value)
+}
@@ -0,0 +1,110 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	taskrunner "github.com/cnap-oss/app/internal/runner"
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestControllerSearchMessages(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:search_messages_ctrl?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	store, err := storage.NewFileMessageStore(t.TempDir())
+	require.NoError(t, err)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 10), make(chan controller.ControllerEvent, 20),
+		controller.WithMessageStore(store))
+
+	ctx := context.Background()
+	taskID := "search-thread"
+	require.NoError(t, ctrl.CreateAgent(ctx, "search-agent", "Search agent", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: taskID, AgentID: "search-agent", Status: storage.TaskStatusRunning}))
+	require.NoError(t, ctrl.OnStarted(taskID, "ses_1"))
+
+	// 사용자 메시지는 추가될 때 색인
+	require.NoError(t, ctrl.AddMessage(ctx, taskID, storage.MessageRoleUser, "디스크 사용량을 확인해줘"))
+
+	// 어시스턴트 메시지는 턴이 끝날 때 도구 출력과 함께 색인
+	end := int64(1700000001000)
+	events := []*opencode.RunnerMessage{
+		{Type: opencode.MessageTypeMessageUpdated, SessionID: "ses_1", Message: opencode.AssistantMessage{ID: "msg_a", SessionID: "ses_1", Role: "assistant"}},
+		{Type: opencode.MessageTypeToolResult, SessionID: "ses_1", MessageID: "msg_a", PartID: "prt_t",
+			ToolResult: &opencode.ToolResultInfo{ToolID: "call_1", ToolName: "bash", Result: "/dev/sda1 42%"},
+			Part: &opencode.Part{ID: "prt_t", SessionID: "ses_1", MessageID: "msg_a", Type: "tool", CallID: "call_1", Tool: "bash",
+				State: &opencode.ToolState{Status: "completed", Input: map[string]any{"command": "df -h"}, Output: "/dev/sda1 42%",
+					Time: &opencode.ToolStateTime{Start: 1700000000000, End: &end}}}},
+		{Type: opencode.MessageTypeText, SessionID: "ses_1", MessageID: "msg_a", PartID: "prt_1",
+			Part: &opencode.Part{ID: "prt_1", SessionID: "ses_1", MessageID: "msg_a", Type: "text", Text: "루트 파티션 사용량은 42%입니다."}},
+	}
+	for _, msg := range events {
+		require.NoError(t, ctrl.OnEvent(taskID, msg))
+	}
+
+	// 턴이 끝나기 전에는 검색되지 않음
+	results, err := ctrl.SearchMessages(ctx, "sda1", "", time.Time{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	require.NoError(t, ctrl.OnComplete(taskID, &taskrunner.RunResult{Success: true, Output: "루트 파티션 사용량은 42%입니다."}))
+
+	results, err = ctrl.SearchMessages(ctx, "sda1", "", time.Time{}, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, taskID, results[0].TaskID)
+	assert.Equal(t, 1, results[0].ConversationIndex)
+	assert.Equal(t, storage.MessageRoleAssistant, results[0].Role)
+
+	results, err = ctrl.SearchMessages(ctx, "디스크", "search-agent", time.Time{}, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 0, results[0].ConversationIndex)
+
+	results, err = ctrl.SearchMessages(ctx, "디스크", "other-agent", time.Time{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// 재색인하면 메시지 저장소에서 다시 채움
+	require.NoError(t, repo.ClearSearchIndex(ctx))
+	n, err := ctrl.ReindexSearch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	results, err = ctrl.SearchMessages(ctx, "파티션", "", time.Time{}, 10)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"12h", now.Add(-12 * time.Hour)},
+		{"2025-01-02", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2025-01-02T03:04:05Z", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := controller.ParseSince(tt.value, now)
+		require.NoError(t, err, tt.value)
+		assert.True(t, tt.want.Equal(got), "%s: got %s", tt.value, got)
+	}
+
+	_, err := controller.ParseSince("yesterday", now)
+	assert.Error(t, err)
+}
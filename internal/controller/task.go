@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	taskrunner "github.com/cnap-oss/app/internal/runner"
+	"github.com/cnap-oss/app/internal/runner/docker"
 	"github.com/cnap-oss/app/internal/storage"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -13,6 +15,7 @@ import (
 
 // CreateTask는 프롬프트와 함께 새로운 작업을 생성합니다.
 // 생성 후 SendMessage를 호출하기 전까지 실행되지 않습니다.
+// ctx에 사용자 식별자(WithActor)가 있으면 프롬프트 작성자로 기록합니다.
 func (c *Controller) CreateTask(ctx context.Context, agentID, taskID, prompt string) error {
 	c.logger.Info("Creating task",
 		zap.String("agent_id", agentID),
@@ -23,25 +26,27 @@ func (c *Controller) CreateTask(ctx context.Context, agentID, taskID, prompt str
 		return fmt.Errorf("controller: repository is not configured")
 	}
 
-	// Agent 존재 여부 확인
-	if _, err := c.repo.GetAgent(ctx, agentID); err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("agent not found: %s", agentID)
-		}
+	// Agent 존재 여부 확인 (테넌트 범위에서는 보이는 에이전트만 사용 가능)
+	rec, err := c.getAgent(ctx, agentID)
+	if err != nil {
 		return err
 	}
+	agentID = rec.AgentID
 
 	task := &storage.Task{
-		TaskID:  taskID,
-		AgentID: agentID,
-		Prompt:  prompt,
-		Status:  storage.TaskStatusPending,
+		TaskID:   taskID,
+		AgentID:  agentID,
+		Prompt:   prompt,
+		Status:   storage.TaskStatusPending,
+		Author:   ActorFromContext(ctx),
+		TenantID: TenantFromContext(ctx),
 	}
 
 	if err := c.repo.CreateTask(ctx, task); err != nil {
 		c.logger.Error("Failed to create task", zap.Error(err))
 		return err
 	}
+	c.audit(ctx, AuditTaskCreate, AuditTargetTask, taskID, nil, taskAuditState{AgentID: agentID, Status: task.Status})
 
 	// Agent 정보 조회
 	agent, err := c.repo.GetAgent(ctx, agentID)
@@ -56,9 +61,10 @@ func (c *Controller) CreateTask(ctx context.Context, agentID, taskID, prompt str
 		Provider: agent.Provider,
 		Model:    agent.Model,
 		Prompt:   agent.Prompt,
+		Image:    agent.Image,
 	}
 
-	_, err = c.runnerManager.CreateRunner(ctx, taskID, agentInfo, c)
+	runner, err := c.runnerManager.CreateRunner(ctx, taskID, agentInfo, c, c.runnerOptions(taskID)...)
 	if err != nil {
 		c.logger.Error("Failed to create runner", zap.Error(err))
 		return fmt.Errorf("failed to create task runner: %w", err)
@@ -68,10 +74,15 @@ func (c *Controller) CreateTask(ctx context.Context, agentID, taskID, prompt str
 	if err := c.runnerManager.StartRunner(ctx, taskID); err != nil {
 		c.logger.Error("Failed to start runner", zap.Error(err))
 		// 생성된 Runner 정리
+		c.dropTaskPermissions(taskID)
+		c.dropTranscript(taskID)
 		_ = c.runnerManager.DeleteRunner(ctx, taskID)
 		return fmt.Errorf("failed to start task runner: %w", err)
 	}
 
+	// 재현성을 위해 실제 사용한 이미지 다이제스트를 Task에 고정
+	c.recordTaskImage(ctx, taskID, runner.ImageRef)
+
 	c.logger.Info("Task created successfully",
 		zap.String("task_id", taskID),
 		zap.String("agent_id", agentID),
@@ -124,12 +135,13 @@ func (c *Controller) GetTaskInfo(ctx context.Context, taskID string) (*TaskInfo,
 	}
 
 	info := &TaskInfo{
-		TaskID:    task.TaskID,
-		AgentID:   task.AgentID,
-		Prompt:    task.Prompt,
-		Status:    task.Status,
-		CreatedAt: task.CreatedAt,
-		UpdatedAt: task.UpdatedAt,
+		TaskID:      task.TaskID,
+		AgentID:     task.AgentID,
+		Prompt:      task.Prompt,
+		Status:      task.Status,
+		ImageDigest: task.ImageDigest,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
 	}
 
 	c.logger.Info("Retrieved task info",
@@ -164,6 +176,8 @@ func (c *Controller) UpdateTaskStatus(ctx context.Context, taskID, status string
 		c.logger.Error("Failed to update task status", zap.Error(err))
 		return err
 	}
+	c.audit(ctx, AuditTaskUpdateStatus, AuditTargetTask, taskID,
+		taskAuditState{AgentID: task.AgentID, Status: task.Status}, taskAuditState{AgentID: task.AgentID, Status: status})
 
 	c.logger.Info("Task status updated successfully",
 		zap.String("task_id", taskID),
@@ -195,6 +209,75 @@ func (c *Controller) ListTasksByAgent(ctx context.Context, agentID string) ([]st
 	return tasks, nil
 }
 
+// TaskStatusActive는 아직 끝나지 않은 Task 상태(pending, running, waiting)를 함께 지정하는 필터 값입니다.
+const TaskStatusActive = "active"
+
+// ParseTaskStatuses는 쉼표로 구분된 상태 목록을 검증하여 반환합니다. "active"는 끝나지 않은 상태로 확장됩니다.
+func ParseTaskStatuses(values ...string) ([]string, error) {
+	known := map[string]bool{
+		storage.TaskStatusPending:   true,
+		storage.TaskStatusRunning:   true,
+		storage.TaskStatusWaiting:   true,
+		storage.TaskStatusCompleted: true,
+		storage.TaskStatusFailed:    true,
+		storage.TaskStatusCanceled:  true,
+	}
+	seen := make(map[string]bool)
+	var statuses []string
+	add := func(status string) {
+		if !seen[status] {
+			seen[status] = true
+			statuses = append(statuses, status)
+		}
+	}
+	for _, value := range values {
+		for _, status := range strings.Split(value, ",") {
+			status = strings.ToLower(strings.TrimSpace(status))
+			switch {
+			case status == "":
+			case status == TaskStatusActive:
+				add(storage.TaskStatusPending)
+				add(storage.TaskStatusRunning)
+				add(storage.TaskStatusWaiting)
+			case known[status]:
+				add(status)
+			default:
+				return nil, fmt.Errorf("unknown task status: %s", status)
+			}
+		}
+	}
+	return statuses, nil
+}
+
+// ListTasks는 조건에 맞는 모든 에이전트의 Task 목록을 한 페이지 반환합니다.
+// 테넌트 범위의 context에서는 해당 테넌트에서 시작된 Task만 반환합니다.
+func (c *Controller) ListTasks(ctx context.Context, query storage.TaskQuery) (*storage.TaskPage, error) {
+	c.logger.Info("Listing tasks",
+		zap.String("agent_id", query.AgentID),
+		zap.Strings("statuses", query.Statuses),
+		zap.Bool("has_cursor", query.Cursor != ""),
+	)
+
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+
+	if tenant := TenantFromContext(ctx); tenant != "" {
+		query.TenantID = tenant
+	}
+	query.AgentID = c.agentFilterID(ctx, query.AgentID)
+	page, err := c.repo.QueryTasks(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Listed tasks",
+		zap.Int("count", len(page.Tasks)),
+		zap.Bool("has_more", page.NextCursor != ""),
+	)
+	return page, nil
+}
+
 // DeleteTask는 작업을 삭제합니다 (soft delete).
 func (c *Controller) DeleteTask(ctx context.Context, taskID string) error {
 	c.logger.Info("Deleting task",
@@ -206,7 +289,7 @@ func (c *Controller) DeleteTask(ctx context.Context, taskID string) error {
 	}
 
 	// Task 존재 여부 확인
-	_, err := c.repo.GetTask(ctx, taskID)
+	task, err := c.repo.GetTask(ctx, taskID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return fmt.Errorf("task not found: %s", taskID)
@@ -219,8 +302,12 @@ func (c *Controller) DeleteTask(ctx context.Context, taskID string) error {
 		c.logger.Error("Failed to delete task", zap.Error(err))
 		return err
 	}
+	c.audit(ctx, AuditTaskDelete, AuditTargetTask, taskID,
+		taskAuditState{AgentID: task.AgentID, Status: task.Status}, taskAuditState{AgentID: task.AgentID, Status: storage.TaskStatusDeleted})
 
 	// Runner도 삭제
+	c.dropTaskPermissions(taskID)
+	c.dropTranscript(taskID)
 	if err := c.runnerManager.DeleteRunner(ctx, taskID); err != nil {
 		c.logger.Warn("Failed to delete runner on task deletion",
 			zap.String("task_id", taskID),
@@ -237,6 +324,49 @@ func (c *Controller) DeleteTask(ctx context.Context, taskID string) error {
 	return nil
 }
 
+// recordTaskImage는 Runner가 사용한 이미지 참조를 Task에 기록합니다.
+// 기록 실패는 실행에 영향을 주지 않으므로 경고만 남깁니다.
+func (c *Controller) recordTaskImage(ctx context.Context, taskID, imageRef string) {
+	if imageRef == "" {
+		return
+	}
+	if err := c.repo.SetTaskImageDigest(ctx, taskID, imageRef); err != nil {
+		c.logger.Warn("Failed to record task image digest",
+			zap.String("task_id", taskID),
+			zap.String("image", imageRef),
+			zap.Error(err),
+		)
+	}
+}
+
+// runnerOptions는 Task의 Runner를 만들 때 공통으로 사용할 옵션을 반환합니다.
+func (c *Controller) runnerOptions(taskID string) []taskrunner.RunnerOption {
+	return []taskrunner.RunnerOption{
+		taskrunner.WithPullProgress(c.reportPullProgress(taskID)),
+	}
+}
+
+// reportPullProgress는 Runner 이미지 pull 진행 상황을 Connector로 전달하는 함수를 반환합니다.
+// 바이트 단위 진행은 너무 잦으므로 레이어 상태가 바뀔 때만 전달합니다.
+func (c *Controller) reportPullProgress(taskID string) func(image string, progress docker.PullProgress) {
+	return func(image string, p docker.PullProgress) {
+		if p.Total > 0 {
+			return
+		}
+		status := p.Status
+		if p.ID != "" {
+			status = p.ID + ": " + p.Status
+		}
+		c.emit(ControllerEvent{
+			TaskID:    taskID,
+			EventType: EventTypeImagePull,
+			Status:    "image_pull",
+			Content:   image,
+			Delta:     status,
+		})
+	}
+}
+
 // ValidateTask는 작업 ID의 유효성을 검증합니다.
 func (c *Controller) ValidateTask(taskID string) error {
 	if taskID == "" {
@@ -63,6 +63,8 @@ func (c *Controller) ExecuteTask(ctx context.Context, taskID string) error {
 		)
 	}
 
+	c.audit(ctx, AuditTaskExecute, AuditTargetTask, taskID, nil, taskAuditState{AgentID: task.AgentID, Status: task.Status})
+
 	// 비동기 실행
 	go c.executeTask(taskCtx.ctx, taskID, task)
 
@@ -96,26 +98,79 @@ func (c *Controller) CancelTask(ctx context.Context, taskID string) error {
 		return fmt.Errorf("task is not running: %s (status: %s)", taskID, task.Status)
 	}
 
-	// TaskContext에서 cancel 호출
-	c.mu.RLock()
-	taskCtx, ok := c.taskContexts[taskID]
-	c.mu.RUnlock()
+	return c.abortTask(ctx, taskID, task.AgentID)
+}
 
-	if !ok {
-		// Context가 없으면 이미 완료되었거나 존재하지 않음
-		return fmt.Errorf("task context not found: %s", taskID)
+// abortTask는 실행 중인 Task를 중단합니다.
+// Runner가 있으면 OpenCode 세션을 중단(AbortSession)하고 session.aborted/idle 이벤트를 기다린 뒤,
+// 중단 시점까지의 어시스턴트 출력을 MessageIndex에 저장합니다. Runner는 다음 메시지를 위해 유지됩니다.
+// Runner가 없으면 TaskContext만 취소합니다.
+func (c *Controller) abortTask(ctx context.Context, taskID, agentID string) error {
+	runner := c.runnerManager.GetRunner(taskID)
+	if runner == nil {
+		c.mu.RLock()
+		taskCtx, ok := c.taskContexts[taskID]
+		c.mu.RUnlock()
+
+		if !ok {
+			// Context가 없으면 이미 완료되었거나 존재하지 않음
+			return fmt.Errorf("task context not found: %s", taskID)
+		}
+		taskCtx.cancel()
+	} else {
+		result, err := runner.Abort(ctx)
+		if err != nil {
+			c.logger.Error("Failed to abort runner session",
+				zap.String("task_id", taskID),
+				zap.Error(err),
+			)
+			return fmt.Errorf("failed to abort task: %w", err)
+		}
+
+		if err := c.savePartialOutput(ctx, taskID, result.PartialOutput); err != nil {
+			c.logger.Error("Failed to save partial output",
+				zap.String("task_id", taskID),
+				zap.Error(err),
+			)
+		}
+		if !result.Confirmed {
+			c.logger.Warn("Session abort not confirmed by event",
+				zap.String("task_id", taskID),
+			)
+		}
+	}
+
+	c.dropTaskPermissions(taskID)
+	if c.repo == nil {
+		c.logger.Warn("Repository is not configured, skipping canceled status update",
+			zap.String("task_id", taskID),
+		)
+	} else if err := c.repo.UpsertTaskStatus(context.Background(), taskID, agentID, storage.TaskStatusCanceled); err != nil {
+		c.logger.Error("Failed to update task status to canceled",
+			zap.String("task_id", taskID),
+			zap.Error(err),
+		)
 	}
+	c.audit(ctx, AuditTaskCancel, AuditTargetTask, taskID, nil, taskAuditState{AgentID: agentID, Status: storage.TaskStatusCanceled})
 
-	// Context 취소
-	taskCtx.cancel()
+	c.emit(ControllerEvent{
+		TaskID:  taskID,
+		Status:  "canceled",
+		Content: "Task canceled by user",
+	})
 
 	c.logger.Info("Task canceled",
 		zap.String("task_id", taskID),
 	)
-
 	return nil
 }
 
+// savePartialOutput은 중단된 턴의 부분 어시스턴트 출력을 메시지로 저장합니다.
+// 완료된 파트는 이미 턴의 메시지 파일에 기록되어 있으므로 content만 채워 마무리합니다.
+func (c *Controller) savePartialOutput(ctx context.Context, taskID, output string) error {
+	return c.finishTranscript(ctx, taskID, output)
+}
+
 // cleanupTaskContext는 TaskContext를 정리합니다 (Runner 삭제 시 호출).
 func (c *Controller) cleanupTaskContext(taskID string) {
 	c.mu.Lock()
@@ -162,15 +217,17 @@ func (c *Controller) executeTask(ctx context.Context, taskID string, task *stora
 		)
 
 		agentInfo := taskrunner.AgentInfo{
-			AgentID:  agent.AgentID,
-			Provider: agent.Provider,
-			Model:    agent.Model,
-			Prompt:   agent.Prompt,
+			AgentID:     agent.AgentID,
+			Provider:    agent.Provider,
+			Model:       agent.Model,
+			Prompt:      agent.Prompt,
+			Image:       agent.Image,
+			ImageDigest: task.ImageDigest, // 최초 실행 시 고정된 이미지 재사용
 		}
 
 		// Runner 생성 (Controller를 callback으로 전달)
 		var err error
-		runner, err = c.runnerManager.CreateRunner(ctx, taskID, agentInfo, c)
+		runner, err = c.runnerManager.CreateRunner(ctx, taskID, agentInfo, c, c.runnerOptions(taskID)...)
 		if err != nil {
 			c.logger.Error("Failed to create runner", zap.Error(err))
 			_ = c.repo.UpsertTaskStatus(ctx, taskID, task.AgentID, storage.TaskStatusFailed)
@@ -182,10 +239,16 @@ func (c *Controller) executeTask(ctx context.Context, taskID string, task *stora
 			c.logger.Error("Failed to start runner", zap.Error(err))
 			_ = c.repo.UpsertTaskStatus(ctx, taskID, task.AgentID, storage.TaskStatusFailed)
 			// 생성된 Runner 정리
+			c.dropTaskPermissions(taskID)
+			c.dropTranscript(taskID)
 			_ = c.runnerManager.DeleteRunner(ctx, taskID)
 			return
 		}
 
+		if task.ImageDigest == "" {
+			c.recordTaskImage(ctx, taskID, runner.ImageRef)
+		}
+
 		c.logger.Info("Runner recreated successfully",
 			zap.String("task_id", taskID),
 		)
@@ -202,6 +265,8 @@ func (c *Controller) executeTask(ctx context.Context, taskID string, task *stora
 				c.logger.Error("Failed to restart runner", zap.Error(err))
 				_ = c.repo.UpsertTaskStatus(ctx, taskID, task.AgentID, storage.TaskStatusFailed)
 				// Runner 정리 후 재생성 시도
+				c.dropTaskPermissions(taskID)
+				c.dropTranscript(taskID)
 				_ = c.runnerManager.DeleteRunner(ctx, taskID)
 				return
 			}
@@ -273,13 +338,15 @@ func (c *Controller) executeTask(ctx context.Context, taskID string, task *stora
 		}
 		_ = c.repo.UpsertTaskStatus(context.Background(), taskID, task.AgentID, status)
 
-		c.controllerEventChan <- ControllerEvent{
+		c.emit(ControllerEvent{
 			TaskID: taskID,
 			Status: status,
 			Error:  ctx.Err(),
-		}
+		})
 
 		// 실행 완료 후 TaskRunner 정리
+		c.dropTaskPermissions(taskID)
+		c.dropTranscript(taskID)
 		if err := c.runnerManager.DeleteRunner(context.Background(), taskID); err != nil {
 			c.logger.Warn("Failed to delete runner",
 				zap.String("task_id", taskID),
@@ -299,11 +366,12 @@ func (c *Controller) executeTask(ctx context.Context, taskID string, task *stora
 			zap.Error(err),
 		)
 		_ = c.repo.UpsertTaskStatus(context.Background(), taskID, task.AgentID, storage.TaskStatusFailed)
-		c.controllerEventChan <- ControllerEvent{
-			TaskID: taskID,
-			Status: "failed",
-			Error:  err,
-		}
+		c.emit(ControllerEvent{
+			TaskID:  taskID,
+			Status:  "failed",
+			Error:   NewEventError(ErrorCodeRunner, true, err),
+			LogTail: c.runnerLogTail(taskID),
+		})
 	} else {
 		c.logger.Info("Task execution started successfully",
 			zap.String("task_id", taskID),
@@ -2,14 +2,9 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"time"
 
-	"github.com/cnap-oss/app/internal/common"
 	taskrunner "github.com/cnap-oss/app/internal/runner"
 	"github.com/cnap-oss/app/internal/runner/opencode"
 	"github.com/cnap-oss/app/internal/storage"
@@ -37,14 +32,8 @@ func (c *Controller) AddMessage(ctx context.Context, taskID, role, content strin
 		return err
 	}
 
-	// 메시지를 파일로 저장하고 인덱스 생성
-	filePath, err := c.saveMessageToFile(ctx, taskID, role, content)
-	if err != nil {
-		c.logger.Error("Failed to save message to file", zap.Error(err))
-		return err
-	}
-
-	if _, err := c.repo.AppendMessageIndex(ctx, taskID, role, filePath); err != nil {
+	// 메시지 인덱스를 할당하고 파일로 저장
+	if _, err := c.saveMessageToFile(ctx, taskID, role, content); err != nil {
 		c.logger.Error("Failed to add message", zap.Error(err))
 		return err
 	}
@@ -98,6 +87,8 @@ func (c *Controller) SendMessage(ctx context.Context, taskID string) error {
 		c.logger.Error("Failed to update task status", zap.Error(err))
 		return err
 	}
+	c.audit(ctx, AuditTaskExecute, AuditTargetTask, taskID,
+		taskAuditState{AgentID: task.AgentID, Status: task.Status}, taskAuditState{AgentID: task.AgentID, Status: storage.TaskStatusRunning})
 
 	c.logger.Info("Task execution triggered",
 		zap.String("task_id", taskID),
@@ -105,19 +96,19 @@ func (c *Controller) SendMessage(ctx context.Context, taskID string) error {
 		zap.Int("message_count", len(messages)),
 	)
 
-	// Task 실행을 위한 context 생성 (5분 타임아웃)
-	taskCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-
-	// TaskContext 저장
+	// TaskContext 조회 또는 생성 (타임아웃 없이, 턴 종료는 idle/abort 이벤트로 판단)
+	// Runner 없이 취소된 Task의 Context는 이미 취소되었으므로 새로 만듦
 	c.mu.Lock()
-	c.taskContexts[taskID] = &TaskContext{
-		ctx:    taskCtx,
-		cancel: cancel,
+	taskCtx, exists := c.taskContexts[taskID]
+	if !exists || taskCtx.ctx.Err() != nil {
+		newCtx, cancel := context.WithCancel(context.Background())
+		taskCtx = &TaskContext{ctx: newCtx, cancel: cancel}
+		c.taskContexts[taskID] = taskCtx
 	}
 	c.mu.Unlock()
 
 	// RunnerManager를 통해 실제 실행 트리거
-	go c.executeTask(taskCtx, taskID, task)
+	go c.executeTask(taskCtx.ctx, taskID, task)
 
 	return nil
 }
@@ -144,86 +135,66 @@ func (c *Controller) ListMessages(ctx context.Context, taskID string) ([]storage
 	return messages, nil
 }
 
-// saveMessageToFile saves message content to a file and returns the file path.
-// Messages are stored in {MessagesDir}/{taskID}/{conversationIndex}.json
+// saveMessageToFile saves message content to the message store, appends it to the conversation
+// and returns its key. Messages are stored under {taskID}/{conversationIndex}.json
+// For user messages, the actor in ctx, if any, is recorded as the message author.
 func (c *Controller) saveMessageToFile(ctx context.Context, taskID, role, content string) (string, error) {
-	// 1. 디렉토리 생성
-	dir := filepath.Join(common.GetMessagesDir(), taskID)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		c.logger.Error("Failed to create message directory",
-			zap.String("dir", dir),
-			zap.Error(err),
-		)
-		return "", fmt.Errorf("failed to create directory: %w", err)
+	msg := newMessageFile(role, content)
+	// 어시스턴트 응답 등은 요청한 사용자가 작성한 것이 아니므로 작성자를 기록하지 않음
+	if role == storage.MessageRoleUser {
+		msg.Author = ActorFromContext(ctx)
 	}
-
-	// 2. Conversation index 조회
-	messages, err := c.repo.ListMessageIndexByTask(ctx, taskID)
+	row, err := c.appendMessageFile(ctx, taskID, msg)
 	if err != nil {
-		c.logger.Error("Failed to list messages", zap.Error(err))
-		return "", fmt.Errorf("failed to list messages: %w", err)
-	}
-	index := len(messages)
-
-	// 3. JSON 파일 저장
-	filename := fmt.Sprintf("%04d.json", index)
-	filePath := filepath.Join(dir, filename)
-
-	msg := map[string]interface{}{
-		"role":      role,
-		"content":   content,
-		"timestamp": time.Now().Format(time.RFC3339),
+		return "", err
 	}
+	c.indexMessage(ctx, taskID, row.ConversationIndex, msg)
+	// 메시지 내용은 감사 기록에 남기지 않음
+	c.audit(ctx, AuditTaskAddMessage, AuditTargetTask, taskID, nil, map[string]any{"role": role, "conversation_index": row.ConversationIndex})
+	return row.FilePath, nil
+}
 
-	data, err := json.MarshalIndent(msg, "", "  ")
+// appendMessageFile allocates the next conversation index, writes msg to its key and
+// appends the MessageIndex row. Concurrent callers always receive distinct indices and keys.
+func (c *Controller) appendMessageFile(ctx context.Context, taskID string, msg *MessageFile) (*storage.MessageIndex, error) {
+	row, err := c.repo.AppendMessage(ctx, taskID, msg.Role, msg.Author, func(index int) (string, error) {
+		key := storage.MessageKey(taskID, index)
+		if err := c.putMessageFile(ctx, key, msg); err != nil {
+			c.logger.Error("Failed to write message",
+				zap.String("key", key),
+				zap.Error(err),
+			)
+			return "", err
+		}
+		return key, nil
+	})
 	if err != nil {
-		c.logger.Error("Failed to marshal message", zap.Error(err))
-		return "", fmt.Errorf("failed to marshal message: %w", err)
-	}
-
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		c.logger.Error("Failed to write file",
-			zap.String("path", filePath),
-			zap.Error(err),
-		)
-		return "", fmt.Errorf("failed to write file: %w", err)
+		return nil, fmt.Errorf("failed to append message: %w", err)
 	}
 
-	c.logger.Debug("Message saved to file",
+	c.logger.Debug("Message saved",
 		zap.String("task_id", taskID),
-		zap.String("role", role),
-		zap.String("path", filePath),
+		zap.String("role", msg.Role),
+		zap.String("key", row.FilePath),
 	)
 
-	return filePath, nil
+	return row, nil
 }
 
-// readMessageFromFile reads message content from a JSON file.
+// readMessageFromFile reads message content from the message store.
+// Both the legacy {role, content, timestamp} format and the structured format with parts are supported.
 func (c *Controller) readMessageFromFile(filePath string) (string, error) {
-	// 파일 읽기
-	data, err := os.ReadFile(filePath)
+	msg, err := c.GetMessageFile(context.Background(), filePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read file: %w", err)
+		return "", err
 	}
-
-	// JSON 파싱
-	var msg map[string]interface{}
-	if err := json.Unmarshal(data, &msg); err != nil {
-		return "", fmt.Errorf("failed to parse JSON: %w", err)
-	}
-
-	// content 필드 추출
-	content, ok := msg["content"].(string)
-	if !ok {
-		return "", fmt.Errorf("content field not found or not a string")
-	}
-
-	return content, nil
+	return msg.Text(), nil
 }
 
 // SendOneMessage adds a single user message to the task and immediately executes it.
 // Unlike SendMessage which executes all accumulated messages, this function only sends
-// the newly added message to the Runner.
+// the newly added message to the Runner. If another turn is in progress, the message is
+// submitted once that turn ends instead of being rejected.
 func (c *Controller) SendOneMessage(ctx context.Context, taskID, content string) error {
 	c.logger.Info("Sending one message for task",
 		zap.String("task_id", taskID),
@@ -242,14 +213,9 @@ func (c *Controller) SendOneMessage(ctx context.Context, taskID, content string)
 		return err
 	}
 
-	// 메시지를 파일로 저장하고 인덱스 생성
+	// 메시지 인덱스를 할당하고 파일로 저장
 	filePath, err := c.saveMessageToFile(ctx, taskID, "user", content)
 	if err != nil {
-		c.logger.Error("Failed to save message to file", zap.Error(err))
-		return err
-	}
-
-	if _, err := c.repo.AppendMessageIndex(ctx, taskID, "user", filePath); err != nil {
 		c.logger.Error("Failed to add message", zap.Error(err))
 		return err
 	}
@@ -276,14 +242,16 @@ func (c *Controller) SendOneMessage(ctx context.Context, taskID, content string)
 		)
 
 		agentInfo := taskrunner.AgentInfo{
-			AgentID:  agent.AgentID,
-			Provider: agent.Provider,
-			Model:    agent.Model,
-			Prompt:   agent.Prompt,
+			AgentID:     agent.AgentID,
+			Provider:    agent.Provider,
+			Model:       agent.Model,
+			Prompt:      agent.Prompt,
+			Image:       agent.Image,
+			ImageDigest: task.ImageDigest, // 최초 실행 시 고정된 이미지 재사용
 		}
 
 		// Runner 생성 (Controller를 callback으로 전달)
-		runner, err = c.runnerManager.CreateRunner(ctx, taskID, agentInfo, c)
+		runner, err = c.runnerManager.CreateRunner(ctx, taskID, agentInfo, c, c.runnerOptions(taskID)...)
 		if err != nil {
 			c.logger.Error("Failed to create runner", zap.Error(err))
 			return fmt.Errorf("failed to create runner: %w", err)
@@ -293,10 +261,16 @@ func (c *Controller) SendOneMessage(ctx context.Context, taskID, content string)
 		if err := c.runnerManager.StartRunner(ctx, taskID); err != nil {
 			c.logger.Error("Failed to start runner", zap.Error(err))
 			// 생성된 Runner 정리
+			c.dropTaskPermissions(taskID)
+			c.dropTranscript(taskID)
 			_ = c.runnerManager.DeleteRunner(ctx, taskID)
 			return fmt.Errorf("failed to start runner: %w", err)
 		}
 
+		if task.ImageDigest == "" {
+			c.recordTaskImage(ctx, taskID, runner.ImageRef)
+		}
+
 		c.logger.Info("Runner recreated successfully",
 			zap.String("task_id", taskID),
 		)
@@ -319,7 +293,17 @@ func (c *Controller) SendOneMessage(ctx context.Context, taskID, content string)
 	}
 
 	// TaskRunner 실행 (비동기, 결과는 callback으로 처리됨)
-	if err := runner.Run(ctx, req); err != nil {
+	// 다른 경로로 시작된 턴이 진행 중이면 끝난 뒤 다시 제출 (메시지는 이미 저장되어 실패로 처리하지 않음)
+	err = runner.Run(ctx, req)
+	for errors.Is(err, taskrunner.ErrTurnInProgress) {
+		c.logger.Info("Turn in progress, waiting before sending message",
+			zap.String("task_id", taskID),
+		)
+		if err = runner.WaitTurn(ctx); err == nil {
+			err = runner.Run(ctx, req)
+		}
+	}
+	if err != nil {
 		c.logger.Error("Failed to start task execution",
 			zap.String("task_id", taskID),
 			zap.Error(err),
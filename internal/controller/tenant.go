@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrPermissionDenied는 요청한 사용자에게 작업 권한이 없을 때 반환됩니다.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// tenantKey는 context에 요청한 테넌트를 담는 키입니다.
+type tenantKey struct{}
+
+// WithTenant는 요청한 테넌트(Discord 서버 등)를 담은 context를 반환합니다.
+// 이 context로 호출하면 에이전트, Task, 검색 결과가 테넌트 범위로 제한되고
+// 에이전트 수정은 소유자(소유자가 없으면 같은 테넌트 사용자)와 테넌트 admin만 할 수 있습니다.
+// 테넌트가 없는 context(CLI, 내부 작업)는 제한 없이 모든 테넌트에 접근합니다.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	if tenant == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext는 ctx에 담긴 테넌트를 반환합니다. 없으면 빈 문자열입니다.
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// ParseAgentVisibility는 에이전트 공개 범위 값을 검증합니다.
+func ParseAgentVisibility(s string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(s)); v {
+	case storage.AgentVisibilityPrivate, storage.AgentVisibilityGuild, storage.AgentVisibilityPublic:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unknown agent visibility: %s (private, guild, public)", s)
+	}
+}
+
+// agentScope는 ctx의 테넌트와 사용자로 에이전트 조회 범위를 만듭니다. 테넌트가 없으면 false입니다.
+func agentScope(ctx context.Context) (storage.AgentScope, bool) {
+	tenant := TenantFromContext(ctx)
+	if tenant == "" {
+		return storage.AgentScope{}, false
+	}
+	return storage.AgentScope{
+		TenantID: tenant,
+		Actor:    ActorFromContext(ctx),
+		Admin:    RoleFromContext(ctx) == storage.RoleAdmin,
+	}, true
+}
+
+// getAgent는 ctx의 사용자에게 보이는 에이전트를 조회합니다.
+// 테넌트 범위에서는 테넌트 안의 이름을 먼저 찾고, 다음으로 식별자를 찾습니다.
+// 둘 다 없으면 보이는 에이전트 중 이름이 같은 것이 하나뿐일 때 그 에이전트를 사용합니다(다른 테넌트의 public 에이전트 등).
+// 다른 테넌트의 에이전트는 존재 여부가 드러나지 않도록 찾을 수 없다고 응답합니다.
+// 호출자는 이후 저장소 호출에 반환된 레코드의 AgentID를 사용해야 합니다.
+func (c *Controller) getAgent(ctx context.Context, agentID string) (*storage.Agent, error) {
+	scope, scoped := agentScope(ctx)
+	visible := func(rec *storage.Agent) bool { return !scoped || scope.Visible(rec) }
+
+	if scoped {
+		rec, err := c.repo.GetAgentByName(ctx, scope.TenantID, agentID)
+		if err == nil && visible(rec) {
+			return rec, nil
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	rec, err := c.repo.GetAgent(ctx, agentID)
+	if err == nil && visible(rec) {
+		return rec, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	agents, err := c.repo.ListAgentsByName(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	var matched []*storage.Agent
+	for i := range agents {
+		if visible(&agents[i]) {
+			matched = append(matched, &agents[i])
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return nil, fmt.Errorf("agent not found: %s", agentID)
+	case 1:
+		return matched[0], nil
+	}
+	ids := make([]string, 0, len(matched))
+	for _, a := range matched {
+		ids = append(ids, a.AgentID)
+	}
+	return nil, fmt.Errorf("agent name %s is used in several tenants; use the agent ID (%s)", agentID, strings.Join(ids, ", "))
+}
+
+// agentFilterID는 목록과 검색의 에이전트 필터를 식별자로 바꿉니다.
+// 찾을 수 없는 에이전트는 그대로 두어 결과가 비게 합니다.
+func (c *Controller) agentFilterID(ctx context.Context, agent string) string {
+	if agent == "" {
+		return ""
+	}
+	if rec, err := c.getAgent(ctx, agent); err == nil {
+		return rec.AgentID
+	}
+	return agent
+}
+
+// getWritableAgent는 ctx의 사용자가 수정할 수 있는 에이전트를 조회합니다.
+func (c *Controller) getWritableAgent(ctx context.Context, agentID string) (*storage.Agent, error) {
+	rec, err := c.getAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	scope, ok := agentScope(ctx)
+	if !ok || canModifyAgent(scope, rec.TenantID, rec.Owner) {
+		return rec, nil
+	}
+	if rec.TenantID != scope.TenantID {
+		return nil, fmt.Errorf("%w: agent %s belongs to another tenant", ErrPermissionDenied, agentID)
+	}
+	return nil, fmt.Errorf("%w: agent %s is owned by %s", ErrPermissionDenied, agentID, rec.Owner)
+}
+
+// CanModifyAgent는 ctx의 사용자가 에이전트를 수정하거나 삭제할 수 있는지 확인합니다.
+// 테넌트가 없는 context는 항상 수정할 수 있습니다.
+func CanModifyAgent(ctx context.Context, agent *AgentInfo) bool {
+	scope, ok := agentScope(ctx)
+	return !ok || canModifyAgent(scope, agent.Tenant, agent.Owner)
+}
+
+// canModifyAgent는 소유 테넌트 안에서 admin이거나 소유자 본인이거나 소유자가 없는 공용 에이전트인지 확인합니다.
+func canModifyAgent(scope storage.AgentScope, tenantID, owner string) bool {
+	return tenantID == scope.TenantID && (scope.Admin || owner == "" || owner == scope.Actor)
+}
+
+// SetAgentVisibility는 에이전트의 공개 범위를 변경합니다. 에이전트를 수정할 수 있는 사용자만 변경할 수 있습니다.
+func (c *Controller) SetAgentVisibility(ctx context.Context, agentID, visibility string) error {
+	c.logger.Info("Setting agent visibility",
+		zap.String("agent_id", agentID),
+		zap.String("visibility", visibility),
+	)
+
+	if c.repo == nil {
+		return fmt.Errorf("controller: repository is not configured")
+	}
+
+	visibility, err := ParseAgentVisibility(visibility)
+	if err != nil {
+		return err
+	}
+	rec, err := c.getWritableAgent(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	agentID = rec.AgentID
+
+	before := c.agentAuditSnapshot(ctx, agentID)
+	if err := c.repo.UpdateAgentOwnership(ctx, agentID, rec.TenantID, rec.Owner, visibility); err != nil {
+		c.logger.Error("Failed to update agent visibility", zap.Error(err))
+		return err
+	}
+	c.audit(ctx, AuditAgentSetVisibility, AuditTargetAgent, agentID, before, c.agentAuditSnapshot(ctx, agentID))
+	return nil
+}
+
+// TransferAgent는 에이전트의 소유 테넌트와 소유자를 변경합니다. owner가 비어 있으면 테넌트 공용 에이전트가 됩니다.
+// 테넌트 범위의 context에서는 같은 테넌트 안에서만 소유자를 넘길 수 있습니다.
+func (c *Controller) TransferAgent(ctx context.Context, agentID, tenant, owner string) error {
+	c.logger.Info("Transferring agent",
+		zap.String("agent_id", agentID),
+		zap.String("tenant", tenant),
+		zap.String("owner", owner),
+	)
+
+	if c.repo == nil {
+		return fmt.Errorf("controller: repository is not configured")
+	}
+
+	rec, err := c.getWritableAgent(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	agentID = rec.AgentID
+	if tenant == "" {
+		tenant = rec.TenantID
+	}
+	if scoped := TenantFromContext(ctx); scoped != "" && tenant != scoped {
+		return fmt.Errorf("%w: cannot move agent %s to another tenant", ErrPermissionDenied, agentID)
+	}
+
+	before := c.agentAuditSnapshot(ctx, agentID)
+	if err := c.repo.UpdateAgentOwnership(ctx, agentID, tenant, ParseActor(owner), rec.Visibility); err != nil {
+		c.logger.Error("Failed to transfer agent", zap.Error(err))
+		return err
+	}
+	c.audit(ctx, AuditAgentTransfer, AuditTargetAgent, agentID, before, c.agentAuditSnapshot(ctx, agentID))
+	return nil
+}
@@ -0,0 +1,131 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAgentTenantScope(t *testing.T) {
+	ctx := context.Background()
+	ctrl, _ := newBundleController(t, "tenant_scope")
+	db, err := gorm.Open(sqlite.Open("file:tenant_scope?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	user := func(tenant, id string) context.Context {
+		return controller.WithTenant(controller.WithActor(ctx, "discord:"+id), tenant)
+	}
+	alice, bob, carol := user("guild:a", "1"), user("guild:a", "2"), user("guild:b", "3")
+	names := func(ctx context.Context) []string {
+		agents, err := ctrl.ListAgents(ctx)
+		require.NoError(t, err)
+		return agents
+	}
+
+	require.NoError(t, ctrl.CreateAgent(alice, "alice-bot", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.CreateAgent(ctx, "shared-bot", "", "opencode", "gpt-4", "prompt"))
+
+	info, err := ctrl.GetAgentInfo(ctx, "alice-bot")
+	require.NoError(t, err)
+	assert.Equal(t, "guild:a", info.Tenant)
+	assert.Equal(t, "discord:1", info.Owner)
+	assert.Equal(t, storage.AgentVisibilityGuild, info.Visibility)
+	info, err = ctrl.GetAgentInfo(ctx, "shared-bot")
+	require.NoError(t, err)
+	assert.Equal(t, storage.DefaultTenantID, info.Tenant)
+	assert.Empty(t, info.Owner)
+
+	// 같은 서버 사용자는 조회만 가능하고 다른 서버에서는 보이지 않음
+	assert.Equal(t, []string{"alice-bot"}, names(bob))
+	assert.Empty(t, names(carol))
+	_, err = ctrl.GetAgentInfo(carol, "alice-bot")
+	assert.EqualError(t, err, "agent not found: alice-bot")
+	assert.ErrorIs(t, ctrl.UpdateAgent(bob, "alice-bot", "", "opencode", "gpt-4o", "prompt"), controller.ErrPermissionDenied)
+	assert.ErrorIs(t, ctrl.DeleteAgent(bob, "alice-bot"), controller.ErrPermissionDenied)
+	assert.ErrorIs(t, ctrl.SetAgentVisibility(bob, "alice-bot", storage.AgentVisibilityPublic), controller.ErrPermissionDenied)
+	require.NoError(t, ctrl.UpdateAgent(alice, "alice-bot", "", "opencode", "gpt-4o", "prompt"))
+
+	// private이면 소유자에게만, public이면 모든 서버에 보이지만 수정은 소유자만 가능
+	require.NoError(t, ctrl.SetAgentVisibility(alice, "alice-bot", storage.AgentVisibilityPrivate))
+	assert.Empty(t, names(bob))
+	assert.Equal(t, []string{"alice-bot"}, names(alice))
+	require.NoError(t, ctrl.SetAgentVisibility(alice, "alice-bot", storage.AgentVisibilityPublic))
+	assert.Equal(t, []string{"alice-bot"}, names(carol))
+	assert.ErrorIs(t, ctrl.SetAgentImage(carol, "alice-bot", "example/runner:1"), controller.ErrPermissionDenied)
+
+	// 기본 테넌트의 공용 에이전트는 기본 테넌트 사용자 누구나 수정 가능
+	require.NoError(t, ctrl.UpdateAgent(user(storage.DefaultTenantID, "4"), "shared-bot", "shared", "opencode", "gpt-4", "prompt"))
+
+	// 소유자 이전은 테넌트 밖으로 할 수 없고, CLI(테넌트 없음)는 제한 없음
+	assert.ErrorIs(t, ctrl.TransferAgent(alice, "alice-bot", "guild:b", ""), controller.ErrPermissionDenied)
+	require.NoError(t, ctrl.TransferAgent(alice, "alice-bot", "", "2"))
+	require.NoError(t, ctrl.UpdateAgent(bob, "alice-bot", "", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.TransferAgent(ctx, "alice-bot", "guild:b", ""))
+	require.NoError(t, ctrl.DeleteAgent(carol, "alice-bot"))
+
+	// Task 목록은 Task를 시작한 테넌트로 제한
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "thread-a", AgentID: "shared-bot", Status: storage.TaskStatusWaiting, TenantID: "guild:a"}))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "cli-task", AgentID: "shared-bot", Status: storage.TaskStatusWaiting}))
+	page, err := ctrl.ListTasks(bob, storage.TaskQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 1)
+	assert.Equal(t, "thread-a", page.Tasks[0].TaskID)
+	page, err = ctrl.ListTasks(ctx, storage.TaskQuery{})
+	require.NoError(t, err)
+	assert.Len(t, page.Tasks, 2)
+	task, err := repo.GetTask(ctx, "cli-task")
+	require.NoError(t, err)
+	assert.Equal(t, storage.DefaultTenantID, task.TenantID)
+
+	events, err := ctrl.QueryAuditEvents(ctx, storage.AuditQuery{Action: controller.AuditAgentTransfer})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "discord:1", events[1].Actor)
+}
+
+func TestAgentNamesPerTenant(t *testing.T) {
+	ctx := context.Background()
+	ctrl, _ := newBundleController(t, "tenant_agent_names")
+	db, err := gorm.Open(sqlite.Open("file:tenant_agent_names?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+	user := func(tenant, id string) context.Context {
+		return controller.WithTenant(controller.WithActor(ctx, "discord:"+id), tenant)
+	}
+	alice, bob, carol := user("guild:a", "1"), user("guild:a", "2"), user("guild:b", "3")
+
+	// 다른 서버의 private 에이전트와 같은 이름을 쓸 수 있고, 각 서버에서는 자기 에이전트로 해석됨
+	require.NoError(t, ctrl.CreateAgent(alice, "helper", "a", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, ctrl.SetAgentVisibility(alice, "helper", storage.AgentVisibilityPrivate))
+	require.NoError(t, ctrl.CreateAgent(carol, "helper", "b", "opencode", "gpt-4", "prompt"))
+	info, err := ctrl.GetAgentInfo(carol, "helper")
+	require.NoError(t, err)
+	assert.Equal(t, "b", info.Description)
+	assert.Equal(t, "helper", info.Name)
+	assert.NotEqual(t, "helper", info.ID)
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "helper-task", AgentID: info.ID, Status: storage.TaskStatusWaiting, TenantID: "guild:b"}))
+	page, err := ctrl.ListTasks(carol, storage.TaskQuery{AgentID: "helper"})
+	require.NoError(t, err)
+	require.Len(t, page.Tasks, 1)
+	assert.Equal(t, info.ID, page.Tasks[0].AgentID)
+
+	// 같은 서버 안의 충돌만 다른 서버를 드러내지 않는 오류로 알림
+	err = ctrl.CreateAgent(bob, "helper", "", "opencode", "gpt-4", "prompt")
+	assert.ErrorIs(t, err, storage.ErrAgentExists)
+	assert.NotContains(t, err.Error(), "guild:")
+
+	// CLI는 여러 테넌트에 있는 이름 대신 식별자를 요구
+	_, err = ctrl.GetAgentInfo(ctx, "helper")
+	assert.ErrorContains(t, err, "use the agent ID")
+	_, err = ctrl.GetAgentInfo(ctx, info.ID)
+	assert.NoError(t, err)
+}
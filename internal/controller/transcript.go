@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+)
+
+// turnTranscript는 진행 중인 턴의 어시스턴트 메시지 파일입니다.
+// 첫 파트가 완료될 때 파일과 MessageIndex가 생성되고, 이후 파트가 완료될 때마다 파일이 갱신됩니다.
+type turnTranscript struct {
+	file      *MessageFile
+	path      string                // 아직 파일이 생성되지 않았으면 빈 문자열
+	index     int                   // 파일이 생성된 후의 ConversationIndex
+	assistant map[string]struct{}   // 루트 세션의 어시스턴트 메시지 ID
+	usage     map[string]TokenUsage // 어시스턴트 메시지별 최신 토큰 사용량
+}
+
+// transcriptRegistry는 Task별 루트 세션과 진행 중인 턴의 메시지 파일을 관리합니다.
+type transcriptRegistry struct {
+	mu       sync.Mutex
+	sessions map[string]string // taskID -> 루트 세션 ID
+	turns    map[string]*turnTranscript
+}
+
+func newTranscriptRegistry() *transcriptRegistry {
+	return &transcriptRegistry{
+		sessions: make(map[string]string),
+		turns:    make(map[string]*turnTranscript),
+	}
+}
+
+// setTranscriptSession은 Task의 루트 세션 ID를 기록합니다.
+func (c *Controller) setTranscriptSession(taskID, sessionID string) {
+	c.transcripts.mu.Lock()
+	defer c.transcripts.mu.Unlock()
+	c.transcripts.sessions[taskID] = sessionID
+}
+
+// recordTranscript는 루트 세션의 어시스턴트 파트가 완료되면 턴의 메시지 파일에 기록합니다.
+func (c *Controller) recordTranscript(taskID string, msg *opencode.RunnerMessage) {
+	r := c.transcripts
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 하위 세션(subagent)의 메시지는 기록하지 않음
+	if root := r.sessions[taskID]; root != "" && msg.SessionID != "" && msg.SessionID != root {
+		return
+	}
+
+	switch msg.Type {
+	case opencode.MessageTypeMessageUpdated:
+		m, ok := msg.Message.(opencode.AssistantMessage)
+		if !ok {
+			return
+		}
+		turn := r.turns[taskID]
+		if turn == nil {
+			turn = &turnTranscript{
+				file:      newMessageFile(storage.MessageRoleAssistant, ""),
+				assistant: make(map[string]struct{}),
+				usage:     make(map[string]TokenUsage),
+			}
+			turn.file.SessionID = m.SessionID
+			r.turns[taskID] = turn
+		}
+		turn.assistant[m.ID] = struct{}{}
+		// 사용량은 마지막 파트 이후에 확정되는 경우가 많으므로 이미 만든 파일에도 반영
+		if turn.recordUsage(m) && turn.path != "" {
+			if err := c.writeTranscript(context.Background(), taskID, turn); err != nil {
+				c.logger.Error("Failed to write transcript usage",
+					zap.String("task_id", taskID),
+					zap.String("message_id", m.ID),
+					zap.Error(err),
+				)
+			}
+		}
+		return
+
+	case opencode.MessageTypeText, opencode.MessageTypeReasoning:
+		if msg.IsPartial {
+			return
+		}
+	case opencode.MessageTypeToolResult:
+	default:
+		return
+	}
+
+	turn := r.turns[taskID]
+	if turn == nil || msg.Part == nil {
+		return
+	}
+	if _, ok := turn.assistant[msg.MessageID]; !ok {
+		return
+	}
+	part, ok := newMessagePart(msg.Part)
+	if !ok {
+		return
+	}
+	turn.file.upsertPart(part)
+	turn.file.addMessageID(msg.MessageID)
+	turn.file.Content = turn.file.partsText()
+
+	if err := c.writeTranscript(context.Background(), taskID, turn); err != nil {
+		c.logger.Error("Failed to write transcript",
+			zap.String("task_id", taskID),
+			zap.String("part_id", part.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// recordUsage는 어시스턴트 메시지의 모델과 토큰 사용량을 턴 합계에 반영합니다.
+// message.updated는 같은 메시지에 대해 여러 번 오므로 메시지별 최신 값으로 다시 합산합니다.
+// 모델이나 사용량이 바뀌었으면 true를 반환합니다.
+func (t *turnTranscript) recordUsage(m opencode.AssistantMessage) bool {
+	model := t.file.Model
+	if m.ModelID != "" {
+		model = m.ModelID
+		if m.ProviderID != "" {
+			model = m.ProviderID + "/" + m.ModelID
+		}
+	}
+	usage := newTokenUsage(m)
+	if prev, ok := t.usage[m.ID]; ok && prev == usage && model == t.file.Model {
+		return false
+	}
+	t.file.Model = model
+	t.usage[m.ID] = usage
+
+	var total TokenUsage
+	for _, u := range t.usage {
+		total.Add(u)
+	}
+	if total == (TokenUsage{}) {
+		t.file.Usage = nil
+	} else {
+		t.file.Usage = &total
+	}
+	return true
+}
+
+// writeTranscript는 턴의 메시지 파일을 씁니다. 처음 쓸 때 파일을 만들고 MessageIndex에 추가합니다.
+// 호출자는 transcripts.mu를 보유해야 합니다.
+func (c *Controller) writeTranscript(ctx context.Context, taskID string, turn *turnTranscript) error {
+	if turn.path != "" {
+		return c.putMessageFile(ctx, turn.path, turn.file)
+	}
+
+	row, err := c.appendMessageFile(ctx, taskID, turn.file)
+	if err != nil {
+		return err
+	}
+	turn.path = row.FilePath
+	turn.index = row.ConversationIndex
+	return nil
+}
+
+// finishTranscript는 턴의 메시지 파일을 최종 어시스턴트 메시지로 마무리하고 검색 색인에 추가한 뒤 턴을 종료합니다.
+// 턴에서 기록된 파트가 없고 content도 비어 있으면 파일을 만들지 않습니다.
+func (c *Controller) finishTranscript(ctx context.Context, taskID, content string) error {
+	r := c.transcripts
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	turn := r.turns[taskID]
+	delete(r.turns, taskID)
+	if turn == nil {
+		turn = &turnTranscript{file: newMessageFile(storage.MessageRoleAssistant, "")}
+	}
+	if content != "" {
+		turn.file.Content = content
+	}
+	if turn.path == "" && turn.file.Content == "" && len(turn.file.Parts) == 0 {
+		return nil
+	}
+	if err := c.writeTranscript(ctx, taskID, turn); err != nil {
+		return err
+	}
+	// 턴이 끝난 메시지만 검색 색인에 추가
+	c.indexMessage(ctx, taskID, turn.index, turn.file)
+	return nil
+}
+
+// dropTranscript는 Task의 턴 기록 상태를 제거합니다 (Runner 삭제 시 호출).
+func (c *Controller) dropTranscript(taskID string) {
+	c.transcripts.mu.Lock()
+	defer c.transcripts.mu.Unlock()
+	delete(c.transcripts.sessions, taskID)
+	delete(c.transcripts.turns, taskID)
+}
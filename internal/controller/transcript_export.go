@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 대화 기록 내보내기 형식
+const (
+	TranscriptFormatMarkdown = "md"
+	TranscriptFormatHTML     = "html"
+	TranscriptFormatJSONL    = "jsonl"
+)
+
+// TaskTranscript는 Task 하나의 대화 기록과 메타데이터입니다.
+type TaskTranscript struct {
+	TaskID     string
+	AgentID    string
+	Provider   string // 에이전트에 설정된 프로바이더
+	Model      string // 에이전트에 설정된 모델 (턴별 실제 모델은 메시지의 Model)
+	Revision   string // Task에 고정된 Runner 이미지 (다이제스트)
+	Status     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ExportedAt time.Time
+	Usage      TokenUsage // 모든 어시스턴트 턴의 합계
+	Messages   []TranscriptMessage
+	RunSteps   []storage.RunStep
+}
+
+// TranscriptMessage는 대화 순번과 메시지 파일 내용입니다.
+// 메시지 파일을 읽지 못하면 Unavailable이 true이고 File에는 역할만 채워집니다.
+type TranscriptMessage struct {
+	Index       int
+	CreatedAt   time.Time
+	File        *MessageFile
+	Unavailable bool
+}
+
+// ExportFileName은 형식에 맞는 내보내기 파일 이름을 반환합니다.
+func (t *TaskTranscript) ExportFileName(format string) string {
+	return fmt.Sprintf("task-%s.%s", t.TaskID, format)
+}
+
+// BuildTaskTranscript는 MessageIndex 순서대로 메시지 파일을 읽어 대화 기록을 구성합니다.
+func (c *Controller) BuildTaskTranscript(ctx context.Context, taskID string) (*TaskTranscript, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("controller: repository is not configured")
+	}
+
+	task, err := c.repo.GetTask(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task not found: %s", taskID)
+		}
+		return nil, err
+	}
+
+	t := &TaskTranscript{
+		TaskID:     task.TaskID,
+		AgentID:    task.AgentID,
+		Revision:   task.ImageDigest,
+		Status:     task.Status,
+		CreatedAt:  task.CreatedAt,
+		UpdatedAt:  task.UpdatedAt,
+		ExportedAt: time.Now(),
+	}
+	if agent, err := c.repo.GetAgent(ctx, task.AgentID); err == nil {
+		t.Provider = agent.Provider
+		t.Model = agent.Model
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	rows, err := c.repo.ListMessageIndexByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		msg := TranscriptMessage{Index: row.ConversationIndex, CreatedAt: row.CreatedAt}
+		file, err := c.GetMessageFile(ctx, row.FilePath)
+		if err != nil {
+			c.logger.Warn("Failed to read message for transcript export",
+				zap.String("task_id", taskID),
+				zap.String("key", row.FilePath),
+				zap.Error(err),
+			)
+			file = &MessageFile{Role: row.Role}
+			msg.Unavailable = true
+		}
+		msg.File = file
+		if file.Usage != nil {
+			t.Usage.Add(*file.Usage)
+		}
+		t.Messages = append(t.Messages, msg)
+	}
+
+	if t.RunSteps, err = c.repo.ListRunSteps(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// WriteTranscript는 대화 기록을 지정한 형식으로 씁니다.
+func WriteTranscript(w io.Writer, t *TaskTranscript, format string) error {
+	switch format {
+	case TranscriptFormatMarkdown:
+		return writeTranscriptMarkdown(w, t)
+	case TranscriptFormatHTML:
+		return writeTranscriptHTML(w, t)
+	case TranscriptFormatJSONL:
+		return writeTranscriptJSONL(w, t)
+	default:
+		return fmt.Errorf("unknown transcript format %q (expected %s, %s or %s)",
+			format, TranscriptFormatMarkdown, TranscriptFormatHTML, TranscriptFormatJSONL)
+	}
+}
+
+// writeTranscriptJSONL은 task 메타데이터, message, run_step 순서로 한 줄에 하나의 JSON 객체를 씁니다.
+func writeTranscriptJSONL(w io.Writer, t *TaskTranscript) error {
+	enc := json.NewEncoder(w)
+
+	if err := enc.Encode(struct {
+		Type       string     `json:"type"`
+		TaskID     string     `json:"task_id"`
+		AgentID    string     `json:"agent_id"`
+		Provider   string     `json:"provider,omitempty"`
+		Model      string     `json:"model,omitempty"`
+		Revision   string     `json:"revision,omitempty"`
+		Status     string     `json:"status"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  time.Time  `json:"updated_at"`
+		ExportedAt time.Time  `json:"exported_at"`
+		Usage      TokenUsage `json:"usage"`
+	}{"task", t.TaskID, t.AgentID, t.Provider, t.Model, t.Revision, t.Status, t.CreatedAt, t.UpdatedAt, t.ExportedAt, t.Usage}); err != nil {
+		return err
+	}
+
+	for _, m := range t.Messages {
+		if err := enc.Encode(struct {
+			Type        string    `json:"type"`
+			Index       int       `json:"index"`
+			CreatedAt   time.Time `json:"created_at"`
+			Unavailable bool      `json:"unavailable,omitempty"`
+			*MessageFile
+		}{"message", m.Index, m.CreatedAt, m.Unavailable, m.File}); err != nil {
+			return err
+		}
+	}
+
+	for _, step := range t.RunSteps {
+		if err := enc.Encode(struct {
+			Type      string    `json:"type"`
+			StepNo    int       `json:"step_no"`
+			StepType  string    `json:"step_type"`
+			Status    string    `json:"status"`
+			CreatedAt time.Time `json:"created_at"`
+		}{"run_step", step.StepNo, step.Type, step.Status, step.CreatedAt}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transcriptPart는 Markdown, HTML 출력에 쓰는 파트 표현입니다.
+type transcriptPart struct {
+	Kind     PartType
+	Text     string
+	Tool     string
+	Title    string
+	Status   string
+	Input    string // 보기 좋게 들여쓴 JSON
+	Output   string
+	Error    string
+	Duration string
+}
+
+// transcriptParts는 메시지의 파트를 출력용으로 변환합니다. 파트가 없는 메시지(사용자, 버전 1)는 본문 하나로 표시합니다.
+func transcriptParts(m *MessageFile) []transcriptPart {
+	if len(m.Parts) == 0 {
+		if m.Content == "" {
+			return nil
+		}
+		return []transcriptPart{{Kind: PartTypeText, Text: m.Content}}
+	}
+
+	parts := make([]transcriptPart, 0, len(m.Parts))
+	for _, p := range m.Parts {
+		tp := transcriptPart{Kind: p.Type, Text: p.Text, Tool: p.Tool, Title: p.Title, Status: p.Status, Output: p.Output, Error: p.Error}
+		if len(p.Input) > 0 {
+			if data, err := json.MarshalIndent(p.Input, "", "  "); err == nil {
+				tp.Input = string(data)
+			}
+		}
+		if p.StartedAt != nil && p.EndedAt != nil {
+			tp.Duration = p.EndedAt.Sub(*p.StartedAt).Round(time.Millisecond).String()
+		}
+		parts = append(parts, tp)
+	}
+	return parts
+}
+
+// formatTokenUsage는 토큰 사용량을 한 줄로 표시합니다.
+func formatTokenUsage(u TokenUsage) string {
+	if u == (TokenUsage{}) {
+		return "-"
+	}
+	s := fmt.Sprintf("입력 %d · 출력 %d", u.Input, u.Output)
+	if u.Reasoning > 0 {
+		s += fmt.Sprintf(" · 추론 %d", u.Reasoning)
+	}
+	if u.CacheRead > 0 || u.CacheWrite > 0 {
+		s += fmt.Sprintf(" · 캐시 읽기 %d/쓰기 %d", u.CacheRead, u.CacheWrite)
+	}
+	if u.Cost > 0 {
+		s += fmt.Sprintf(" · $%.4f", u.Cost)
+	}
+	return s
+}
+
+// transcriptMetadata는 Markdown, HTML 머리말에 표시할 항목입니다.
+func transcriptMetadata(t *TaskTranscript) [][2]string {
+	model := t.Model
+	if t.Provider != "" && t.Model != "" {
+		model = t.Provider + "/" + t.Model
+	}
+	return [][2]string{
+		{"에이전트", orDash(t.AgentID)},
+		{"모델", orDash(model)},
+		{"리비전", orDash(t.Revision)},
+		{"상태", orDash(t.Status)},
+		{"생성", formatTranscriptTime(t.CreatedAt)},
+		{"마지막 활동", formatTranscriptTime(t.UpdatedAt)},
+		{"토큰 사용량", formatTokenUsage(t.Usage)},
+		{"내보낸 시각", formatTranscriptTime(t.ExportedAt)},
+	}
+}
+
+// messageHeading은 메시지 제목(순번, 역할, 시각, 모델, 토큰)을 만듭니다.
+func messageHeading(m TranscriptMessage) string {
+	items := []string{fmt.Sprintf("#%d %s", m.Index, m.File.Role), formatTranscriptTime(m.CreatedAt)}
+	if m.File.Model != "" {
+		items = append(items, m.File.Model)
+	}
+	if m.File.Usage != nil {
+		items = append(items, fmt.Sprintf("%d tokens", m.File.Usage.Total()))
+	}
+	return strings.Join(items, " · ")
+}
+
+func formatTranscriptTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05 MST")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// writeTranscriptMarkdown은 도구 호출을 접을 수 있는 <details> 블록으로 표시하는 Markdown을 씁니다.
+func writeTranscriptMarkdown(w io.Writer, t *TaskTranscript) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Task %s 대화 기록\n\n", t.TaskID)
+	b.WriteString("| 항목 | 값 |\n| --- | --- |\n")
+	for _, kv := range transcriptMetadata(t) {
+		fmt.Fprintf(&b, "| %s | %s |\n", kv[0], strings.ReplaceAll(kv[1], "|", "\\|"))
+	}
+
+	for _, m := range t.Messages {
+		fmt.Fprintf(&b, "\n---\n\n## %s\n\n", messageHeading(m))
+		if m.Unavailable {
+			b.WriteString("_(메시지 파일을 읽을 수 없습니다)_\n")
+			continue
+		}
+		if m.File.RedactedAt != "" {
+			b.WriteString("_(사용자 요청으로 삭제된 메시지입니다)_\n")
+			continue
+		}
+		for _, p := range transcriptParts(m.File) {
+			switch p.Kind {
+			case PartTypeText:
+				b.WriteString(p.Text + "\n\n")
+			case PartTypeReasoning:
+				b.WriteString("<details>\n<summary>💭 추론</summary>\n\n")
+				b.WriteString(p.Text + "\n\n</details>\n\n")
+			case PartTypeTool:
+				fmt.Fprintf(&b, "<details>\n<summary>🔧 %s</summary>\n\n", toolSummary(p))
+				if p.Input != "" {
+					b.WriteString("**입력**\n\n" + fenced(p.Input, "json"))
+				}
+				if p.Output != "" {
+					b.WriteString("**출력**\n\n" + fenced(p.Output, ""))
+				}
+				if p.Error != "" {
+					b.WriteString("**오류**\n\n" + fenced(p.Error, ""))
+				}
+				b.WriteString("</details>\n\n")
+			}
+		}
+	}
+
+	if len(t.RunSteps) > 0 {
+		b.WriteString("\n---\n\n## 실행 단계\n\n| # | 종류 | 상태 | 시각 |\n| --- | --- | --- | --- |\n")
+		for _, step := range t.RunSteps {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s |\n", step.StepNo, step.Type, step.Status, formatTranscriptTime(step.CreatedAt))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// toolSummary는 도구 파트의 한 줄 요약(도구 이름, 제목, 상태, 소요 시간)입니다.
+func toolSummary(p transcriptPart) string {
+	s := p.Tool
+	if p.Title != "" {
+		s += " — " + p.Title
+	}
+	var extra []string
+	if p.Status != "" {
+		extra = append(extra, p.Status)
+	}
+	if p.Duration != "" {
+		extra = append(extra, p.Duration)
+	}
+	if len(extra) > 0 {
+		s += " (" + strings.Join(extra, ", ") + ")"
+	}
+	return s
+}
+
+// fenced는 내용에 포함된 백틱보다 긴 펜스로 코드 블록을 만듭니다.
+func fenced(content, lang string) string {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + strings.TrimRight(content, "\n") + "\n" + fence + "\n\n"
+}
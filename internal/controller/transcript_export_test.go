@@ -0,0 +1,98 @@
+package controller_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTaskTranscriptExport(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:transcript_export?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	store, err := storage.NewFileMessageStore(t.TempDir())
+	require.NoError(t, err)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 10), make(chan controller.ControllerEvent, 20),
+		controller.WithMessageStore(store))
+
+	ctx := context.Background()
+	taskID := "export-task"
+	require.NoError(t, ctrl.CreateAgent(ctx, "export-agent", "Export agent", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: taskID, AgentID: "export-agent", Status: storage.TaskStatusRunning, ImageDigest: "sha256:abc"}))
+	require.NoError(t, ctrl.AddMessage(ctx, taskID, storage.MessageRoleUser, "list <files>"))
+	require.NoError(t, ctrl.OnStarted(taskID, "ses_1"))
+
+	assistant := opencode.AssistantMessage{ID: "msg_a", SessionID: "ses_1", Role: "assistant", ProviderID: "openai", ModelID: "gpt-4"}
+	events := []*opencode.RunnerMessage{
+		{Type: opencode.MessageTypeMessageUpdated, SessionID: "ses_1", Message: assistant},
+		{Type: opencode.MessageTypeToolResult, SessionID: "ses_1", MessageID: "msg_a", PartID: "prt_t",
+			ToolResult: &opencode.ToolResultInfo{ToolID: "call_1", ToolName: "bash", Result: "```\nREADME.md"},
+			Part: &opencode.Part{ID: "prt_t", SessionID: "ses_1", MessageID: "msg_a", Type: "tool", CallID: "call_1", Tool: "bash",
+				State: &opencode.ToolState{Status: "completed", Input: map[string]any{"command": "ls"}, Output: "```\nREADME.md"}}},
+		{Type: opencode.MessageTypeText, SessionID: "ses_1", MessageID: "msg_a", PartID: "prt_1",
+			Part: &opencode.Part{ID: "prt_1", SessionID: "ses_1", MessageID: "msg_a", Type: "text", Text: "done"}},
+	}
+	// 같은 메시지의 사용량은 갱신될 때마다 누적되지 않고 최신 값으로 대체됨
+	for _, tokens := range []opencode.MessageTokens{{Input: 10, Output: 5}, {Input: 100, Output: 20}} {
+		updated := assistant
+		updated.Tokens = tokens
+		events = append(events, &opencode.RunnerMessage{Type: opencode.MessageTypeMessageUpdated, SessionID: "ses_1", Message: updated})
+	}
+	for _, msg := range events {
+		require.NoError(t, ctrl.OnEvent(taskID, msg))
+	}
+	require.NoError(t, repo.UpsertRunStep(ctx, &storage.RunStep{TaskID: taskID, StepNo: 1, Type: "run", Status: "completed"}))
+
+	transcript, err := ctrl.BuildTaskTranscript(ctx, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:abc", transcript.Revision)
+	assert.Equal(t, "gpt-4", transcript.Model)
+	require.Len(t, transcript.Messages, 2)
+	assert.Equal(t, "user", transcript.Messages[0].File.Role)
+	assert.Equal(t, "openai/gpt-4", transcript.Messages[1].File.Model)
+	assert.Equal(t, controller.TokenUsage{Input: 100, Output: 20}, transcript.Usage)
+	require.Len(t, transcript.RunSteps, 1)
+
+	var md strings.Builder
+	require.NoError(t, controller.WriteTranscript(&md, transcript, controller.TranscriptFormatMarkdown))
+	assert.Contains(t, md.String(), "| 리비전 | sha256:abc |")
+	assert.Contains(t, md.String(), "<summary>🔧 bash (completed)</summary>")
+	// 출력에 포함된 펜스보다 긴 펜스를 사용
+	assert.Contains(t, md.String(), "````\n```\nREADME.md\n````")
+
+	var html strings.Builder
+	require.NoError(t, controller.WriteTranscript(&html, transcript, controller.TranscriptFormatHTML))
+	assert.Contains(t, html.String(), "list &lt;files&gt;")
+	assert.Contains(t, html.String(), "<details><summary>🔧 bash (completed)</summary>")
+	assert.NotContains(t, html.String(), "<script")
+
+	var jsonl strings.Builder
+	require.NoError(t, controller.WriteTranscript(&jsonl, transcript, controller.TranscriptFormatJSONL))
+	var types []string
+	scanner := bufio.NewScanner(strings.NewReader(jsonl.String()))
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		types = append(types, line["type"].(string))
+	}
+	assert.Equal(t, []string{"task", "message", "message", "run_step"}, types)
+
+	assert.Error(t, controller.WriteTranscript(&md, transcript, "pdf"))
+	_, err = ctrl.BuildTaskTranscript(ctx, "missing-task")
+	assert.Error(t, err)
+}
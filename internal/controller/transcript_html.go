@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"html/template"
+	"io"
+)
+
+// transcriptHTMLView는 HTML 템플릿에 전달하는 값입니다.
+type transcriptHTMLView struct {
+	*TaskTranscript
+	Metadata [][2]string
+	Messages []transcriptHTMLMessage
+}
+
+type transcriptHTMLMessage struct {
+	Heading     string
+	Role        string
+	Unavailable bool
+	Redacted    bool
+	Parts       []transcriptPart
+}
+
+// writeTranscriptHTML은 외부 리소스 없이 열 수 있는 단일 HTML 문서를 씁니다.
+// 도구 호출과 추론은 <details>로 접혀 있습니다.
+func writeTranscriptHTML(w io.Writer, t *TaskTranscript) error {
+	view := transcriptHTMLView{TaskTranscript: t, Metadata: transcriptMetadata(t)}
+	for _, m := range t.Messages {
+		view.Messages = append(view.Messages, transcriptHTMLMessage{
+			Heading:     messageHeading(m),
+			Role:        m.File.Role,
+			Unavailable: m.Unavailable,
+			Redacted:    m.File.RedactedAt != "",
+			Parts:       transcriptParts(m.File),
+		})
+	}
+	return transcriptHTMLTemplate.Execute(w, view)
+}
+
+var transcriptHTMLTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{
+	"summary":    toolSummary,
+	"formatTime": formatTranscriptTime,
+}).Parse(`<!DOCTYPE html>
+<html lang="ko">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Task {{.TaskID}} 대화 기록</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "Noto Sans KR", sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; line-height: 1.6; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d7de; padding: .3rem .7rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.message { border: 1px solid #d0d7de; border-radius: 8px; margin: 1rem 0; padding: .5rem 1rem; }
+.message.user { background: #f6f8fa; }
+.message h2 { font-size: .95rem; color: #57606a; margin: .3rem 0 .6rem; }
+.text { white-space: pre-wrap; word-wrap: break-word; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin: .5rem 0; padding: .3rem .7rem; background: #fff; }
+details.error summary { color: #cf222e; }
+summary { cursor: pointer; font-family: ui-monospace, monospace; font-size: .9rem; }
+pre { background: #f6f8fa; padding: .6rem; overflow-x: auto; white-space: pre-wrap; word-wrap: break-word; font-size: .85rem; }
+.unavailable { color: #57606a; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>Task {{.TaskID}} 대화 기록</h1>
+<table>
+{{- range .Metadata}}
+<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{- end}}
+</table>
+{{range .Messages}}
+<section class="message {{.Role}}">
+<h2>{{.Heading}}</h2>
+{{- if .Unavailable}}
+<p class="unavailable">(메시지 파일을 읽을 수 없습니다)</p>
+{{- else if .Redacted}}
+<p class="unavailable">(사용자 요청으로 삭제된 메시지입니다)</p>
+{{- end}}
+{{- range .Parts}}
+{{- if eq .Kind "text"}}
+<div class="text">{{.Text}}</div>
+{{- else if eq .Kind "reasoning"}}
+<details><summary>💭 추론</summary><div class="text">{{.Text}}</div></details>
+{{- else if eq .Kind "tool"}}
+<details{{if .Error}} class="error"{{end}}><summary>🔧 {{summary .}}</summary>
+{{- if .Input}}<h4>입력</h4><pre>{{.Input}}</pre>{{end}}
+{{- if .Output}}<h4>출력</h4><pre>{{.Output}}</pre>{{end}}
+{{- if .Error}}<h4>오류</h4><pre>{{.Error}}</pre>{{end}}
+</details>
+{{- end}}
+{{- end}}
+</section>
+{{- end}}
+{{if .RunSteps}}
+<h2>실행 단계</h2>
+<table>
+<tr><th>#</th><th>종류</th><th>상태</th><th>시각</th></tr>
+{{- range .RunSteps}}
+<tr><td>{{.StepNo}}</td><td>{{.Type}}</td><td>{{.Status}}</td><td>{{formatTime .CreatedAt}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
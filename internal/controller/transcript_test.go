@@ -0,0 +1,139 @@
+package controller_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/controller"
+	taskrunner "github.com/cnap-oss/app/internal/runner"
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestControllerTranscript(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:transcript?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	store, err := storage.NewFileMessageStore(t.TempDir())
+	require.NoError(t, err)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 10), make(chan controller.ControllerEvent, 20),
+		controller.WithMessageStore(store))
+
+	ctx := context.Background()
+	taskID := "transcript-task"
+	require.NoError(t, ctrl.CreateAgent(ctx, "transcript-agent", "Transcript agent", "opencode", "gpt-4", "prompt"))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: taskID, AgentID: "transcript-agent", Status: storage.TaskStatusRunning}))
+	require.NoError(t, ctrl.OnStarted(taskID, "ses_1"))
+
+	end := int64(1700000001000)
+	events := []*opencode.RunnerMessage{
+		{Type: opencode.MessageTypeMessageUpdated, SessionID: "ses_1", Message: opencode.UserMessage{ID: "msg_u", SessionID: "ses_1", Role: "user"}},
+		{Type: opencode.MessageTypeText, SessionID: "ses_1", MessageID: "msg_u", PartID: "prt_u",
+			Part: &opencode.Part{ID: "prt_u", SessionID: "ses_1", MessageID: "msg_u", Type: "text", Text: "hello"}},
+		{Type: opencode.MessageTypeMessageUpdated, SessionID: "ses_1", Message: opencode.AssistantMessage{ID: "msg_a", SessionID: "ses_1", Role: "assistant"}},
+		{Type: opencode.MessageTypeReasoning, SessionID: "ses_1", MessageID: "msg_a", PartID: "prt_r",
+			Part: &opencode.Part{ID: "prt_r", SessionID: "ses_1", MessageID: "msg_a", Type: "reasoning", Text: "thinking"}},
+		{Type: opencode.MessageTypeToolResult, SessionID: "ses_1", MessageID: "msg_a", PartID: "prt_t",
+			ToolResult: &opencode.ToolResultInfo{ToolID: "call_1", ToolName: "bash", Result: "README.md"},
+			Part: &opencode.Part{ID: "prt_t", SessionID: "ses_1", MessageID: "msg_a", Type: "tool", CallID: "call_1", Tool: "bash",
+				State: &opencode.ToolState{Status: "completed", Input: map[string]any{"command": "ls"}, Output: "README.md",
+					Time: &opencode.ToolStateTime{Start: 1700000000000, End: &end}}}},
+		// 하위 세션의 파트는 기록하지 않음
+		{Type: opencode.MessageTypeMessageUpdated, SessionID: "ses_child", Message: opencode.AssistantMessage{ID: "msg_c", SessionID: "ses_child", Role: "assistant"}},
+		{Type: opencode.MessageTypeText, SessionID: "ses_child", MessageID: "msg_c", PartID: "prt_c",
+			Part: &opencode.Part{ID: "prt_c", SessionID: "ses_child", MessageID: "msg_c", Type: "text", Text: "child"}},
+		{Type: opencode.MessageTypeText, SessionID: "ses_1", MessageID: "msg_a", PartID: "prt_1",
+			Part: &opencode.Part{ID: "prt_1", SessionID: "ses_1", MessageID: "msg_a", Type: "text", Text: "done"}},
+	}
+	for _, msg := range events {
+		require.NoError(t, ctrl.OnEvent(taskID, msg))
+	}
+
+	// 파트가 완료될 때마다 턴의 메시지 파일이 기록됨
+	messages, err := repo.ListMessageIndexByTask(ctx, taskID)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, storage.MessageRoleAssistant, messages[0].Role)
+	assert.Equal(t, storage.MessageKey(taskID, 0), messages[0].FilePath)
+
+	file, err := ctrl.GetMessageFile(ctx, messages[0].FilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "done", file.Content)
+	require.Len(t, file.Parts, 3)
+
+	require.NoError(t, ctrl.OnComplete(taskID, &taskrunner.RunResult{Success: true, Output: "done"}))
+
+	messages, err = repo.ListMessageIndexByTask(ctx, taskID)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	file, err = ctrl.GetMessageFile(ctx, messages[0].FilePath)
+	require.NoError(t, err)
+	assert.Equal(t, controller.MessageFileVersion, file.Version)
+	assert.Equal(t, "assistant", file.Role)
+	assert.Equal(t, "ses_1", file.SessionID)
+	assert.Equal(t, []string{"msg_a"}, file.MessageIDs)
+	assert.Equal(t, "done", file.Text())
+
+	require.Len(t, file.Parts, 3)
+	assert.Equal(t, controller.PartTypeReasoning, file.Parts[0].Type)
+	assert.Equal(t, "thinking", file.Parts[0].Text)
+
+	tool := file.Parts[1]
+	assert.Equal(t, controller.PartTypeTool, tool.Type)
+	assert.Equal(t, "bash", tool.Tool)
+	assert.Equal(t, "call_1", tool.CallID)
+	assert.Equal(t, "completed", tool.Status)
+	assert.Equal(t, map[string]any{"command": "ls"}, tool.Input)
+	assert.Equal(t, "README.md", tool.Output)
+	require.NotNil(t, tool.StartedAt)
+	require.NotNil(t, tool.EndedAt)
+	assert.Equal(t, int64(1000), tool.EndedAt.Sub(*tool.StartedAt).Milliseconds())
+
+	assert.Equal(t, controller.PartTypeText, file.Parts[2].Type)
+	assert.Equal(t, "msg_a", file.Parts[2].MessageID)
+}
+
+func TestGetMessageFile_Legacy(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:legacy_message?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	root := t.TempDir()
+	store, err := storage.NewFileMessageStore(root)
+	require.NoError(t, err)
+	ctrl := controller.NewController(zaptest.NewLogger(t), repo, make(chan controller.ConnectorEvent, 1), make(chan controller.ControllerEvent, 1),
+		controller.WithMessageStore(store))
+	ctx := context.Background()
+
+	// 이전 버전은 MessageIndex.FilePath에 메시지 디렉토리 기준 절대 경로를 기록함
+	path := filepath.Join(root, "task-legacy", "0000.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(`{"role":"user","content":"hi","timestamp":"2025-01-01T00:00:00Z"}`), 0644))
+
+	file, err := ctrl.GetMessageFile(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, 0, file.Version)
+	assert.Equal(t, "user", file.Role)
+	assert.Equal(t, "hi", file.Text())
+	assert.Empty(t, file.Parts)
+
+	_, err = controller.ParseMessageFile([]byte(`{"role":"user","timestamp":"2025-01-01T00:00:00Z"}`))
+	assert.Error(t, err)
+
+	_, err = ctrl.GetMessageFile(ctx, "missing/0000.json")
+	assert.ErrorIs(t, err, storage.ErrMessageNotFound)
+}
@@ -19,10 +19,19 @@ type ConnectorEvent struct {
 	//   - "cancel": Task 취소
 	//   - "continue": 기존 Task에 메시지 추가 후 실행 계속 (멀티턴 대화)
 	//   - "complete": Task 명시적 완료
-	Type      string
+	//   - "permission": 도구 권한 요청 응답 (PermissionID, Response 사용)
+	Type string
+	// ID는 Connector가 부여하는 이벤트 ID입니다 (optional, 예: discord:message:{메시지 ID}).
+	// 같은 ID의 이벤트는 다시 전달되어도 한 번만 처리되며(처리에 실패하면 다시 처리), 비어 있으면 중복 검사를 하지 않습니다.
+	ID        string
 	TaskID    string
-	AgentName string
+	AgentName string // 에이전트 이름 또는 식별자 (AgentInfo.ID)
 	Prompt    string // 사용자 메시지 (optional)
+	Actor     string // 이벤트를 보낸 사용자 식별자 ({connector}:{id}, 예: discord:1234) (optional)
+	Tenant    string // 이벤트가 발생한 테넌트 (optional, 비어 있으면 기본 테넌트)
+
+	PermissionID string // 응답할 권한 요청 ID ("permission" 이벤트)
+	Response     string // once, always, reject ("permission" 이벤트)
 }
 
 // ControllerEventType은 이벤트의 종류를 구분합니다
@@ -50,6 +59,21 @@ const (
 	// EventTypeMessageComplete - 메시지 완료
 	EventTypeMessageComplete ControllerEventType = "message_complete"
 
+	// EventTypeTurnComplete - 턴 완료 (세션 idle, Content는 조립된 어시스턴트 메시지)
+	EventTypeTurnComplete ControllerEventType = "turn_complete"
+
+	// EventTypePermissionRequest - 도구 실행 권한 승인 요청
+	EventTypePermissionRequest ControllerEventType = "permission_request"
+
+	// EventTypePermissionResolved - 권한 요청 응답 완료 (사용자 응답 또는 시간 초과)
+	EventTypePermissionResolved ControllerEventType = "permission_resolved"
+
+	// EventTypeImagePull - Runner 이미지 pull 진행 (Content는 이미지, Delta는 레이어 상태)
+	EventTypeImagePull ControllerEventType = "image_pull"
+
+	// EventTypeStreamStatus - Runner 이벤트 스트림 연결 상태 변화 (Status: reconnecting, connected)
+	EventTypeStreamStatus ControllerEventType = "stream_status"
+
 	// EventTypeError - 일반 에러
 	EventTypeError ControllerEventType = "error"
 
@@ -77,7 +101,22 @@ type ToolEventInfo struct {
 	Error    string         `json:"error,omitempty"`
 }
 
+// PermissionEventInfo는 도구 권한 요청 이벤트 정보를 담습니다
+type PermissionEventInfo struct {
+	ID        string         `json:"id"`                  // OpenCode 권한 요청 ID
+	SessionID string         `json:"session_id"`          // 요청한 세션 (하위 세션일 수 있음)
+	Type      string         `json:"type"`                // 권한 종류 (bash, edit, webfetch 등)
+	Title     string         `json:"title"`               // 사용자에게 보여줄 설명
+	Patterns  []string       `json:"patterns,omitempty"`  // 대상 패턴
+	CallID    string         `json:"call_id,omitempty"`   // 관련 도구 호출 ID
+	Metadata  map[string]any `json:"metadata,omitempty"`  // 추가 정보 (명령어, 파일 경로 등)
+	ExpiresAt time.Time      `json:"expires_at"`          // 응답 대기 만료 시각
+	Response  string         `json:"response,omitempty"`  // 적용된 응답 (resolved 이벤트)
+	TimedOut  bool           `json:"timed_out,omitempty"` // 시간 초과로 정책 응답이 적용되었는지 여부
+}
+
 // ControllerEvent는 Controller에서 Connector로 전송되는 이벤트(결과)를 나타냅니다.
+// JSON으로는 직렬화 형식(WireEvent)으로 변환되며, Error는 EventError 객체가 됩니다.
 type ControllerEvent struct {
 	// 기존 필드 (하위 호환성 유지)
 	TaskID string `json:"task_id"`
@@ -87,19 +126,24 @@ type ControllerEvent struct {
 	//   - "completed": Task 완료
 	//   - "failed": Task 실패
 	//   - "canceled": Task 취소
-	Status  string `json:"status"`   // legacy 호환
+	Status  string `json:"status"` // legacy 호환
 	Content string `json:"content"`
-	Error   error  `json:"error,omitempty"`
+	Error   error  `json:"error,omitempty"` // 직렬화된 이벤트에서 읽으면 *EventError
 
 	// 새로 추가되는 필드
-	EventType ControllerEventType `json:"event_type"`
-	MessageID string              `json:"message_id,omitempty"`  // OpenCode 메시지 ID
-	PartID    string              `json:"part_id,omitempty"`     // OpenCode Part ID
-	PartType  PartType            `json:"part_type,omitempty"`   // text, tool, reasoning 등
-	Delta     string              `json:"delta,omitempty"`       // 부분 업데이트 텍스트
-	IsPartial bool                `json:"is_partial,omitempty"`  // 부분 업데이트 여부
-	Role      string              `json:"role,omitempty"`        // 메시지 role (user, assistant)
-	ToolInfo  *ToolEventInfo      `json:"tool_info,omitempty"`   // 도구 관련 정보
+	EventType  ControllerEventType  `json:"event_type"`
+	MessageID  string               `json:"message_id,omitempty"` // OpenCode 메시지 ID
+	PartID     string               `json:"part_id,omitempty"`    // OpenCode Part ID
+	PartType   PartType             `json:"part_type,omitempty"`  // text, tool, reasoning 등
+	Delta      string               `json:"delta,omitempty"`      // 부분 업데이트 텍스트
+	IsPartial  bool                 `json:"is_partial,omitempty"` // 부분 업데이트 여부
+	Role       string               `json:"role,omitempty"`       // 메시지 role (user, assistant)
+	ToolInfo   *ToolEventInfo       `json:"tool_info,omitempty"`  // 도구 관련 정보
+	LogTail    []string             `json:"log_tail,omitempty"`   // 실패 시 Runner Container 로그 마지막 N줄
+	Permission *PermissionEventInfo `json:"permission,omitempty"` // 도구 권한 요청 정보
+	Seq        int64                `json:"seq,omitempty"`        // Task 이벤트 로그 순번 (기록하지 못했으면 0)
+	ID         string               `json:"id"`                   // 이벤트 ID (Controller가 전송할 때 부여)
+	Timestamp  time.Time            `json:"timestamp"`            // 이벤트 발생 시각 (UTC)
 }
 
 // IsStreamingEvent는 스트리밍 중인 이벤트인지 확인합니다
@@ -115,30 +159,48 @@ func (e ControllerEvent) IsToolEvent() bool {
 		e.EventType == EventTypeToolError
 }
 
+// IsPermissionEvent는 도구 권한 관련 이벤트인지 확인합니다
+func (e ControllerEvent) IsPermissionEvent() bool {
+	return e.EventType == EventTypePermissionRequest ||
+		e.EventType == EventTypePermissionResolved
+}
+
 // IsTerminalEvent는 완료/에러 이벤트인지 확인합니다
 func (e ControllerEvent) IsTerminalEvent() bool {
 	return e.EventType == EventTypeMessageComplete ||
+		e.EventType == EventTypeTurnComplete ||
 		e.EventType == EventTypeError
 }
 
 // AgentInfo는 에이전트 정보를 나타냅니다.
 type AgentInfo struct {
-	Name        string
+	ID          string // 내부 식별자 (Task와 작업 공간이 참조, 기본 테넌트에서는 Name과 같음)
+	Name        string // 테넌트 안에서 유일한 이름
 	Description string
 	Provider    string
 	Model       string
 	Prompt      string
+	Image       string // 전용 Runner 이미지 (비어 있으면 기본 이미지)
 	Status      string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+
+	PermissionTimeout   time.Duration   // 권한 요청 응답 대기 시간
+	PermissionOnTimeout string          // 대기 시간 초과 시 적용할 응답 (once, reject)
+	ConfigSource        string          // 선언형 설정 파일 이름 (비어 있지 않으면 설정 반영 외에는 수정/삭제 불가)
+	Retention           RetentionPolicy // 에이전트별 보관 정책 (0이면 기본값, storage.RetentionKeepForever이면 삭제하지 않음)
+	Tenant              string          // 소유 테넌트
+	Owner               string          // 소유자 ({connector}:{id}, 비어 있으면 테넌트 공용)
+	Visibility          string          // 공개 범위 (private, guild, public)
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
 
 // TaskInfo는 작업 정보를 나타냅니다.
 type TaskInfo struct {
-	TaskID    string
-	AgentID   string
-	Prompt    string
-	Status    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	TaskID      string
+	AgentID     string
+	Prompt      string
+	Status      string
+	ImageDigest string // 고정된 Runner 이미지 참조
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventSchemaVersion은 ControllerEvent 직렬화 형식의 버전입니다.
+// 필드 추가는 같은 버전 안에서 하고, 기존 필드의 의미나 형식이 바뀔 때만 올립니다.
+// 형식 정의는 api/proto/cnap/v1/controller_event.proto와 같으며 JSON 필드 이름은 proto의 json_name을 따릅니다.
+const EventSchemaVersion = 1
+
+// 이벤트 오류 코드
+const (
+	ErrorCodeInternal         = "internal"
+	ErrorCodeNotFound         = "not_found"
+	ErrorCodePermissionDenied = "permission_denied"
+	ErrorCodeTimeout          = "timeout"
+	ErrorCodeCanceled         = "canceled"
+	ErrorCodeRunner           = "runner_error"    // Runner 실행 또는 시작 실패
+	ErrorCodeSession          = "session_error"   // OpenCode 세션 오류
+	ErrorCodeSessionAborted   = "session_aborted" // 사용자 요청이 아닌 세션 중단
+)
+
+// EventError는 이벤트에 담기는 오류입니다.
+// Go error 값은 JSON으로 직렬화되지 않으므로 코드, 메시지, 재시도 가능 여부로 프로세스 밖에 전달합니다.
+type EventError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"` // 같은 요청을 다시 보내면 성공할 수 있는지 여부
+
+	cause error
+}
+
+// NewEventError는 err를 원인으로 하는 이벤트 오류를 만듭니다. 메시지는 err의 메시지입니다.
+func NewEventError(code string, retryable bool, err error) *EventError {
+	return &EventError{Code: code, Message: err.Error(), Retryable: retryable, cause: err}
+}
+
+// Error는 error 인터페이스를 구현합니다.
+func (e *EventError) Error() string {
+	return e.Message
+}
+
+// Unwrap은 원인 오류를 반환합니다. 직렬화된 이벤트에서 읽은 오류는 원인이 없습니다.
+func (e *EventError) Unwrap() error {
+	return e.cause
+}
+
+// UnmarshalJSON은 오류 객체와 함께, 버전이 없는 이전 이벤트 로그에 문자열로 저장된 오류 메시지도 읽습니다.
+func (e *EventError) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*e = EventError{Code: ErrorCodeInternal, Message: message}
+		return nil
+	}
+	type plain EventError
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*e = EventError(p)
+	return nil
+}
+
+// AsEventError는 err를 이벤트 오류로 변환합니다. err가 nil이면 nil입니다.
+// err가 이벤트 오류를 감싸고 있으면 그 코드를 사용하고, 아니면 알려진 오류로 코드를 정합니다.
+func AsEventError(err error) *EventError {
+	if err == nil {
+		return nil
+	}
+	var ee *EventError
+	if errors.As(err, &ee) {
+		if ee == err {
+			return ee
+		}
+		return &EventError{Code: ee.Code, Message: err.Error(), Retryable: ee.Retryable, cause: err}
+	}
+	code, retryable := classifyError(err)
+	return NewEventError(code, retryable, err)
+}
+
+// classifyError는 이벤트 오류 코드가 없는 오류의 코드와 재시도 가능 여부를 정합니다.
+func classifyError(err error) (string, bool) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCodeTimeout, true
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCanceled, false
+	case errors.Is(err, ErrPermissionDenied):
+		return ErrorCodePermissionDenied, false
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrorCodeNotFound, false
+	default:
+		return ErrorCodeInternal, false
+	}
+}
+
+// WireEvent는 ControllerEvent의 직렬화 형식(EventSchemaVersion)입니다.
+// 외부 Connector, 로그, 이벤트 로그는 모두 이 형식으로 이벤트를 주고받습니다.
+type WireEvent struct {
+	Version    int                  `json:"version"`
+	ID         string               `json:"id"`
+	TaskID     string               `json:"task_id"`
+	Seq        int64                `json:"seq,omitempty"` // Task별로 증가하지만 연속적이지 않은 순번 (기록하지 못했으면 0)
+	Timestamp  time.Time            `json:"timestamp"`
+	EventType  string               `json:"event_type"`
+	Status     string               `json:"status,omitempty"`
+	Content    string               `json:"content,omitempty"`
+	Error      *EventError          `json:"error,omitempty"`
+	MessageID  string               `json:"message_id,omitempty"`
+	PartID     string               `json:"part_id,omitempty"`
+	PartType   string               `json:"part_type,omitempty"`
+	Delta      string               `json:"delta,omitempty"`
+	IsPartial  bool                 `json:"is_partial,omitempty"`
+	Role       string               `json:"role,omitempty"`
+	ToolInfo   *ToolEventInfo       `json:"tool_info,omitempty"`
+	LogTail    []string             `json:"log_tail,omitempty"`
+	Permission *PermissionEventInfo `json:"permission,omitempty"`
+}
+
+// ToWire는 이벤트를 직렬화 형식으로 변환합니다. 오류는 AsEventError로 변환합니다.
+func (e ControllerEvent) ToWire() WireEvent {
+	return WireEvent{
+		Version:    EventSchemaVersion,
+		ID:         e.ID,
+		TaskID:     e.TaskID,
+		Seq:        e.Seq,
+		Timestamp:  e.Timestamp,
+		EventType:  string(e.EventType),
+		Status:     e.Status,
+		Content:    e.Content,
+		Error:      AsEventError(e.Error),
+		MessageID:  e.MessageID,
+		PartID:     e.PartID,
+		PartType:   string(e.PartType),
+		Delta:      e.Delta,
+		IsPartial:  e.IsPartial,
+		Role:       e.Role,
+		ToolInfo:   e.ToolInfo,
+		LogTail:    e.LogTail,
+		Permission: e.Permission,
+	}
+}
+
+// FromWire는 직렬화 형식을 이벤트로 변환합니다. 오류는 *EventError입니다.
+// 버전이 없는(0) 이벤트는 버전이 도입되기 전의 이벤트 로그로 보고 읽으며, 지원하지 않는 버전이면 오류입니다.
+func FromWire(w WireEvent) (ControllerEvent, error) {
+	if w.Version < 0 || w.Version > EventSchemaVersion {
+		return ControllerEvent{}, fmt.Errorf("unsupported event schema version: %d", w.Version)
+	}
+	e := ControllerEvent{
+		ID:         w.ID,
+		TaskID:     w.TaskID,
+		Seq:        w.Seq,
+		Timestamp:  w.Timestamp,
+		EventType:  ControllerEventType(w.EventType),
+		Status:     w.Status,
+		Content:    w.Content,
+		MessageID:  w.MessageID,
+		PartID:     w.PartID,
+		PartType:   PartType(w.PartType),
+		Delta:      w.Delta,
+		IsPartial:  w.IsPartial,
+		Role:       w.Role,
+		ToolInfo:   w.ToolInfo,
+		LogTail:    w.LogTail,
+		Permission: w.Permission,
+	}
+	if w.Error != nil {
+		e.Error = w.Error
+	}
+	return e, nil
+}
+
+// MarshalJSON은 이벤트를 직렬화 형식(WireEvent)의 JSON으로 변환합니다.
+func (e ControllerEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.ToWire())
+}
+
+// UnmarshalJSON은 직렬화 형식(WireEvent)의 JSON을 읽습니다.
+func (e *ControllerEvent) UnmarshalJSON(data []byte) error {
+	var w WireEvent
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	event, err := FromWire(w)
+	if err != nil {
+		return err
+	}
+	*e = event
+	return nil
+}
+
+// newEventID는 이벤트 ID를 만듭니다.
+func newEventID() string {
+	var b [12]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("evt_%x", time.Now().UnixNano())
+	}
+	return "evt_" + hex.EncodeToString(b[:])
+}
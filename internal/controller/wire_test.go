@@ -0,0 +1,146 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWireEventGolden은 testdata/events의 v1 이벤트를 읽고 다시 직렬화해도 같은 JSON이 되는지 확인합니다.
+// 형식이 바뀌어 이 테스트가 실패하면 기존 Connector와 이벤트 로그가 깨지므로 EventSchemaVersion을 올려야 합니다.
+func TestWireEventGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "events", "v1_*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			var event controller.ControllerEvent
+			require.NoError(t, json.Unmarshal(data, &event))
+			assert.NotEmpty(t, event.ID)
+			assert.NotZero(t, event.Seq)
+			assert.False(t, event.Timestamp.IsZero())
+
+			encoded, err := json.Marshal(event)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(encoded))
+		})
+	}
+}
+
+// TestWireEventLegacy는 버전이 도입되기 전의 이벤트 로그(오류가 문자열)를 읽을 수 있는지 확인합니다.
+func TestWireEventLegacy(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "events", "legacy_failed.json"))
+	require.NoError(t, err)
+
+	var event controller.ControllerEvent
+	require.NoError(t, json.Unmarshal(data, &event))
+	assert.Equal(t, "1234567890", event.TaskID)
+	assert.Equal(t, "failed", event.Status)
+	assert.Equal(t, []string{"boom"}, event.LogTail)
+
+	var eventErr *controller.EventError
+	require.True(t, errors.As(event.Error, &eventErr))
+	assert.Equal(t, controller.ErrorCodeInternal, eventErr.Code)
+	assert.Equal(t, "runner crashed", eventErr.Message)
+	assert.False(t, eventErr.Retryable)
+
+	// 다시 직렬화하면 현재 버전이 됨
+	assert.Equal(t, controller.EventSchemaVersion, event.ToWire().Version)
+}
+
+func TestWireEventConversion(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := controller.ControllerEvent{
+		ID:        "evt_1",
+		TaskID:    "task-1",
+		Seq:       3,
+		Timestamp: ts,
+		EventType: controller.EventTypeError,
+		Status:    "error",
+		Error:     fmt.Errorf("run turn: %w", context.DeadlineExceeded),
+	}
+
+	w := event.ToWire()
+	assert.Equal(t, controller.EventSchemaVersion, w.Version)
+	require.NotNil(t, w.Error)
+	assert.Equal(t, controller.ErrorCodeTimeout, w.Error.Code)
+	assert.Equal(t, "run turn: context deadline exceeded", w.Error.Message)
+	assert.True(t, w.Error.Retryable)
+
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	var decoded controller.ControllerEvent
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "evt_1", decoded.ID)
+	assert.Equal(t, int64(3), decoded.Seq)
+	assert.True(t, ts.Equal(decoded.Timestamp))
+	assert.Equal(t, controller.EventTypeError, decoded.EventType)
+	assert.Equal(t, &controller.EventError{Code: controller.ErrorCodeTimeout, Message: "run turn: context deadline exceeded", Retryable: true}, decoded.Error)
+
+	// 오류 코드 분류와 감싼 이벤트 오류의 코드 유지
+	assert.Nil(t, controller.AsEventError(nil))
+	assert.Equal(t, controller.ErrorCodePermissionDenied, controller.AsEventError(fmt.Errorf("edit: %w", controller.ErrPermissionDenied)).Code)
+	assert.Equal(t, controller.ErrorCodeCanceled, controller.AsEventError(context.Canceled).Code)
+	assert.Equal(t, controller.ErrorCodeInternal, controller.AsEventError(errors.New("boom")).Code)
+	runnerErr := controller.NewEventError(controller.ErrorCodeRunner, true, errors.New("container exited"))
+	wrapped := controller.AsEventError(fmt.Errorf("start: %w", runnerErr))
+	assert.Equal(t, controller.ErrorCodeRunner, wrapped.Code)
+	assert.True(t, wrapped.Retryable)
+	assert.Equal(t, "start: container exited", wrapped.Message)
+
+	// 지원하지 않는 버전은 읽지 않음
+	err = json.Unmarshal([]byte(`{"version": 99, "task_id": "task-1"}`), &decoded)
+	assert.EqualError(t, err, "unsupported event schema version: 99")
+}
+
+// TestWireEventProtoFields는 proto 정의의 json_name과 Go 직렬화 형식의 JSON 필드가 같은지 확인합니다.
+func TestWireEventProtoFields(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "api", "proto", "cnap", "v1", "controller_event.proto"))
+	require.NoError(t, err)
+
+	messages := map[string][]string{}
+	messageRe := regexp.MustCompile(`(?s)message (\w+) \{(.*?)\n\}`)
+	fieldRe := regexp.MustCompile(`json_name = "(\w+)"`)
+	for _, m := range messageRe.FindAllStringSubmatch(string(data), -1) {
+		for _, f := range fieldRe.FindAllStringSubmatch(m[2], -1) {
+			messages[m[1]] = append(messages[m[1]], f[1])
+		}
+	}
+
+	for name, v := range map[string]any{
+		"ControllerEvent":     controller.WireEvent{},
+		"EventError":          controller.EventError{},
+		"ToolEventInfo":       controller.ToolEventInfo{},
+		"PermissionEventInfo": controller.PermissionEventInfo{},
+	} {
+		assert.ElementsMatch(t, jsonFields(reflect.TypeOf(v)), messages[name], name)
+	}
+}
+
+// jsonFields는 구조체의 JSON 필드 이름 목록을 반환합니다.
+func jsonFields(typ reflect.Type) []string {
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("json")
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
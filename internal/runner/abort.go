@@ -0,0 +1,68 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// abortWaitTimeout은 세션 중단 후 session.aborted/idle 이벤트를 기다리는 최대 시간입니다.
+const abortWaitTimeout = 15 * time.Second
+
+// AbortResult는 실행 중단 결과입니다.
+type AbortResult struct {
+	// PartialOutput은 중단 시점까지 생성된 어시스턴트 텍스트입니다.
+	PartialOutput string
+	// Confirmed는 OpenCode가 세션 중단(또는 idle)을 이벤트로 확인했는지 여부입니다.
+	Confirmed bool
+}
+
+// IsAborting은 현재 턴이 Abort로 중단되었는지 확인합니다.
+// 중단으로 인해 발생하는 session.aborted 이벤트를 에러로 보고하지 않기 위해 사용됩니다.
+func (r *Runner) IsAborting() bool {
+	return r.aborted.Load()
+}
+
+// Abort는 진행 중인 OpenCode 세션 생성을 중단하고 session.aborted 또는 idle 이벤트를 기다립니다.
+// Container와 세션은 유지되므로 Runner는 다음 메시지에 그대로 사용할 수 있습니다.
+func (r *Runner) Abort(ctx context.Context) (*AbortResult, error) {
+	if r.apiClient == nil || r.sessionID == "" {
+		return nil, fmt.Errorf("runner가 초기화되지 않음")
+	}
+
+	running := r.inTurn.Load()
+	r.logger.Info("Runner 실행 중단",
+		zap.String("runner_id", r.ID),
+		zap.String("session_id", r.sessionID),
+		zap.Bool("in_turn", running),
+	)
+
+	r.aborted.Store(true)
+	turnDone := r.currentTurnDone()
+
+	if err := r.apiClient.AbortSession(ctx, r.sessionID); err != nil {
+		return nil, fmt.Errorf("세션 중단 실패: %w", err)
+	}
+
+	result := &AbortResult{Confirmed: !running}
+	if running && turnDone != nil {
+		waitCtx, cancel := context.WithTimeout(ctx, abortWaitTimeout)
+		defer cancel()
+
+		// 턴은 session.aborted/idle 이벤트를 받으면 종료됨
+		select {
+		case <-turnDone:
+			result.Confirmed = true
+		case <-waitCtx.Done():
+			r.logger.Warn("세션 중단 확인 대기 시간 초과",
+				zap.String("runner_id", r.ID),
+				zap.String("session_id", r.sessionID),
+			)
+		}
+	}
+
+	result.PartialOutput = r.PartialOutput()
+	return result, nil
+}
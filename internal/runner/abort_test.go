@@ -0,0 +1,85 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestRunner_Abort는 실행 중인 턴을 중단하면 idle 이벤트를 기다리고 부분 출력을 반환하는지 확인합니다.
+func TestRunner_Abort(t *testing.T) {
+	events := make(chan string, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/event":
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "data: {\"type\":\"server.connected\",\"properties\":{}}\n\n")
+			w.(http.Flusher).Flush()
+			for {
+				select {
+				case data := <-events:
+					_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
+					w.(http.Flusher).Flush()
+				case <-r.Context().Done():
+					return
+				}
+			}
+		case "/session/ses_1/prompt_async":
+			// 어시스턴트가 응답을 생성하는 도중 (idle 없이 진행 중)
+			events <- `{"type":"message.updated","properties":{"info":{"id":"msg_a","sessionID":"ses_1","role":"assistant"}}}`
+			events <- `{"type":"message.part.updated","properties":{"part":{"id":"prt_1","sessionID":"ses_1","messageID":"msg_a","type":"text","text":"partial answer"}}}`
+			w.WriteHeader(http.StatusNoContent)
+		case "/session/ses_1/abort":
+			events <- `{"type":"session.aborted","properties":{"sessionID":"ses_1"}}`
+			events <- `{"type":"session.idle","properties":{"sessionID":"ses_1"}}`
+			_, _ = fmt.Fprint(w, "true")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	callback := NewMockStatusCallback()
+	runner, err := NewRunner("task-abort", AgentInfo{AgentID: "test-agent"}, callback, zaptest.NewLogger(t),
+		WithDockerClient(&fakeLogClient{}),
+		WithWorkspacePath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	runner.apiClient = opencode.NewClient(server.URL)
+	runner.sessionID = "ses_1"
+	runner.sessions.add("ses_1")
+	runner.Status = RunnerStatusReady
+
+	runner.startEventStream()
+	require.NoError(t, runner.waitForEventStream(context.Background(), 5*time.Second))
+	defer runner.eventCancel()
+
+	require.NoError(t, runner.Run(context.Background(), &RunRequest{
+		TaskID:   "task-abort",
+		Model:    "test/model",
+		Messages: []opencode.ChatMessage{{Role: "user", Content: "long task"}},
+	}))
+	require.Eventually(t, func() bool { return runner.PartialOutput() != "" }, 5*time.Second, 10*time.Millisecond)
+
+	result, err := runner.Abort(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.Confirmed)
+	assert.Equal(t, "partial answer", result.PartialOutput)
+	assert.True(t, runner.IsAborting())
+
+	// 중단된 턴은 에러나 완료로 보고되지 않고 턴이 종료됨
+	require.Eventually(t, func() bool { return !runner.inTurn.Load() }, 5*time.Second, 10*time.Millisecond)
+	callback.mu.Lock()
+	defer callback.mu.Unlock()
+	assert.False(t, callback.ErrorCalled)
+	assert.False(t, callback.CompletedCalled)
+}
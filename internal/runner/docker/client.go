@@ -2,9 +2,14 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"io"
+	"strings"
 )
 
+// ErrImageNotFound는 로컬에 이미지가 없을 때 반환됩니다.
+var ErrImageNotFound = errors.New("이미지를 찾을 수 없음")
+
 // DockerClient는 Docker Container 관리를 위한 인터페이스입니다.
 // 테스트 시 mock 구현을 주입할 수 있도록 인터페이스로 정의합니다.
 type DockerClient interface {
@@ -24,9 +29,28 @@ type DockerClient interface {
 	// ContainerLogs는 Container의 로그를 반환합니다.
 	ContainerLogs(ctx context.Context, containerID string) (io.ReadCloser, error)
 
+	// FollowContainerLogs는 Container의 stdout/stderr를 Container가 종료되거나 ctx가 취소될 때까지 스트리밍합니다.
+	// 반환되는 스트림은 Docker 멀티플렉싱 헤더가 제거된 평문이며, 각 줄 앞에 타임스탬프가 붙습니다.
+	FollowContainerLogs(ctx context.Context, containerID string) (io.ReadCloser, error)
+
 	// ContainerInspect는 Container의 상세 정보를 반환합니다.
 	ContainerInspect(ctx context.Context, containerID string) (ContainerInfo, error)
 
+	// PullImage는 레지스트리에서 이미지를 가져옵니다.
+	// progress가 nil이 아니면 레이어별 진행 상황을 전달합니다.
+	PullImage(ctx context.Context, image string, progress PullProgressFunc) error
+
+	// ImageInspect는 로컬 이미지의 상세 정보를 반환합니다.
+	// 이미지가 없으면 ErrImageNotFound를 감싼 에러를 반환합니다.
+	ImageInspect(ctx context.Context, image string) (ImageInfo, error)
+
+	// ListImages는 reference 필터와 일치하는 로컬 이미지 목록을 반환합니다.
+	// reference가 비어 있으면 모든 이미지를 반환합니다.
+	ListImages(ctx context.Context, reference string) ([]ImageInfo, error)
+
+	// RemoveImage는 로컬 이미지를 삭제합니다.
+	RemoveImage(ctx context.Context, imageID string, force bool) error
+
 	// Ping은 Docker daemon과의 연결을 확인합니다.
 	Ping(ctx context.Context) error
 
@@ -71,3 +95,55 @@ type ContainerInfo struct {
 	ExitCode   int               // 종료 코드
 	Error      string            // 에러 메시지 (있는 경우)
 }
+
+// ImageInfo는 로컬 이미지의 상세 정보입니다.
+type ImageInfo struct {
+	ID          string   // 이미지 ID (sha256:...)
+	RepoTags    []string // 태그 목록 (repo:tag)
+	RepoDigests []string // 다이제스트 목록 (repo@sha256:...)
+	Size        int64    // 이미지 크기 (bytes)
+	Created     int64    // 생성 시각 (unix seconds)
+}
+
+// DigestReference는 repository와 일치하는 다이제스트 참조(repo@sha256:...)를 반환합니다.
+// 같은 이미지가 다른 repository(미러 등)의 다이제스트만 가진 경우나, 로컬에서 빌드되어
+// 다이제스트가 없는 이미지는 빈 문자열을 반환합니다.
+func (i ImageInfo) DigestReference(repository string) string {
+	want := normalizeRepository(repository)
+	for _, d := range i.RepoDigests {
+		if normalizeRepository(RepositoryOf(d)) == want {
+			return d
+		}
+	}
+	return ""
+}
+
+// normalizeRepository는 Docker Hub 기본 레지스트리와 library 접두사를 제거해
+// "ubuntu"와 "docker.io/library/ubuntu"를 같은 repository로 비교할 수 있게 합니다.
+func normalizeRepository(repository string) string {
+	repository = strings.TrimPrefix(repository, "docker.io/")
+	return strings.TrimPrefix(repository, "library/")
+}
+
+// PullProgress는 이미지 pull 진행 상황입니다.
+type PullProgress struct {
+	ID      string // 레이어 ID
+	Status  string // 상태 메시지 (Downloading, Extracting, ...)
+	Current int64  // 현재 진행량 (bytes)
+	Total   int64  // 전체 크기 (bytes)
+}
+
+// PullProgressFunc는 이미지 pull 진행 상황을 전달받는 콜백입니다.
+type PullProgressFunc func(PullProgress)
+
+// RepositoryOf는 이미지 참조에서 태그와 다이제스트를 제외한 repository를 반환합니다.
+func RepositoryOf(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	// 레지스트리 포트(host:5000/repo)와 태그(repo:tag)를 구분
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
@@ -2,12 +2,18 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 )
 
@@ -146,6 +152,31 @@ func (d *RealClient) ContainerLogs(ctx context.Context, containerID string) (io.
 	return logs, nil
 }
 
+// FollowContainerLogs implements Client.
+func (d *RealClient) FollowContainerLogs(ctx context.Context, containerID string) (io.ReadCloser, error) {
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Timestamps: true,
+	}
+
+	logs, err := d.client.ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		return nil, fmt.Errorf("container 로그 스트림 시작 실패: %w", err)
+	}
+
+	// TTY 없이 생성된 Container의 로그는 stdout/stderr가 멀티플렉싱되어 있으므로 평문으로 변환
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, logs)
+		_ = logs.Close()
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
 // ContainerInspect implements Client.
 func (d *RealClient) ContainerInspect(ctx context.Context, containerID string) (ContainerInfo, error) {
 	inspect, err := d.client.ContainerInspect(ctx, containerID)
@@ -197,6 +228,106 @@ func (d *RealClient) ContainerInspect(ctx context.Context, containerID string) (
 	return info, nil
 }
 
+// pullMessage는 이미지 pull 응답 스트림의 JSON 메시지입니다.
+type pullMessage struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	Error string `json:"error"`
+}
+
+// PullImage implements Client.
+func (d *RealClient) PullImage(ctx context.Context, ref string, progress PullProgressFunc) error {
+	reader, err := d.client.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("이미지 pull 실패: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	// pull은 스트림을 끝까지 읽어야 완료됩니다.
+	decoder := json.NewDecoder(reader)
+	for {
+		var msg pullMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("이미지 pull 응답 읽기 실패: %w", err)
+		}
+		if msg.Error != "" {
+			return fmt.Errorf("이미지 pull 실패: %s", msg.Error)
+		}
+		if progress != nil {
+			progress(PullProgress{
+				ID:      msg.ID,
+				Status:  msg.Status,
+				Current: msg.ProgressDetail.Current,
+				Total:   msg.ProgressDetail.Total,
+			})
+		}
+	}
+}
+
+// ImageInspect implements Client.
+func (d *RealClient) ImageInspect(ctx context.Context, ref string) (ImageInfo, error) {
+	inspect, _, err := d.client.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return ImageInfo{}, fmt.Errorf("%w: %s", ErrImageNotFound, ref)
+		}
+		return ImageInfo{}, fmt.Errorf("이미지 조회 실패: %w", err)
+	}
+
+	info := ImageInfo{
+		ID:          inspect.ID,
+		RepoTags:    inspect.RepoTags,
+		RepoDigests: inspect.RepoDigests,
+		Size:        inspect.Size,
+	}
+	return info, nil
+}
+
+// ListImages implements Client.
+func (d *RealClient) ListImages(ctx context.Context, reference string) ([]ImageInfo, error) {
+	options := image.ListOptions{}
+	if reference != "" {
+		options.Filters = filters.NewArgs(filters.Arg("reference", reference))
+	}
+
+	summaries, err := d.client.ImageList(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("이미지 목록 조회 실패: %w", err)
+	}
+
+	images := make([]ImageInfo, 0, len(summaries))
+	for _, s := range summaries {
+		images = append(images, ImageInfo{
+			ID:          s.ID,
+			RepoTags:    s.RepoTags,
+			RepoDigests: s.RepoDigests,
+			Size:        s.Size,
+			Created:     s.Created,
+		})
+	}
+	return images, nil
+}
+
+// RemoveImage implements Client.
+func (d *RealClient) RemoveImage(ctx context.Context, imageID string, force bool) error {
+	options := image.RemoveOptions{
+		Force:         force,
+		PruneChildren: true,
+	}
+
+	if _, err := d.client.ImageRemove(ctx, imageID, options); err != nil {
+		return fmt.Errorf("이미지 삭제 실패: %w", err)
+	}
+	return nil
+}
+
 // Ping implements Client.
 func (d *RealClient) Ping(ctx context.Context) error {
 	_, err := d.client.Ping(ctx)
@@ -14,7 +14,12 @@ type MockDockerClient struct {
 	StopContainerFunc    func(ctx context.Context, containerID string, timeout int) error
 	RemoveContainerFunc  func(ctx context.Context, containerID string) error
 	ContainerLogsFunc    func(ctx context.Context, containerID string) (io.ReadCloser, error)
+	FollowLogsFunc       func(ctx context.Context, containerID string) (io.ReadCloser, error)
 	ContainerInspectFunc func(ctx context.Context, containerID string) (ContainerInfo, error)
+	PullImageFunc        func(ctx context.Context, image string, progress PullProgressFunc) error
+	ImageInspectFunc     func(ctx context.Context, image string) (ImageInfo, error)
+	ListImagesFunc       func(ctx context.Context, reference string) ([]ImageInfo, error)
+	RemoveImageFunc      func(ctx context.Context, imageID string, force bool) error
 	PingFunc             func(ctx context.Context) error
 	CloseFunc            func() error
 }
@@ -54,6 +59,13 @@ func (m *MockDockerClient) ContainerLogs(ctx context.Context, containerID string
 	return io.NopCloser(strings.NewReader("mock logs")), nil
 }
 
+func (m *MockDockerClient) FollowContainerLogs(ctx context.Context, containerID string) (io.ReadCloser, error) {
+	if m.FollowLogsFunc != nil {
+		return m.FollowLogsFunc(ctx, containerID)
+	}
+	return io.NopCloser(strings.NewReader("mock logs\n")), nil
+}
+
 func (m *MockDockerClient) ContainerInspect(ctx context.Context, containerID string) (ContainerInfo, error) {
 	if m.ContainerInspectFunc != nil {
 		return m.ContainerInspectFunc(ctx, containerID)
@@ -68,6 +80,38 @@ func (m *MockDockerClient) ContainerInspect(ctx context.Context, containerID str
 	}, nil
 }
 
+func (m *MockDockerClient) PullImage(ctx context.Context, image string, progress PullProgressFunc) error {
+	if m.PullImageFunc != nil {
+		return m.PullImageFunc(ctx, image, progress)
+	}
+	return nil
+}
+
+func (m *MockDockerClient) ImageInspect(ctx context.Context, image string) (ImageInfo, error) {
+	if m.ImageInspectFunc != nil {
+		return m.ImageInspectFunc(ctx, image)
+	}
+	return ImageInfo{
+		ID:          "sha256:mock",
+		RepoTags:    []string{image},
+		RepoDigests: []string{RepositoryOf(image) + "@sha256:mock"},
+	}, nil
+}
+
+func (m *MockDockerClient) ListImages(ctx context.Context, reference string) ([]ImageInfo, error) {
+	if m.ListImagesFunc != nil {
+		return m.ListImagesFunc(ctx, reference)
+	}
+	return nil, nil
+}
+
+func (m *MockDockerClient) RemoveImage(ctx context.Context, imageID string, force bool) error {
+	if m.RemoveImageFunc != nil {
+		return m.RemoveImageFunc(ctx, imageID, force)
+	}
+	return nil
+}
+
 func (m *MockDockerClient) Ping(ctx context.Context) error {
 	if m.PingFunc != nil {
 		return m.PingFunc(ctx)
@@ -268,6 +312,46 @@ func TestGetContainerPort(t *testing.T) {
 	}
 }
 
+func TestRepositoryOf(t *testing.T) {
+	tests := map[string]string{
+		"cnap-runner:latest":                         "cnap-runner",
+		"ghcr.io/cnap-oss/cnap-runner:latest":        "ghcr.io/cnap-oss/cnap-runner",
+		"localhost:5000/runner:v1":                   "localhost:5000/runner",
+		"localhost:5000/runner":                      "localhost:5000/runner",
+		"ghcr.io/cnap-oss/cnap-runner@sha256:abc":    "ghcr.io/cnap-oss/cnap-runner",
+		"ghcr.io/cnap-oss/cnap-runner:v1@sha256:abc": "ghcr.io/cnap-oss/cnap-runner",
+	}
+
+	for input, expected := range tests {
+		if got := RepositoryOf(input); got != expected {
+			t.Errorf("RepositoryOf(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestImageInfo_DigestReference(t *testing.T) {
+	info := ImageInfo{
+		RepoDigests: []string{
+			"mirror.local/runner@sha256:111",
+			"ghcr.io/cnap-oss/cnap-runner@sha256:222",
+		},
+	}
+
+	if got := info.DigestReference("ghcr.io/cnap-oss/cnap-runner"); got != "ghcr.io/cnap-oss/cnap-runner@sha256:222" {
+		t.Errorf("Expected matching repository digest, got '%s'", got)
+	}
+	if got := info.DigestReference("other"); got != "" {
+		t.Errorf("Expected no digest for other repository, got '%s'", got)
+	}
+	hub := ImageInfo{RepoDigests: []string{"ubuntu@sha256:333"}}
+	if got := hub.DigestReference("docker.io/library/ubuntu"); got != "ubuntu@sha256:333" {
+		t.Errorf("Expected Docker Hub digest to match normalized repository, got '%s'", got)
+	}
+	if got := (ImageInfo{}).DigestReference("any"); got != "" {
+		t.Errorf("Expected empty digest for local image, got '%s'", got)
+	}
+}
+
 func TestContainerConfig(t *testing.T) {
 	config := ContainerConfig{
 		Image: "cnap-runner:latest",
@@ -18,6 +18,7 @@ var (
 	ErrRunnerNotReady      = errors.New("runner가 준비되지 않음")
 	ErrRunnerAlreadyExists = errors.New("runner가 이미 존재함")
 	ErrRunnerNotFound      = errors.New("runner를 찾을 수 없음")
+	ErrTurnInProgress      = errors.New("이미 진행 중인 턴이 있음")
 
 	// 리소스 관련 에러
 	ErrMaxContainersReached  = errors.New("최대 container 수 초과")
@@ -0,0 +1,108 @@
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cnap-oss/app/internal/runner/docker"
+	"go.uber.org/zap"
+)
+
+// DefaultRunnerImage는 에이전트에 이미지가 지정되지 않았을 때 사용할 기본 Runner 이미지를 반환합니다.
+// CNAP_RUNNER_IMAGE가 설정되어 있으면 우선 사용하고, 없으면 CNAP_ENV에 따라 분기합니다.
+func DefaultRunnerImage() string {
+	if image := os.Getenv("CNAP_RUNNER_IMAGE"); image != "" {
+		return image
+	}
+
+	// 환경별 기본 이미지 설정 (기본값: production)
+	if os.Getenv("CNAP_ENV") == "development" {
+		return "cnap-runner:latest"
+	}
+	// production 또는 CNAP_ENV 미설정 시 ghcr.io 이미지 사용
+	return "ghcr.io/cnap-oss/cnap-runner:latest"
+}
+
+// resolveImage는 Container 생성에 사용할 이미지 참조를 결정합니다.
+//
+// 작업에 이미 고정된 다이제스트(AgentInfo.ImageDigest)가 있으면 그대로 사용하고,
+// 없으면 에이전트 이미지 또는 기본 이미지를 확인(필요 시 pull)한 뒤 다이제스트로 고정합니다.
+// 레지스트리 다이제스트가 없는 로컬 빌드 이미지는 이미지 ID로 고정합니다.
+func (r *Runner) resolveImage(ctx context.Context) (string, error) {
+	if r.agentInfo.ImageDigest != "" {
+		if _, err := r.ensureImage(ctx, r.agentInfo.ImageDigest); err != nil {
+			return "", err
+		}
+		return r.agentInfo.ImageDigest, nil
+	}
+
+	image := r.agentInfo.Image
+	if image == "" {
+		image = DefaultRunnerImage()
+	}
+
+	info, err := r.ensureImage(ctx, image)
+	if err != nil {
+		return "", err
+	}
+
+	if digest := info.DigestReference(docker.RepositoryOf(image)); digest != "" {
+		return digest, nil
+	}
+	return info.ID, nil
+}
+
+// ensureImage는 이미지가 로컬에 없으면 pull한 뒤 이미지 정보를 반환합니다.
+func (r *Runner) ensureImage(ctx context.Context, image string) (docker.ImageInfo, error) {
+	info, err := r.dockerClient.ImageInspect(ctx, image)
+	if err == nil {
+		return info, nil
+	}
+	if !errors.Is(err, docker.ErrImageNotFound) {
+		return docker.ImageInfo{}, err
+	}
+
+	r.logger.Info("Runner 이미지가 없어 pull을 시작합니다",
+		zap.String("runner_id", r.ID),
+		zap.String("image", image),
+	)
+
+	if err := r.dockerClient.PullImage(ctx, image, r.reportPullProgress(image)); err != nil {
+		return docker.ImageInfo{}, fmt.Errorf("이미지 pull 실패 (%s): %w", image, err)
+	}
+
+	r.logger.Info("Runner 이미지 pull 완료",
+		zap.String("runner_id", r.ID),
+		zap.String("image", image),
+	)
+
+	return r.dockerClient.ImageInspect(ctx, image)
+}
+
+// reportPullProgress는 pull 진행 상황을 로그와 등록된 콜백으로 전달하는 함수를 반환합니다.
+func (r *Runner) reportPullProgress(image string) docker.PullProgressFunc {
+	return func(p docker.PullProgress) {
+		if r.pullProgress != nil {
+			r.pullProgress(image, p)
+		}
+
+		// 레이어 단위 완료 메시지만 Info로 남기고 바이트 단위 진행은 Debug로 기록
+		if p.Total > 0 {
+			r.logger.Debug("이미지 pull 진행 중",
+				zap.String("image", image),
+				zap.String("layer", p.ID),
+				zap.String("status", p.Status),
+				zap.Int64("current", p.Current),
+				zap.Int64("total", p.Total),
+			)
+			return
+		}
+		r.logger.Info("이미지 pull 상태",
+			zap.String("image", image),
+			zap.String("layer", p.ID),
+			zap.String("status", p.Status),
+		)
+	}
+}
@@ -0,0 +1,123 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/runner/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeImageClient는 이미지 관련 메서드만 구현한 테스트용 DockerClient입니다.
+type fakeImageClient struct {
+	docker.DockerClient
+	images map[string]docker.ImageInfo
+	pulled []string
+}
+
+func (f *fakeImageClient) ImageInspect(ctx context.Context, image string) (docker.ImageInfo, error) {
+	info, ok := f.images[image]
+	if !ok {
+		return docker.ImageInfo{}, fmt.Errorf("%w: %s", docker.ErrImageNotFound, image)
+	}
+	return info, nil
+}
+
+func (f *fakeImageClient) PullImage(ctx context.Context, image string, progress docker.PullProgressFunc) error {
+	f.pulled = append(f.pulled, image)
+	if progress != nil {
+		progress(docker.PullProgress{ID: "layer1", Status: "Downloading", Current: 50, Total: 100})
+		progress(docker.PullProgress{ID: "layer1", Status: "Pull complete"})
+	}
+	f.images[image] = docker.ImageInfo{
+		ID:          "sha256:pulled",
+		RepoDigests: []string{docker.RepositoryOf(image) + "@sha256:pulled"},
+	}
+	return nil
+}
+
+func newImageTestRunner(t *testing.T, client docker.DockerClient, info AgentInfo, opts ...RunnerOption) *Runner {
+	t.Helper()
+	opts = append([]RunnerOption{WithDockerClient(client)}, opts...)
+	r, err := NewRunner("task-image", info, NewMockStatusCallback(), zaptest.NewLogger(t), opts...)
+	require.NoError(t, err)
+	return r
+}
+
+func TestResolveImage_PullsMissingImageAndPinsDigest(t *testing.T) {
+	client := &fakeImageClient{images: map[string]docker.ImageInfo{}}
+
+	var progress []docker.PullProgress
+	r := newImageTestRunner(t, client,
+		AgentInfo{AgentID: "agent", Image: "ghcr.io/example/go-runner:1.24"},
+		WithPullProgress(func(image string, p docker.PullProgress) {
+			progress = append(progress, p)
+		}),
+	)
+
+	ref, err := r.resolveImage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/example/go-runner@sha256:pulled", ref)
+	assert.Equal(t, []string{"ghcr.io/example/go-runner:1.24"}, client.pulled)
+	assert.Len(t, progress, 2)
+}
+
+func TestResolveImage_UsesPinnedDigest(t *testing.T) {
+	pinned := "ghcr.io/example/go-runner@sha256:old"
+	client := &fakeImageClient{images: map[string]docker.ImageInfo{
+		pinned:                           {ID: "sha256:old"},
+		"ghcr.io/example/go-runner:1.24": {ID: "sha256:new", RepoDigests: []string{"ghcr.io/example/go-runner@sha256:new"}},
+	}}
+
+	r := newImageTestRunner(t, client, AgentInfo{
+		AgentID:     "agent",
+		Image:       "ghcr.io/example/go-runner:1.24",
+		ImageDigest: pinned,
+	})
+
+	ref, err := r.resolveImage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, pinned, ref)
+	assert.Empty(t, client.pulled)
+}
+
+func TestResolveImage_LocalImageFallsBackToID(t *testing.T) {
+	t.Setenv("CNAP_RUNNER_IMAGE", "cnap-runner:dev")
+	client := &fakeImageClient{images: map[string]docker.ImageInfo{
+		"cnap-runner:dev": {ID: "sha256:localbuild"},
+	}}
+
+	r := newImageTestRunner(t, client, AgentInfo{AgentID: "agent"})
+
+	ref, err := r.resolveImage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:localbuild", ref)
+}
+
+func TestResolveImage_OtherRepositoryDigestFallsBackToID(t *testing.T) {
+	// 미러에서 받은 이미지에 다시 태그를 붙인 경우 다른 repository의 다이제스트로 고정하지 않음
+	client := &fakeImageClient{images: map[string]docker.ImageInfo{
+		"ghcr.io/example/go-runner:1.24": {ID: "sha256:mirrored", RepoDigests: []string{"mirror.local/go-runner@sha256:111"}},
+	}}
+
+	r := newImageTestRunner(t, client, AgentInfo{AgentID: "agent", Image: "ghcr.io/example/go-runner:1.24"})
+
+	ref, err := r.resolveImage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:mirrored", ref)
+}
+
+func TestDefaultRunnerImage(t *testing.T) {
+	t.Setenv("CNAP_RUNNER_IMAGE", "")
+	t.Setenv("CNAP_ENV", "development")
+	assert.Equal(t, "cnap-runner:latest", DefaultRunnerImage())
+
+	t.Setenv("CNAP_ENV", "")
+	assert.Equal(t, "ghcr.io/cnap-oss/cnap-runner:latest", DefaultRunnerImage())
+
+	t.Setenv("CNAP_RUNNER_IMAGE", "custom:1")
+	assert.Equal(t, "custom:1", DefaultRunnerImage())
+}
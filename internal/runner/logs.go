@@ -0,0 +1,277 @@
+package taskrunner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cnap-oss/app/internal/common"
+	"go.uber.org/zap"
+)
+
+// 컨테이너 로그 파일 이름
+const containerLogFile = "container.log"
+
+// LogConfig는 Container 로그 저장 설정입니다.
+type LogConfig struct {
+	MaxSizeMB  int // 로그 파일 최대 크기 (MB, 기본: 10)
+	MaxBackups int // 보관할 회전 파일 수 (기본: 3)
+	TailLines  int // 실패 이벤트에 첨부할 로그 줄 수 (기본: 50)
+}
+
+// DefaultLogConfig는 환경 변수에서 로그 설정을 읽어 반환합니다.
+func DefaultLogConfig() LogConfig {
+	return LogConfig{
+		MaxSizeMB:  getEnvOrDefaultInt("CNAP_RUNNER_LOG_MAX_SIZE_MB", 10),
+		MaxBackups: getEnvOrDefaultInt("CNAP_RUNNER_LOG_MAX_BACKUPS", 3),
+		TailLines:  getEnvOrDefaultInt("CNAP_RUNNER_LOG_TAIL_LINES", 50),
+	}
+}
+
+// AgentWorkspacePath는 Agent 작업 공간의 절대 경로를 반환합니다.
+// WorkspaceManager와 같은 기본 디렉토리(common.GetWorkspaceDir) 아래 agentID 디렉토리를 사용합니다.
+func AgentWorkspacePath(agentID string) (string, error) {
+	workspaceBaseDir := common.GetWorkspaceDir()
+
+	// 상대 경로를 절대 경로로 변환 (Docker 볼륨 마운트 요구사항)
+	absPath, err := filepath.Abs(filepath.Join(workspaceBaseDir, agentID))
+	if err != nil {
+		return "", fmt.Errorf("작업 공간 절대 경로 변환 실패: %w", err)
+	}
+	return absPath, nil
+}
+
+// TaskLogPath는 작업 공간 아래 Task별 Container 로그 파일 경로를 반환합니다.
+// 경로: {workspace}/logs/{taskID}/container.log
+func TaskLogPath(workspacePath, taskID string) string {
+	return filepath.Join(workspacePath, "logs", taskID, containerLogFile)
+}
+
+// rotatingFile은 크기 기준으로 회전하는 로그 파일 writer입니다.
+// 회전 시 container.log → container.log.1 → ... → container.log.{maxBackups} 순으로 밀려납니다.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// newRotatingFile은 로그 파일을 append 모드로 열어 rotatingFile을 생성합니다.
+func newRotatingFile(path string, maxSizeMB, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("로그 디렉토리 생성 실패: %w", err)
+	}
+
+	rf := &rotatingFile{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *rotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("로그 파일 열기 실패: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("로그 파일 조회 실패: %w", err)
+	}
+	rf.file = f
+	rf.size = info.Size()
+	return nil
+}
+
+// Write implements io.Writer.
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.maxSize > 0 && rf.size+int64(len(p)) > rf.maxSize && rf.size > 0 {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// rotate는 현재 파일을 닫고 백업 파일을 한 칸씩 밀어낸 뒤 새 파일을 엽니다.
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return fmt.Errorf("로그 파일 닫기 실패: %w", err)
+	}
+
+	if rf.maxBackups <= 0 {
+		_ = os.Remove(rf.path)
+	} else {
+		_ = os.Remove(fmt.Sprintf("%s.%d", rf.path, rf.maxBackups))
+		for i := rf.maxBackups - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", rf.path, i), fmt.Sprintf("%s.%d", rf.path, i+1))
+		}
+		if err := os.Rename(rf.path, rf.path+".1"); err != nil {
+			return fmt.Errorf("로그 파일 회전 실패: %w", err)
+		}
+	}
+
+	return rf.open()
+}
+
+// Close implements io.Closer.
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+// ReadLogTail은 로그 파일의 마지막 n줄을 반환합니다.
+// 현재 파일의 줄 수가 부족하면 직전 회전 파일(.1)에서 이어서 읽습니다.
+func ReadLogTail(path string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	lines, err := readLastLines(path, n)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) < n {
+		prev, err := readLastLines(path+".1", n-len(lines))
+		if err == nil {
+			lines = append(prev, lines...)
+		}
+	}
+	return lines, nil
+}
+
+// readLastLines는 파일의 마지막 n줄을 읽습니다. 파일이 없으면 빈 결과를 반환합니다.
+func readLastLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("로그 파일 열기 실패: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	ring := make([]string, 0, n)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(ring) == n {
+			ring = ring[1:]
+		}
+		ring = append(ring, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("로그 파일 읽기 실패: %w", err)
+	}
+	return ring, nil
+}
+
+// LogPath는 이 Runner의 Container 로그 파일 경로를 반환합니다.
+func (r *Runner) LogPath() string {
+	return TaskLogPath(r.WorkspacePath, r.ID)
+}
+
+// TailLogs는 Container 로그의 마지막 n줄을 반환합니다. n이 0 이하이면 설정값을 사용합니다.
+func (r *Runner) TailLogs(n int) []string {
+	if n <= 0 {
+		n = r.logConfig.TailLines
+	}
+	lines, err := ReadLogTail(r.LogPath(), n)
+	if err != nil {
+		r.logger.Warn("Container 로그 조회 실패",
+			zap.String("runner_id", r.ID),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return lines
+}
+
+// startLogStreaming은 Container 로그를 Task별 로그 파일로 스트리밍하기 시작합니다.
+// 스트림은 Container가 종료되거나 stopLogStreaming이 호출될 때까지 유지됩니다.
+func (r *Runner) startLogStreaming() {
+	writer, err := newRotatingFile(r.LogPath(), r.logConfig.MaxSizeMB, r.logConfig.MaxBackups)
+	if err != nil {
+		r.logger.Warn("Container 로그 파일 준비 실패",
+			zap.String("runner_id", r.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	// 재시작 구분을 위한 헤더 기록
+	_, _ = fmt.Fprintf(writer, "=== container %s started at %s (image: %s) ===\n",
+		r.ContainerID, time.Now().Format(time.RFC3339), r.ImageRef)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	r.logCancel = cancel
+	r.logDone = done
+
+	go func() {
+		defer close(done)
+		defer func() { _ = writer.Close() }()
+
+		stream, err := r.dockerClient.FollowContainerLogs(ctx, r.ContainerID)
+		if err != nil {
+			r.logger.Warn("Container 로그 스트림 시작 실패",
+				zap.String("runner_id", r.ID),
+				zap.Error(err),
+			)
+			return
+		}
+		defer func() { _ = stream.Close() }()
+
+		if _, err := io.Copy(writer, stream); err != nil && ctx.Err() == nil {
+			r.logger.Warn("Container 로그 스트림 중단",
+				zap.String("runner_id", r.ID),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// stopLogStreaming은 로그 스트림이 남은 출력을 기록할 때까지 최대 wait만큼 기다린 뒤 종료합니다.
+func (r *Runner) stopLogStreaming(wait time.Duration) {
+	if r.logDone == nil {
+		return
+	}
+
+	select {
+	case <-r.logDone:
+	case <-time.After(wait):
+	}
+
+	if r.logCancel != nil {
+		r.logCancel()
+	}
+	<-r.logDone
+	r.logCancel = nil
+	r.logDone = nil
+}
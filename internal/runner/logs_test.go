@@ -0,0 +1,102 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/runner/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeLogClient는 로그 스트림만 구현한 테스트용 DockerClient입니다.
+type fakeLogClient struct {
+	docker.DockerClient
+	logs string
+}
+
+func (f *fakeLogClient) FollowContainerLogs(ctx context.Context, containerID string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(f.logs)), nil
+}
+
+func TestRotatingFile_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "task-1", containerLogFile)
+
+	rf, err := newRotatingFile(path, 1, 2)
+	require.NoError(t, err)
+	// 테스트를 위해 최대 크기를 작게 조정
+	rf.maxSize = 20
+
+	for i := 0; i < 5; i++ {
+		_, err := fmt.Fprintf(rf, "line-%02d-xxxxxxxx\n", i)
+		require.NoError(t, err)
+	}
+	require.NoError(t, rf.Close())
+
+	// 현재 파일 + 백업 2개만 남아야 함
+	assert.FileExists(t, path)
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "line-04-xxxxxxxx\n", string(data))
+}
+
+func TestReadLogTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), containerLogFile)
+	require.NoError(t, os.WriteFile(path+".1", []byte("a\nb\nc\n"), 0644))
+	require.NoError(t, os.WriteFile(path, []byte("d\ne\n"), 0644))
+
+	lines, err := ReadLogTail(path, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e"}, lines)
+
+	// 현재 파일이 부족하면 회전 파일에서 이어서 읽음
+	lines, err = ReadLogTail(path, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "d", "e"}, lines)
+
+	// 파일이 없으면 빈 결과
+	lines, err = ReadLogTail(filepath.Join(t.TempDir(), "missing.log"), 10)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
+
+func TestRunner_LogStreaming(t *testing.T) {
+	client := &fakeLogClient{logs: "server starting\npanic: boom\n"}
+	r, err := NewRunner("task-logs", AgentInfo{AgentID: "agent"}, NewMockStatusCallback(), zaptest.NewLogger(t),
+		WithDockerClient(client),
+		WithWorkspacePath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	r.ContainerID = "container-1"
+
+	r.startLogStreaming()
+	r.stopLogStreaming(time.Second)
+
+	lines := r.TailLogs(2)
+	assert.Equal(t, []string{"server starting", "panic: boom"}, lines)
+
+	all := r.TailLogs(10)
+	require.Len(t, all, 3)
+	assert.Contains(t, all[0], "container container-1 started")
+}
+
+func TestAgentWorkspacePath_MatchesWorkspaceManager(t *testing.T) {
+	// Task 로그와 작업 공간이 같은 디렉토리를 사용해야 함
+	path, err := AgentWorkspacePath("agent-a")
+	require.NoError(t, err)
+
+	base, err := filepath.Abs(DefaultWorkspaceConfig().BaseDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "agent-a"), path)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/cnap-oss/app/internal/common"
 	"github.com/cnap-oss/app/internal/runner/docker"
+	"github.com/cnap-oss/app/internal/runner/opencode"
 	"go.uber.org/zap"
 )
 
@@ -116,7 +117,7 @@ func (rm *RunnerManager) CreateRunner(ctx context.Context, taskID string, agentI
 	}
 
 	// DockerClient를 옵션에 추가
-	allOpts := append([]RunnerOption{WithDockerClient(rm.dockerClient)}, opts...)
+	allOpts := append([]RunnerOption{WithDockerClient(rm.dockerClient), WithSessionOwners(rm)}, opts...)
 
 	runner, err := NewRunner(
 		taskID,
@@ -204,6 +205,27 @@ func (rm *RunnerManager) DeleteRunner(ctx context.Context, taskID string) error
 	return nil
 }
 
+// OwnedByOther는 SessionOwners 인터페이스를 구현합니다.
+// 이벤트의 세션을 sourceID가 아닌 다른 Runner가 소유하는지 확인합니다.
+func (rm *RunnerManager) OwnedByOther(sourceID string, event *opencode.Event) bool {
+	rm.mu.RLock()
+	candidates := make([]*Runner, 0, len(rm.runners))
+	for taskID, runner := range rm.runners {
+		if taskID != sourceID && runner != nil && runner.sessions != nil {
+			candidates = append(candidates, runner)
+		}
+	}
+	rm.mu.RUnlock()
+
+	// 다른 Runner의 레지스트리는 읽기만 함 (하위 세션 등록은 각 Runner가 자체 이벤트 루프에서 처리)
+	for _, runner := range candidates {
+		if runner.wouldOwn(event) {
+			return true
+		}
+	}
+	return false
+}
+
 // NotifyRunnerActivity는 Runner 활동을 알립니다.
 func (rm *RunnerManager) NotifyRunnerActivity(taskID string) {
 	if rm.lifecycleManager != nil {
@@ -247,3 +269,6 @@ func (rm *RunnerManager) GetRunnerCount() int {
 	defer rm.mu.RUnlock()
 	return len(rm.runners)
 }
+
+// ensure RunnerManager implements SessionOwners
+var _ SessionOwners = (*RunnerManager)(nil)
@@ -27,8 +27,24 @@ type Metrics struct {
 	ErrorsTotal     int64
 	ErrorsRecovered int64
 	RetriesTotal    int64
+
+	// 이벤트 라우팅 메트릭
+	EventsDelivered    int64 // 소유 세션 이벤트
+	EventsUnattributed int64 // 세션 정보가 없는 이벤트
+	EventsForeign      int64 // 다른 Runner 소유 세션이라 버린 이벤트 (소유 Runner가 자체 스트림으로 받음)
+	EventsDropped      int64 // 소유자를 찾지 못해 버린 이벤트
 }
 
+// EventRoute는 Runner가 수신한 SSE 이벤트의 처리 결과입니다.
+type EventRoute int
+
+const (
+	EventRouteDelivered    EventRoute = iota // Runner 소유 세션의 이벤트
+	EventRouteUnattributed                   // 세션 정보가 없는 이벤트
+	EventRouteForeign                        // 다른 Runner 소유 세션의 이벤트 (소유 Runner가 자체 스트림으로 받으므로 버림)
+	EventRouteDropped                        // 소유자를 찾지 못해 버린 이벤트
+)
+
 // GlobalMetrics는 전역 메트릭 인스턴스입니다.
 var GlobalMetrics = &Metrics{}
 
@@ -83,6 +99,20 @@ func (m *Metrics) RecordRetry() {
 	atomic.AddInt64(&m.RetriesTotal, 1)
 }
 
+// RecordEvent는 SSE 이벤트 라우팅 결과를 기록합니다.
+func (m *Metrics) RecordEvent(route EventRoute) {
+	switch route {
+	case EventRouteDelivered:
+		atomic.AddInt64(&m.EventsDelivered, 1)
+	case EventRouteUnattributed:
+		atomic.AddInt64(&m.EventsUnattributed, 1)
+	case EventRouteForeign:
+		atomic.AddInt64(&m.EventsForeign, 1)
+	case EventRouteDropped:
+		atomic.AddInt64(&m.EventsDropped, 1)
+	}
+}
+
 // GetSnapshot은 현재 메트릭 스냅샷을 반환합니다.
 func (m *Metrics) GetSnapshot() MetricsSnapshot {
 	return MetricsSnapshot{
@@ -98,6 +128,10 @@ func (m *Metrics) GetSnapshot() MetricsSnapshot {
 		ErrorsTotal:         atomic.LoadInt64(&m.ErrorsTotal),
 		ErrorsRecovered:     atomic.LoadInt64(&m.ErrorsRecovered),
 		RetriesTotal:        atomic.LoadInt64(&m.RetriesTotal),
+		EventsDelivered:     atomic.LoadInt64(&m.EventsDelivered),
+		EventsUnattributed:  atomic.LoadInt64(&m.EventsUnattributed),
+		EventsForeign:       atomic.LoadInt64(&m.EventsForeign),
+		EventsDropped:       atomic.LoadInt64(&m.EventsDropped),
 	}
 }
 
@@ -116,6 +150,10 @@ func (m *Metrics) Reset() {
 	atomic.StoreInt64(&m.ErrorsTotal, 0)
 	atomic.StoreInt64(&m.ErrorsRecovered, 0)
 	atomic.StoreInt64(&m.RetriesTotal, 0)
+	atomic.StoreInt64(&m.EventsDelivered, 0)
+	atomic.StoreInt64(&m.EventsUnattributed, 0)
+	atomic.StoreInt64(&m.EventsForeign, 0)
+	atomic.StoreInt64(&m.EventsDropped, 0)
 }
 
 func (m *Metrics) calculateAvgExecutionTime() float64 {
@@ -150,4 +188,8 @@ type MetricsSnapshot struct {
 	ErrorsTotal         int64   `json:"errors_total"`
 	ErrorsRecovered     int64   `json:"errors_recovered"`
 	RetriesTotal        int64   `json:"retries_total"`
+	EventsDelivered     int64   `json:"events_delivered"`
+	EventsUnattributed  int64   `json:"events_unattributed"`
+	EventsForeign       int64   `json:"events_foreign"`
+	EventsDropped       int64   `json:"events_dropped"`
 }
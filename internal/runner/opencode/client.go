@@ -1,7 +1,6 @@
 package opencode
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -316,26 +315,11 @@ func (c *OpenCodeClient) GetMessage(ctx context.Context, sessionID, messageID st
 	}
 
 	// role 필드로 타입 구분
-	var roleCheck struct {
-		Role string `json:"role"`
-	}
-	if err := json.Unmarshal(rawResult.Info, &roleCheck); err != nil {
-		return nil, fmt.Errorf("role 파싱 실패: %w", err)
-	}
-
-	if roleCheck.Role == "user" {
-		var userMsg UserMessage
-		if err := json.Unmarshal(rawResult.Info, &userMsg); err != nil {
-			return nil, fmt.Errorf("user message 파싱 실패: %w", err)
-		}
-		result.Info = userMsg
-	} else {
-		var assistantMsg AssistantMessage
-		if err := json.Unmarshal(rawResult.Info, &assistantMsg); err != nil {
-			return nil, fmt.Errorf("assistant message 파싱 실패: %w", err)
-		}
-		result.Info = assistantMsg
+	info, err := decodeMessageInfo(rawResult.Info)
+	if err != nil {
+		return nil, err
 	}
+	result.Info = info
 
 	return result, nil
 }
@@ -355,6 +339,32 @@ func (c *OpenCodeClient) AbortSession(ctx context.Context, sessionID string) err
 	return nil
 }
 
+// ======================================
+// Permission API
+// ======================================
+
+// RespondPermission은 도구 실행 권한 요청(permission.updated)에 응답합니다.
+func (c *OpenCodeClient) RespondPermission(ctx context.Context, sessionID, permissionID string, response PermissionResponse) error {
+	if !response.Valid() {
+		return fmt.Errorf("지원하지 않는 권한 응답: %q", response)
+	}
+
+	path := fmt.Sprintf("/session/%s/permissions/%s", sessionID, permissionID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, &RespondPermissionRequest{Response: response})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	c.logger.Info("권한 요청 응답됨",
+		zap.String("session_id", sessionID),
+		zap.String("permission_id", permissionID),
+		zap.String("response", string(response)),
+	)
+
+	return nil
+}
+
 // ======================================
 // Path API
 // ======================================
@@ -382,69 +392,6 @@ func (c *OpenCodeClient) GetPath(ctx context.Context) (*PathInfo, error) {
 // EventHandler는 SSE 이벤트 핸들러입니다.
 type EventHandler func(event *Event) error
 
-// SubscribeEvents는 이벤트 스트림을 구독합니다.
-func (c *OpenCodeClient) SubscribeEvents(ctx context.Context, handler EventHandler) error {
-	httpReq, err := c.buildRequest(ctx, http.MethodGet, "/event", nil)
-	if err != nil {
-		return err
-	}
-	httpReq.Header.Set("Accept", "text/event-stream")
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("요청 실패: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return c.handleErrorResponse(resp)
-	}
-
-	c.logger.Info("이벤트 스트림 구독 시작")
-
-	// SSE 파싱
-	reader := bufio.NewReader(resp.Body)
-	for {
-		select {
-		case <-ctx.Done():
-			c.logger.Info("이벤트 스트림 구독 중단")
-			return ctx.Err()
-		default:
-		}
-
-		line, err := reader.ReadString('\n')
-		if err != nil {
-			if err == io.EOF {
-				c.logger.Info("이벤트 스트림 종료")
-				return nil
-			}
-			return fmt.Errorf("스트림 읽기 실패: %w", err)
-		}
-
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, ":") {
-			continue // 빈 줄 또는 주석
-		}
-
-		if strings.HasPrefix(line, "data: ") {
-			data := strings.TrimPrefix(line, "data: ")
-
-			var event Event
-			if err := json.Unmarshal([]byte(data), &event); err != nil {
-				c.logger.Warn("이벤트 파싱 실패",
-					zap.String("data", data),
-					zap.Error(err),
-				)
-				continue
-			}
-
-			if err := handler(&event); err != nil {
-				return err
-			}
-		}
-	}
-}
-
 // ======================================
 // Internal Methods
 // ======================================
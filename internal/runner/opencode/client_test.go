@@ -257,6 +257,27 @@ func TestOpenCodeClient_GetMessages(t *testing.T) {
 	assert.True(t, ok, "두 번째 메시지는 AssistantMessage여야 함")
 }
 
+func TestOpenCodeClient_RespondPermission(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/session/ses_123/permissions/per_1", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var req RespondPermissionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, PermissionAlways, req.Response)
+
+		_ = json.NewEncoder(w).Encode(true)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	err := client.RespondPermission(context.Background(), "ses_123", "per_1", PermissionAlways)
+	require.NoError(t, err)
+
+	err = client.RespondPermission(context.Background(), "ses_123", "per_1", "maybe")
+	assert.Error(t, err)
+}
+
 func TestOpenCodeClient_GetPath(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "/path", r.URL.Path)
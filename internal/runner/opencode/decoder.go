@@ -0,0 +1,317 @@
+package opencode
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ======================================
+// Event Decoder
+// ======================================
+
+// 이벤트 payload 구조체 (OpenCode /event properties)
+
+type messageUpdatedProps struct {
+	Info json.RawMessage `json:"info"`
+}
+
+type messagePartUpdatedProps struct {
+	Part  Part    `json:"part"`
+	Delta *string `json:"delta,omitempty"` // 키가 없으면 파트 완료 스냅샷
+}
+
+type messageRemovedProps struct {
+	SessionID string `json:"sessionID"`
+	MessageID string `json:"messageID"`
+}
+
+type messageCompletedProps struct {
+	SessionID string `json:"sessionID,omitempty"`
+	MessageID string `json:"messageID"`
+}
+
+type sessionInfoProps struct {
+	Info Session `json:"info"`
+}
+
+type sessionStatusProps struct {
+	SessionID string        `json:"sessionID"`
+	Status    SessionStatus `json:"status"`
+}
+
+type sessionIDProps struct {
+	SessionID string `json:"sessionID"`
+}
+
+type sessionErrorProps struct {
+	SessionID string        `json:"sessionID,omitempty"`
+	Error     *MessageError `json:"error,omitempty"`
+}
+
+type fileEditedProps struct {
+	File string `json:"file"`
+}
+
+type todoUpdatedProps struct {
+	SessionID string     `json:"sessionID"`
+	Todos     []TodoItem `json:"todos"`
+}
+
+// DecodeEvent는 원시 SSE 이벤트를 타입이 지정된 RunnerMessage로 변환합니다.
+//
+// 문서화된 이벤트 타입은 해당 payload 구조체로 디코딩되고, 알 수 없는 타입은
+// MessageTypeUnknown으로 반환되어 RawEvent로만 접근할 수 있습니다.
+// RawEvent에는 항상 원본 이벤트가 담기며, Timestamp는 호출자가 수신 시각으로 설정합니다.
+func DecodeEvent(evt *Event) (*RunnerMessage, error) {
+	if evt == nil {
+		return nil, fmt.Errorf("이벤트가 nil입니다")
+	}
+
+	raw, err := json.Marshal(evt.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("이벤트 속성 직렬화 실패 (%s): %w", evt.Type, err)
+	}
+
+	msg := &RunnerMessage{RawEvent: evt}
+	if err := decodeInto(evt.Type, raw, msg); err != nil {
+		return nil, fmt.Errorf("이벤트 디코딩 실패 (%s): %w", evt.Type, err)
+	}
+	return msg, nil
+}
+
+func decodeInto(eventType string, raw json.RawMessage, msg *RunnerMessage) error {
+	switch eventType {
+	case "server.connected":
+		msg.Type = MessageTypeConnected
+
+	case "message.updated":
+		var props messageUpdatedProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		info, err := decodeMessageInfo(props.Info)
+		if err != nil {
+			return err
+		}
+		msg.Type = MessageTypeMessageUpdated
+		msg.Message = info
+		switch m := info.(type) {
+		case UserMessage:
+			msg.SessionID, msg.MessageID = m.SessionID, m.ID
+		case AssistantMessage:
+			msg.SessionID, msg.MessageID = m.SessionID, m.ID
+			if m.Error != nil {
+				msg.Error = newMessageErrorInfo(m.Error)
+			}
+		}
+
+	case "message.part.updated":
+		var props messagePartUpdatedProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		decodePart(&props.Part, props.Delta, msg)
+
+	case "message.removed":
+		var props messageRemovedProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeMessageRemoved
+		msg.SessionID, msg.MessageID = props.SessionID, props.MessageID
+
+	case "message.completed":
+		var props messageCompletedProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeComplete
+		msg.SessionID, msg.MessageID = props.SessionID, props.MessageID
+
+	case "session.created", "session.updated", "session.deleted":
+		var props sessionInfoProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeSessionUpdated
+		if eventType == "session.created" {
+			msg.Type = MessageTypeSessionCreated
+		}
+		msg.SessionID = props.Info.ID
+		msg.Session = &props.Info
+		msg.Status = eventType[len("session."):]
+
+	case "session.status":
+		var props sessionStatusProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeStatus
+		msg.SessionID = props.SessionID
+		msg.Status = props.Status.Type
+		msg.SessionStatus = &props.Status
+
+	case "session.idle":
+		var props sessionIDProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeIdle
+		msg.SessionID = props.SessionID
+		msg.Status = "idle"
+
+	case "session.compacted":
+		var props sessionIDProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeSessionCompacted
+		msg.SessionID = props.SessionID
+
+	case "session.aborted":
+		var props sessionIDProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeSessionAborted
+		msg.SessionID = props.SessionID
+
+	case "session.error":
+		var props sessionErrorProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeError
+		msg.SessionID = props.SessionID
+		msg.Error = newMessageErrorInfo(props.Error)
+
+	case "file.edited":
+		var props fileEditedProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeFileEdited
+		msg.File = props.File
+
+	case "permission.updated":
+		var perm PermissionInfo
+		if err := json.Unmarshal(raw, &perm); err != nil {
+			return err
+		}
+		msg.Type = MessageTypePermission
+		msg.SessionID, msg.MessageID = perm.SessionID, perm.MessageID
+		msg.Permission = &perm
+
+	case "todo.updated":
+		var props todoUpdatedProps
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return err
+		}
+		msg.Type = MessageTypeTodo
+		msg.SessionID = props.SessionID
+		msg.Todos = props.Todos
+
+	default:
+		msg.Type = MessageTypeUnknown
+	}
+	return nil
+}
+
+// decodePart는 message.part.updated 이벤트의 파트를 파트 타입에 맞는 메시지로 변환합니다.
+// delta 키가 있으면 값이 빈 문자열이어도 스트리밍 중인 부분 업데이트로 보고,
+// delta 키가 없거나 파트에 종료 시각이 기록되면 완료된 파트로 봅니다.
+func decodePart(part *Part, delta *string, msg *RunnerMessage) {
+	msg.SessionID = part.SessionID
+	msg.MessageID = part.MessageID
+	msg.PartID = part.ID
+	msg.Part = part
+
+	switch part.Type {
+	case "text", "reasoning":
+		msg.Type = MessageTypeText
+		if part.Type == "reasoning" {
+			msg.Type = MessageTypeReasoning
+		}
+		msg.Content = part.Text
+		if delta != nil {
+			msg.Delta = *delta
+		}
+		ended := part.Time != nil && part.Time.End != nil
+		msg.IsPartial = delta != nil && !ended
+
+	case "tool":
+		state := part.State
+		if state == nil {
+			state = &ToolState{}
+		}
+		switch state.Status {
+		case "completed", "error":
+			msg.Type = MessageTypeToolResult
+			result := &ToolResultInfo{
+				ToolID:   part.CallID,
+				ToolName: part.Tool,
+				Result:   state.Output,
+				IsError:  state.Status == "error",
+			}
+			if result.IsError {
+				result.Result = state.Error
+				if result.Result == "" {
+					result.Result = state.Output
+				}
+			}
+			msg.ToolResult = result
+		default:
+			msg.Type = MessageTypeToolCall
+			msg.ToolCall = &ToolCallInfo{
+				ToolID:    part.CallID,
+				ToolName:  part.Tool,
+				Arguments: state.Input,
+			}
+		}
+		msg.Status = state.Status
+
+	default:
+		// step-start, step-finish, file, patch, agent, snapshot, retry, compaction 등
+		msg.Type = MessageTypePart
+		msg.Status = part.Type
+	}
+}
+
+// decodeMessageInfo는 role 필드에 따라 UserMessage 또는 AssistantMessage로 디코딩합니다.
+func decodeMessageInfo(raw json.RawMessage) (Message, error) {
+	var roleCheck struct {
+		Role string `json:"role"`
+	}
+	if err := json.Unmarshal(raw, &roleCheck); err != nil {
+		return nil, fmt.Errorf("role 파싱 실패: %w", err)
+	}
+
+	if roleCheck.Role == "user" {
+		var userMsg UserMessage
+		if err := json.Unmarshal(raw, &userMsg); err != nil {
+			return nil, fmt.Errorf("user message 파싱 실패: %w", err)
+		}
+		return userMsg, nil
+	}
+
+	var assistantMsg AssistantMessage
+	if err := json.Unmarshal(raw, &assistantMsg); err != nil {
+		return nil, fmt.Errorf("assistant message 파싱 실패: %w", err)
+	}
+	return assistantMsg, nil
+}
+
+// newMessageErrorInfo는 OpenCode 에러 객체를 MessageErrorInfo로 변환합니다.
+func newMessageErrorInfo(err *MessageError) *MessageErrorInfo {
+	if err == nil {
+		return &MessageErrorInfo{Code: "UnknownError"}
+	}
+	info := &MessageErrorInfo{Code: err.Name}
+	if len(err.Data) > 0 {
+		info.Details = err.Data
+		if message, ok := err.Data["message"].(string); ok {
+			info.Message = message
+		}
+	}
+	return info
+}
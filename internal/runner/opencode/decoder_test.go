@@ -0,0 +1,117 @@
+package opencode
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "golden 파일 갱신")
+
+// TestDecodeEvent_Golden은 testdata/events의 녹화된 SSE 스트림을 디코딩하여
+// golden 파일과 비교합니다. 갱신: go test ./internal/runner/opencode -run Golden -update
+func TestDecodeEvent_Golden(t *testing.T) {
+	streams, err := filepath.Glob(filepath.Join("testdata", "events", "*.sse"))
+	require.NoError(t, err)
+	require.NotEmpty(t, streams)
+
+	for _, stream := range streams {
+		name := strings.TrimSuffix(filepath.Base(stream), ".sse")
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open(stream)
+			require.NoError(t, err)
+			defer f.Close()
+
+			messages := make([]*RunnerMessage, 0)
+			reader := newSSEReader(f)
+			for {
+				frame, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+
+				var event Event
+				require.NoError(t, json.Unmarshal([]byte(frame.Data), &event), frame.Data)
+				event.ID = frame.ID
+
+				msg, err := DecodeEvent(&event)
+				require.NoError(t, err, event.Type)
+				require.Same(t, &event, msg.RawEvent)
+
+				// 원본 이벤트는 golden에서 제외
+				msg.RawEvent = nil
+				messages = append(messages, msg)
+			}
+
+			got, err := json.MarshalIndent(messages, "", "  ")
+			require.NoError(t, err)
+			got = append(got, '\n')
+
+			goldenPath := filepath.Join("testdata", "events", name+".golden.json")
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(goldenPath, got, 0644))
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			require.NoError(t, err, "golden 파일이 없으면 -update로 생성하세요")
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+func TestDecodeEvent_TypedPayloads(t *testing.T) {
+	msg, err := DecodeEvent(&Event{Type: "message.updated", Properties: map[string]interface{}{
+		"info": map[string]interface{}{"id": "msg_1", "sessionID": "ses_1", "role": "user"},
+	}})
+	require.NoError(t, err)
+	user, ok := msg.Message.(UserMessage)
+	require.True(t, ok)
+	assert.Equal(t, "user", user.Role)
+	assert.Equal(t, "ses_1", msg.SessionID)
+
+	msg, err = DecodeEvent(&Event{Type: "message.part.updated", Properties: map[string]interface{}{
+		"part":  map[string]interface{}{"id": "prt_1", "type": "text", "text": "hi"},
+		"delta": "hi",
+	}})
+	require.NoError(t, err)
+	assert.True(t, msg.IsText())
+	assert.True(t, msg.IsPartial)
+	assert.Equal(t, "hi", msg.Delta)
+
+	// 빈 delta도 스트리밍 중인 업데이트이며, delta 키가 없거나 종료 시각이 있으면 완료
+	msg, err = DecodeEvent(&Event{Type: "message.part.updated", Properties: map[string]interface{}{
+		"part":  map[string]interface{}{"id": "prt_1", "type": "text", "text": "hi"},
+		"delta": "",
+	}})
+	require.NoError(t, err)
+	assert.True(t, msg.IsPartial)
+
+	msg, err = DecodeEvent(&Event{Type: "message.part.updated", Properties: map[string]interface{}{
+		"part": map[string]interface{}{"id": "prt_1", "type": "text", "text": "hi"},
+	}})
+	require.NoError(t, err)
+	assert.False(t, msg.IsPartial)
+
+	msg, err = DecodeEvent(&Event{Type: "message.part.updated", Properties: map[string]interface{}{
+		"part":  map[string]interface{}{"id": "prt_1", "type": "text", "text": "hi", "time": map[string]interface{}{"start": 1, "end": 2}},
+		"delta": "",
+	}})
+	require.NoError(t, err)
+	assert.False(t, msg.IsPartial)
+
+	// 스키마와 맞지 않는 payload는 에러
+	_, err = DecodeEvent(&Event{Type: "todo.updated", Properties: map[string]interface{}{"todos": "oops"}})
+	assert.Error(t, err)
+
+	_, err = DecodeEvent(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,335 @@
+package opencode
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 이벤트 스트림 재연결 기본값
+const (
+	defaultReconnectDelay       = 500 * time.Millisecond
+	defaultMaxReconnectDelay    = 30 * time.Second
+	defaultMaxReconnectAttempts = 10
+)
+
+// ErrReconnectExhausted는 이벤트 스트림 재연결 시도가 모두 실패했을 때 반환됩니다.
+var ErrReconnectExhausted = errors.New("이벤트 스트림 재연결 한도 초과")
+
+// errStreamEnded는 서버가 이벤트 스트림을 정상 종료(EOF)했음을 나타냅니다.
+var errStreamEnded = errors.New("서버가 이벤트 스트림을 종료함")
+
+// SubscribeOption은 이벤트 구독 옵션입니다.
+type SubscribeOption func(*subscribeConfig)
+
+type subscribeConfig struct {
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
+	maxAttempts       int
+	onDisconnect      func(err error, attempt int)
+}
+
+// WithReconnectBackoff는 재연결 대기 시간의 초기값과 최대값을 설정합니다.
+// 대기 시간은 실패할 때마다 두 배씩 늘어나며, 서버가 retry 필드를 보내면 초기값이 그 값으로 바뀝니다.
+func WithReconnectBackoff(initial, max time.Duration) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.reconnectDelay = initial
+		c.maxReconnectDelay = max
+	}
+}
+
+// WithMaxReconnectAttempts는 연속 재연결 시도 횟수를 제한합니다. 0 이하이면 무제한입니다.
+func WithMaxReconnectAttempts(n int) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.maxAttempts = n
+	}
+}
+
+// WithDisconnectHandler는 스트림 연결이 끊길 때마다 호출될 함수를 등록합니다.
+// attempt는 이번 재연결 시도 번호(1부터)입니다.
+func WithDisconnectHandler(fn func(err error, attempt int)) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.onDisconnect = fn
+	}
+}
+
+// handlerError는 EventHandler가 반환한 에러로, 재연결 없이 구독을 종료시킵니다.
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string { return e.err.Error() }
+func (e *handlerError) Unwrap() error { return e.err }
+
+// streamState는 재연결 사이에 유지되는 스트림 상태입니다.
+type streamState struct {
+	lastEventID string
+	retry       time.Duration // 서버가 지정한 재연결 대기 시간 (0이면 미지정)
+}
+
+// SubscribeEvents는 이벤트 스트림을 구독합니다.
+//
+// 연결이 끊기면 지수 백오프로 재연결하며, 마지막으로 받은 이벤트 ID를 Last-Event-ID 헤더로 보내
+// 서버가 지원하는 경우 누락된 이벤트부터 이어 받습니다.
+// ctx가 취소되거나, handler가 에러를 반환하거나, 재연결 한도를 넘으면 반환합니다.
+func (c *OpenCodeClient) SubscribeEvents(ctx context.Context, handler EventHandler, opts ...SubscribeOption) error {
+	cfg := subscribeConfig{
+		reconnectDelay:    defaultReconnectDelay,
+		maxReconnectDelay: defaultMaxReconnectDelay,
+		maxAttempts:       defaultMaxReconnectAttempts,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	state := &streamState{}
+	delay := cfg.reconnectDelay
+	attempt := 0
+
+	for {
+		connected, err := c.streamEvents(ctx, handler, state)
+		if ctx.Err() != nil {
+			c.logger.Info("이벤트 스트림 구독 중단")
+			return ctx.Err()
+		}
+
+		var hErr *handlerError
+		if errors.As(err, &hErr) {
+			return hErr.err
+		}
+
+		// 한 번이라도 연결에 성공했으면 백오프를 초기화
+		if connected {
+			attempt = 0
+			delay = cfg.reconnectDelay
+			if state.retry > 0 {
+				delay = state.retry
+			}
+		}
+
+		attempt++
+		if cfg.maxAttempts > 0 && attempt > cfg.maxAttempts {
+			return fmt.Errorf("%w (%d회): %w", ErrReconnectExhausted, cfg.maxAttempts, err)
+		}
+
+		if cfg.onDisconnect != nil {
+			cfg.onDisconnect(err, attempt)
+		}
+
+		c.logger.Warn("이벤트 스트림 연결 끊김, 재연결 대기",
+			zap.Error(err),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.String("last_event_id", state.lastEventID),
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > cfg.maxReconnectDelay {
+			delay = cfg.maxReconnectDelay
+		}
+	}
+}
+
+// streamEvents는 이벤트 스트림에 한 번 연결하여 끊길 때까지 이벤트를 처리합니다.
+// connected는 서버가 200 응답으로 스트림을 열었는지 여부입니다.
+func (c *OpenCodeClient) streamEvents(ctx context.Context, handler EventHandler, state *streamState) (connected bool, err error) {
+	httpReq, err := c.buildRequest(ctx, http.MethodGet, "/event", nil)
+	if err != nil {
+		return false, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("Cache-Control", "no-cache")
+	if state.lastEventID != "" {
+		httpReq.Header.Set("Last-Event-ID", state.lastEventID)
+	}
+
+	// 스트림은 장시간 유지되므로 전체 요청 타임아웃을 적용하지 않음
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+
+	resp, err := streamClient.Do(httpReq)
+	if err != nil {
+		return false, fmt.Errorf("요청 실패: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, c.handleErrorResponse(resp)
+	}
+
+	c.logger.Info("이벤트 스트림 구독 시작",
+		zap.String("last_event_id", state.lastEventID),
+	)
+
+	reader := newSSEReader(resp.Body)
+	reader.lastEventID = state.lastEventID
+	defer func() {
+		state.lastEventID = reader.lastEventID
+		state.retry = reader.retry
+	}()
+
+	for {
+		frame, err := reader.Next()
+		if err != nil {
+			if err == io.EOF {
+				return true, errStreamEnded
+			}
+			return true, fmt.Errorf("스트림 읽기 실패: %w", err)
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(frame.Data), &event); err != nil {
+			c.logger.Warn("이벤트 파싱 실패",
+				zap.String("sse_event", frame.Event),
+				zap.String("data", frame.Data),
+				zap.Error(err),
+			)
+			continue
+		}
+		event.ID = frame.ID
+
+		if err := handler(&event); err != nil {
+			return true, &handlerError{err: err}
+		}
+	}
+}
+
+// sseFrame은 SSE 스펙에 따라 조립된 하나의 이벤트입니다.
+type sseFrame struct {
+	ID    string // 마지막으로 설정된 이벤트 ID
+	Event string // event 필드 (기본: "message")
+	Data  string // data 필드 (여러 줄은 "\n"으로 연결)
+}
+
+// sseReader는 text/event-stream 본문을 WHATWG SSE 스펙에 따라 파싱합니다.
+// 줄 끝은 LF, CRLF, CR을 모두 허용하며, 이벤트 ID와 retry 값은 이벤트 사이에 유지됩니다.
+type sseReader struct {
+	r           *bufio.Reader
+	started     bool
+	lastEventID string
+	retry       time.Duration
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{r: bufio.NewReader(r)}
+}
+
+// Next는 다음 이벤트를 반환합니다. 스트림이 끝나면 io.EOF를 반환하며,
+// 빈 줄로 끝나지 않은 마지막 이벤트는 스펙에 따라 버립니다.
+func (s *sseReader) Next() (*sseFrame, error) {
+	var data strings.Builder
+	hasData := false
+	eventType := ""
+
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		// 빈 줄: 이벤트 디스패치
+		if line == "" {
+			if !hasData {
+				eventType = ""
+				continue
+			}
+			if eventType == "" {
+				eventType = "message"
+			}
+			return &sseFrame{
+				ID:    s.lastEventID,
+				Event: eventType,
+				Data:  strings.TrimSuffix(data.String(), "\n"),
+			}, nil
+		}
+
+		// 주석
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if idx := strings.IndexByte(line, ':'); idx >= 0 {
+			field = line[:idx]
+			value = strings.TrimPrefix(line[idx+1:], " ")
+		}
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				s.lastEventID = value
+			}
+		case "retry":
+			if !isASCIIDigits(value) {
+				continue
+			}
+			if ms, err := strconv.Atoi(value); err == nil {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// readLine은 줄 끝 문자(LF, CRLF, CR)를 제외한 한 줄을 읽습니다.
+func (s *sseReader) readLine() (string, error) {
+	var sb strings.Builder
+	for {
+		b, err := s.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		switch b {
+		case '\n':
+			return s.stripBOM(sb.String()), nil
+		case '\r':
+			if next, err := s.r.Peek(1); err == nil && next[0] == '\n' {
+				_, _ = s.r.ReadByte()
+			}
+			return s.stripBOM(sb.String()), nil
+		default:
+			sb.WriteByte(b)
+		}
+	}
+}
+
+// stripBOM은 스트림 첫 줄의 UTF-8 BOM을 제거합니다.
+func (s *sseReader) stripBOM(line string) string {
+	if !s.started {
+		s.started = true
+		return strings.TrimPrefix(line, "\uFEFF")
+	}
+	return line
+}
+
+func isASCIIDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package opencode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEReader_Spec(t *testing.T) {
+	stream := "\uFEFF: comment\n" +
+		"retry: 1500\n" +
+		"id: 1\n" +
+		"event: custom\n" +
+		"data: line1\n" +
+		"data:line2\n" +
+		"\n" +
+		// data 없는 이벤트는 디스패치되지 않지만 id는 갱신됨
+		"id: 2\r\n" +
+		"\r\n" +
+		"data: {\"type\":\"a\"}\r" +
+		"\r" +
+		"retry: abc\n" +
+		"data\n" +
+		"\n" +
+		"data: incomplete"
+
+	reader := newSSEReader(strings.NewReader(stream))
+
+	frame, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, &sseFrame{ID: "1", Event: "custom", Data: "line1\nline2"}, frame)
+	assert.Equal(t, 1500*time.Millisecond, reader.retry)
+
+	frame, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, &sseFrame{ID: "2", Event: "message", Data: `{"type":"a"}`}, frame)
+
+	// 값 없는 data 필드는 빈 데이터로 디스패치되고, 잘못된 retry는 무시됨
+	frame, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "", frame.Data)
+	assert.Equal(t, 1500*time.Millisecond, reader.retry)
+
+	// 빈 줄로 끝나지 않은 마지막 이벤트는 버림
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestSubscribeEvents_ReconnectWithLastEventID(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		n := len(lastEventIDs)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "id: %d\ndata: {\"type\":\"server.connected\",\"properties\":{}}\n\n", n*10)
+		_, _ = fmt.Fprintf(w, "id: %d\ndata: {\"type\":\"message.updated\",\n", n*10+1)
+		_, _ = fmt.Fprint(w, "data: \"properties\":{\"n\":1}}\n\n")
+		w.(http.Flusher).Flush()
+		// 첫 연결은 바로 끊고, 두 번째 연결은 클라이언트가 끊을 때까지 유지
+		if n > 1 {
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var events []*Event
+	var disconnects []int
+	client := NewClient(server.URL)
+	err := client.SubscribeEvents(ctx, func(event *Event) error {
+		events = append(events, event)
+		if len(events) == 4 {
+			cancel()
+		}
+		return nil
+	},
+		WithReconnectBackoff(10*time.Millisecond, 50*time.Millisecond),
+		WithDisconnectHandler(func(err error, attempt int) {
+			disconnects = append(disconnects, attempt)
+		}),
+	)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Len(t, events, 4)
+	assert.Equal(t, "server.connected", events[0].Type)
+	assert.Equal(t, "10", events[0].ID)
+	assert.Equal(t, "message.updated", events[1].Type)
+	assert.Equal(t, float64(1), events[1].Properties["n"])
+	assert.Equal(t, "21", events[3].ID)
+	assert.Equal(t, []int{1}, disconnects)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"", "11"}, lastEventIDs)
+}
+
+func TestSubscribeEvents_ReconnectExhausted(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	err := client.SubscribeEvents(context.Background(), func(event *Event) error { return nil },
+		WithReconnectBackoff(time.Millisecond, 5*time.Millisecond),
+		WithMaxReconnectAttempts(2),
+	)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrReconnectExhausted))
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 3, attempts)
+}
+
+func TestSubscribeEvents_HandlerErrorStops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: {\"type\":\"server.connected\"}\n\n")
+	}))
+	defer server.Close()
+
+	stop := errors.New("stop")
+	client := NewClient(server.URL)
+	err := client.SubscribeEvents(context.Background(), func(event *Event) error { return stop })
+	assert.Equal(t, stop, err)
+}
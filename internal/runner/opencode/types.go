@@ -1,6 +1,9 @@
 package opencode
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // ======================================
 // Runner Message Types (Phase 1)
@@ -29,8 +32,31 @@ const (
 	MessageTypeError    RunnerMessageType = "error"
 
 	// 세션 관련
-	MessageTypeSessionCreated RunnerMessageType = "session_created"
-	MessageTypeSessionAborted RunnerMessageType = "session_aborted"
+	MessageTypeSessionCreated   RunnerMessageType = "session_created"
+	MessageTypeSessionUpdated   RunnerMessageType = "session_updated"
+	MessageTypeSessionAborted   RunnerMessageType = "session_aborted"
+	MessageTypeSessionCompacted RunnerMessageType = "session_compacted"
+	MessageTypeIdle             RunnerMessageType = "idle"
+
+	// 메시지/파트 관련
+	MessageTypeMessageUpdated RunnerMessageType = "message_updated"
+	MessageTypeMessageRemoved RunnerMessageType = "message_removed"
+	MessageTypePart           RunnerMessageType = "part" // text/reasoning/tool 외 파트 (step, patch 등)
+
+	// 기타
+	MessageTypeConnected RunnerMessageType = "connected"
+	// MessageTypeStreamStatus는 OpenCode 이벤트가 아니라 Runner가 이벤트 스트림 연결 상태 변화를 알릴 때 사용합니다 (Status: StreamStatus*).
+	MessageTypeStreamStatus RunnerMessageType = "stream_status"
+	MessageTypeFileEdited   RunnerMessageType = "file_edited"
+	MessageTypePermission   RunnerMessageType = "permission"
+	MessageTypeTodo         RunnerMessageType = "todo"
+	MessageTypeUnknown      RunnerMessageType = "unknown" // 문서화되지 않은 이벤트 (RawEvent로만 접근)
+)
+
+// 이벤트 스트림 연결 상태 (MessageTypeStreamStatus 메시지의 Status)
+const (
+	StreamStatusReconnecting = "reconnecting" // 연결이 끊겨 재연결 시도 중 (이벤트가 지연될 수 있음)
+	StreamStatusConnected    = "connected"    // 재연결 성공
 )
 
 // RunnerMessage는 OpenCode SSE 이벤트를 추상화한 메시지 구조체입니다.
@@ -69,6 +95,15 @@ type RunnerMessage struct {
 	Error      *MessageErrorInfo `json:"error,omitempty"`
 	Metadata   map[string]any    `json:"metadata,omitempty"`
 	RawEvent   *Event            `json:"raw_event,omitempty"`
+
+	// 이벤트 타입별 payload
+	Message       Message         `json:"message,omitempty"`        // message.updated
+	Part          *Part           `json:"part,omitempty"`           // message.part.updated
+	Session       *Session        `json:"session,omitempty"`        // session.created/updated/deleted
+	SessionStatus *SessionStatus  `json:"session_status,omitempty"` // session.status
+	File          string          `json:"file,omitempty"`           // file.edited
+	Permission    *PermissionInfo `json:"permission,omitempty"`     // permission.updated
+	Todos         []TodoItem      `json:"todos,omitempty"`          // todo.updated
 }
 
 // ToolCallInfo는 도구 호출 정보를 담는 구조체입니다.
@@ -88,6 +123,37 @@ type ToolResultInfo struct {
 	IsError  bool   `json:"is_error"`
 }
 
+// SessionStatus는 session.status 이벤트의 세션 상태입니다.
+type SessionStatus struct {
+	Type    string `json:"type"`              // "idle", "busy", "retry"
+	Attempt int    `json:"attempt,omitempty"` // 재시도 횟수 (retry)
+	Message string `json:"message,omitempty"` // 재시도 사유 (retry)
+	Next    int64  `json:"next,omitempty"`    // 다음 재시도 시각 (retry)
+}
+
+// PermissionInfo는 permission.updated 이벤트의 권한 요청 정보입니다.
+type PermissionInfo struct {
+	ID        string                 `json:"id"`                 // 권한 요청 ID
+	Type      string                 `json:"type"`               // 권한 종류 (bash, edit, webfetch 등)
+	Pattern   any                    `json:"pattern,omitempty"`  // 대상 패턴 (문자열 또는 배열)
+	SessionID string                 `json:"sessionID"`          // 세션 ID
+	MessageID string                 `json:"messageID"`          // 메시지 ID
+	CallID    string                 `json:"callID,omitempty"`   // 도구 호출 ID
+	Title     string                 `json:"title"`              // 요청 제목
+	Metadata  map[string]interface{} `json:"metadata,omitempty"` // 메타데이터
+	Time      struct {
+		Created int64 `json:"created"` // 생성 시간
+	} `json:"time"`
+}
+
+// TodoItem은 todo.updated 이벤트의 할 일 항목입니다.
+type TodoItem struct {
+	ID       string `json:"id"`                 // 항목 ID
+	Content  string `json:"content"`            // 내용
+	Status   string `json:"status"`             // "pending", "in_progress", "completed", "cancelled"
+	Priority string `json:"priority,omitempty"` // "high", "medium", "low"
+}
+
 // MessageErrorInfo는 메시지 에러 정보를 담는 구조체입니다.
 // MessageTypeError 타입의 RunnerMessage에서 사용됩니다.
 type MessageErrorInfo struct {
@@ -487,10 +553,84 @@ type ToolStateTime struct {
 
 // Event는 SSE 이벤트입니다.
 type Event struct {
+	ID         string                 `json:"-"`          // SSE id 필드 (재연결 시 Last-Event-ID로 사용)
 	Type       string                 `json:"type"`       // 이벤트 타입
 	Properties map[string]interface{} `json:"properties"` // 이벤트 속성
 }
 
+// SessionID는 이벤트가 속한 세션 ID를 반환합니다. 세션과 무관한 인스턴스 이벤트이면 빈 문자열입니다.
+//
+// OpenCode 이벤트는 타입에 따라 세션 ID 위치가 다릅니다:
+//   - session.status, session.idle, session.error 등: properties.sessionID
+//   - message.part.updated: properties.part.sessionID
+//   - message.updated: properties.info.sessionID
+//   - session.created, session.updated, session.deleted: properties.info.id
+func (e *Event) SessionID() string {
+	if e == nil || e.Properties == nil {
+		return ""
+	}
+	if id, ok := e.Properties["sessionID"].(string); ok && id != "" {
+		return id
+	}
+	if part, ok := e.Properties["part"].(map[string]interface{}); ok {
+		if id, ok := part["sessionID"].(string); ok && id != "" {
+			return id
+		}
+	}
+	if info, ok := e.Properties["info"].(map[string]interface{}); ok {
+		if id, ok := info["sessionID"].(string); ok && id != "" {
+			return id
+		}
+		if strings.HasPrefix(e.Type, "session.") {
+			if id, ok := info["id"].(string); ok {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// ParentSessionID는 session.created/session.updated 이벤트의 부모 세션 ID를 반환합니다.
+// 하위(child) 세션이 아니면 빈 문자열입니다.
+func (e *Event) ParentSessionID() string {
+	if e == nil || !strings.HasPrefix(e.Type, "session.") {
+		return ""
+	}
+	if info, ok := e.Properties["info"].(map[string]interface{}); ok {
+		if id, ok := info["parentID"].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// ======================================
+// Permission API
+// ======================================
+
+// PermissionResponse는 도구 권한 요청에 대한 응답 종류입니다.
+type PermissionResponse string
+
+const (
+	PermissionOnce   PermissionResponse = "once"   // 이번 한 번만 허용
+	PermissionAlways PermissionResponse = "always" // 세션 동안 같은 패턴 항상 허용
+	PermissionReject PermissionResponse = "reject" // 거부
+)
+
+// Valid는 OpenCode가 지원하는 응답 값인지 확인합니다.
+func (r PermissionResponse) Valid() bool {
+	switch r {
+	case PermissionOnce, PermissionAlways, PermissionReject:
+		return true
+	}
+	return false
+}
+
+// RespondPermissionRequest는 /session/{sessionID}/permissions/{permissionID} POST 요청입니다.
+type RespondPermissionRequest struct {
+	Response PermissionResponse `json:"response"` // once, always, reject
+}
+
 // ======================================
 // Path API
 // ======================================
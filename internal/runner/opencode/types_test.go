@@ -0,0 +1,55 @@
+package opencode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvent_SessionID(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    *Event
+		session  string
+		parentID string
+	}{
+		{
+			name:    "session.status",
+			event:   &Event{Type: "session.status", Properties: map[string]interface{}{"sessionID": "ses_1"}},
+			session: "ses_1",
+		},
+		{
+			name: "message.part.updated",
+			event: &Event{Type: "message.part.updated", Properties: map[string]interface{}{
+				"part": map[string]interface{}{"sessionID": "ses_2"},
+			}},
+			session: "ses_2",
+		},
+		{
+			name: "message.updated",
+			event: &Event{Type: "message.updated", Properties: map[string]interface{}{
+				"info": map[string]interface{}{"id": "msg_1", "sessionID": "ses_3"},
+			}},
+			session: "ses_3",
+		},
+		{
+			name: "session.created child",
+			event: &Event{Type: "session.created", Properties: map[string]interface{}{
+				"info": map[string]interface{}{"id": "ses_child", "parentID": "ses_root"},
+			}},
+			session:  "ses_child",
+			parentID: "ses_root",
+		},
+		{
+			name:  "server.connected",
+			event: &Event{Type: "server.connected", Properties: map[string]interface{}{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.session, tt.event.SessionID())
+			assert.Equal(t, tt.parentID, tt.event.ParentSessionID())
+		})
+	}
+}
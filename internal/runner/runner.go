@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cnap-oss/app/internal/runner/docker"
@@ -49,6 +51,8 @@ type AgentInfo struct {
 	Model         string
 	Prompt        string
 	WorkspacePath string // 신규: Agent 작업 공간 경로
+	Image         string // Agent 전용 Runner 이미지 (비어 있으면 기본 이미지)
+	ImageDigest   string // Task에 고정된 이미지 참조 (repo@sha256:..., 재시작 시 동일 이미지 보장)
 }
 
 // StatusCallback은 Task 실행 중 상태 변경을 Controller에 알리기 위한 콜백 인터페이스입니다.
@@ -57,7 +61,7 @@ type AgentInfo struct {
 // 콜백 호출 순서:
 //  1. OnStarted - 세션 생성 및 실행 시작
 //  2. OnMessage - SSE 이벤트 수신 시 (여러 번 호출 가능)
-//  3. OnComplete 또는 OnError - 실행 종료
+//  3. OnComplete 또는 OnError - 턴 종료 (Run 호출마다 한 번)
 type StatusCallback interface {
 	// OnStarted는 Runner가 시작되고 OpenCode 세션이 생성될 때 호출됩니다.
 	//
@@ -70,27 +74,27 @@ type StatusCallback interface {
 	OnStarted(taskID string, sessionID string) error
 
 	// OnEvent는 Runner가 SSE 이벤트를 수신할 때 호출됩니다.
-	// 텍스트 스트리밍, 도구 호출, 상태 변경 등 다양한 이벤트를 실시간으로 전달합니다.
+	// 텍스트 스트리밍, 도구 호출, 상태 변경 등 다양한 이벤트를 타입이 지정된 메시지로 실시간 전달합니다.
 	//
 	// Parameters:
 	//   - taskID: Task 식별자
-	//   - event: Event (원시 SSE 이벤트)
+	//   - msg: 디코딩된 메시지 (원시 SSE 이벤트는 msg.RawEvent)
 	//
 	// Returns:
 	//   - error: 콜백 처리 실패 시 에러 (로깅용, Runner 실행에는 영향 없음)
-	OnEvent(taskID string, event *opencode.Event) error
+	OnEvent(taskID string, msg *opencode.RunnerMessage) error
 
-	// OnComplete는 Task가 성공적으로 완료될 때 호출됩니다.
+	// OnComplete는 턴이 성공적으로 끝날 때(루트 세션 idle) 호출됩니다.
 	//
 	// Parameters:
 	//   - taskID: Task 식별자
-	//   - result: 실행 결과 (전체 출력 텍스트 포함)
+	//   - result: 실행 결과 (해당 턴의 어시스턴트 메시지 텍스트 포함)
 	//
 	// Returns:
 	//   - error: 콜백 처리 실패 시 에러 (로깅용)
 	OnComplete(taskID string, result *RunResult) error
 
-	// OnError는 Task 실행 중 에러가 발생할 때 호출됩니다.
+	// OnError는 Task 실행 중 에러가 발생하거나, 이벤트 스트림이 재연결에 실패하여 끊겼을 때 호출됩니다.
 	//
 	// Parameters:
 	//   - taskID: Task 식별자
@@ -135,6 +139,14 @@ type Runner struct {
 	// 작업 공간
 	WorkspacePath string // 마운트된 작업 공간 경로
 
+	// 이미지 정보
+	ImageRef string // Container 생성에 사용한 이미지 참조 (다이제스트 고정)
+
+	// Container 로그 스트리밍
+	logConfig LogConfig
+	logCancel context.CancelFunc
+	logDone   chan struct{}
+
 	// 세션 관리 (Runner 생명 주기 동안 유지)
 	apiClient   *opencode.OpenCodeClient // OpenCode API 클라이언트
 	session     *opencode.Session        // OpenCode 세션
@@ -142,7 +154,19 @@ type Runner struct {
 	eventCtx    context.Context          // 이벤트 스트림 컨텍스트
 	eventCancel context.CancelFunc       // 이벤트 스트림 취소 함수
 	eventDone   chan error               // 이벤트 스트림 완료 채널
-	fullContent *strings.Builder         // 누적 컨텐츠
+	connected   chan struct{}            // server.connected 이벤트 수신 시 닫힘
+	connectOnce sync.Once                // connected 중복 close 방지
+	degraded    atomic.Bool              // 이벤트 스트림 재연결 중 여부 (상태 변화만 콜백으로 알림)
+	sessions    *sessionRegistry         // 소유 세션 (루트 + 하위 세션)
+	owners      SessionOwners            // 다른 Runner 소유 세션 확인 (메트릭 분류)
+	streamDone  chan struct{}            // 이벤트 스트림 종료 시 닫힘
+	turn        *turnOutput              // 현재 턴의 어시스턴트 출력
+	turnMu      sync.Mutex               // turnDone 보호
+	turnDone    chan struct{}            // 진행 중인 턴이 끝나면 닫힘
+	aborted     atomic.Bool              // 현재 턴이 Abort로 중단되었는지 여부
+	inTurn      atomic.Bool              // 턴 진행 중 여부 (Status와 달리 고루틴 간 안전)
+	idle        chan struct{}            // 루트 세션 idle/aborted 신호 (턴 종료 판단용)
+	idleSent    atomic.Bool              // 마지막 idle 신호 이후 루트 세션 활동이 없었는지 여부
 
 	// 콜백 핸들러 (생성 시 등록)
 	callback StatusCallback
@@ -151,6 +175,8 @@ type Runner struct {
 	dockerClient docker.DockerClient
 	httpClient   *http.Client
 	logger       *zap.Logger
+	pullProgress func(image string, progress docker.PullProgress)
+	streamOpts   []opencode.SubscribeOption
 
 	// 레거시 필드 (Phase 2 이후 제거 예정)
 	apiKey  string
@@ -188,6 +214,20 @@ func WithWorkspacePath(path string) RunnerOption {
 	}
 }
 
+// WithPullProgress는 이미지 pull 진행 상황을 전달받을 콜백을 등록합니다.
+func WithPullProgress(fn func(image string, progress docker.PullProgress)) RunnerOption {
+	return func(r *Runner) {
+		r.pullProgress = fn
+	}
+}
+
+// WithEventStreamOptions는 SSE 이벤트 구독 옵션(재연결 백오프 등)을 지정합니다.
+func WithEventStreamOptions(opts ...opencode.SubscribeOption) RunnerOption {
+	return func(r *Runner) {
+		r.streamOpts = append(r.streamOpts, opts...)
+	}
+}
+
 // OpenCodeRequest는 OpenCode Zen API 요청 바디입니다 (레거시).
 type OpenCodeRequest struct {
 	Model    string                 `json:"model"`
@@ -227,14 +267,13 @@ func NewRunner(taskID string, agentInfo AgentInfo, callback StatusCallback, logg
 	}
 
 	// 기본 설정
-	workspaceBaseDir := os.Getenv("CNAP_RUNNER_WORKSPACE_DIR")
-	if workspaceBaseDir == "" {
-		workspaceBaseDir = "./data/workspace"
-	}
-
 	workspacePath := agentInfo.WorkspacePath
 	if workspacePath == "" {
-		workspacePath = fmt.Sprintf("%s/%s", workspaceBaseDir, agentInfo.AgentID)
+		path, err := AgentWorkspacePath(agentInfo.AgentID)
+		if err != nil {
+			return nil, err
+		}
+		workspacePath = path
 	}
 
 	// 상대 경로를 절대 경로로 변환 (Docker 볼륨 마운트 요구사항)
@@ -254,6 +293,10 @@ func NewRunner(taskID string, agentInfo AgentInfo, callback StatusCallback, logg
 		ContainerPort: 3000,
 		WorkspacePath: workspacePath,
 		ContainerName: fmt.Sprintf("cnap-runner-%s", taskID),
+		logConfig:     DefaultLogConfig(),
+		sessions:      newSessionRegistry(),
+		turn:          newTurnOutput(),
+		idle:          make(chan struct{}, 1),
 		// 레거시 필드 (Phase 2 이후 제거)
 		apiKey:  os.Getenv("OPEN_CODE_API_KEY"),
 		baseURL: defaultBaseURL,
@@ -294,22 +337,17 @@ func (r *Runner) Start(ctx context.Context) error {
 	// 환경 변수 구성
 	env := r.buildEnvironmentVariables()
 
-	// Docker 이미지 이름 - CNAP_ENV에 따라 분기
-	imageName := os.Getenv("CNAP_RUNNER_IMAGE")
-	if imageName == "" {
-		// 환경별 기본 이미지 설정 (기본값: production)
-		env := os.Getenv("CNAP_ENV")
-		if env == "development" {
-			imageName = "cnap-runner:latest"
-		} else {
-			// production 또는 CNAP_ENV 미설정 시 ghcr.io 이미지 사용
-			imageName = "ghcr.io/cnap-oss/cnap-runner:latest"
-		}
+	// 이미지 확인 (없으면 pull) 및 다이제스트 고정
+	imageRef, err := r.resolveImage(ctx)
+	if err != nil {
+		r.Status = RunnerStatusFailed
+		return fmt.Errorf("runner 이미지 준비 실패: %w", err)
 	}
+	r.ImageRef = imageRef
 
 	// Container 생성
 	containerID, err := r.dockerClient.CreateContainer(ctx, docker.ContainerConfig{
-		Image: imageName,
+		Image: imageRef,
 		Name:  r.ContainerName,
 		Env:   env,
 		Mounts: []docker.MountConfig{
@@ -342,6 +380,9 @@ func (r *Runner) Start(ctx context.Context) error {
 		return fmt.Errorf("container 시작 실패: %w", err)
 	}
 
+	// Container 로그를 Task별 로그 파일로 스트리밍 (Container 종료 후에도 보존)
+	r.startLogStreaming()
+
 	// Container 정보 조회하여 포트 매핑 확인
 	info, err := r.dockerClient.ContainerInspect(ctx, r.ContainerID)
 	if err != nil {
@@ -394,6 +435,7 @@ func (r *Runner) Start(ctx context.Context) error {
 	}
 	r.session = session
 	r.sessionID = session.ID
+	r.sessions.add(session.ID)
 
 	r.logger.Info("OpenCode 세션 생성됨",
 		zap.String("runner_id", r.ID),
@@ -407,35 +449,114 @@ func (r *Runner) Start(ctx context.Context) error {
 		}
 	}
 
-	// 누적 컨텐츠 초기화
-	r.fullContent = &strings.Builder{}
-
 	// SSE 이벤트 구독 시작 (백그라운드에서 실행)
-	r.eventCtx, r.eventCancel = context.WithCancel(context.Background())
-	r.eventDone = make(chan error, 1)
+	r.startEventStream()
 
-	go func() {
-		err := r.apiClient.SubscribeEvents(r.eventCtx, r.handleEvent)
-		select {
-		case r.eventDone <- err:
-		default:
-		}
-	}()
-
-	// 이벤트 구독이 준비될 때까지 잠시 대기
-	time.Sleep(500 * time.Millisecond)
+	// 이벤트 스트림이 연결될 때까지 대기 (server.connected 수신)
+	if err := r.waitForEventStream(ctx, eventStreamReadyTimeout); err != nil {
+		r.Status = RunnerStatusFailed
+		_ = r.Stop(ctx)
+		return fmt.Errorf("이벤트 스트림 연결 실패: %w", err)
+	}
 
 	r.Status = RunnerStatusReady
 	r.logger.Info("Runner container started successfully",
 		zap.String("runner_id", r.ID),
 		zap.String("container_id", r.ContainerID),
 		zap.String("session_id", r.sessionID),
+		zap.String("image", r.ImageRef),
 		zap.Int("host_port", r.HostPort),
 	)
 
 	return nil
 }
 
+// eventStreamReadyTimeout은 Runner 시작 시 server.connected 이벤트를 기다리는 최대 시간입니다.
+const eventStreamReadyTimeout = 30 * time.Second
+
+// startEventStream은 SSE 이벤트 구독을 백그라운드에서 시작합니다.
+// 구독은 연결이 끊기면 자동으로 재연결하며, 재연결에 최종 실패하면 OnError 콜백으로 알립니다.
+func (r *Runner) startEventStream() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.eventCtx, r.eventCancel = ctx, cancel
+	r.eventDone = make(chan error, 1)
+	r.streamDone = make(chan struct{})
+	r.connected = make(chan struct{})
+	r.connectOnce = sync.Once{}
+	r.degraded.Store(false)
+	connected := r.connected
+	streamDone := r.streamDone
+
+	go func() {
+		opts := append([]opencode.SubscribeOption{
+			opencode.WithDisconnectHandler(func(err error, attempt int) {
+				r.logger.Warn("이벤트 스트림 연결 끊김, 재연결 시도",
+					zap.String("runner_id", r.ID),
+					zap.Int("attempt", attempt),
+					zap.Error(err),
+				)
+				// 시작 중 실패는 Start의 반환값으로 전달되므로 연결된 적이 있을 때만 알림
+				select {
+				case <-connected:
+				default:
+					return
+				}
+				if r.degraded.CompareAndSwap(false, true) {
+					r.reportStreamStatus(opencode.StreamStatusReconnecting, err)
+				}
+			}),
+		}, r.streamOpts...)
+		err := r.apiClient.SubscribeEvents(ctx, r.handleEvent, opts...)
+		r.eventDone <- err
+		close(streamDone)
+
+		// Stop에 의한 종료가 아니고 연결된 적이 있으면 콜백으로 알림
+		// (시작 중 실패는 Start의 반환값으로 전달됨)
+		if ctx.Err() != nil || err == nil {
+			return
+		}
+		select {
+		case <-connected:
+		default:
+			return
+		}
+		// 진행 중인 턴이 있으면 runInternal이 턴 실패로 보고
+		if r.inTurn.Load() {
+			return
+		}
+
+		r.logger.Error("이벤트 스트림 종료",
+			zap.String("runner_id", r.ID),
+			zap.Error(err),
+		)
+		if r.callback != nil {
+			if cbErr := r.callback.OnError(r.ID, fmt.Errorf("이벤트 스트림 연결 끊김: %w", err)); cbErr != nil {
+				r.logger.Warn("OnError 콜백 실패", zap.Error(cbErr))
+			}
+		}
+	}()
+}
+
+// waitForEventStream은 server.connected 이벤트를 수신할 때까지 대기합니다.
+func (r *Runner) waitForEventStream(ctx context.Context, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-r.connected:
+		return nil
+	case err := <-r.eventDone:
+		if err == nil {
+			err = fmt.Errorf("이벤트 스트림이 종료됨")
+		}
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return fmt.Errorf("server.connected 이벤트 대기 타임아웃 (%s)", timeout)
+	}
+}
+
 // IsContainerStopped는 Container가 stopped 상태인지 확인합니다.
 func (r *Runner) IsContainerStopped(ctx context.Context) bool {
 	if r.ContainerID == "" {
@@ -463,32 +584,8 @@ func (r *Runner) Stop(ctx context.Context) error {
 		zap.String("container_id", r.ContainerID),
 	)
 
-	// 정상 종료 상태 확인 (Ready 또는 Running 상태에서 Stop 호출 시)
-	shouldComplete := r.Status == RunnerStatusReady || r.Status == RunnerStatusRunning
-
 	r.Status = RunnerStatusStopping
 
-	// 정상 종료 시 OnComplete 콜백 호출
-	if shouldComplete && r.callback != nil {
-		r.logger.Info("Runner stopping normally, calling OnComplete",
-			zap.String("runner_id", r.ID),
-		)
-
-		output := ""
-		if r.fullContent != nil {
-			output = r.fullContent.String()
-		}
-
-		result := &RunResult{
-			Success: true,
-			Output:  output,
-		}
-
-		if err := r.callback.OnComplete(r.ID, result); err != nil {
-			r.logger.Warn("OnComplete 콜백 실패", zap.Error(err))
-		}
-	}
-
 	// 이벤트 스트림 중지
 	if r.eventCancel != nil {
 		r.eventCancel()
@@ -506,6 +603,9 @@ func (r *Runner) Stop(ctx context.Context) error {
 		r.sessionID = ""
 		r.session = nil
 	}
+	if r.sessions != nil {
+		r.sessions.clear()
+	}
 
 	if r.ContainerID == "" {
 		r.Status = RunnerStatusStopped
@@ -520,6 +620,9 @@ func (r *Runner) Stop(ctx context.Context) error {
 		)
 	}
 
+	// 종료 직전 출력까지 기록되도록 로그 스트림이 끝나기를 잠시 대기
+	r.stopLogStreaming(3 * time.Second)
+
 	// Container 삭제
 	if err := r.dockerClient.RemoveContainer(ctx, r.ContainerID); err != nil {
 		r.logger.Warn("Container 삭제 중 오류",
@@ -543,9 +646,64 @@ func (r *Runner) handleEvent(event *opencode.Event) error {
 		zap.Any("properties", event.Properties),
 	)
 
-	// 콜백으로 이벤트 전달
+	// 스트림 연결 확인 (재연결 시에도 다시 수신되므로 한 번만 처리)
+	if event.Type == "server.connected" && r.connected != nil {
+		r.connectOnce.Do(func() { close(r.connected) })
+		if r.degraded.CompareAndSwap(true, false) {
+			r.reportStreamStatus(opencode.StreamStatusConnected, nil)
+		}
+	}
+
+	// 다른 세션의 이벤트는 소유 Runner로 넘기거나 버림
+	if !r.routeEvent(event) {
+		return nil
+	}
+
+	r.deliverEvent(event)
+	return nil
+}
+
+// reportStreamStatus는 이벤트 스트림 연결 상태 변화를 콜백으로 알립니다.
+// 재연결에 최종 실패하면 OnError로 따로 보고합니다.
+func (r *Runner) reportStreamStatus(status string, cause error) {
+	if r.callback == nil {
+		return
+	}
+	msg := &opencode.RunnerMessage{
+		Type:      opencode.MessageTypeStreamStatus,
+		SessionID: r.sessionID,
+		Status:    status,
+		Timestamp: time.Now(),
+	}
+	if cause != nil {
+		msg.Error = &opencode.MessageErrorInfo{Code: "stream_disconnected", Message: cause.Error()}
+	}
+	if err := r.callback.OnEvent(r.ID, msg); err != nil {
+		r.logger.Warn("콜백 전달 실패",
+			zap.String("runner_id", r.ID),
+			zap.String("stream_status", status),
+			zap.Error(err),
+		)
+	}
+}
+
+// deliverEvent는 이벤트를 타입이 지정된 메시지로 디코딩하여 콜백으로 전달합니다.
+// 디코딩에 실패한 이벤트도 버리지 않고 MessageTypeUnknown으로 전달합니다.
+func (r *Runner) deliverEvent(event *opencode.Event) {
+	msg, err := opencode.DecodeEvent(event)
+	if err != nil {
+		r.logger.Warn("이벤트 디코딩 실패",
+			zap.String("runner_id", r.ID),
+			zap.String("event_type", event.Type),
+			zap.Error(err),
+		)
+		msg = &opencode.RunnerMessage{Type: opencode.MessageTypeUnknown, RawEvent: event}
+	}
+	msg.Timestamp = time.Now()
+	r.turn.observe(msg, r.sessionID)
+
 	if r.callback != nil {
-		if err := r.callback.OnEvent(r.ID, event); err != nil {
+		if err := r.callback.OnEvent(r.ID, msg); err != nil {
 			r.logger.Warn("콜백 전달 실패",
 				zap.String("runner_id", r.ID),
 				zap.String("event_type", event.Type),
@@ -554,7 +712,8 @@ func (r *Runner) handleEvent(event *opencode.Event) error {
 		}
 	}
 
-	return nil
+	// 콜백이 상태를 반영한 뒤 Abort 대기자를 깨움
+	r.signalIdle(msg)
 }
 
 // buildEnvironmentVariables는 Container에 전달할 환경 변수를 구성합니다.
@@ -619,9 +778,9 @@ var _ TaskRunner = (*Runner)(nil)
 // 실행 흐름:
 //  1. Runner 상태 및 요청 검증
 //  2. 고루틴 시작 (즉시 반환)
-//  3. [고루틴 내부] 기존 세션을 사용하여 프롬프트 전송
-//  4. [고루틴 내부] 이벤트 수신 대기 (백그라운드 handleEvent가 처리 중)
-//  5. [고루틴 내부] 완료 시 OnComplete 또는 OnError 호출
+//  3. [고루틴 내부] 기존 세션에 prompt_async로 프롬프트 제출
+//  4. [고루틴 내부] 루트 세션 idle 이벤트 대기 (백그라운드 handleEvent가 처리 중)
+//  5. [고루틴 내부] 턴 종료 시 OnComplete(조립된 어시스턴트 메시지) 또는 OnError 호출
 //
 // Parameters:
 //   - ctx: 실행 컨텍스트 (고루틴에 전달되어 취소 시그널로 사용)
@@ -633,26 +792,35 @@ var _ TaskRunner = (*Runner)(nil)
 // 주의사항:
 //   - 콜백은 반드시 NewRunner() 생성자에서 등록되어야 함
 //   - Runner 상태가 RunnerStatusReady가 아니면 실패
+//   - 이미 진행 중인 턴이 있으면 ErrTurnInProgress 반환 (턴은 고루틴 시작 전에 점유됨)
 //   - 세션은 Start()에서 이미 생성되었어야 함
 //   - 에러 반환은 실행 시작 실패를 의미하며, 실행 중 에러는 OnError 콜백으로 전달됨
 func (r *Runner) Run(ctx context.Context, req *RunRequest) error {
-	if r.Status != RunnerStatusReady {
-		return fmt.Errorf("runner가 준비되지 않음 (status: %s)", r.Status)
-	}
-
 	// 요청 검증
 	if req == nil {
 		return fmt.Errorf("request is nil")
 	}
 
+	// 턴은 고루틴 시작 전에 점유해 동시 Run이 같은 턴 상태를 덮어쓰지 않게 함
+	if !r.inTurn.CompareAndSwap(false, true) {
+		return ErrTurnInProgress
+	}
+	if r.Status != RunnerStatusReady {
+		r.inTurn.Store(false)
+		return fmt.Errorf("runner가 준비되지 않음 (status: %s)", r.Status)
+	}
+	r.Status = RunnerStatusRunning
+	done := r.beginTurn()
+
 	r.logger.Info("Runner starting async execution",
 		zap.String("runner_id", r.ID),
 		zap.String("task_id", req.TaskID),
 		zap.Int("message_count", len(req.Messages)),
 	)
 
-	// 비동기 실행 시작
+	// 비동기 실행 시작 (턴은 결과 콜백 호출 뒤에 종료되어 WaitTurn 이후에는 결과가 반영되어 있음)
 	go func() {
+		defer r.endTurn(done)
 		err := r.runInternal(ctx, req)
 		if err != nil {
 			_ = r.callback.OnError(req.TaskID, err)
@@ -664,10 +832,11 @@ func (r *Runner) Run(ctx context.Context, req *RunRequest) error {
 }
 
 // runInternal은 실제 실행 로직을 담당합니다.
+// 턴은 Run에서 시작되고 종료됩니다.
 // Start()에서 이미 세션이 생성되고 이벤트 구독이 시작되었으므로,
-// 여기서는 프롬프트만 전송하고 결과를 기다립니다.
+// 여기서는 프롬프트를 prompt_async로 제출하고 루트 세션의 idle 이벤트로 턴 종료를 판단합니다.
+// 턴이 정상 종료되면 조립된 어시스턴트 메시지로 OnComplete를 호출합니다.
 func (r *Runner) runInternal(ctx context.Context, req *RunRequest) error {
-	r.Status = RunnerStatusRunning
 	defer func() {
 		r.Status = RunnerStatusReady
 	}()
@@ -683,9 +852,6 @@ func (r *Runner) runInternal(ctx context.Context, req *RunRequest) error {
 		return fmt.Errorf("세션이 준비되지 않음")
 	}
 
-	// 누적 컨텐츠 초기화 (새 실행 시작)
-	r.fullContent.Reset()
-
 	// 시스템 프롬프트와 메시지 결합
 	messages := r.buildMessages(req)
 
@@ -711,11 +877,54 @@ func (r *Runner) runInternal(ctx context.Context, req *RunRequest) error {
 		Parts:  parts,
 	}
 
-	_, err := r.apiClient.Message(ctx, r.sessionID, promptReq)
-	if err != nil {
+	if err := r.apiClient.PromptAsync(ctx, r.sessionID, promptReq); err != nil {
 		return fmt.Errorf("메시지 전송 실패: %w", err)
 	}
 
+	// 턴 종료 대기 (session.idle / session.status idle / session.aborted)
+	select {
+	case <-r.idle:
+	case <-r.streamDone:
+		r.logger.Warn("이벤트 스트림 종료로 턴 대기 중단",
+			zap.String("runner_id", r.ID),
+			zap.String("task_id", req.TaskID),
+		)
+		// Stop이나 Abort로 닫힌 스트림은 호출자가 Task 상태를 처리
+		if r.aborted.Load() || r.eventCtx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("이벤트 스트림 종료로 턴을 완료하지 못함")
+	case <-ctx.Done():
+		return fmt.Errorf("턴 대기 중단: %w", ctx.Err())
+	}
+
+	// Abort로 중단된 턴은 보고하지 않음 (결과는 Abort 호출자가 처리)
+	if r.aborted.Load() {
+		r.logger.Info("중단된 턴 종료",
+			zap.String("runner_id", r.ID),
+			zap.String("task_id", req.TaskID),
+		)
+		return nil
+	}
+
+	if errInfo := r.turn.error(); errInfo != nil {
+		if errInfo.Message != "" {
+			return fmt.Errorf("세션 에러: %s: %s", errInfo.Code, errInfo.Message)
+		}
+		return fmt.Errorf("세션 에러: %s", errInfo.Code)
+	}
+
+	if r.callback != nil {
+		result := &RunResult{
+			Agent:   r.agentInfo.AgentID,
+			Name:    req.TaskID,
+			Success: true,
+			Output:  r.turn.text(),
+		}
+		if err := r.callback.OnComplete(req.TaskID, result); err != nil {
+			r.logger.Warn("OnComplete 콜백 실패", zap.Error(err))
+		}
+	}
 	return nil
 }
 
@@ -752,6 +961,22 @@ func (r *Runner) GetMessage(ctx context.Context, messageID string) (*struct {
 	return r.apiClient.GetMessage(ctx, r.sessionID, messageID)
 }
 
+// RespondPermission은 Runner 세션(또는 하위 세션)의 도구 권한 요청에 응답합니다.
+// sessionID가 비어 있으면 Runner의 루트 세션을 사용합니다.
+func (r *Runner) RespondPermission(ctx context.Context, sessionID, permissionID string, response opencode.PermissionResponse) error {
+	if r.apiClient == nil || r.sessionID == "" {
+		return fmt.Errorf("runner가 초기화되지 않음")
+	}
+	if sessionID == "" {
+		sessionID = r.sessionID
+	}
+	if !r.sessions.has(sessionID) {
+		return fmt.Errorf("runner가 소유하지 않은 세션입니다: %s", sessionID)
+	}
+
+	return r.apiClient.RespondPermission(ctx, sessionID, permissionID, response)
+}
+
 // parseModel은 "provider/model" 형식의 모델 문자열을 파싱합니다.
 func parseModel(model string) (providerID, modelID string) {
 	parts := strings.SplitN(model, "/", 2)
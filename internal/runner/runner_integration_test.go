@@ -53,24 +53,18 @@ func (m *mockCallback) OnStarted(taskID string, sessionID string) error {
 	return nil
 }
 
-func (m *mockCallback) OnEvent(taskID string, event *opencode.Event) error {
+func (m *mockCallback) OnEvent(taskID string, msg *opencode.RunnerMessage) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// text 파트에서 텍스트 추출
-	if event.Type == "message.part.updated" {
-		if props, ok := event.Properties["part"].(map[string]interface{}); ok {
-			if partType, _ := props["type"].(string); partType == "text" {
-				if text, ok := props["text"].(string); ok {
-					m.messages = append(m.messages, text)
-				}
-			}
-		}
+	if msg.Type == opencode.MessageTypeText {
+		m.messages = append(m.messages, msg.Content)
 	}
 
 	m.onEventCalled++
-	m.t.Logf("[Callback] OnEvent #%d: taskID=%s, eventType=%s",
-		m.onEventCalled, taskID, event.Type)
+	m.t.Logf("[Callback] OnEvent #%d: taskID=%s, messageType=%s",
+		m.onEventCalled, taskID, msg.Type)
 	return nil
 }
 
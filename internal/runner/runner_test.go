@@ -3,6 +3,7 @@ package taskrunner
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -24,6 +25,7 @@ type MockStatusCallback struct {
 	CompletedCalled  bool
 	ErrorCalled      bool
 	Events           []*opencode.Event
+	Messages         []*opencode.RunnerMessage
 	Result           *RunResult
 	Error            error
 	Done             chan struct{}
@@ -44,10 +46,13 @@ func (m *MockStatusCallback) OnStarted(taskID string, sessionID string) error {
 	return nil
 }
 
-func (m *MockStatusCallback) OnEvent(taskID string, event *opencode.Event) error {
+func (m *MockStatusCallback) OnEvent(taskID string, msg *opencode.RunnerMessage) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.Events = append(m.Events, event)
+	m.Messages = append(m.Messages, msg)
+	if msg.RawEvent != nil {
+		m.Events = append(m.Events, msg.RawEvent)
+	}
 	return nil
 }
 
@@ -257,3 +262,136 @@ func TestNewRunner_WithOptions(t *testing.T) {
 	assert.NotNil(t, runner)
 	assert.Equal(t, customClient, runner.httpClient)
 }
+
+// TestRunner_EventStream tests server.connected 대기와 재연결 실패 시 OnError 전달
+func TestRunner_EventStream(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections++
+		n := connections
+		mu.Unlock()
+
+		// 첫 연결만 성공시키고 이후 재연결은 모두 실패
+		if n > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("id: 1\ndata: {\"type\":\"server.connected\",\"properties\":{}}\n\n"))
+	}))
+	defer server.Close()
+
+	callback := NewMockStatusCallback()
+	runner, err := NewRunner("task-stream", AgentInfo{AgentID: "test-agent"}, callback, zaptest.NewLogger(t),
+		WithDockerClient(&fakeLogClient{}),
+		WithWorkspacePath(t.TempDir()),
+		WithEventStreamOptions(
+			opencode.WithReconnectBackoff(time.Millisecond, 5*time.Millisecond),
+			opencode.WithMaxReconnectAttempts(2),
+		),
+	)
+	require.NoError(t, err)
+	runner.apiClient = opencode.NewClient(server.URL)
+
+	runner.startEventStream()
+	require.NoError(t, runner.waitForEventStream(context.Background(), 5*time.Second))
+
+	select {
+	case <-callback.Done:
+		callback.mu.Lock()
+		defer callback.mu.Unlock()
+		assert.True(t, callback.ErrorCalled)
+		assert.ErrorIs(t, callback.Error, opencode.ErrReconnectExhausted)
+		require.NotEmpty(t, callback.Events)
+		assert.Equal(t, "server.connected", callback.Events[0].Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for OnError")
+	}
+}
+
+// TestRunner_EventStreamReconnecting tests 연결이 끊겼다가 복구되면 재연결 상태 변화를 한 번씩 알리는지 확인
+func TestRunner_EventStreamReconnecting(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections++
+		n := connections
+		mu.Unlock()
+
+		// 첫 연결은 바로 끊기고, 두세 번째 재연결은 실패한 뒤 네 번째에 복구
+		if n == 2 || n == 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(fmt.Sprintf("id: %d\ndata: {\"type\":\"server.connected\",\"properties\":{}}\n\n", n)))
+		if n > 1 {
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	callback := NewMockStatusCallback()
+	runner, err := NewRunner("task-stream-reconnect", AgentInfo{AgentID: "test-agent"}, callback, zaptest.NewLogger(t),
+		WithDockerClient(&fakeLogClient{}),
+		WithWorkspacePath(t.TempDir()),
+		WithEventStreamOptions(opencode.WithReconnectBackoff(time.Millisecond, 5*time.Millisecond)),
+	)
+	require.NoError(t, err)
+	runner.apiClient = opencode.NewClient(server.URL)
+
+	runner.startEventStream()
+	defer runner.eventCancel()
+	require.NoError(t, runner.waitForEventStream(context.Background(), 5*time.Second))
+
+	statuses := func() []string {
+		callback.mu.Lock()
+		defer callback.mu.Unlock()
+		var out []string
+		for _, msg := range callback.Messages {
+			if msg.Type == opencode.MessageTypeStreamStatus {
+				out = append(out, msg.Status)
+			}
+		}
+		return out
+	}
+	require.Eventually(t, func() bool { return len(statuses()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{opencode.StreamStatusReconnecting, opencode.StreamStatusConnected}, statuses())
+
+	callback.mu.Lock()
+	defer callback.mu.Unlock()
+	assert.False(t, callback.ErrorCalled)
+}
+
+// TestRunner_WaitForEventStream_Failure tests 스트림 연결 실패 시 Start 대기가 에러를 반환하는지 확인
+func TestRunner_WaitForEventStream_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	callback := NewMockStatusCallback()
+	runner, err := NewRunner("task-stream-fail", AgentInfo{AgentID: "test-agent"}, callback, zaptest.NewLogger(t),
+		WithDockerClient(&fakeLogClient{}),
+		WithWorkspacePath(t.TempDir()),
+		WithEventStreamOptions(
+			opencode.WithReconnectBackoff(time.Millisecond, 5*time.Millisecond),
+			opencode.WithMaxReconnectAttempts(1),
+		),
+	)
+	require.NoError(t, err)
+	runner.apiClient = opencode.NewClient(server.URL)
+
+	runner.startEventStream()
+	err = runner.waitForEventStream(context.Background(), 5*time.Second)
+	assert.ErrorIs(t, err, opencode.ErrReconnectExhausted)
+
+	// 연결된 적이 없으므로 OnError로 중복 보고하지 않음
+	callback.mu.Lock()
+	defer callback.mu.Unlock()
+	assert.False(t, callback.ErrorCalled)
+}
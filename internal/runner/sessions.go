@@ -0,0 +1,132 @@
+package taskrunner
+
+import (
+	"sync"
+
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"go.uber.org/zap"
+)
+
+// SessionOwners는 Runner가 소유하지 않은 세션의 이벤트를 다른 Runner가 소유하는지 확인합니다.
+// OpenCode 인스턴스를 여러 Task가 공유하면 각 Runner가 자체 이벤트 스트림으로 같은 이벤트를 모두 받으므로,
+// 다른 Runner 소유 세션의 이벤트는 넘겨주지 않고 버립니다 (넘기면 소유 Runner가 같은 이벤트를 두 번 처리함).
+type SessionOwners interface {
+	// OwnedByOther는 sourceID가 아닌 다른 Runner가 이벤트의 세션을 소유하면 true를 반환합니다.
+	OwnedByOther(sourceID string, event *opencode.Event) bool
+}
+
+// sessionRegistry는 Runner가 소유한 세션(루트 세션과 하위 세션) 목록입니다.
+type sessionRegistry struct {
+	mu  sync.RWMutex
+	ids map[string]struct{}
+}
+
+func newSessionRegistry() *sessionRegistry {
+	return &sessionRegistry{ids: make(map[string]struct{})}
+}
+
+func (s *sessionRegistry) add(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids[sessionID] = struct{}{}
+}
+
+func (s *sessionRegistry) has(sessionID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.ids[sessionID]
+	return ok
+}
+
+func (s *sessionRegistry) list() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]string, 0, len(s.ids))
+	for id := range s.ids {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (s *sessionRegistry) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids = make(map[string]struct{})
+}
+
+// WithSessionOwners는 다른 세션의 이벤트가 다른 Runner 소유인지 확인할 대상을 지정합니다(메트릭 분류용).
+func WithSessionOwners(owners SessionOwners) RunnerOption {
+	return func(r *Runner) {
+		r.owners = owners
+	}
+}
+
+// Sessions는 Runner가 소유한 세션 ID 목록(하위 세션 포함)을 반환합니다.
+func (r *Runner) Sessions() []string {
+	return r.sessions.list()
+}
+
+// wouldOwn은 이벤트가 이 Runner의 세션(또는 소유 세션의 하위 세션)에 속하는지 레지스트리를 바꾸지 않고 확인합니다.
+// 다른 Runner의 이벤트 루프에서 호출되므로 하위 세션 등록은 소유 Runner의 ownsEvent에 맡깁니다.
+func (r *Runner) wouldOwn(event *opencode.Event) bool {
+	sessionID := event.SessionID()
+	if sessionID == "" {
+		return false
+	}
+	if r.sessions.has(sessionID) {
+		return true
+	}
+	parentID := event.ParentSessionID()
+	return parentID != "" && r.sessions.has(parentID)
+}
+
+// ownsEvent는 이벤트가 이 Runner의 세션에 속하는지 확인합니다.
+// 소유 세션을 부모로 하는 하위 세션 생성 이벤트이면 하위 세션을 등록합니다.
+// 레지스트리를 바꾸므로 이 Runner의 이벤트 루프에서만 호출합니다.
+func (r *Runner) ownsEvent(event *opencode.Event) bool {
+	sessionID := event.SessionID()
+	if sessionID == "" {
+		return false
+	}
+	if r.sessions.has(sessionID) {
+		return true
+	}
+
+	if parentID := event.ParentSessionID(); parentID != "" && r.sessions.has(parentID) {
+		r.sessions.add(sessionID)
+		r.logger.Info("하위 세션 등록",
+			zap.String("runner_id", r.ID),
+			zap.String("session_id", sessionID),
+			zap.String("parent_id", parentID),
+		)
+		return true
+	}
+	return false
+}
+
+// routeEvent는 이벤트의 세션 ID에 따라 전달 여부를 결정합니다.
+// 세션 정보가 없는 인스턴스 이벤트는 그대로 전달하고, 다른 세션의 이벤트는 버립니다.
+// 다른 Runner 소유 세션의 이벤트는 그 Runner가 자체 스트림으로 받으므로 메트릭만 따로 집계합니다.
+func (r *Runner) routeEvent(event *opencode.Event) bool {
+	if event.SessionID() == "" {
+		GlobalMetrics.RecordEvent(EventRouteUnattributed)
+		return true
+	}
+	if r.ownsEvent(event) {
+		GlobalMetrics.RecordEvent(EventRouteDelivered)
+		return true
+	}
+
+	if r.owners != nil && r.owners.OwnedByOther(r.ID, event) {
+		GlobalMetrics.RecordEvent(EventRouteForeign)
+		return false
+	}
+
+	GlobalMetrics.RecordEvent(EventRouteDropped)
+	r.logger.Debug("다른 세션의 이벤트 무시",
+		zap.String("runner_id", r.ID),
+		zap.String("event_type", event.Type),
+		zap.String("session_id", event.SessionID()),
+	)
+	return false
+}
@@ -0,0 +1,108 @@
+package taskrunner
+
+import (
+	"testing"
+
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newSessionTestRunner(t *testing.T, taskID, sessionID string, owners SessionOwners) (*Runner, *MockStatusCallback) {
+	t.Helper()
+	callback := NewMockStatusCallback()
+	opts := []RunnerOption{WithDockerClient(&fakeLogClient{}), WithWorkspacePath(t.TempDir())}
+	if owners != nil {
+		opts = append(opts, WithSessionOwners(owners))
+	}
+	r, err := NewRunner(taskID, AgentInfo{AgentID: "agent"}, callback, zaptest.NewLogger(t), opts...)
+	require.NoError(t, err)
+	r.sessions.add(sessionID)
+	return r, callback
+}
+
+func partEvent(sessionID, text string) *opencode.Event {
+	return &opencode.Event{
+		Type: "message.part.updated",
+		Properties: map[string]interface{}{
+			"part": map[string]interface{}{"type": "text", "sessionID": sessionID, "text": text},
+		},
+	}
+}
+
+func TestRunner_SessionFiltering(t *testing.T) {
+	GlobalMetrics.Reset()
+	defer GlobalMetrics.Reset()
+
+	r, callback := newSessionTestRunner(t, "task-a", "ses_a", nil)
+
+	// 소유 세션, 인스턴스 이벤트, 다른 세션 이벤트
+	require.NoError(t, r.handleEvent(partEvent("ses_a", "mine")))
+	require.NoError(t, r.handleEvent(&opencode.Event{Type: "server.connected", Properties: map[string]interface{}{}}))
+	require.NoError(t, r.handleEvent(partEvent("ses_other", "foreign")))
+
+	// 하위 세션 생성 후 하위 세션 이벤트는 전달됨
+	require.NoError(t, r.handleEvent(&opencode.Event{
+		Type: "session.created",
+		Properties: map[string]interface{}{
+			"info": map[string]interface{}{"id": "ses_child", "parentID": "ses_a"},
+		},
+	}))
+	require.NoError(t, r.handleEvent(partEvent("ses_child", "child")))
+
+	assert.ElementsMatch(t, []string{"ses_a", "ses_child"}, r.Sessions())
+
+	types := make([]string, 0, len(callback.Events))
+	for _, e := range callback.Events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{"message.part.updated", "server.connected", "session.created", "message.part.updated"}, types)
+	assert.NotContains(t, callback.GetTextContent(), "foreign")
+
+	snapshot := GlobalMetrics.GetSnapshot()
+	assert.Equal(t, int64(3), snapshot.EventsDelivered)
+	assert.Equal(t, int64(1), snapshot.EventsUnattributed)
+	assert.Equal(t, int64(1), snapshot.EventsDropped)
+	assert.Equal(t, int64(0), snapshot.EventsForeign)
+}
+
+func TestRunnerManager_ForeignSessionEvents(t *testing.T) {
+	GlobalMetrics.Reset()
+	defer GlobalMetrics.Reset()
+
+	rm := &RunnerManager{runners: make(map[string]*Runner), logger: zaptest.NewLogger(t)}
+	a, callbackA := newSessionTestRunner(t, "task-a", "ses_a", rm)
+	b, callbackB := newSessionTestRunner(t, "task-b", "ses_b", rm)
+	rm.runners[a.ID] = a
+	rm.runners[b.ID] = b
+
+	// 같은 OpenCode 인스턴스를 공유하면 두 Runner의 스트림에 같은 이벤트가 들어옴
+	event := partEvent("ses_b", "for b")
+	require.NoError(t, a.handleEvent(event))
+	require.NoError(t, b.handleEvent(event))
+	// 아무도 소유하지 않은 세션 이벤트
+	require.NoError(t, a.handleEvent(partEvent("ses_x", "nobody")))
+
+	// b는 자체 스트림으로 한 번만 받고, a는 넘겨주지 않음
+	assert.Empty(t, callbackA.Events)
+	require.Len(t, callbackB.Events, 1)
+	assert.Equal(t, "for b", callbackB.GetTextContent())
+
+	// b의 하위 세션 생성 이벤트를 a가 먼저 받아도 b의 레지스트리는 바뀌지 않음
+	childCreated := &opencode.Event{
+		Type: "session.created",
+		Properties: map[string]interface{}{
+			"info": map[string]interface{}{"id": "ses_b_child", "parentID": "ses_b"},
+		},
+	}
+	require.NoError(t, a.handleEvent(childCreated))
+	assert.Equal(t, []string{"ses_b"}, b.Sessions())
+	require.NoError(t, b.handleEvent(childCreated))
+	assert.ElementsMatch(t, []string{"ses_b", "ses_b_child"}, b.Sessions())
+
+	snapshot := GlobalMetrics.GetSnapshot()
+	assert.Equal(t, int64(2), snapshot.EventsDelivered)
+	assert.Equal(t, int64(2), snapshot.EventsForeign)
+	assert.Equal(t, int64(1), snapshot.EventsDropped)
+}
@@ -0,0 +1,162 @@
+package taskrunner
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/cnap-oss/app/internal/runner/opencode"
+)
+
+// turnOutput은 현재 턴에서 생성된 어시스턴트 텍스트 파트와 세션 에러를 모읍니다.
+type turnOutput struct {
+	mu        sync.Mutex
+	assistant map[string]struct{} // 어시스턴트 메시지 ID
+	order     []string            // 파트 ID 수신 순서
+	parts     map[string]string   // 파트 ID -> 텍스트
+	err       *opencode.MessageErrorInfo
+}
+
+func newTurnOutput() *turnOutput {
+	t := &turnOutput{}
+	t.reset()
+	return t
+}
+
+func (t *turnOutput) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.assistant = make(map[string]struct{})
+	t.order = nil
+	t.parts = make(map[string]string)
+	t.err = nil
+}
+
+// observe는 디코딩된 이벤트에서 어시스턴트 메시지, 텍스트 파트, 루트 세션 에러를 기록합니다.
+func (t *turnOutput) observe(msg *opencode.RunnerMessage, rootSessionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch msg.Type {
+	case opencode.MessageTypeMessageUpdated:
+		if m, ok := msg.Message.(opencode.AssistantMessage); ok {
+			t.assistant[m.ID] = struct{}{}
+			if msg.Error != nil && m.SessionID == rootSessionID {
+				t.err = msg.Error
+			}
+		}
+	case opencode.MessageTypeText:
+		if _, ok := t.assistant[msg.MessageID]; !ok || msg.PartID == "" {
+			return
+		}
+		text, seen := t.parts[msg.PartID]
+		if !seen {
+			t.order = append(t.order, msg.PartID)
+		}
+		if msg.Content != "" {
+			text = msg.Content
+		} else {
+			text += msg.Delta
+		}
+		t.parts[msg.PartID] = text
+	case opencode.MessageTypeError:
+		if msg.SessionID == "" || msg.SessionID == rootSessionID {
+			t.err = msg.Error
+		}
+	}
+}
+
+func (t *turnOutput) text() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	texts := make([]string, 0, len(t.order))
+	for _, id := range t.order {
+		if text := t.parts[id]; text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+func (t *turnOutput) error() *opencode.MessageErrorInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// PartialOutput은 현재(또는 마지막) 턴에서 지금까지 생성된 어시스턴트 텍스트를 반환합니다.
+func (r *Runner) PartialOutput() string {
+	return r.turn.text()
+}
+
+// beginTurn은 새 턴을 시작하고 턴 종료 시 닫히는 채널을 반환합니다.
+func (r *Runner) beginTurn() chan struct{} {
+	r.turn.reset()
+	r.aborted.Store(false)
+
+	// 이전 턴에서 남은 idle 신호 제거
+	select {
+	case <-r.idle:
+	default:
+	}
+
+	done := make(chan struct{})
+	r.turnMu.Lock()
+	r.turnDone = done
+	r.turnMu.Unlock()
+	r.inTurn.Store(true)
+	return done
+}
+
+// endTurn은 턴을 종료하고 Abort 대기자를 깨웁니다.
+func (r *Runner) endTurn(done chan struct{}) {
+	r.inTurn.Store(false)
+	close(done)
+}
+
+// currentTurnDone은 진행 중인 턴의 종료 채널을 반환합니다.
+func (r *Runner) currentTurnDone() chan struct{} {
+	r.turnMu.Lock()
+	defer r.turnMu.Unlock()
+	return r.turnDone
+}
+
+// WaitTurn은 진행 중인 턴이 끝날 때까지 기다립니다. 진행 중인 턴이 없으면 바로 반환합니다.
+// 턴은 OnComplete/OnError 콜백 호출 뒤에 끝나므로, 반환 시점에는 턴 결과가 이미 반영되어 있습니다.
+func (r *Runner) WaitTurn(ctx context.Context) error {
+	done := r.currentTurnDone()
+	if done == nil {
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// signalIdle은 루트 세션이 idle 또는 aborted 상태가 되었음을 진행 중인 턴에 알립니다.
+// OpenCode는 턴이 끝나면 session.status(idle)와 session.idle을 연달아 보내므로,
+// 늦게 도착한 두 번째 신호가 다음 턴을 끝내지 않도록 루트 세션 활동이 있은 뒤의 첫 신호만 전달합니다.
+func (r *Runner) signalIdle(msg *opencode.RunnerMessage) {
+	if msg.SessionID == "" || msg.SessionID != r.sessionID {
+		return
+	}
+	switch {
+	case msg.Type == opencode.MessageTypeIdle,
+		msg.Type == opencode.MessageTypeSessionAborted,
+		msg.Type == opencode.MessageTypeStatus && msg.Status == "idle":
+	default:
+		r.idleSent.Store(false)
+		return
+	}
+	if !r.idleSent.CompareAndSwap(false, true) {
+		return
+	}
+
+	select {
+	case r.idle <- struct{}{}:
+	default:
+	}
+}
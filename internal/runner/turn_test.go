@@ -0,0 +1,266 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/runner/opencode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// startTurnTestRunner는 prompt_async 요청 시 promptEvents를 SSE로 흘려보내는 서버에 연결된 Runner를 생성합니다.
+func startTurnTestRunner(t *testing.T, promptEvents []string) (*Runner, *MockStatusCallback) {
+	t.Helper()
+	events := make(chan string, len(promptEvents)+1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/event":
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "data: {\"type\":\"server.connected\",\"properties\":{}}\n\n")
+			w.(http.Flusher).Flush()
+			for {
+				select {
+				case data := <-events:
+					_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
+					w.(http.Flusher).Flush()
+				case <-r.Context().Done():
+					return
+				}
+			}
+		case "/session/ses_1/prompt_async":
+			for _, data := range promptEvents {
+				events <- data
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	callback := NewMockStatusCallback()
+	runner, err := NewRunner("task-turn", AgentInfo{AgentID: "test-agent"}, callback, zaptest.NewLogger(t),
+		WithDockerClient(&fakeLogClient{}),
+		WithWorkspacePath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	runner.apiClient = opencode.NewClient(server.URL)
+	runner.sessionID = "ses_1"
+	runner.sessions.add("ses_1")
+	runner.Status = RunnerStatusReady
+
+	runner.startEventStream()
+	require.NoError(t, runner.waitForEventStream(context.Background(), 5*time.Second))
+	t.Cleanup(runner.eventCancel)
+
+	require.NoError(t, runner.Run(context.Background(), &RunRequest{
+		TaskID:   "task-turn",
+		Model:    "test/model",
+		Messages: []opencode.ChatMessage{{Role: "user", Content: "hello"}},
+	}))
+	return runner, callback
+}
+
+// TestRunner_TurnCompletesOnIdle은 루트 세션 idle 이벤트로 턴이 끝나고 조립된 어시스턴트 메시지로 OnComplete가 호출되는지 확인합니다.
+func TestRunner_TurnCompletesOnIdle(t *testing.T) {
+	_, callback := startTurnTestRunner(t, []string{
+		`{"type":"message.updated","properties":{"info":{"id":"msg_u","sessionID":"ses_1","role":"user"}}}`,
+		`{"type":"message.part.updated","properties":{"part":{"id":"prt_u","sessionID":"ses_1","messageID":"msg_u","type":"text","text":"hello"}}}`,
+		`{"type":"message.updated","properties":{"info":{"id":"msg_a","sessionID":"ses_1","role":"assistant"}}}`,
+		`{"type":"message.part.updated","properties":{"part":{"id":"prt_1","sessionID":"ses_1","messageID":"msg_a","type":"text","text":"first"}}}`,
+		`{"type":"message.part.updated","properties":{"part":{"id":"prt_2","sessionID":"ses_1","messageID":"msg_a","type":"text","text":"second"}}}`,
+		`{"type":"session.idle","properties":{"sessionID":"ses_1"}}`,
+	})
+
+	select {
+	case <-callback.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for turn completion")
+	}
+
+	callback.mu.Lock()
+	defer callback.mu.Unlock()
+	require.True(t, callback.CompletedCalled)
+	assert.False(t, callback.ErrorCalled)
+	assert.True(t, callback.Result.Success)
+	assert.Equal(t, "first\n\nsecond", callback.Result.Output)
+}
+
+// TestRunner_TurnReportsSessionError는 루트 세션 에러로 끝난 턴이 OnError로 보고되는지 확인합니다.
+func TestRunner_TurnReportsSessionError(t *testing.T) {
+	_, callback := startTurnTestRunner(t, []string{
+		`{"type":"message.updated","properties":{"info":{"id":"msg_a","sessionID":"ses_1","role":"assistant","error":{"name":"ProviderAuthError","data":{"message":"invalid key"}}}}}`,
+		`{"type":"session.idle","properties":{"sessionID":"ses_1"}}`,
+	})
+
+	select {
+	case <-callback.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for turn error")
+	}
+
+	callback.mu.Lock()
+	defer callback.mu.Unlock()
+	assert.False(t, callback.CompletedCalled)
+	require.True(t, callback.ErrorCalled)
+	assert.Contains(t, callback.Error.Error(), "invalid key")
+}
+
+// TestRunner_RunRejectsConcurrentTurn은 턴 진행 중의 Run이 첫 턴의 출력을 초기화하지 않고 거부되는지 확인합니다.
+func TestRunner_RunRejectsConcurrentTurn(t *testing.T) {
+	runner, callback := startTurnTestRunner(t, []string{
+		`{"type":"message.updated","properties":{"info":{"id":"msg_a","sessionID":"ses_1","role":"assistant"}}}`,
+		`{"type":"message.part.updated","properties":{"part":{"id":"prt_1","sessionID":"ses_1","messageID":"msg_a","type":"text","text":"first"}}}`,
+	})
+	require.Eventually(t, func() bool { return runner.PartialOutput() == "first" }, 5*time.Second, 10*time.Millisecond)
+
+	err := runner.Run(context.Background(), &RunRequest{
+		TaskID:   "task-turn",
+		Messages: []opencode.ChatMessage{{Role: "user", Content: "again"}},
+	})
+	require.ErrorIs(t, err, ErrTurnInProgress)
+	assert.Equal(t, "first", runner.PartialOutput())
+
+	runner.signalIdle(&opencode.RunnerMessage{Type: opencode.MessageTypeIdle, SessionID: "ses_1"})
+	select {
+	case <-callback.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for turn completion")
+	}
+
+	callback.mu.Lock()
+	defer callback.mu.Unlock()
+	require.True(t, callback.CompletedCalled)
+	assert.Equal(t, "first", callback.Result.Output)
+}
+
+// TestRunner_WaitTurnReturnsAfterCallback은 WaitTurn이 턴 결과 콜백이 호출된 뒤에 반환되는지 확인합니다.
+func TestRunner_WaitTurnReturnsAfterCallback(t *testing.T) {
+	runner, callback := startTurnTestRunner(t, []string{
+		`{"type":"message.updated","properties":{"info":{"id":"msg_a","sessionID":"ses_1","role":"assistant"}}}`,
+		`{"type":"message.part.updated","properties":{"part":{"id":"prt_1","sessionID":"ses_1","messageID":"msg_a","type":"text","text":"done"}}}`,
+		`{"type":"session.idle","properties":{"sessionID":"ses_1"}}`,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, runner.WaitTurn(ctx))
+
+	callback.mu.Lock()
+	assert.True(t, callback.CompletedCalled)
+	callback.mu.Unlock()
+	assert.False(t, runner.inTurn.Load())
+
+	// 진행 중인 턴이 없으면 바로 반환
+	require.NoError(t, runner.WaitTurn(ctx))
+}
+
+// TestRunner_LateIdleDoesNotEndNextTurn은 이전 턴 끝에 연달아 온 idle 신호가 다음 턴을 끝내지 않는지 확인합니다.
+func TestRunner_LateIdleDoesNotEndNextTurn(t *testing.T) {
+	runner, err := NewRunner("task-idle", AgentInfo{AgentID: "test-agent"}, NewMockStatusCallback(), zaptest.NewLogger(t),
+		WithDockerClient(&fakeLogClient{}),
+		WithWorkspacePath(t.TempDir()),
+	)
+	require.NoError(t, err)
+	runner.sessionID = "ses_1"
+
+	statusIdle := &opencode.RunnerMessage{Type: opencode.MessageTypeStatus, SessionID: "ses_1", Status: "idle"}
+	sessionIdle := &opencode.RunnerMessage{Type: opencode.MessageTypeIdle, SessionID: "ses_1"}
+	activity := &opencode.RunnerMessage{Type: opencode.MessageTypeStatus, SessionID: "ses_1", Status: "busy"}
+
+	// 첫 번째 턴은 session.status(idle)로 끝남
+	done := runner.beginTurn()
+	runner.signalIdle(statusIdle)
+	require.Len(t, runner.idle, 1)
+	<-runner.idle
+	runner.endTurn(done)
+
+	// 다음 턴이 시작된 뒤 도착한 session.idle은 무시됨
+	done = runner.beginTurn()
+	defer runner.endTurn(done)
+	runner.signalIdle(sessionIdle)
+	assert.Len(t, runner.idle, 0)
+
+	// 루트 세션 활동 이후의 idle은 턴을 끝냄
+	runner.signalIdle(activity)
+	runner.signalIdle(sessionIdle)
+	assert.Len(t, runner.idle, 1)
+}
+
+// TestRunner_TurnReportsStreamEnd는 턴 도중 이벤트 스트림이 끝나면 OnError가 한 번만 호출되는지 확인합니다.
+func TestRunner_TurnReportsStreamEnd(t *testing.T) {
+	var once sync.Once
+	dropStream := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/event":
+			select {
+			case <-dropStream:
+				// 재연결은 실패
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			default:
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "data: {\"type\":\"server.connected\",\"properties\":{}}\n\n")
+			w.(http.Flusher).Flush()
+			select {
+			case <-dropStream:
+			case <-r.Context().Done():
+			}
+		case "/session/ses_1/prompt_async":
+			once.Do(func() { close(dropStream) })
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	callback := NewMockStatusCallback()
+	runner, err := NewRunner("task-stream-end", AgentInfo{AgentID: "test-agent"}, callback, zaptest.NewLogger(t),
+		WithDockerClient(&fakeLogClient{}),
+		WithWorkspacePath(t.TempDir()),
+		WithEventStreamOptions(
+			opencode.WithReconnectBackoff(time.Millisecond, 5*time.Millisecond),
+			opencode.WithMaxReconnectAttempts(2),
+		),
+	)
+	require.NoError(t, err)
+	runner.apiClient = opencode.NewClient(server.URL)
+	runner.sessionID = "ses_1"
+	runner.Status = RunnerStatusReady
+
+	runner.startEventStream()
+	defer runner.eventCancel()
+	require.NoError(t, runner.waitForEventStream(context.Background(), 5*time.Second))
+
+	require.NoError(t, runner.Run(context.Background(), &RunRequest{
+		TaskID:   "task-stream-end",
+		Model:    "test/model",
+		Messages: []opencode.ChatMessage{{Role: "user", Content: "hello"}},
+	}))
+
+	select {
+	case <-callback.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for turn error")
+	}
+	<-runner.streamDone
+	// 스트림 고루틴이 같은 Task를 다시 보고하면 Done이 두 번 닫혀 panic
+	time.Sleep(50 * time.Millisecond)
+
+	callback.mu.Lock()
+	defer callback.mu.Unlock()
+	assert.False(t, callback.CompletedCalled)
+	require.True(t, callback.ErrorCalled)
+	assert.Contains(t, callback.Error.Error(), "이벤트 스트림")
+}
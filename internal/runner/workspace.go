@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/cnap-oss/app/internal/common"
 	"go.uber.org/zap"
@@ -234,12 +237,141 @@ func (wm *workspaceManager) ListWorkspaces(ctx context.Context) ([]*Workspace, e
 }
 
 // CleanupStaleWorkspaces implements WorkspaceManager.
+// 작업 공간 안의 파일이 maxAgeDays 동안 하나도 수정되지 않았으면 삭제합니다. maxAgeDays가 0 이하이면 아무것도 하지 않습니다.
 func (wm *workspaceManager) CleanupStaleWorkspaces(ctx context.Context, maxAgeDays int) error {
-	// TODO: 구현 - 오래된 작업 공간 정리
-	// 마지막 수정 시간 기준으로 정리
+	if maxAgeDays <= 0 {
+		return nil
+	}
+	workspaces, err := wm.ListWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -maxAgeDays)
+	for _, ws := range workspaces {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		modified, err := WorkspaceLastModified(ws)
+		if err != nil {
+			wm.logger.Warn("작업 공간 수정 시각 확인 실패",
+				zap.String("agent_id", ws.AgentID),
+				zap.Error(err),
+			)
+			continue
+		}
+		if modified.After(cutoff) {
+			continue
+		}
+		if err := wm.DeleteWorkspace(ctx, ws.AgentID, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WorkspaceLastModified는 작업 공간 안의 파일과 디렉토리 중 가장 최근 수정 시각을 반환합니다.
+func WorkspaceLastModified(ws *Workspace) (time.Time, error) {
+	var latest time.Time
+	err := filepath.WalkDir(ws.BasePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest, err
+}
+
+// taskDataDirs는 Task 실행 중에 쌓이는 산출물 디렉토리입니다. .opencode의 에이전트 설정은 포함하지 않습니다.
+func taskDataDirs(ws *Workspace) []string {
+	return []string{ws.ProjectDir, ws.LogDir}
+}
+
+// WorkspaceHasTaskData는 project, logs 디렉토리에 정리할 항목이 있는지 반환합니다.
+// keep은 정리하지 않을 작업 공간 기준 상대 경로(시드 파일 등)입니다.
+func WorkspaceHasTaskData(ws *Workspace, keep []string) (bool, error) {
+	paths, err := taskDataPaths(ws, keep)
+	if err != nil {
+		return false, err
+	}
+	return len(paths) > 0, nil
+}
+
+// ClearWorkspaceTaskData는 project, logs 디렉토리의 내용만 삭제합니다.
+// 디렉토리 자체와 .opencode의 설정(config.json, mcp.json, 시드 파일 목록), keep에 있는 경로는 유지합니다.
+func ClearWorkspaceTaskData(ws *Workspace, keep []string) error {
+	paths, err := taskDataPaths(ws, keep)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("작업 공간 정리 실패: %w", err)
+		}
+	}
 	return nil
 }
 
+// taskDataPaths는 project, logs 디렉토리에서 삭제할 항목의 경로를 반환합니다.
+// keep 경로와, keep 경로를 담고 있는 디렉토리는 안쪽 항목만 확인합니다.
+func taskDataPaths(ws *Workspace, keep []string) ([]string, error) {
+	kept := make(map[string]bool, len(keep))
+	for _, rel := range keep {
+		kept[filepath.Clean(filepath.FromSlash(rel))] = true
+	}
+	containsKept := func(rel string) bool {
+		prefix := rel + string(filepath.Separator)
+		for k := range kept {
+			if strings.HasPrefix(k, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var paths []string
+	var visit func(dir string) error
+	visit = func(dir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("디렉토리 읽기 실패: %w", err)
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			rel, err := filepath.Rel(ws.BasePath, path)
+			if err != nil {
+				return err
+			}
+			switch {
+			case kept[rel]:
+			case entry.IsDir() && containsKept(rel):
+				if err := visit(path); err != nil {
+					return err
+				}
+			default:
+				paths = append(paths, path)
+			}
+		}
+		return nil
+	}
+	for _, dir := range taskDataDirs(ws) {
+		if err := visit(dir); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
 // initializeConfigs는 기본 설정 파일을 복사합니다.
 func (wm *workspaceManager) initializeConfigs(ws *Workspace) error {
 	// 기본 OpenCode 설정 복사
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AuditQuery는 감사 기록 조회 조건입니다. 비어 있는 조건은 적용하지 않습니다.
+type AuditQuery struct {
+	Actor      string
+	Action     string // 정확히 일치하거나 "agent"처럼 대상 종류만 지정하면 agent.* 전체
+	TargetType string
+	TargetID   string
+	Since      time.Time
+	Limit      int // 0 이하이면 기본값 50
+}
+
+// AppendAuditEvent는 감사 기록을 추가합니다.
+func (r *Repository) AppendAuditEvent(ctx context.Context, event *AuditEvent) error {
+	if event.Action == "" {
+		return fmt.Errorf("storage: empty audit action")
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// QueryAuditEvents는 조건에 맞는 감사 기록을 최신순으로 반환합니다.
+func (r *Repository) QueryAuditEvents(ctx context.Context, q AuditQuery) ([]AuditEvent, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := r.db.WithContext(ctx).Model(&AuditEvent{})
+	if q.Actor != "" {
+		query = query.Where("actor = ?", q.Actor)
+	}
+	if q.Action != "" {
+		query = query.Where("(action = ? OR action LIKE ?)", q.Action, q.Action+".%")
+	}
+	if q.TargetType != "" {
+		query = query.Where("target_type = ?", q.TargetType)
+	}
+	if q.TargetID != "" {
+		query = query.Where("target_id = ?", q.TargetID)
+	}
+	if !q.Since.IsZero() {
+		query = query.Where("created_at >= ?", q.Since)
+	}
+
+	var events []AuditEvent
+	if err := query.Order("created_at DESC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,710 @@
+package storage
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// BackupFormatVersion은 백업 아카이브 형식 버전입니다. 아카이브 구조나 테이블 직렬화 방식이 바뀌면 올립니다.
+const BackupFormatVersion = 1
+
+// 백업 아카이브 안의 최상위 디렉토리
+const (
+	BackupSectionDB         = "db"
+	BackupSectionMessages   = "messages"
+	BackupSectionConfigs    = "configs"
+	BackupSectionWorkspaces = "workspaces"
+)
+
+const (
+	backupManifestName  = "manifest.json"
+	backupRestoreBatch  = 500
+	backupTableFileExt  = ".jsonl"
+	backupPreRestoreTag = ".pre-restore-"
+)
+
+// BackupManifest는 아카이브 마지막에 기록되는 백업 정보입니다.
+type BackupManifest struct {
+	FormatVersion int            `json:"format_version"`
+	AppVersion    string         `json:"app_version"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Dialect       string         `json:"dialect"`  // 백업한 DB 종류 (sqlite, postgres)
+	Tables        map[string]int `json:"tables"`   // 테이블별 행 수
+	Sections      []string       `json:"sections"` // 포함된 파일 디렉토리 (messages, configs, workspaces)
+	Files         []BackupFile   `json:"files"`
+}
+
+// BackupFile은 아카이브에 포함된 파일의 크기와 체크섬입니다.
+type BackupFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupDirs는 백업에 포함하거나 복원할 디렉토리입니다. 빈 값인 디렉토리는 건너뜁니다.
+type BackupDirs struct {
+	Messages   string
+	Configs    string
+	Workspaces string
+}
+
+// sections는 설정된 디렉토리를 아카이브 섹션 순서대로 반환합니다.
+func (d BackupDirs) sections() [][2]string {
+	var out [][2]string
+	for _, s := range [][2]string{
+		{BackupSectionMessages, d.Messages},
+		{BackupSectionConfigs, d.Configs},
+		{BackupSectionWorkspaces, d.Workspaces},
+	} {
+		if s[1] != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// BackupRestoreResult는 복원 결과입니다.
+type BackupRestoreResult struct {
+	Manifest *BackupManifest
+	// Previous는 복원 전에 있던 디렉토리를 옮겨 둔 경로입니다. 복원을 확인한 뒤 직접 삭제합니다.
+	Previous []string
+}
+
+// backupTable은 테이블 하나를 JSONL로 덤프하고 복원하는 방법입니다.
+type backupTable struct {
+	name    string
+	model   any
+	dump    func(tx *gorm.DB, enc *json.Encoder) (int, error)
+	restore func(tx *gorm.DB, dec *json.Decoder) (int, error)
+}
+
+// newBackupTable은 모델 T의 테이블을 행 단위 JSON으로 덤프하고 복원합니다.
+// 논리 덤프이므로 SQLite 백업을 PostgreSQL로 복원하는 것처럼 DB 종류가 달라도 복원할 수 있습니다.
+func newBackupTable[T schema.Tabler]() backupTable {
+	var zero T
+	return backupTable{
+		name:  zero.TableName(),
+		model: new(T),
+		dump: func(tx *gorm.DB, enc *json.Encoder) (int, error) {
+			rows, err := tx.Model(new(T)).Rows()
+			if err != nil {
+				return 0, err
+			}
+			defer rows.Close()
+
+			n := 0
+			for rows.Next() {
+				var row T
+				if err := tx.ScanRows(rows, &row); err != nil {
+					return n, err
+				}
+				if err := enc.Encode(&row); err != nil {
+					return n, err
+				}
+				n++
+			}
+			return n, rows.Err()
+		},
+		restore: func(tx *gorm.DB, dec *json.Decoder) (int, error) {
+			n := 0
+			batch := make([]T, 0, backupRestoreBatch)
+			flush := func() error {
+				if len(batch) == 0 {
+					return nil
+				}
+				if err := tx.CreateInBatches(batch, backupRestoreBatch).Error; err != nil {
+					return err
+				}
+				n += len(batch)
+				batch = batch[:0]
+				return nil
+			}
+			for {
+				var row T
+				if err := dec.Decode(&row); errors.Is(err, io.EOF) {
+					break
+				} else if err != nil {
+					return n, err
+				}
+				batch = append(batch, row)
+				if len(batch) == backupRestoreBatch {
+					if err := flush(); err != nil {
+						return n, err
+					}
+				}
+			}
+			return n, flush()
+		},
+	}
+}
+
+// backupTables는 백업 대상 테이블입니다. AutoMigrate에 모델을 추가하면 여기에도 추가합니다.
+// 검색 색인(msg_search)은 메시지에서 다시 만들 수 있으므로 포함하지 않습니다.
+var backupTables = []backupTable{
+	newBackupTable[Agent](),
+	newBackupTable[Task](),
+	newBackupTable[MessageIndex](),
+	newBackupTable[MessageCounter](),
+	newBackupTable[MessageBlob](),
+	newBackupTable[RunStep](),
+	newBackupTable[TaskEvent](),
+	newBackupTable[TaskEventCounter](),
+	newBackupTable[ProcessedEvent](),
+	newBackupTable[Checkpoint](),
+	newBackupTable[PrivacyDeletion](),
+	newBackupTable[AuditEvent](),
+	newBackupTable[RoleBinding](),
+}
+
+func findBackupTable(name string) (backupTable, bool) {
+	for _, t := range backupTables {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return backupTable{}, false
+}
+
+// backupTxOptions는 덤프 트랜잭션 옵션입니다. PostgreSQL은 트랜잭션 전체에서 같은 스냅샷을 보도록 REPEATABLE READ를 사용합니다.
+func backupTxOptions(db *gorm.DB) []*sql.TxOptions {
+	if isSQLite(db) {
+		return nil
+	}
+	return []*sql.TxOptions{{Isolation: sql.LevelRepeatableRead}}
+}
+
+// quiesceWrites는 덤프하는 동안 다른 프로세스의 쓰기를 멈춥니다.
+// PostgreSQL은 SHARE 잠금으로 쓰기를 트랜잭션 종료까지 대기시킵니다.
+// SQLite는 쓰기를 멈추지 않습니다. 대신 sqliteSnapshot으로 만든 사본을 덤프하므로 아무 작업도 하지 않습니다.
+func quiesceWrites(tx *gorm.DB) error {
+	if isSQLite(tx) {
+		return nil
+	}
+	names := make([]string, 0, len(backupTables))
+	for _, t := range backupTables {
+		names = append(names, t.name)
+	}
+	if err := tx.Exec("LOCK TABLE " + strings.Join(names, ", ") + " IN SHARE MODE").Error; err != nil {
+		return fmt.Errorf("storage: lock tables for backup: %w", err)
+	}
+	return nil
+}
+
+// sqliteSnapshot은 VACUUM INTO로 SQLite DB의 일관된 사본을 임시 파일에 만들고 엽니다.
+// 사본을 만드는 동안만 쓰기가 대기하고, 덤프는 사본에서 읽으므로 서버의 쓰기를 오래 막지 않습니다.
+func sqliteSnapshot(ctx context.Context, db *gorm.DB) (*gorm.DB, func(), error) {
+	dir, err := os.MkdirTemp("", "cnap-backup-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: create snapshot directory: %w", err)
+	}
+	removeDir := func() { _ = os.RemoveAll(dir) }
+
+	path := filepath.Join(dir, "snapshot.db")
+	if err := db.WithContext(ctx).Exec("VACUUM INTO ?", path).Error; err != nil {
+		removeDir()
+		return nil, nil, fmt.Errorf("storage: snapshot sqlite database: %w", err)
+	}
+	snap, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: db.Logger})
+	if err != nil {
+		removeDir()
+		return nil, nil, fmt.Errorf("storage: open sqlite snapshot: %w", err)
+	}
+	return snap, func() {
+		if sqlDB, err := snap.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+		removeDir()
+	}, nil
+}
+
+// backupArchiveWriter는 tar 항목을 쓰면서 파일별 체크섬을 기록합니다.
+type backupArchiveWriter struct {
+	tw    *tar.Writer
+	files []BackupFile
+}
+
+func (a *backupArchiveWriter) add(name string, mode int64, modTime time.Time, size int64, r io.Reader) error {
+	hdr := &tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: mode, Size: size, ModTime: modTime}
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(a.tw, h), io.LimitReader(r, size))
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("storage: %s changed during backup", name)
+	}
+	a.files = append(a.files, BackupFile{Path: name, Size: size, SHA256: hex.EncodeToString(h.Sum(nil))})
+	return nil
+}
+
+func (a *backupArchiveWriter) addFile(name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return a.add(name, int64(info.Mode().Perm()), info.ModTime(), info.Size(), f)
+}
+
+// addDir은 디렉토리의 일반 파일과 하위 디렉토리를 section 아래에 추가합니다. 디렉토리가 없으면 건너뜁니다.
+// 심볼릭 링크와 소켓 등 특수 파일과 메시지 잠금 파일은 포함하지 않습니다.
+func (a *backupArchiveWriter) addDir(section, root string) error {
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		name := section + "/" + filepath.ToSlash(rel)
+
+		switch {
+		case d.IsDir():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return a.tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: name + "/", Mode: int64(info.Mode().Perm()), ModTime: info.ModTime()})
+		case d.Type().IsRegular():
+			if section == BackupSectionMessages && rel == messageLockName {
+				return nil
+			}
+			return a.addFile(name, p)
+		default:
+			return nil
+		}
+	})
+}
+
+// addTable은 테이블을 임시 파일에 덤프한 뒤 아카이브에 추가합니다. tar 헤더에 크기가 먼저 필요하기 때문입니다.
+func (a *backupArchiveWriter) addTable(tx *gorm.DB, table backupTable) (int, error) {
+	tmp, err := os.CreateTemp("", "cnap-backup-"+table.name+"-*")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	n, err := table.dump(tx, json.NewEncoder(tmp))
+	if err != nil {
+		return 0, fmt.Errorf("storage: dump %s: %w", table.name, err)
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return n, a.add(BackupSectionDB+"/"+table.name+backupTableFileExt, 0644, time.Now(), size, tmp)
+}
+
+// writeConsistent는 DB 덤프와 메시지, 설정 파일을 기록합니다.
+// 메시지 디렉토리 배타 잠금으로 FileMessageStore의 쓰기를 멈추고, DB는 PostgreSQL이면 테이블 잠금을,
+// SQLite면 스냅샷 사본을 사용하여 파일과 같은 시점을 덤프합니다.
+func (a *backupArchiveWriter) writeConsistent(ctx context.Context, db *gorm.DB, dirs BackupDirs, manifest *BackupManifest) error {
+	if dirs.Messages != "" {
+		unlock, err := lockMessageDir(dirs.Messages, true)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	src := db
+	if isSQLite(db) {
+		snap, cleanup, err := sqliteSnapshot(ctx, db)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		src = snap
+	}
+
+	return src.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := quiesceWrites(tx); err != nil {
+			return err
+		}
+		for _, table := range backupTables {
+			n, err := a.addTable(tx, table)
+			if err != nil {
+				return err
+			}
+			manifest.Tables[table.name] = n
+		}
+		for _, s := range dirs.sections() {
+			if s[0] == BackupSectionWorkspaces {
+				continue
+			}
+			if err := a.addDir(s[0], s[1]); err != nil {
+				return fmt.Errorf("storage: backup %s: %w", s[0], err)
+			}
+			manifest.Sections = append(manifest.Sections, s[0])
+		}
+		return nil
+	}, backupTxOptions(src)...)
+}
+
+// CreateBackup은 DB, 메시지, 설정, 작업 공간을 tar.zst 아카이브로 씁니다.
+// DB 덤프와 메시지, 설정 파일은 writeConsistent에서 메시지 쓰기를 멈춘 채 기록하여 서로 일치합니다.
+// 작업 공간은 크기가 클 수 있어 잠금을 푼 뒤 기록합니다.
+func CreateBackup(ctx context.Context, db *gorm.DB, w io.Writer, dirs BackupDirs, appVersion string) (*BackupManifest, error) {
+	if db == nil {
+		return nil, fmt.Errorf("storage: nil database handle")
+	}
+
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return nil, err
+	}
+	aw := &backupArchiveWriter{tw: tar.NewWriter(zw)}
+	manifest := &BackupManifest{
+		FormatVersion: BackupFormatVersion,
+		AppVersion:    appVersion,
+		CreatedAt:     time.Now().UTC(),
+		Dialect:       db.Dialector.Name(),
+		Tables:        make(map[string]int, len(backupTables)),
+	}
+
+	if err := aw.writeConsistent(ctx, db, dirs, manifest); err != nil {
+		_ = zw.Close()
+		return nil, err
+	}
+
+	if dirs.Workspaces != "" {
+		if err := aw.addDir(BackupSectionWorkspaces, dirs.Workspaces); err != nil {
+			return nil, fmt.Errorf("storage: backup %s: %w", BackupSectionWorkspaces, err)
+		}
+		manifest.Sections = append(manifest.Sections, BackupSectionWorkspaces)
+	}
+
+	manifest.Files = aw.files
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := aw.tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: backupManifestName, Mode: 0644, Size: int64(len(data)), ModTime: manifest.CreatedAt}); err != nil {
+		return nil, err
+	}
+	if _, err := aw.tw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := aw.tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// cleanBackupEntry는 아카이브 항목 이름을 검증하여 정리된 상대 경로를 반환합니다.
+func cleanBackupEntry(name string) (string, error) {
+	clean := path.Clean(strings.TrimSuffix(name, "/"))
+	if path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("storage: invalid backup entry %q", name)
+	}
+	return clean, nil
+}
+
+// VerifyBackup은 아카이브 전체를 읽어 매니페스트의 버전과 모든 파일의 크기, 체크섬을 확인합니다.
+func VerifyBackup(r io.Reader) (*BackupManifest, error) {
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("storage: open backup: %w", err)
+	}
+	defer zr.Close()
+
+	var manifest *BackupManifest
+	seen := make(map[string]BackupFile)
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("storage: read backup: %w", err)
+		}
+		name, err := cleanBackupEntry(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case hdr.Typeflag == tar.TypeDir:
+			continue
+		case hdr.Typeflag != tar.TypeReg:
+			return nil, fmt.Errorf("storage: unsupported backup entry type %q", name)
+		case name == backupManifestName:
+			manifest = &BackupManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("storage: decode backup manifest: %w", err)
+			}
+		default:
+			h := sha256.New()
+			n, err := io.Copy(h, tr)
+			if err != nil {
+				return nil, fmt.Errorf("storage: read backup entry %s: %w", name, err)
+			}
+			seen[name] = BackupFile{Path: name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("storage: backup manifest not found")
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, err
+	}
+	for _, f := range manifest.Files {
+		got, ok := seen[f.Path]
+		if !ok {
+			return nil, fmt.Errorf("storage: backup entry %s is missing", f.Path)
+		}
+		if got != f {
+			return nil, fmt.Errorf("storage: checksum mismatch for %s", f.Path)
+		}
+		delete(seen, f.Path)
+	}
+	if len(seen) > 0 {
+		extra := make([]string, 0, len(seen))
+		for name := range seen {
+			extra = append(extra, name)
+		}
+		sort.Strings(extra)
+		return nil, fmt.Errorf("storage: backup entries not in manifest: %s", strings.Join(extra, ", "))
+	}
+	return manifest, nil
+}
+
+// validate는 이 버전에서 복원할 수 있는 매니페스트인지 확인합니다.
+func (m *BackupManifest) validate() error {
+	if m.FormatVersion < 1 || m.FormatVersion > BackupFormatVersion {
+		return fmt.Errorf("storage: unsupported backup format version %d (supported up to %d)", m.FormatVersion, BackupFormatVersion)
+	}
+	for name := range m.Tables {
+		if _, ok := findBackupTable(name); !ok {
+			return fmt.Errorf("storage: backup contains unknown table %q", name)
+		}
+	}
+	for _, s := range m.Sections {
+		if s != BackupSectionMessages && s != BackupSectionConfigs && s != BackupSectionWorkspaces {
+			return fmt.Errorf("storage: backup contains unknown section %q", s)
+		}
+	}
+	return nil
+}
+
+// RestoreBackup은 VerifyBackup으로 확인한 아카이브를 복원합니다.
+// 모든 테이블의 기존 행과 검색 색인을 지운 뒤 하나의 트랜잭션으로 다시 채우고,
+// 파일 디렉토리는 옆에 풀어 둔 뒤 기존 디렉토리를 {dir}.pre-restore-{시각}으로 옮기고 교체합니다.
+// 아카이브에 없는 섹션의 디렉토리는 그대로 둡니다. 검색 색인은 호출자가 다시 만들어야 합니다.
+func RestoreBackup(ctx context.Context, db *gorm.DB, r io.Reader, dirs BackupDirs) (*BackupRestoreResult, error) {
+	if db == nil {
+		return nil, fmt.Errorf("storage: nil database handle")
+	}
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("storage: open backup: %w", err)
+	}
+	defer zr.Close()
+
+	stamp := time.Now().Format("20060102-150405")
+	targets := make(map[string]string)
+	staging := make(map[string]string)
+	for _, s := range dirs.sections() {
+		targets[s[0]] = filepath.Clean(s[1])
+	}
+	defer func() {
+		for _, dir := range staging {
+			_ = os.RemoveAll(dir)
+		}
+	}()
+	stagingDir := func(section string) (string, error) {
+		if dir, ok := staging[section]; ok {
+			return dir, nil
+		}
+		dir := targets[section] + ".restore-" + stamp
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", err
+		}
+		staging[section] = dir
+		return dir, nil
+	}
+
+	result := &BackupRestoreResult{}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range backupTables {
+			if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(table.model).Error; err != nil {
+				return fmt.Errorf("storage: clear %s: %w", table.name, err)
+			}
+		}
+		if err := tx.Exec("DELETE FROM msg_search").Error; err != nil {
+			return fmt.Errorf("storage: clear search index: %w", err)
+		}
+
+		tr := tar.NewReader(zr)
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("storage: read backup: %w", err)
+			}
+			name, err := cleanBackupEntry(hdr.Name)
+			if err != nil {
+				return err
+			}
+			if name == backupManifestName {
+				result.Manifest = &BackupManifest{}
+				if err := json.NewDecoder(tr).Decode(result.Manifest); err != nil {
+					return fmt.Errorf("storage: decode backup manifest: %w", err)
+				}
+				continue
+			}
+
+			section, rel, _ := strings.Cut(name, "/")
+			if section == BackupSectionDB {
+				table, ok := findBackupTable(strings.TrimSuffix(rel, backupTableFileExt))
+				if !ok {
+					return fmt.Errorf("storage: backup contains unknown table file %s", name)
+				}
+				if _, err := table.restore(tx, json.NewDecoder(tr)); err != nil {
+					return fmt.Errorf("storage: restore %s: %w", table.name, err)
+				}
+				continue
+			}
+			if _, ok := targets[section]; !ok || rel == "" {
+				continue
+			}
+			root, err := stagingDir(section)
+			if err != nil {
+				return err
+			}
+			if err := extractBackupEntry(tr, hdr, filepath.Join(root, filepath.FromSlash(rel))); err != nil {
+				return fmt.Errorf("storage: restore %s: %w", name, err)
+			}
+		}
+
+		if result.Manifest == nil {
+			return fmt.Errorf("storage: backup manifest not found")
+		}
+		if err := result.Manifest.validate(); err != nil {
+			return err
+		}
+		if err := resetSequences(tx); err != nil {
+			return err
+		}
+
+		// DB 커밋 직전에 디렉토리를 교체하여, 교체에 실패하면 DB도 롤백되게 함
+		for _, section := range result.Manifest.Sections {
+			target, ok := targets[section]
+			if !ok {
+				continue
+			}
+			src, err := stagingDir(section)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(target); err == nil {
+				prev := target + backupPreRestoreTag + stamp
+				if err := os.Rename(target, prev); err != nil {
+					return fmt.Errorf("storage: move aside %s: %w", target, err)
+				}
+				result.Previous = append(result.Previous, prev)
+			}
+			if err := os.Rename(src, target); err != nil {
+				return fmt.Errorf("storage: replace %s: %w", target, err)
+			}
+			delete(staging, section)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// extractBackupEntry는 tar 항목 하나를 dest에 씁니다.
+func extractBackupEntry(tr *tar.Reader, hdr *tar.Header, dest string) error {
+	mode := fs.FileMode(hdr.Mode).Perm()
+	if hdr.Typeflag == tar.TypeDir {
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return err
+		}
+		return os.Chmod(dest, mode|0700)
+	}
+	if hdr.Typeflag != tar.TypeReg {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, tr); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dest, hdr.ModTime, hdr.ModTime)
+}
+
+// resetSequences는 id를 그대로 복원한 PostgreSQL 테이블의 시퀀스를 최대 id 다음 값으로 맞춥니다.
+func resetSequences(tx *gorm.DB) error {
+	if isSQLite(tx) {
+		return nil
+	}
+	for _, table := range backupTables {
+		if !tx.Migrator().HasColumn(table.model, "id") {
+			continue
+		}
+		stmt := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %s", table.name, table.name)
+		if err := tx.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("storage: reset sequence for %s: %w", table.name, err)
+		}
+	}
+	return nil
+}
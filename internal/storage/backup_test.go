@@ -0,0 +1,140 @@
+package storage_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	srcDB := newMessageStoreDB(t, "backup_src")
+	repo, err := storage.NewRepository(srcDB)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.CreateAgent(ctx, &storage.Agent{AgentID: "backup-agent", Model: "gpt-4", Prompt: "prompt"}))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "backup-task", AgentID: "backup-agent", Status: storage.TaskStatusCompleted}))
+	blobs, err := storage.NewDBMessageStore(srcDB)
+	require.NoError(t, err)
+	require.NoError(t, blobs.Put(ctx, "backup-task/0000.json", []byte(`{"role":"user"}`)))
+
+	src := storage.BackupDirs{Messages: t.TempDir(), Configs: filepath.Join(t.TempDir(), "missing"), Workspaces: t.TempDir()}
+	files, err := storage.NewFileMessageStore(src.Messages)
+	require.NoError(t, err)
+	require.NoError(t, files.Put(ctx, "backup-task/0000.json", []byte(`{"role":"user"}`)))
+	require.NoError(t, os.MkdirAll(filepath.Join(src.Workspaces, "backup-agent", "project"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src.Workspaces, "backup-agent", "run.sh"), []byte("#!/bin/sh\n"), 0755))
+
+	var archive bytes.Buffer
+	manifest, err := storage.CreateBackup(ctx, srcDB, &archive, src, "test")
+	require.NoError(t, err)
+	assert.Equal(t, 1, manifest.Tables["agents"])
+	assert.Equal(t, 1, manifest.Tables["message_blobs"])
+	assert.Equal(t, []string{storage.BackupSectionMessages, storage.BackupSectionConfigs, storage.BackupSectionWorkspaces}, manifest.Sections)
+	for _, f := range manifest.Files {
+		assert.NotEqual(t, "messages/.lock", f.Path, "메시지 잠금 파일은 백업하지 않음")
+	}
+
+	verified, err := storage.VerifyBackup(bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Files, verified.Files)
+
+	// 기존 데이터가 있는 다른 환경에 복원
+	dstDB := newMessageStoreDB(t, "backup_dst")
+	dstRepo, err := storage.NewRepository(dstDB)
+	require.NoError(t, err)
+	require.NoError(t, dstRepo.CreateAgent(ctx, &storage.Agent{AgentID: "stale-agent", Model: "gpt-4"}))
+	require.NoError(t, dstRepo.IndexMessage(ctx, "stale-task", 0, "user", "stale"))
+
+	dstRoot := t.TempDir()
+	dst := storage.BackupDirs{
+		Messages:   filepath.Join(dstRoot, "messages"),
+		Configs:    filepath.Join(dstRoot, "configs"),
+		Workspaces: filepath.Join(dstRoot, "workspace"),
+	}
+	require.NoError(t, os.MkdirAll(dst.Messages, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dst.Messages, "stale.json"), []byte("{}"), 0644))
+
+	result, err := storage.RestoreBackup(ctx, dstDB, bytes.NewReader(archive.Bytes()), dst)
+	require.NoError(t, err)
+	// 아카이브에 있는 섹션 중 기존 디렉토리가 있던 messages만 옮겨 둠
+	require.Len(t, result.Previous, 1)
+	assert.True(t, strings.HasPrefix(result.Previous[0], dst.Messages+".pre-restore-"))
+
+	agents, err := dstRepo.ListAgents(ctx)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, "backup-agent", agents[0].AgentID)
+	task, err := dstRepo.GetTask(ctx, "backup-task")
+	require.NoError(t, err)
+	assert.Equal(t, storage.TaskStatusCompleted, task.Status)
+	dstBlobs, err := storage.NewDBMessageStore(dstDB)
+	require.NoError(t, err)
+	data, err := dstBlobs.Get(ctx, "backup-task/0000.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"role":"user"}`, string(data))
+	var indexed int64
+	require.NoError(t, dstDB.Raw("SELECT count(*) FROM msg_search").Scan(&indexed).Error)
+	assert.Zero(t, indexed)
+
+	data, err = os.ReadFile(filepath.Join(dst.Messages, "backup-task", "0000.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"role":"user"}`, string(data))
+	assert.NoFileExists(t, filepath.Join(dst.Messages, "stale.json"))
+	assert.FileExists(t, filepath.Join(result.Previous[0], "stale.json"))
+	assert.DirExists(t, filepath.Join(dst.Workspaces, "backup-agent", "project"))
+	st, err := os.Stat(filepath.Join(dst.Workspaces, "backup-agent", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), st.Mode().Perm())
+}
+
+func TestVerifyBackupDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	db := newMessageStoreDB(t, "backup_tamper")
+	dirs := storage.BackupDirs{Messages: t.TempDir()}
+	require.NoError(t, os.WriteFile(filepath.Join(dirs.Messages, "a.json"), []byte("original"), 0644))
+
+	var archive bytes.Buffer
+	_, err := storage.CreateBackup(ctx, db, &archive, dirs, "test")
+	require.NoError(t, err)
+
+	// 같은 크기의 다른 내용으로 바꾼 아카이브
+	var tampered bytes.Buffer
+	zr, err := zstd.NewReader(&archive)
+	require.NoError(t, err)
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+	zw, err := zstd.NewWriter(&tampered)
+	require.NoError(t, err)
+	tw := tar.NewWriter(zw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		if hdr.Name == "messages/a.json" {
+			body = []byte("modified")
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err = tw.Write(body)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, zw.Close())
+
+	_, err = storage.VerifyBackup(&tampered)
+	assert.ErrorContains(t, err, "checksum mismatch for messages/a.json")
+}
@@ -34,4 +34,26 @@ const (
 	MessageRoleUser      = "user"
 	MessageRoleAssistant = "assistant"
 	MessageRoleSystem    = "system"
+
+	// 도구 권한 요청 응답 (OpenCode permission response)
+	PermissionResponseOnce   = "once"
+	PermissionResponseAlways = "always"
+	PermissionResponseReject = "reject"
+
+	// 에이전트별 보관 정책에서 기본값 대신 삭제하지 않음을 나타내는 값
+	RetentionKeepForever = -1
+
+	// 테넌트 도입 이전 레코드와 테넌트 없이(CLI 등) 만든 레코드가 속하는 테넌트
+	DefaultTenantID = "default"
+
+	// 에이전트 공개 범위
+	AgentVisibilityPrivate = "private" // 소유자만 조회/호출
+	AgentVisibilityGuild   = "guild"   // 같은 테넌트의 모든 사용자
+	AgentVisibilityPublic  = "public"  // 모든 테넌트 (수정은 소유 테넌트에서만)
+
+	// 테넌트 안의 사용자 역할 (위에서부터 권한이 높은 순)
+	RoleAdmin       = "admin"        // 모든 에이전트 관리, 역할 매핑 설정
+	RoleAgentAuthor = "agent-author" // 에이전트 생성, 자신의 에이전트 수정/삭제
+	RoleUser        = "user"         // 에이전트 호출, 대화, 도구 권한 응답
+	RoleViewer      = "viewer"       // 에이전트, Task, 대화 검색 조회
 )
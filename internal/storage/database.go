@@ -29,7 +29,7 @@ func Open(cfg Config) (*gorm.DB, error) {
 	// DSN에서 데이터베이스 드라이버 자동 감지
 	var dialector gorm.Dialector
 	if isSQLiteDSN(cfg.DSN) {
-		dialector = sqlite.Open(cfg.DSN)
+		dialector = sqlite.Open(withSQLiteBusyTimeout(cfg.DSN))
 	} else {
 		dialector = postgres.Open(cfg.DSN)
 	}
@@ -67,12 +67,26 @@ func AutoMigrate(db *gorm.DB) error {
 		&Agent{},
 		&Task{},
 		&MessageIndex{},
+		&MessageCounter{},
+		&MessageBlob{},
 		&RunStep{},
+		&TaskEvent{},
+		&TaskEventCounter{},
+		&ProcessedEvent{},
 		&Checkpoint{},
+		&PrivacyDeletion{},
+		&AuditEvent{},
+		&RoleBinding{},
 	); err != nil {
 		return fmt.Errorf("storage: migrate: %w", err)
 	}
-	return nil
+	if err := migrateDefaultTenant(db); err != nil {
+		return err
+	}
+	if err := migrateAgentNames(db); err != nil {
+		return err
+	}
+	return migrateSearchIndex(db)
 }
 
 // Close는 하부 sql.DB 자원을 해제합니다.
@@ -99,3 +113,16 @@ func isSQLiteDSN(dsn string) bool {
 		dsn == ":memory:" ||
 		strings.HasSuffix(dsn, ".db")
 }
+
+// withSQLiteBusyTimeout은 SQLite DSN에 busy timeout이 없으면 추가합니다.
+// 여러 goroutine이 동시에 쓸 때 곧바로 "database is locked"로 실패하지 않고 잠금을 기다립니다.
+func withSQLiteBusyTimeout(dsn string) string {
+	if strings.Contains(dsn, "_timeout=") { // _busy_timeout, _timeout
+		return dsn
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "_busy_timeout=5000"
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// messageLockName은 파일 메시지 저장소의 쓰기와 백업을 조율하는 잠금 파일 이름입니다.
+// 점으로 시작하므로 FileMessageStore.List와 백업 아카이브에서 제외됩니다.
+const messageLockName = ".lock"
+
+// lockMessageDir은 메시지 디렉토리 잠금을 잡고 해제 함수를 반환합니다.
+// 메시지 쓰기는 공유 잠금을, 백업은 배타 잠금을 잡으므로 서버가 실행 중이어도
+// 백업하는 동안에는 다른 프로세스의 메시지 쓰기가 대기합니다.
+func lockMessageDir(root string, exclusive bool) (func(), error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("storage: create message directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(root, messageLockName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("storage: open message lock: %w", err)
+	}
+	if err := lockFile(f, exclusive); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("storage: lock message directory: %w", err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
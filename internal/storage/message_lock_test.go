@@ -0,0 +1,46 @@
+//go:build !windows
+
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMessageStorePutWaitsForBackupLock(t *testing.T) {
+	root := t.TempDir()
+	store, err := storage.NewFileMessageStore(root)
+	require.NoError(t, err)
+
+	// 백업이 잡는 것과 같은 배타 잠금
+	f, err := os.OpenFile(filepath.Join(root, ".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, syscall.Flock(int(f.Fd()), syscall.LOCK_EX))
+
+	done := make(chan error, 1)
+	go func() { done <- store.Put(context.Background(), "task/0000.json", []byte("{}")) }()
+
+	select {
+	case <-done:
+		t.Fatal("백업 잠금 중에 Put이 완료됨")
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.NoFileExists(t, filepath.Join(root, "task", "0000.json"))
+
+	require.NoError(t, syscall.Flock(int(f.Fd()), syscall.LOCK_UN))
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("잠금 해제 후에도 Put이 완료되지 않음")
+	}
+	require.FileExists(t, filepath.Join(root, "task", "0000.json"))
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package storage
+
+import "os"
+
+// Windows에서는 파일 잠금으로 다른 프로세스의 메시지 쓰기를 멈추지 않습니다.
+// 서버를 중지한 상태에서 백업하세요.
+
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,355 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 메시지 저장소 종류
+const (
+	MessageStoreFile = "file" // 파일 시스템 ({MessagesDir}/{taskID}/{index}.json)
+	MessageStoreDB   = "db"   // 데이터베이스 (message_blobs 테이블)
+)
+
+// ErrMessageNotFound는 메시지 저장소에 키가 없을 때 반환됩니다.
+var ErrMessageNotFound = errors.New("storage: message not found")
+
+// MessageStore는 대화 메시지 내용을 저장하는 저장소입니다.
+// MessageIndex.FilePath에는 저장소 키가 기록됩니다.
+type MessageStore interface {
+	// Put은 key에 data를 저장합니다. 이미 있으면 덮어씁니다.
+	Put(ctx context.Context, key string, data []byte) error
+	// Get은 key의 내용을 반환합니다. 없으면 ErrMessageNotFound를 반환합니다.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// List는 prefix로 시작하는 키 목록을 정렬하여 반환합니다.
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Delete는 key를 삭제합니다. 없는 키는 에러가 아닙니다.
+	Delete(ctx context.Context, key string) error
+}
+
+// MessageKey는 Task의 대화 순서에 해당하는 메시지 저장소 키를 반환합니다. (예: task-1/0003.json)
+func MessageKey(taskID string, conversationIndex int) string {
+	return fmt.Sprintf("%s/%04d.json", taskID, conversationIndex)
+}
+
+// NewMessageStore는 종류에 맞는 메시지 저장소를 생성합니다.
+// file은 root 디렉토리를, db는 db 핸들을 사용합니다.
+func NewMessageStore(kind string, db *gorm.DB, root string) (MessageStore, error) {
+	switch kind {
+	case "", MessageStoreFile:
+		return NewFileMessageStore(root)
+	case MessageStoreDB:
+		return NewDBMessageStore(db)
+	default:
+		return nil, fmt.Errorf("storage: unknown message store: %s", kind)
+	}
+}
+
+// FileMessageStore는 root 디렉토리 아래 파일로 메시지를 저장합니다.
+// 이전 버전에서 MessageIndex에 기록된 root 아래 파일 경로도 키로 읽을 수 있습니다.
+type FileMessageStore struct {
+	root    string
+	absRoot string
+}
+
+// NewFileMessageStore는 파일 시스템 메시지 저장소를 생성합니다.
+func NewFileMessageStore(root string) (*FileMessageStore, error) {
+	if root == "" {
+		return nil, fmt.Errorf("storage: empty message store root")
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("storage: resolve message store root: %w", err)
+	}
+	return &FileMessageStore{root: root, absRoot: absRoot}, nil
+}
+
+// Root는 저장소 루트 디렉토리를 반환합니다.
+func (s *FileMessageStore) Root() string {
+	return s.root
+}
+
+// path는 키에 해당하는 파일 경로를 반환합니다.
+// 마이그레이션 이전 MessageIndex.FilePath에 기록된 경로({MessagesDir}/{taskID}/{index}.json)는 root 기준 키로 바꿔 사용합니다.
+func (s *FileMessageStore) path(key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("storage: empty message key")
+	}
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if rel, ok := s.legacyKey(clean); ok {
+		clean = rel
+	} else if filepath.IsAbs(clean) {
+		return "", fmt.Errorf("storage: invalid message key: %s", key)
+	}
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("storage: invalid message key: %s", key)
+	}
+	return filepath.Join(s.root, clean), nil
+}
+
+// legacyKey는 설정된 메시지 디렉토리(root)로 시작하는 이전 버전 경로에서 root 기준 키를 돌려줍니다.
+// 상대 경로는 설정된 root 그대로, 절대 경로는 root의 절대 경로와 비교합니다.
+func (s *FileMessageStore) legacyKey(clean string) (string, bool) {
+	prefix := filepath.Clean(s.root)
+	if filepath.IsAbs(clean) {
+		prefix = s.absRoot
+	}
+	if prefix == "." {
+		return "", false
+	}
+	rel, ok := strings.CutPrefix(clean, prefix+string(filepath.Separator))
+	return rel, ok
+}
+
+// Put은 임시 파일에 쓴 뒤 rename하여, 읽는 쪽이 쓰는 중인 파일을 보지 않도록 합니다.
+// 절대 경로 키는 허용하지 않습니다.
+// 백업이 진행 중이면 끝날 때까지 대기합니다.
+func (s *FileMessageStore) Put(ctx context.Context, key string, data []byte) error {
+	if filepath.IsAbs(key) {
+		return fmt.Errorf("storage: invalid message key: %s", key)
+	}
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockMessageDir(s.root, false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("storage: create message directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("storage: create temp message file: %w", err)
+	}
+	tmpName := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpName) }
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return fmt.Errorf("storage: write message file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return fmt.Errorf("storage: sync message file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return fmt.Errorf("storage: close message file: %w", err)
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		cleanup()
+		return fmt.Errorf("storage: chmod message file: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		cleanup()
+		return fmt.Errorf("storage: rename message file: %w", err)
+	}
+	return nil
+}
+
+// Get은 키에 해당하는 파일 내용을 읽습니다.
+func (s *FileMessageStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrMessageNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("storage: read message file: %w", err)
+	}
+	return data, nil
+}
+
+// List는 root 아래 파일 중 prefix로 시작하는 키를 반환합니다. 쓰는 중인 임시 파일은 제외합니다.
+func (s *FileMessageStore) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: list message files: %w", err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Delete는 키에 해당하는 파일을 삭제합니다. 백업이 진행 중이면 끝날 때까지 대기합니다.
+// 절대 경로 키는 허용하지 않습니다.
+func (s *FileMessageStore) Delete(ctx context.Context, key string) error {
+	if filepath.IsAbs(key) {
+		return fmt.Errorf("storage: invalid message key: %s", key)
+	}
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockMessageDir(s.root, false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("storage: delete message file: %w", err)
+	}
+	return nil
+}
+
+// MessageBlob은 message_blobs 테이블 레코드를 나타냅니다.
+type MessageBlob struct {
+	ID        int64     `gorm:"column:id;type:bigserial;primaryKey"`
+	Key       string    `gorm:"column:msg_key;type:varchar(255);not null;uniqueIndex:idx_message_blobs_key"`
+	Data      []byte    `gorm:"column:data;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+// TableName은 gorm Tabler 인터페이스를 구현합니다.
+func (MessageBlob) TableName() string {
+	return "message_blobs"
+}
+
+// DBMessageStore는 메시지를 데이터베이스(PostgreSQL bytea / SQLite blob)에 저장합니다.
+// 여러 호스트의 Controller가 같은 데이터베이스를 사용하면 메시지도 공유됩니다.
+type DBMessageStore struct {
+	db *gorm.DB
+}
+
+// NewDBMessageStore는 데이터베이스 메시지 저장소를 생성합니다.
+func NewDBMessageStore(db *gorm.DB) (*DBMessageStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("storage: message store requires a non-nil db handle")
+	}
+	return &DBMessageStore{db: db}, nil
+}
+
+// Put은 키에 내용을 저장하거나 갱신합니다.
+func (s *DBMessageStore) Put(ctx context.Context, key string, data []byte) error {
+	if key == "" {
+		return fmt.Errorf("storage: empty message key")
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	now := time.Now().UTC()
+	return s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "msg_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"data", "updated_at"}),
+		}).
+		Create(&MessageBlob{Key: key, Data: data, CreatedAt: now, UpdatedAt: now}).Error
+}
+
+// Get은 키의 내용을 조회합니다.
+func (s *DBMessageStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var blob MessageBlob
+	err := s.db.WithContext(ctx).Where("msg_key = ?", key).First(&blob).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrMessageNotFound, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return blob.Data, nil
+}
+
+// List는 prefix로 시작하는 키를 정렬하여 반환합니다.
+func (s *DBMessageStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	if err := s.db.WithContext(ctx).
+		Model(&MessageBlob{}).
+		Where("msg_key LIKE ? ESCAPE '\\'", escapeLike(prefix)+"%").
+		Order("msg_key ASC").
+		Pluck("msg_key", &keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Delete는 키를 삭제합니다.
+func (s *DBMessageStore) Delete(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("msg_key = ?", key).Delete(&MessageBlob{}).Error
+}
+
+// escapeLike는 LIKE 패턴의 와일드카드 문자를 이스케이프합니다.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// MigrateMessages는 모든 MessageIndex의 메시지를 src에서 dst로 복사하고 FilePath를 저장소 키로 갱신합니다.
+// 같은 저장소 간 재실행해도 안전하며, 복사한 메시지 개수를 반환합니다.
+// dryRun이면 읽기만 하고 쓰지 않습니다.
+func MigrateMessages(ctx context.Context, repo *Repository, src, dst MessageStore, dryRun bool) (int, error) {
+	rows, err := repo.ListAllMessageIndex(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("storage: list message index: %w", err)
+	}
+
+	migrated := 0
+	for _, row := range rows {
+		data, err := src.Get(ctx, row.FilePath)
+		if err != nil {
+			return migrated, fmt.Errorf("storage: read message %s#%d: %w", row.TaskID, row.ConversationIndex, err)
+		}
+		if dryRun {
+			migrated++
+			continue
+		}
+
+		key := MessageKey(row.TaskID, row.ConversationIndex)
+		if err := dst.Put(ctx, key, data); err != nil {
+			return migrated, fmt.Errorf("storage: write message %s: %w", key, err)
+		}
+		if row.FilePath != key {
+			if err := repo.db.WithContext(ctx).
+				Model(&MessageIndex{}).
+				Where("task_id = ? AND conversation_index = ?", row.TaskID, row.ConversationIndex).
+				Updates(map[string]any{"file_path": key, "updated_at": time.Now().UTC()}).Error; err != nil {
+				return migrated, fmt.Errorf("storage: update message index %s: %w", key, err)
+			}
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+var (
+	_ MessageStore = (*FileMessageStore)(nil)
+	_ MessageStore = (*DBMessageStore)(nil)
+)
@@ -0,0 +1,179 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newMessageStoreDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, storage.AutoMigrate(db))
+	t.Cleanup(func() { _ = storage.Close(db) })
+	return db
+}
+
+func TestMessageStores(t *testing.T) {
+	fileStore, err := storage.NewFileMessageStore(t.TempDir())
+	require.NoError(t, err)
+	dbStore, err := storage.NewDBMessageStore(newMessageStoreDB(t, "message_store"))
+	require.NoError(t, err)
+
+	stores := map[string]storage.MessageStore{
+		storage.MessageStoreFile: fileStore,
+		storage.MessageStoreDB:   dbStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			_, err := store.Get(ctx, "task_1/0000.json")
+			assert.ErrorIs(t, err, storage.ErrMessageNotFound)
+
+			require.NoError(t, store.Put(ctx, storage.MessageKey("task_1", 0), []byte(`{"n":0}`)))
+			require.NoError(t, store.Put(ctx, storage.MessageKey("task_1", 1), []byte(`{"n":1}`)))
+			require.NoError(t, store.Put(ctx, storage.MessageKey("task_10", 0), []byte(`{"n":2}`)))
+
+			// 덮어쓰기
+			require.NoError(t, store.Put(ctx, "task_1/0000.json", []byte(`{"n":3}`)))
+			data, err := store.Get(ctx, "task_1/0000.json")
+			require.NoError(t, err)
+			assert.Equal(t, `{"n":3}`, string(data))
+
+			keys, err := store.List(ctx, "task_1/")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"task_1/0000.json", "task_1/0001.json"}, keys)
+
+			keys, err = store.List(ctx, "")
+			require.NoError(t, err)
+			assert.Len(t, keys, 3)
+
+			require.NoError(t, store.Delete(ctx, "task_1/0001.json"))
+			require.NoError(t, store.Delete(ctx, "task_1/0001.json"))
+			_, err = store.Get(ctx, "task_1/0001.json")
+			assert.ErrorIs(t, err, storage.ErrMessageNotFound)
+		})
+	}
+
+	_, err = fileStore.Get(context.Background(), "../outside.json")
+	assert.Error(t, err)
+}
+
+func TestFileMessageStoreKeys(t *testing.T) {
+	ctx := context.Background()
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+	root := t.TempDir()
+	store, err := storage.NewFileMessageStore(root)
+	require.NoError(t, err)
+
+	// '/'가 들어간 Task ID의 키도 작업 디렉토리가 아닌 root 아래에 저장
+	key := storage.MessageKey("a/b", 0)
+	require.NoError(t, os.MkdirAll(filepath.Join(cwd, "a", "b"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(cwd, "a", "b", "0000.json"), []byte(`{"n":"cwd"}`), 0644))
+	require.NoError(t, store.Put(ctx, key, []byte(`{"n":0}`)))
+	assert.FileExists(t, filepath.Join(root, "a", "b", "0000.json"))
+	data, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, `{"n":0}`, string(data))
+	require.NoError(t, store.Delete(ctx, key))
+	assert.NoFileExists(t, filepath.Join(root, "a", "b", "0000.json"))
+	assert.FileExists(t, filepath.Join(cwd, "a", "b", "0000.json"))
+
+	// 절대 경로 키로는 쓰거나 지울 수 없고, root 밖의 파일은 읽을 수 없음
+	outside := filepath.Join(t.TempDir(), "outside.json")
+	require.NoError(t, os.WriteFile(outside, []byte(`{}`), 0644))
+	assert.Error(t, store.Put(ctx, outside, []byte(`{"n":1}`)))
+	assert.Error(t, store.Delete(ctx, outside))
+	_, err = store.Get(ctx, outside)
+	assert.Error(t, err)
+	data, err = os.ReadFile(outside)
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+
+	inside := filepath.Join(root, "task-1", "0000.json")
+	require.NoError(t, store.Put(ctx, storage.MessageKey("task-1", 0), []byte(`{"n":2}`)))
+	assert.Error(t, store.Delete(ctx, inside))
+	assert.FileExists(t, inside)
+}
+
+func TestMigrateMessages(t *testing.T) {
+	db := newMessageStoreDB(t, "migrate_messages")
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// 상대 MessagesDir(예: data/messages)는 작업 디렉토리 기준
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+	root := filepath.Join("data", "messages")
+	fileStore, err := storage.NewFileMessageStore(root)
+	require.NoError(t, err)
+	dbStore, err := storage.NewDBMessageStore(db)
+	require.NoError(t, err)
+
+	// 이전 버전 형식: 절대 경로가 기록된 메시지와 키가 기록된 메시지
+	legacyPath := filepath.Join(cwd, root, "task-m", "0000.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(legacyPath), 0755))
+	require.NoError(t, os.WriteFile(legacyPath, []byte(`{"role":"user","content":"hi"}`), 0644))
+	_, err = repo.AppendMessageIndex(ctx, "task-m", storage.MessageRoleUser, legacyPath)
+	require.NoError(t, err)
+	require.NoError(t, fileStore.Put(ctx, storage.MessageKey("task-m", 1), []byte(`{"role":"assistant","content":"hello"}`)))
+	_, err = repo.AppendMessageIndex(ctx, "task-m", storage.MessageRoleAssistant, storage.MessageKey("task-m", 1))
+	require.NoError(t, err)
+
+	// 상대 MessagesDir 기준으로 기록된 경로도 root 아래에서 읽음
+	relativePath := filepath.Join(root, "task-m", "0002.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(relativePath), 0755))
+	require.NoError(t, os.WriteFile(relativePath, []byte(`{"role":"user","content":"again"}`), 0644))
+	_, err = repo.AppendMessageIndex(ctx, "task-m", storage.MessageRoleUser, relativePath)
+	require.NoError(t, err)
+	data, err := fileStore.Get(ctx, relativePath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"role":"user","content":"again"}`, string(data))
+
+	// dry-run은 쓰지 않음
+	n, err := storage.MigrateMessages(ctx, repo, fileStore, dbStore, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	keys, err := dbStore.List(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	n, err = storage.MigrateMessages(ctx, repo, fileStore, dbStore, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	rows, err := repo.ListMessageIndexByTask(ctx, "task-m")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	for _, row := range rows {
+		assert.Equal(t, storage.MessageKey("task-m", row.ConversationIndex), row.FilePath)
+	}
+
+	data, err = dbStore.Get(ctx, "task-m/0000.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"role":"user","content":"hi"}`, string(data))
+	data, err = dbStore.Get(ctx, "task-m/0002.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"role":"user","content":"again"}`, string(data))
+
+	// 되돌리기 (db -> file)도 가능
+	back, err := storage.NewFileMessageStore(t.TempDir())
+	require.NoError(t, err)
+	n, err = storage.MigrateMessages(ctx, repo, dbStore, back, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	data, err = back.Get(ctx, "task-m/0001.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"role":"assistant","content":"hello"}`, string(data))
+}
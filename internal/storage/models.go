@@ -5,14 +5,33 @@ import "time"
 // Agent는 agents 테이블 레코드를 나타냅니다.
 type Agent struct {
 	ID          int64     `gorm:"column:id;type:bigserial;primaryKey"`
-	AgentID     string    `gorm:"column:agent_id;type:varchar(64);not null;uniqueIndex:idx_agents_agent_id"`
+	AgentID     string    `gorm:"column:agent_id;type:varchar(64);not null;uniqueIndex:idx_agents_agent_id"` // 내부 식별자 (Task, 작업 공간이 참조, AgentKey 참고)
+	Name        string    `gorm:"column:name;type:varchar(64);not null;default:''"`                          // 테넌트 안에서 유일한 이름 (idx_agents_tenant_name)
 	Description string    `gorm:"column:description;type:text"`
 	Provider    string    `gorm:"column:provider;type:varchar(32);not null;default:'opencode'"`
 	Model       string    `gorm:"column:model;type:varchar(64)"`
 	Prompt      string    `gorm:"column:prompt;type:text"`
+	Image       string    `gorm:"column:image;type:varchar(255)"`
 	Status      string    `gorm:"column:status;type:varchar(32);not null;default:'active'"`
 	CreatedAt   time.Time `gorm:"column:created_at;not null;autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"column:updated_at;not null;autoUpdateTime"`
+
+	// 도구 권한 승인 정책 (0이면 기본 대기 시간 사용)
+	PermissionTimeoutSec int    `gorm:"column:permission_timeout_sec;not null;default:0"`
+	PermissionOnTimeout  string `gorm:"column:permission_on_timeout;type:varchar(16);not null;default:'reject'"`
+
+	// 선언형 설정 파일로 관리되는 에이전트의 파일 이름 (비어 있으면 직접 관리)
+	ConfigSource string `gorm:"column:config_source;type:varchar(255);not null;default:''"`
+
+	// 보관 정책 (0이면 기본값, RetentionKeepForever이면 삭제하지 않음)
+	RetentionCompletedDays     int `gorm:"column:retention_completed_days;not null;default:0"`
+	RetentionFailedDays        int `gorm:"column:retention_failed_days;not null;default:0"`
+	RetentionWorkspaceIdleDays int `gorm:"column:retention_workspace_idle_days;not null;default:0"`
+
+	// 소유 테넌트(Discord 서버 등)와 소유자({connector}:{id}, 비어 있으면 테넌트 공용), 공개 범위
+	TenantID   string `gorm:"column:tenant_id;type:varchar(128);not null;default:'';index:idx_agents_tenant"`
+	Owner      string `gorm:"column:owner;type:varchar(128);not null;default:''"`
+	Visibility string `gorm:"column:visibility;type:varchar(16);not null;default:''"`
 }
 
 // TableName은 gorm Tabler 인터페이스를 구현합니다.
@@ -22,13 +41,16 @@ func (Agent) TableName() string {
 
 // Task는 tasks 테이블 레코드를 나타냅니다.
 type Task struct {
-	ID        int64     `gorm:"column:id;type:bigserial;primaryKey"`
-	TaskID    string    `gorm:"column:task_id;type:varchar(64);not null;uniqueIndex:idx_tasks_task_id"`
-	AgentID   string    `gorm:"column:agent_id;type:varchar(64);not null;index:idx_tasks_agent_id"`
-	Prompt    string    `gorm:"column:prompt;type:text"`
-	Status    string    `gorm:"column:status;type:varchar(32);not null"`
-	CreatedAt time.Time `gorm:"column:created_at;not null;autoCreateTime"`
-	UpdatedAt time.Time `gorm:"column:updated_at;not null;autoUpdateTime"`
+	ID          int64     `gorm:"column:id;type:bigserial;primaryKey"`
+	TaskID      string    `gorm:"column:task_id;type:varchar(64);not null;uniqueIndex:idx_tasks_task_id"`
+	AgentID     string    `gorm:"column:agent_id;type:varchar(64);not null;index:idx_tasks_agent_id"`
+	Prompt      string    `gorm:"column:prompt;type:text"`
+	Status      string    `gorm:"column:status;type:varchar(32);not null"`
+	ImageDigest string    `gorm:"column:image_digest;type:varchar(255)"`
+	Author      string    `gorm:"column:author;type:varchar(128);index:idx_tasks_author"`                        // 프롬프트 작성자 ({connector}:{id})
+	TenantID    string    `gorm:"column:tenant_id;type:varchar(128);not null;default:'';index:idx_tasks_tenant"` // Task를 시작한 테넌트
+	CreatedAt   time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;not null;autoUpdateTime"`
 }
 
 // TableName은 gorm Tabler 인터페이스를 구현합니다.
@@ -43,6 +65,7 @@ type MessageIndex struct {
 	ConversationIndex int       `gorm:"column:conversation_index;type:int;not null;uniqueIndex:idx_msg_idx_task_conv,priority:2"`
 	Role              string    `gorm:"column:role;type:varchar(32);not null"`
 	FilePath          string    `gorm:"column:file_path;type:text;not null"`
+	Author            string    `gorm:"column:author;type:varchar(128);index:idx_msg_index_author"` // 메시지 작성자 ({connector}:{id})
 	CreatedAt         time.Time `gorm:"column:created_at;not null;autoCreateTime"`
 	UpdatedAt         time.Time `gorm:"column:updated_at;not null;autoUpdateTime"`
 }
@@ -52,6 +75,18 @@ func (MessageIndex) TableName() string {
 	return "msg_index"
 }
 
+// MessageCounter는 작업별로 다음에 할당할 ConversationIndex를 보관합니다.
+// 동시에 메시지를 추가해도 인덱스가 중복되지 않도록 한 문장으로 증가시킵니다.
+type MessageCounter struct {
+	TaskID    string `gorm:"column:task_id;type:varchar(64);primaryKey"`
+	NextIndex int    `gorm:"column:next_index;type:int;not null"`
+}
+
+// TableName은 gorm Tabler 인터페이스를 구현합니다.
+func (MessageCounter) TableName() string {
+	return "msg_counters"
+}
+
 // RunStep은 작업 실행 단계를 기록합니다.
 type RunStep struct {
 	ID        int64     `gorm:"column:id;type:bigserial;primaryKey"`
@@ -67,6 +102,49 @@ func (RunStep) TableName() string {
 	return "run_steps"
 }
 
+// TaskEvent는 Task에서 발생한 Controller 이벤트를 Task별 순번과 함께 추가 전용으로 기록합니다.
+// 스트리밍 델타는 해당 Part가 완료되면 정리됩니다.
+type TaskEvent struct {
+	ID        int64     `gorm:"column:id;type:bigserial;primaryKey"`
+	TaskID    string    `gorm:"column:task_id;type:varchar(64);not null;uniqueIndex:idx_task_events_task_seq,priority:1"`
+	Seq       int64     `gorm:"column:seq;type:bigint;not null;uniqueIndex:idx_task_events_task_seq,priority:2"`
+	EventType string    `gorm:"column:event_type;type:varchar(32);not null"`
+	PartID    string    `gorm:"column:part_id;type:varchar(128);not null;default:''"`
+	Payload   string    `gorm:"column:payload;type:text;not null"` // 이벤트 JSON
+	CreatedAt time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+// TableName은 gorm Tabler 인터페이스를 구현합니다.
+func (TaskEvent) TableName() string {
+	return "task_events"
+}
+
+// TaskEventCounter는 Task별로 다음에 할당할 이벤트 순번을 보관합니다.
+// Controller는 순번을 묶음으로 예약해 두고 메모리에서 부여하므로, 예약만 하고 쓰지 않은 순번은 건너뛸 수 있습니다.
+type TaskEventCounter struct {
+	TaskID  string `gorm:"column:task_id;type:varchar(64);primaryKey"`
+	NextSeq int64  `gorm:"column:next_seq;type:bigint;not null"`
+}
+
+// TableName은 gorm Tabler 인터페이스를 구현합니다.
+func (TaskEventCounter) TableName() string {
+	return "task_event_counters"
+}
+
+// ProcessedEvent는 처리한 Connector 이벤트 ID를 기록합니다.
+// Connector가 다시 보낸 execute 이벤트를 Controller가 재시작한 뒤에도 한 번만 처리하기 위해 사용합니다.
+type ProcessedEvent struct {
+	EventID   string    `gorm:"column:event_id;type:varchar(128);primaryKey"`
+	Type      string    `gorm:"column:type;type:varchar(32);not null"`
+	TaskID    string    `gorm:"column:task_id;type:varchar(64);not null;index:idx_processed_events_task"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;autoCreateTime;index:idx_processed_events_created"`
+}
+
+// TableName은 gorm Tabler 인터페이스를 구현합니다.
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
 // Checkpoint는 작업의 Git 스냅샷 참조를 저장합니다.
 type Checkpoint struct {
 	ID        int64     `gorm:"column:id;type:bigserial;primaryKey"`
@@ -79,3 +157,54 @@ type Checkpoint struct {
 func (Checkpoint) TableName() string {
 	return "checkpoints"
 }
+
+// PrivacyDeletion은 사용자 데이터 삭제 요청을 처리한 기록입니다.
+// 삭제된 내용은 남기지 않고 누가 언제 몇 건을 삭제했는지만 보관합니다.
+type PrivacyDeletion struct {
+	ID          int64     `gorm:"column:id;type:bigserial;primaryKey"`
+	Author      string    `gorm:"column:author;type:varchar(128);not null;index:idx_privacy_deletions_author"`
+	RequestedBy string    `gorm:"column:requested_by;type:varchar(128)"`
+	Tasks       int       `gorm:"column:tasks;type:int;not null"`
+	Messages    int       `gorm:"column:messages;type:int;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+// TableName은 gorm Tabler 인터페이스를 구현합니다.
+func (PrivacyDeletion) TableName() string {
+	return "privacy_deletions"
+}
+
+// AuditEvent는 관리 작업과 에이전트 작업의 감사 기록입니다. 추가만 하며 수정하거나 삭제하지 않습니다.
+// Before, After는 변경 전후 상태의 JSON이며, 생성이나 삭제처럼 한쪽 상태가 없으면 비어 있습니다.
+type AuditEvent struct {
+	ID         int64     `gorm:"column:id;type:bigserial;primaryKey"`
+	Actor      string    `gorm:"column:actor;type:varchar(128);not null;index:idx_audit_events_actor"`  // {connector}:{id} (예: discord:1234, cli:alice, system:retention)
+	Source     string    `gorm:"column:source;type:varchar(32);not null"`                               // 요청 경로 (discord, cli, system)
+	Action     string    `gorm:"column:action;type:varchar(64);not null;index:idx_audit_events_action"` // {대상 종류}.{동작} (예: agent.update)
+	TargetType string    `gorm:"column:target_type;type:varchar(32);not null;index:idx_audit_events_target,priority:1"`
+	TargetID   string    `gorm:"column:target_id;type:varchar(128);not null;index:idx_audit_events_target,priority:2"`
+	Before     string    `gorm:"column:before_state;type:text"`
+	After      string    `gorm:"column:after_state;type:text"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null;autoCreateTime;index:idx_audit_events_created"`
+}
+
+// TableName은 gorm Tabler 인터페이스를 구현합니다.
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// RoleBinding은 테넌트 안에서 커넥터 역할(Discord 역할 등)에 부여한 CNAP 역할입니다.
+type RoleBinding struct {
+	ID           int64     `gorm:"column:id;type:bigserial;primaryKey"`
+	TenantID     string    `gorm:"column:tenant_id;type:varchar(128);not null;uniqueIndex:idx_role_bindings_tenant_role,priority:1"`
+	ExternalRole string    `gorm:"column:external_role;type:varchar(64);not null;uniqueIndex:idx_role_bindings_tenant_role,priority:2"` // Discord 역할 ID
+	Role         string    `gorm:"column:role;type:varchar(32);not null"`                                                               // admin, agent-author, user, viewer
+	UpdatedBy    string    `gorm:"column:updated_by;type:varchar(128);not null;default:''"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+// TableName은 gorm Tabler 인터페이스를 구현합니다.
+func (RoleBinding) TableName() string {
+	return "role_bindings"
+}
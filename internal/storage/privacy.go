@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ListTasksByAuthor는 author가 프롬프트를 작성한 Task를 생성 순으로 반환합니다.
+func (r *Repository) ListTasksByAuthor(ctx context.Context, author string) ([]Task, error) {
+	if author == "" {
+		return nil, fmt.Errorf("storage: empty author")
+	}
+	var tasks []Task
+	if err := r.db.WithContext(ctx).
+		Where("author = ?", author).
+		Order("created_at ASC").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ListMessageIndexByAuthor는 author가 작성한 메시지를 Task, 대화 순서대로 반환합니다.
+func (r *Repository) ListMessageIndexByAuthor(ctx context.Context, author string) ([]MessageIndex, error) {
+	if author == "" {
+		return nil, fmt.Errorf("storage: empty author")
+	}
+	var messages []MessageIndex
+	if err := r.db.WithContext(ctx).
+		Where("author = ?", author).
+		Order("task_id ASC, conversation_index ASC").
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// RedactAuthor는 author가 작성한 Task 프롬프트와 메시지의 작성자 정보 및 검색 색인을 지우고 삭제 기록을 남깁니다.
+// 보관 정책의 기준인 updated_at은 갱신하지 않습니다.
+// 메시지 저장소의 내용은 호출자가 먼저 지워야 합니다. 하나의 트랜잭션으로 처리되므로
+// 실패하면 작성자 정보가 그대로 남아 다시 시도할 수 있습니다.
+func (r *Repository) RedactAuthor(ctx context.Context, author, requestedBy string) (*PrivacyDeletion, error) {
+	if author == "" {
+		return nil, fmt.Errorf("storage: empty author")
+	}
+
+	record := &PrivacyDeletion{Author: author, RequestedBy: requestedBy}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var messages []MessageIndex
+		if err := tx.Where("author = ?", author).Find(&messages).Error; err != nil {
+			return err
+		}
+		for _, m := range messages {
+			if err := tx.Exec("DELETE FROM msg_search WHERE task_id = ? AND conversation_index = ?",
+				m.TaskID, m.ConversationIndex).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&MessageIndex{}).Where("author = ?", author).
+			UpdateColumn("author", "").Error; err != nil {
+			return err
+		}
+		record.Messages = len(messages)
+
+		res := tx.Model(&Task{}).Where("author = ?", author).
+			UpdateColumns(map[string]any{"prompt": "", "author": ""})
+		if res.Error != nil {
+			return res.Error
+		}
+		record.Tasks = int(res.RowsAffected)
+
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// ListPrivacyDeletions는 author의 데이터 삭제 기록을 오래된 순으로 반환합니다. author가 비어 있으면 모든 기록을 반환합니다.
+func (r *Repository) ListPrivacyDeletions(ctx context.Context, author string) ([]PrivacyDeletion, error) {
+	query := r.db.WithContext(ctx).Order("created_at ASC")
+	if author != "" {
+		query = query.Where("author = ?", author)
+	}
+	var records []PrivacyDeletion
+	if err := query.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
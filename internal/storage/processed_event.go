@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// MarkEventProcessed는 Connector 이벤트 ID를 처리한 것으로 기록합니다.
+// 처음 기록했으면 true, 이미 기록된 ID이면 false를 반환합니다.
+func (r *Repository) MarkEventProcessed(ctx context.Context, event *ProcessedEvent) (bool, error) {
+	if event.EventID == "" {
+		return false, fmt.Errorf("storage: empty eventID")
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(event)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// DeleteProcessedEvent는 처리하지 못한 Connector 이벤트 ID 기록을 삭제해 다시 처리할 수 있게 합니다.
+func (r *Repository) DeleteProcessedEvent(ctx context.Context, eventID string) error {
+	if eventID == "" {
+		return fmt.Errorf("storage: empty eventID")
+	}
+	return r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Delete(&ProcessedEvent{}).Error
+}
+
+// PruneProcessedEvents는 before 이전에 기록한 Connector 이벤트 ID를 삭제하고 삭제한 개수를 반환합니다.
+func (r *Repository) PruneProcessedEvents(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("created_at < ?", before).
+		Delete(&ProcessedEvent{})
+	return res.RowsAffected, res.Error
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -28,11 +29,49 @@ func (r *Repository) DB() *gorm.DB {
 }
 
 // CreateAgent는 새로운 에이전트 레코드를 저장합니다.
+// 이름이 비어 있으면 식별자를, 식별자가 비어 있으면 AgentKey로 만든 값을 사용합니다.
+// 같은 테넌트에 같은 이름이 있으면 ErrAgentExists를 반환합니다.
 func (r *Repository) CreateAgent(ctx context.Context, agent *Agent) error {
 	if agent == nil {
 		return fmt.Errorf("storage: nil agent payload")
 	}
-	return r.db.WithContext(ctx).Create(agent).Error
+	if agent.TenantID == "" {
+		agent.TenantID = DefaultTenantID
+	}
+	if agent.Visibility == "" {
+		agent.Visibility = AgentVisibilityGuild
+	}
+	if agent.Name == "" {
+		agent.Name = agent.AgentID
+	}
+	if agent.AgentID == "" {
+		agent.AgentID = AgentKey(agent.TenantID, agent.Name)
+	}
+	// 이름이나 식별자가 겹치면 어느 테넌트의 에이전트와 겹쳤는지 드러내지 않고 같은 오류를 반환
+	conflict := func(tx *gorm.DB) (bool, error) {
+		var count int64
+		err := tx.Model(&Agent{}).
+			Where("(tenant_id = ? AND name = ?) OR agent_id = ?", agent.TenantID, agent.Name, agent.AgentID).
+			Count(&count).Error
+		return count > 0, err
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		exists, err := conflict(tx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: %s", ErrAgentExists, agent.Name)
+		}
+		return tx.Create(agent).Error
+	})
+	if err != nil && !errors.Is(err, ErrAgentExists) {
+		// 동시에 같은 이름으로 만든 경우 유일 색인 위반을 같은 오류로 바꿈
+		if exists, _ := conflict(r.db.WithContext(ctx)); exists {
+			return fmt.Errorf("%w: %s", ErrAgentExists, agent.Name)
+		}
+	}
+	return err
 }
 
 // UpsertAgentStatus는 agentID로 에이전트 상태를 갱신하거나 생성합니다.
@@ -46,8 +85,11 @@ func (r *Repository) UpsertAgentStatus(ctx context.Context, agentID, status stri
 			DoUpdates: clause.AssignmentColumns([]string{"status", "updated_at"}),
 		}).
 		Create(&Agent{
-			AgentID: agentID,
-			Status:  status,
+			AgentID:    agentID,
+			Name:       agentID,
+			Status:     status,
+			TenantID:   DefaultTenantID,
+			Visibility: AgentVisibilityGuild,
 		}).Error
 }
 
@@ -98,11 +140,89 @@ func (r *Repository) UpdateAgent(ctx context.Context, agent *Agent) error {
 		}).Error
 }
 
+// UpdateAgentImage는 에이전트 전용 Runner 이미지를 설정합니다.
+// 빈 문자열이면 기본 이미지를 사용하도록 초기화합니다.
+func (r *Repository) UpdateAgentImage(ctx context.Context, agentID, image string) error {
+	if agentID == "" {
+		return fmt.Errorf("storage: empty agentID")
+	}
+	return r.db.WithContext(ctx).
+		Model(&Agent{}).
+		Where("agent_id = ?", agentID).
+		Updates(map[string]interface{}{
+			"image":      image,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// UpdateAgentPermissionPolicy는 에이전트의 도구 권한 승인 정책을 설정합니다.
+// timeoutSec가 0이면 기본 대기 시간을 사용하며, onTimeout은 대기 시간 초과 시 적용할 응답입니다.
+func (r *Repository) UpdateAgentPermissionPolicy(ctx context.Context, agentID string, timeoutSec int, onTimeout string) error {
+	if agentID == "" {
+		return fmt.Errorf("storage: empty agentID")
+	}
+	if timeoutSec < 0 {
+		return fmt.Errorf("storage: negative permission timeout")
+	}
+	switch onTimeout {
+	case PermissionResponseOnce, PermissionResponseReject:
+	default:
+		return fmt.Errorf("storage: invalid permission timeout action %q", onTimeout)
+	}
+	return r.db.WithContext(ctx).
+		Model(&Agent{}).
+		Where("agent_id = ?", agentID).
+		Updates(map[string]interface{}{
+			"permission_timeout_sec": timeoutSec,
+			"permission_on_timeout":  onTimeout,
+			"updated_at":             time.Now(),
+		}).Error
+}
+
+// UpdateAgentRetention은 에이전트별 보관 정책을 갱신합니다.
+// 0이면 기본값을 사용하고, RetentionKeepForever이면 삭제하지 않습니다.
+func (r *Repository) UpdateAgentRetention(ctx context.Context, agentID string, completedDays, failedDays, workspaceIdleDays int) error {
+	if agentID == "" {
+		return fmt.Errorf("storage: empty agentID")
+	}
+	for _, days := range []int{completedDays, failedDays, workspaceIdleDays} {
+		if days < RetentionKeepForever {
+			return fmt.Errorf("storage: invalid retention days %d", days)
+		}
+	}
+	return r.db.WithContext(ctx).
+		Model(&Agent{}).
+		Where("agent_id = ?", agentID).
+		Updates(map[string]interface{}{
+			"retention_completed_days":      completedDays,
+			"retention_failed_days":         failedDays,
+			"retention_workspace_idle_days": workspaceIdleDays,
+			"updated_at":                    time.Now(),
+		}).Error
+}
+
+// SetAgentConfigSource는 에이전트를 관리하는 설정 파일 이름을 기록합니다. 빈 문자열이면 관리 대상에서 제외합니다.
+func (r *Repository) SetAgentConfigSource(ctx context.Context, agentID, source string) error {
+	if agentID == "" {
+		return fmt.Errorf("storage: empty agentID")
+	}
+	return r.db.WithContext(ctx).
+		Model(&Agent{}).
+		Where("agent_id = ?", agentID).
+		Updates(map[string]interface{}{
+			"config_source": source,
+			"updated_at":    time.Now(),
+		}).Error
+}
+
 // CreateTask는 새로운 작업 레코드를 추가합니다.
 func (r *Repository) CreateTask(ctx context.Context, task *Task) error {
 	if task == nil {
 		return fmt.Errorf("storage: nil task payload")
 	}
+	if task.TenantID == "" {
+		task.TenantID = DefaultTenantID
+	}
 	return r.db.WithContext(ctx).Create(task).Error
 }
 
@@ -117,12 +237,39 @@ func (r *Repository) UpsertTaskStatus(ctx context.Context, taskID, agentID, stat
 			DoUpdates: clause.AssignmentColumns([]string{"status", "updated_at"}),
 		}).
 		Create(&Task{
-			TaskID:  taskID,
-			AgentID: agentID,
-			Status:  status,
+			TaskID:   taskID,
+			AgentID:  agentID,
+			Status:   status,
+			TenantID: DefaultTenantID,
 		}).Error
 }
 
+// SetTaskImageDigest는 작업에 고정된 Runner 이미지 다이제스트를 기록합니다.
+func (r *Repository) SetTaskImageDigest(ctx context.Context, taskID, digest string) error {
+	if taskID == "" {
+		return fmt.Errorf("storage: empty taskID")
+	}
+	return r.db.WithContext(ctx).
+		Model(&Task{}).
+		Where("task_id = ?", taskID).
+		Update("image_digest", digest).Error
+}
+
+// ListTaskImageDigests는 주어진 상태의 작업에 고정된 이미지 다이제스트 목록을 중복 없이 반환합니다.
+func (r *Repository) ListTaskImageDigests(ctx context.Context, statuses ...string) ([]string, error) {
+	q := r.db.WithContext(ctx).
+		Model(&Task{}).
+		Where("image_digest IS NOT NULL AND image_digest <> ''")
+	if len(statuses) > 0 {
+		q = q.Where("status IN ?", statuses)
+	}
+	var digests []string
+	if err := q.Distinct("image_digest").Pluck("image_digest", &digests).Error; err != nil {
+		return nil, err
+	}
+	return digests, nil
+}
+
 // GetTask는 작업 식별자로 레코드를 조회합니다.
 func (r *Repository) GetTask(ctx context.Context, taskID string) (*Task, error) {
 	var task Task
@@ -156,6 +303,75 @@ func (r *Repository) DeleteTask(ctx context.Context, taskID string) error {
 		Delete(&Task{}).Error
 }
 
+// ListInactiveTasks는 에이전트의 Task 중 statuses 상태이면서 before 이전에 마지막으로 갱신된 Task를 오래된 순으로 반환합니다.
+func (r *Repository) ListInactiveTasks(ctx context.Context, agentID string, statuses []string, before time.Time) ([]Task, error) {
+	if agentID == "" {
+		return nil, fmt.Errorf("storage: empty agentID")
+	}
+	var tasks []Task
+	if err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND status IN ? AND updated_at < ?", agentID, statuses, before).
+		Order("updated_at ASC").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// LatestTaskByAgent는 에이전트의 Task 중 마지막으로 갱신된 Task를 반환합니다. 없으면 gorm.ErrRecordNotFound를 반환합니다.
+func (r *Repository) LatestTaskByAgent(ctx context.Context, agentID string) (*Task, error) {
+	if agentID == "" {
+		return nil, fmt.Errorf("storage: empty agentID")
+	}
+	var task Task
+	if err := r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Order("updated_at DESC").
+		First(&task).Error; err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// HasActiveTaskByAgent는 에이전트에 진행 중(pending, running)인 Task가 하나라도 있는지 반환합니다.
+func (r *Repository) HasActiveTaskByAgent(ctx context.Context, agentID string) (bool, error) {
+	if agentID == "" {
+		return false, fmt.Errorf("storage: empty agentID")
+	}
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&Task{}).
+		Where("agent_id = ? AND status IN ?", agentID, []string{TaskStatusPending, TaskStatusRunning}).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// PurgeTask는 Task와 메시지 인덱스, 순번 카운터, 실행 단계, 이벤트 로그, 처리한 이벤트 ID, 체크포인트, 검색 색인을 하나의 트랜잭션으로 삭제합니다.
+// 메시지 저장소에서 지워야 할 메시지 키를 반환합니다.
+func (r *Repository) PurgeTask(ctx context.Context, taskID string) ([]string, error) {
+	if taskID == "" {
+		return nil, fmt.Errorf("storage: empty taskID")
+	}
+	var keys []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&MessageIndex{}).Where("task_id = ?", taskID).
+			Order("conversation_index ASC").Pluck("file_path", &keys).Error; err != nil {
+			return err
+		}
+		for _, model := range []any{&MessageIndex{}, &MessageCounter{}, &RunStep{}, &TaskEvent{}, &TaskEventCounter{}, &ProcessedEvent{}, &Checkpoint{}, &Task{}} {
+			if err := tx.Where("task_id = ?", taskID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Exec("DELETE FROM msg_search WHERE task_id = ?", taskID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
 // GetNextConversationIndex는 해당 Task의 다음 ConversationIndex를 반환합니다.
 func (r *Repository) GetNextConversationIndex(ctx context.Context, taskID string) (int, error) {
 	if taskID == "" {
@@ -179,28 +395,48 @@ func (r *Repository) GetNextConversationIndex(ctx context.Context, taskID string
 
 // AppendMessageIndex는 새로운 메시지를 대화에 추가합니다 (ConversationIndex 자동 증가).
 func (r *Repository) AppendMessageIndex(ctx context.Context, taskID, role, filePath string) (*MessageIndex, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("storage: empty filePath")
+	}
+	return r.AppendMessage(ctx, taskID, role, "", func(int) (string, error) {
+		return filePath, nil
+	})
+}
+
+// AppendMessage는 Task의 다음 ConversationIndex를 원자적으로 할당하고, write로 메시지를 저장한 뒤 MessageIndex를 추가합니다.
+// write는 할당된 인덱스를 받아 메시지를 저장하고 기록할 경로(저장소 키)를 반환합니다.
+// author는 메시지 작성자의 커넥터 식별자이며, 알 수 없으면 빈 문자열입니다.
+// 동시에 호출되어도 각 호출은 서로 다른 인덱스를 받습니다. write가 실패하면 할당된 인덱스는 비어 있는 채로 남습니다.
+func (r *Repository) AppendMessage(ctx context.Context, taskID, role, author string, write func(conversationIndex int) (string, error)) (*MessageIndex, error) {
 	if taskID == "" {
 		return nil, fmt.Errorf("storage: empty taskID")
 	}
 	if role == "" {
 		return nil, fmt.Errorf("storage: empty role")
 	}
-	if filePath == "" {
-		return nil, fmt.Errorf("storage: empty filePath")
+
+	index, err := r.allocateConversationIndex(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("storage: failed to allocate conversation index: %w", err)
 	}
 
-	nextIndex, err := r.GetNextConversationIndex(ctx, taskID)
+	filePath, err := write(index)
 	if err != nil {
-		return nil, fmt.Errorf("storage: failed to get next conversation index: %w", err)
+		return nil, err
+	}
+	if filePath == "" {
+		return nil, fmt.Errorf("storage: empty filePath")
 	}
 
+	now := time.Now().UTC()
 	payload := &MessageIndex{
 		TaskID:            taskID,
-		ConversationIndex: nextIndex,
+		ConversationIndex: index,
 		Role:              role,
 		FilePath:          filePath,
-		CreatedAt:         time.Now().UTC(),
-		UpdatedAt:         time.Now().UTC(),
+		Author:            author,
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}
 
 	if err := r.db.WithContext(ctx).Create(payload).Error; err != nil {
@@ -210,6 +446,23 @@ func (r *Repository) AppendMessageIndex(ctx context.Context, taskID, role, fileP
 	return payload, nil
 }
 
+// allocateConversationIndex는 msg_counters의 카운터를 한 문장으로 증가시켜 다음 ConversationIndex를 할당합니다.
+// 카운터가 없으면 기존 msg_index의 MAX(conversation_index)+1부터 시작합니다.
+// 단일 INSERT ... ON CONFLICT DO UPDATE 문이므로 PostgreSQL은 행 잠금으로, SQLite는 쓰기 잠금으로 직렬화됩니다.
+func (r *Repository) allocateConversationIndex(ctx context.Context, taskID string) (int, error) {
+	var next int
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO msg_counters (task_id, next_index)
+		SELECT ?, COALESCE(MAX(conversation_index), -1) + 2 FROM msg_index WHERE task_id = ?
+		ON CONFLICT (task_id) DO UPDATE SET next_index = msg_counters.next_index + 1
+		RETURNING next_index`, taskID, taskID).
+		Scan(&next).Error
+	if err != nil {
+		return 0, err
+	}
+	return next - 1, nil
+}
+
 // ListMessageIndexByTask는 작업에 연결된 메시지 참조 목록을 순서대로 반환합니다.
 func (r *Repository) ListMessageIndexByTask(ctx context.Context, taskID string) ([]MessageIndex, error) {
 	var rows []MessageIndex
@@ -222,6 +475,17 @@ func (r *Repository) ListMessageIndexByTask(ctx context.Context, taskID string)
 	return rows, nil
 }
 
+// ListAllMessageIndex는 모든 작업의 메시지 참조 목록을 작업별 대화 순서대로 반환합니다.
+func (r *Repository) ListAllMessageIndex(ctx context.Context) ([]MessageIndex, error) {
+	var rows []MessageIndex
+	if err := r.db.WithContext(ctx).
+		Order("task_id ASC, conversation_index ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // UpsertRunStep은 실행 단계를 생성하거나 갱신합니다.
 func (r *Repository) UpsertRunStep(ctx context.Context, step *RunStep) error {
 	if step == nil {
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// SetRoleBinding은 테넌트의 커넥터 역할에 CNAP 역할을 부여합니다. 이미 있으면 역할을 바꿉니다.
+func (r *Repository) SetRoleBinding(ctx context.Context, binding *RoleBinding) error {
+	if binding.TenantID == "" || binding.ExternalRole == "" {
+		return fmt.Errorf("storage: empty role binding key")
+	}
+	switch binding.Role {
+	case RoleAdmin, RoleAgentAuthor, RoleUser, RoleViewer:
+	default:
+		return fmt.Errorf("storage: invalid role %q", binding.Role)
+	}
+	binding.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "external_role"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "updated_by", "updated_at"}),
+		}).
+		Create(binding).Error
+}
+
+// DeleteRoleBinding은 커넥터 역할의 CNAP 역할 부여를 취소합니다. 삭제한 매핑이 없으면 false를 반환합니다.
+func (r *Repository) DeleteRoleBinding(ctx context.Context, tenantID, externalRole string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND external_role = ?", tenantID, externalRole).
+		Delete(&RoleBinding{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// ListRoleBindings는 테넌트의 역할 매핑을 반환합니다.
+func (r *Repository) ListRoleBindings(ctx context.Context, tenantID string) ([]RoleBinding, error) {
+	var bindings []RoleBinding
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("external_role ASC").
+		Find(&bindings).Error; err != nil {
+		return nil, err
+	}
+	return bindings, nil
+}
@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 검색 결과 snippet에서 일치한 부분을 감싸는 문자열
+const (
+	SearchMatchStart = "["
+	SearchMatchEnd   = "]"
+)
+
+// SearchQuery는 대화 메시지 전문 검색 조건입니다.
+type SearchQuery struct {
+	Query    string    // 검색어 (공백으로 구분된 모든 단어를 포함하는 메시지를 찾음)
+	TenantID string    // 비어 있으면 모든 테넌트의 Task
+	AgentID  string    // 비어 있으면 모든 에이전트
+	Since    time.Time // zero이면 기간 제한 없음
+	Limit    int       // 0 이하이면 기본값 20
+}
+
+// SearchResult는 검색된 메시지입니다. TaskID는 Discord에서는 스레드 ID입니다.
+type SearchResult struct {
+	TaskID            string    `gorm:"column:task_id"`
+	AgentID           string    `gorm:"column:agent_id"`
+	ConversationIndex int       `gorm:"column:conversation_index"`
+	Role              string    `gorm:"column:role"`
+	Snippet           string    `gorm:"column:snippet"`
+	CreatedAt         time.Time `gorm:"column:created_at"`
+}
+
+// migrateSearchIndex는 msg_search 전문 검색 테이블을 생성합니다.
+// SQLite는 FTS4 가상 테이블을 사용합니다. (FTS5는 sqlite_fts5 빌드 태그가 필요하여 사용하지 않음)
+// PostgreSQL은 tsvector 생성 컬럼과 GIN 인덱스를 사용합니다.
+func migrateSearchIndex(db *gorm.DB) error {
+	var stmts []string
+	if isSQLite(db) {
+		stmts = []string{
+			`CREATE VIRTUAL TABLE IF NOT EXISTS msg_search USING fts4(
+				task_id, conversation_index, role, body,
+				notindexed=task_id, notindexed=conversation_index, notindexed=role,
+				tokenize=unicode61
+			)`,
+		}
+	} else {
+		stmts = []string{
+			`CREATE TABLE IF NOT EXISTS msg_search (
+				task_id varchar(64) NOT NULL,
+				conversation_index int NOT NULL,
+				role varchar(32) NOT NULL,
+				body text NOT NULL,
+				tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple', body)) STORED,
+				PRIMARY KEY (task_id, conversation_index)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_msg_search_tsv ON msg_search USING GIN (tsv)`,
+		}
+	}
+	for _, stmt := range stmts {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("storage: migrate search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// isSQLite는 db가 SQLite 드라이버를 사용하는지 확인합니다.
+func isSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == "sqlite"
+}
+
+// IndexMessage는 메시지 본문을 전문 검색 색인에 추가하거나 교체합니다.
+func (r *Repository) IndexMessage(ctx context.Context, taskID string, conversationIndex int, role, body string) error {
+	if taskID == "" {
+		return fmt.Errorf("storage: empty taskID")
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM msg_search WHERE task_id = ? AND conversation_index = ?`,
+			taskID, conversationIndex).Error; err != nil {
+			return err
+		}
+		if strings.TrimSpace(body) == "" {
+			return nil
+		}
+		return tx.Exec(`INSERT INTO msg_search (task_id, conversation_index, role, body) VALUES (?, ?, ?, ?)`,
+			taskID, conversationIndex, role, body).Error
+	})
+}
+
+// ClearSearchIndex는 전문 검색 색인을 모두 비웁니다.
+func (r *Repository) ClearSearchIndex(ctx context.Context) error {
+	return r.db.WithContext(ctx).Exec(`DELETE FROM msg_search`).Error
+}
+
+// SearchEntry는 전문 검색 색인에 넣을 메시지 하나입니다.
+type SearchEntry struct {
+	TaskID            string
+	ConversationIndex int
+	Role              string
+	Body              string
+}
+
+// ReplaceSearchIndex는 전문 검색 색인 전체를 entries로 교체합니다.
+// 하나의 트랜잭션에서 비우고 다시 채우므로, 검색하는 쪽은 이전 색인이나 새 색인 중 하나만 봅니다.
+// 실패하면 이전 색인을 그대로 유지합니다.
+func (r *Repository) ReplaceSearchIndex(ctx context.Context, entries []SearchEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM msg_search`).Error; err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if e.TaskID == "" {
+				return fmt.Errorf("storage: empty taskID")
+			}
+			if strings.TrimSpace(e.Body) == "" {
+				continue
+			}
+			if err := tx.Exec(`INSERT INTO msg_search (task_id, conversation_index, role, body) VALUES (?, ?, ?, ?)`,
+				e.TaskID, e.ConversationIndex, e.Role, e.Body).Error; err != nil {
+				return fmt.Errorf("storage: index message %s#%d: %w", e.TaskID, e.ConversationIndex, err)
+			}
+		}
+		return nil
+	})
+}
+
+// SearchMessages는 전문 검색 색인에서 조건에 맞는 메시지를 최신순으로 반환합니다.
+func (r *Repository) SearchMessages(ctx context.Context, q SearchQuery) ([]SearchResult, error) {
+	terms := searchTerms(q.Query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("storage: empty search query")
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var (
+		sql  strings.Builder
+		args []any
+	)
+	if isSQLite(r.db) {
+		// 각 단어를 접두어 검색 구문으로 감싸서 FTS 연산자로 해석되지 않도록 함
+		phrases := make([]string, len(terms))
+		for i, term := range terms {
+			phrases[i] = `"` + term + `*"`
+		}
+		sql.WriteString(`SELECT msg_search.task_id AS task_id, tasks.agent_id AS agent_id,
+			msg_index.conversation_index AS conversation_index, msg_search.role AS role,
+			snippet(msg_search, '` + SearchMatchStart + `', '` + SearchMatchEnd + `', '…', 3, 16) AS snippet,
+			msg_index.created_at AS created_at
+			FROM msg_search
+			JOIN tasks ON tasks.task_id = msg_search.task_id
+			JOIN msg_index ON msg_index.task_id = msg_search.task_id AND msg_index.conversation_index = msg_search.conversation_index
+			WHERE msg_search MATCH ?`)
+		args = append(args, strings.Join(phrases, " "))
+	} else {
+		sql.WriteString(`SELECT s.task_id AS task_id, tasks.agent_id AS agent_id,
+			s.conversation_index AS conversation_index, s.role AS role,
+			ts_headline('simple', s.body, q.query, 'StartSel=` + SearchMatchStart + `, StopSel=` + SearchMatchEnd + `, MaxWords=24, MinWords=8') AS snippet,
+			msg_index.created_at AS created_at
+			FROM msg_search s
+			CROSS JOIN plainto_tsquery('simple', ?) AS q(query)
+			JOIN tasks ON tasks.task_id = s.task_id
+			JOIN msg_index ON msg_index.task_id = s.task_id AND msg_index.conversation_index = s.conversation_index
+			WHERE s.tsv @@ q.query`)
+		args = append(args, strings.Join(terms, " "))
+	}
+	if q.TenantID != "" {
+		sql.WriteString(` AND tasks.tenant_id = ?`)
+		args = append(args, q.TenantID)
+	}
+	if q.AgentID != "" {
+		sql.WriteString(` AND tasks.agent_id = ?`)
+		args = append(args, q.AgentID)
+	}
+	if !q.Since.IsZero() {
+		sql.WriteString(` AND msg_index.created_at >= ?`)
+		args = append(args, q.Since.UTC())
+	}
+	sql.WriteString(` ORDER BY msg_index.created_at DESC LIMIT ?`)
+	args = append(args, limit)
+
+	var results []SearchResult
+	if err := r.db.WithContext(ctx).Raw(sql.String(), args...).Scan(&results).Error; err != nil {
+		return nil, fmt.Errorf("storage: search messages: %w", err)
+	}
+	return results, nil
+}
+
+// searchTerms는 검색어를 단어로 나누고 검색 문법에 쓰이는 따옴표와 별표를 제거합니다.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		term := strings.NewReplacer(`"`, "", `*`, "").Replace(field)
+		if term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,102 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositorySearchMessages(t *testing.T) {
+	db := newMessageStoreDB(t, "search_messages")
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, agentID := range []string{"agent-a", "agent-b"} {
+		require.NoError(t, repo.CreateAgent(ctx, &storage.Agent{AgentID: agentID, Status: storage.AgentStatusActive}))
+	}
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "thread-a", AgentID: "agent-a", Status: storage.TaskStatusWaiting}))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "thread-b", AgentID: "agent-b", Status: storage.TaskStatusWaiting}))
+
+	index := func(taskID, role, body string) {
+		row, err := repo.AppendMessageIndex(ctx, taskID, role, "unused.json")
+		require.NoError(t, err)
+		require.NoError(t, repo.IndexMessage(ctx, taskID, row.ConversationIndex, role, body))
+	}
+	index("thread-a", storage.MessageRoleUser, "nginx 설정 파일은 어디에 있나요?")
+	index("thread-a", storage.MessageRoleAssistant, "nginx 설정은 /etc/nginx/nginx.conf 에 있습니다.")
+	index("thread-b", storage.MessageRoleAssistant, "Kubernetes ingress에서 nginx를 사용합니다.")
+
+	results, err := repo.SearchMessages(ctx, storage.SearchQuery{Query: "nginx"})
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	// 모든 단어를 포함해야 하며, 단어는 접두어로도 일치함
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "nginx.conf 설정"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "thread-a", results[0].TaskID)
+	assert.Equal(t, "agent-a", results[0].AgentID)
+	assert.Equal(t, 1, results[0].ConversationIndex)
+	assert.Equal(t, storage.MessageRoleAssistant, results[0].Role)
+	assert.Contains(t, results[0].Snippet, storage.SearchMatchStart+"nginx"+storage.SearchMatchEnd)
+	assert.False(t, results[0].CreatedAt.IsZero())
+
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "ingr"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "thread-b", results[0].TaskID)
+
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "nginx", AgentID: "agent-b"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "nginx", Since: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// FTS 연산자는 일반 문자로 취급
+	_, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: `"nginx* OR -`})
+	require.NoError(t, err)
+
+	_, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "  "})
+	assert.Error(t, err)
+
+	// 다시 색인하면 이전 내용을 교체
+	require.NoError(t, repo.IndexMessage(ctx, "thread-b", 0, storage.MessageRoleAssistant, "helm chart"))
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "ingress"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// 색인 교체가 실패하면 이전 색인을 유지
+	err = repo.ReplaceSearchIndex(ctx, []storage.SearchEntry{
+		{TaskID: "thread-a", ConversationIndex: 0, Role: storage.MessageRoleUser, Body: "redis cluster"},
+		{TaskID: "", ConversationIndex: 1, Role: storage.MessageRoleUser, Body: "broken"},
+	})
+	assert.Error(t, err)
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "helm"})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "redis"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	require.NoError(t, repo.ReplaceSearchIndex(ctx, []storage.SearchEntry{
+		{TaskID: "thread-a", ConversationIndex: 0, Role: storage.MessageRoleUser, Body: "redis cluster"},
+	}))
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "redis"})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "helm"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	require.NoError(t, repo.ClearSearchIndex(ctx))
+	results, err = repo.SearchMessages(ctx, storage.SearchQuery{Query: "redis"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
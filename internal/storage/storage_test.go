@@ -2,12 +2,18 @@ package storage_test
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/cnap-oss/app/internal/storage"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 func newTestRepository(t *testing.T) (*storage.Repository, func()) {
@@ -206,3 +212,228 @@ func TestRepositoryRunStepsAndCheckpoints(t *testing.T) {
 	require.Len(t, checkpoints, 1)
 	require.Equal(t, "abc123", checkpoints[0].GitHash)
 }
+
+func TestRepositoryImagePinning(t *testing.T) {
+	repo, cleanup := newTestRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	require.NoError(t, repo.CreateAgent(ctx, &storage.Agent{
+		AgentID: "agent-image",
+		Status:  storage.AgentStatusActive,
+	}))
+
+	// 에이전트 전용 이미지 설정
+	require.NoError(t, repo.UpdateAgentImage(ctx, "agent-image", "ghcr.io/example/go-runner:1.24"))
+	agent, err := repo.GetAgent(ctx, "agent-image")
+	require.NoError(t, err)
+	require.Equal(t, "ghcr.io/example/go-runner:1.24", agent.Image)
+
+	// 작업별 다이제스트 기록
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "task-img-1", AgentID: "agent-image", Status: storage.TaskStatusRunning}))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "task-img-2", AgentID: "agent-image", Status: storage.TaskStatusCompleted}))
+	require.NoError(t, repo.CreateTask(ctx, &storage.Task{TaskID: "task-img-3", AgentID: "agent-image", Status: storage.TaskStatusRunning}))
+
+	require.NoError(t, repo.SetTaskImageDigest(ctx, "task-img-1", "ghcr.io/example/go-runner@sha256:aaa"))
+	require.NoError(t, repo.SetTaskImageDigest(ctx, "task-img-2", "ghcr.io/example/go-runner@sha256:bbb"))
+	require.NoError(t, repo.SetTaskImageDigest(ctx, "task-img-3", "ghcr.io/example/go-runner@sha256:aaa"))
+
+	task, err := repo.GetTask(ctx, "task-img-1")
+	require.NoError(t, err)
+	require.Equal(t, "ghcr.io/example/go-runner@sha256:aaa", task.ImageDigest)
+
+	digests, err := repo.ListTaskImageDigests(ctx, storage.TaskStatusRunning)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ghcr.io/example/go-runner@sha256:aaa"}, digests)
+
+	all, err := repo.ListTaskImageDigests(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+}
+
+func TestRepositoryAgentPermissionPolicy(t *testing.T) {
+	repo, cleanup := newTestRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	require.NoError(t, repo.CreateAgent(ctx, &storage.Agent{
+		AgentID: "agent-perm",
+		Status:  storage.AgentStatusActive,
+	}))
+
+	// 기본 정책: 기본 대기 시간, 초과 시 거부
+	agent, err := repo.GetAgent(ctx, "agent-perm")
+	require.NoError(t, err)
+	require.Equal(t, 0, agent.PermissionTimeoutSec)
+	require.Equal(t, storage.PermissionResponseReject, agent.PermissionOnTimeout)
+
+	require.NoError(t, repo.UpdateAgentPermissionPolicy(ctx, "agent-perm", 30, storage.PermissionResponseOnce))
+	agent, err = repo.GetAgent(ctx, "agent-perm")
+	require.NoError(t, err)
+	require.Equal(t, 30, agent.PermissionTimeoutSec)
+	require.Equal(t, storage.PermissionResponseOnce, agent.PermissionOnTimeout)
+
+	// 시간 초과 시 "항상 허용"은 허용하지 않음
+	require.Error(t, repo.UpdateAgentPermissionPolicy(ctx, "agent-perm", 30, storage.PermissionResponseAlways))
+	require.Error(t, repo.UpdateAgentPermissionPolicy(ctx, "agent-perm", -1, storage.PermissionResponseReject))
+}
+
+func TestRepositoryAppendMessageConcurrent(t *testing.T) {
+	dsns := map[string]string{
+		// 공유 메모리 DB는 busy timeout 대신 테이블 잠금 에러를 반환하므로 파일 DB를 사용
+		"sqlite": "file:" + filepath.Join(t.TempDir(), "append.db"),
+	}
+	// 예: CNAP_TEST_POSTGRES_DSN="host=localhost user=cnap password=cnap dbname=cnap_test sslmode=disable"
+	if dsn := os.Getenv("CNAP_TEST_POSTGRES_DSN"); dsn != "" {
+		dsns["postgres"] = dsn
+	}
+
+	for name, dsn := range dsns {
+		t.Run(name, func(t *testing.T) {
+			db, err := storage.Open(storage.Config{DSN: dsn, LogLevel: gormlogger.Silent})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = storage.Close(db) })
+			require.NoError(t, storage.AutoMigrate(db))
+
+			repo, err := storage.NewRepository(db)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			taskID := fmt.Sprintf("append-%s-%d", name, os.Getpid())
+			t.Cleanup(func() {
+				db.Where("task_id = ?", taskID).Delete(&storage.MessageIndex{})
+				db.Where("task_id = ?", taskID).Delete(&storage.MessageCounter{})
+			})
+
+			// 기존 메시지가 있으면 그 다음 인덱스부터 할당
+			_, err = repo.AppendMessageIndex(ctx, taskID, storage.MessageRoleUser, "seed.json")
+			require.NoError(t, err)
+
+			const workers = 16
+			const perWorker = 10
+
+			var (
+				wg      sync.WaitGroup
+				mu      sync.Mutex
+				written = make(map[int]string)
+				errs    = make(chan error, workers*perWorker)
+			)
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < perWorker; i++ {
+						_, err := repo.AppendMessage(ctx, taskID, storage.MessageRoleUser, "", func(index int) (string, error) {
+							key := storage.MessageKey(taskID, index)
+							mu.Lock()
+							defer mu.Unlock()
+							if prev, ok := written[index]; ok {
+								return "", fmt.Errorf("index %d allocated twice (%s)", index, prev)
+							}
+							written[index] = key
+							return key, nil
+						})
+						if err != nil {
+							errs <- err
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				require.NoError(t, err)
+			}
+
+			rows, err := repo.ListMessageIndexByTask(ctx, taskID)
+			require.NoError(t, err)
+			require.Len(t, rows, workers*perWorker+1)
+			for i, row := range rows {
+				require.Equal(t, i, row.ConversationIndex)
+				if i > 0 {
+					require.Equal(t, storage.MessageKey(taskID, i), row.FilePath)
+				}
+			}
+
+			next, err := repo.GetNextConversationIndex(ctx, taskID)
+			require.NoError(t, err)
+			require.Equal(t, workers*perWorker+1, next)
+		})
+	}
+}
+
+func TestRepositoryProcessedEvents(t *testing.T) {
+	repo, cleanup := newTestRepository(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	old := time.Now().UTC().Add(-48 * time.Hour)
+	first, err := repo.MarkEventProcessed(ctx, &storage.ProcessedEvent{EventID: "discord:message:1", Type: "execute", TaskID: "t1", CreatedAt: old})
+	require.NoError(t, err)
+	require.True(t, first)
+
+	again, err := repo.MarkEventProcessed(ctx, &storage.ProcessedEvent{EventID: "discord:message:1", Type: "execute", TaskID: "t1"})
+	require.NoError(t, err)
+	require.False(t, again)
+
+	first, err = repo.MarkEventProcessed(ctx, &storage.ProcessedEvent{EventID: "discord:message:2", Type: "execute", TaskID: "t2"})
+	require.NoError(t, err)
+	require.True(t, first)
+
+	_, err = repo.MarkEventProcessed(ctx, &storage.ProcessedEvent{})
+	require.Error(t, err)
+
+	// 처리에 실패해 지운 ID는 다시 기록할 수 있음
+	require.NoError(t, repo.DeleteProcessedEvent(ctx, "discord:message:2"))
+	first, err = repo.MarkEventProcessed(ctx, &storage.ProcessedEvent{EventID: "discord:message:2", Type: "execute", TaskID: "t2"})
+	require.NoError(t, err)
+	require.True(t, first)
+	require.Error(t, repo.DeleteProcessedEvent(ctx, ""))
+
+	n, err := repo.PruneProcessedEvents(ctx, time.Now().UTC().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	// 정리된 ID는 다시 기록할 수 있음
+	first, err = repo.MarkEventProcessed(ctx, &storage.ProcessedEvent{EventID: "discord:message:1", Type: "execute", TaskID: "t1"})
+	require.NoError(t, err)
+	require.True(t, first)
+}
+
+func TestRepositoryTaskEventSeqs(t *testing.T) {
+	repo, cleanup := newTestRepository(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// 카운터가 없으면 기존 이벤트의 다음 순번부터 예약
+	require.NoError(t, repo.AppendTaskEvents(ctx, []*storage.TaskEvent{
+		{TaskID: "seq-task", Seq: 3, EventType: "tool_start", Payload: "{}"},
+	}))
+	first, err := repo.ReserveTaskEventSeqs(ctx, "seq-task", 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), first)
+
+	first, err = repo.ReserveTaskEventSeqs(ctx, "seq-task", 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(14), first)
+
+	first, err = repo.ReserveTaskEventSeqs(ctx, "other-task", 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), first)
+
+	_, err = repo.ReserveTaskEventSeqs(ctx, "seq-task", 0)
+	require.Error(t, err)
+	require.Error(t, repo.AppendTaskEvents(ctx, []*storage.TaskEvent{{TaskID: "seq-task", EventType: "error", Payload: "{}"}}))
+
+	// 순번 충돌은 트랜잭션 전체를 되돌림
+	err = repo.AppendTaskEvents(ctx, []*storage.TaskEvent{
+		{TaskID: "seq-task", Seq: 14, EventType: "stream_delta", Payload: "{}"},
+		{TaskID: "seq-task", Seq: 3, EventType: "stream_delta", Payload: "{}"},
+	})
+	require.Error(t, err)
+	events, err := repo.ListTaskEvents(ctx, "seq-task", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReserveTaskEventSeqs는 Task의 이벤트 순번 n개를 연속으로 예약하고 첫 순번을 반환합니다.
+// task_event_counters의 카운터를 한 문장으로 증가시키므로 여러 프로세스가 동시에 예약해도 겹치지 않으며,
+// 카운터가 없으면 기존 task_events의 MAX(seq)+1부터 시작합니다.
+func (r *Repository) ReserveTaskEventSeqs(ctx context.Context, taskID string, n int64) (int64, error) {
+	if taskID == "" {
+		return 0, fmt.Errorf("storage: empty taskID")
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("storage: invalid task event seq count: %d", n)
+	}
+	var next int64
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO task_event_counters (task_id, next_seq)
+		SELECT ?, COALESCE(MAX(seq), 0) + 1 + ? FROM task_events WHERE task_id = ?
+		ON CONFLICT (task_id) DO UPDATE SET next_seq = task_event_counters.next_seq + ?
+		RETURNING next_seq`, taskID, n, taskID, n).
+		Scan(&next).Error
+	if err != nil {
+		return 0, fmt.Errorf("storage: reserve task event seq: %w", err)
+	}
+	return next - n, nil
+}
+
+// AppendTaskEvents는 ReserveTaskEventSeqs로 순번을 부여한 이벤트들을 한 트랜잭션으로 추가합니다.
+// 정리(CompactTaskEvents)는 순번을 되돌리지 않으므로 순번은 재사용되지 않습니다.
+func (r *Repository) AppendTaskEvents(ctx context.Context, events []*TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	for _, event := range events {
+		if event.TaskID == "" {
+			return fmt.Errorf("storage: empty taskID")
+		}
+		if event.Seq <= 0 {
+			return fmt.Errorf("storage: task event seq is not reserved: %s", event.TaskID)
+		}
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = now
+		}
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(events, 100).Error
+	})
+}
+
+// ListTaskEvents는 Task에서 afterSeq 이후에 기록된 이벤트를 순번 순으로 반환합니다.
+// limit이 0 이하이면 모두 반환합니다.
+func (r *Repository) ListTaskEvents(ctx context.Context, taskID string, afterSeq int64, limit int) ([]TaskEvent, error) {
+	if taskID == "" {
+		return nil, fmt.Errorf("storage: empty taskID")
+	}
+	q := r.db.WithContext(ctx).
+		Where("task_id = ? AND seq > ?", taskID, afterSeq).
+		Order("seq ASC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var events []TaskEvent
+	if err := q.Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// CompactTaskEvents는 Task의 Part에서 발생한 eventType 이벤트를 삭제하고 삭제한 개수를 반환합니다.
+// Part가 완료된 뒤 더 이상 필요 없는 스트리밍 델타를 정리할 때 사용합니다.
+func (r *Repository) CompactTaskEvents(ctx context.Context, taskID, partID, eventType string) (int64, error) {
+	if taskID == "" || partID == "" {
+		return 0, fmt.Errorf("storage: empty taskID or partID")
+	}
+	res := r.db.WithContext(ctx).
+		Where("task_id = ? AND part_id = ? AND event_type = ?", taskID, partID, eventType).
+		Delete(&TaskEvent{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Task 목록 정렬 기준
+const (
+	TaskSortCreated = "created_at"
+	TaskSortUpdated = "updated_at"
+)
+
+// Task 목록 페이지 크기
+const (
+	DefaultTaskPageSize = 50
+	MaxTaskPageSize     = 500
+)
+
+// TaskQuery는 Task 목록 조회 조건입니다. 비어 있는 필드는 조건에서 제외됩니다.
+type TaskQuery struct {
+	TenantID       string // 비어 있으면 모든 테넌트
+	AgentID        string
+	Statuses       []string
+	CreatedAfter   time.Time // 포함
+	CreatedBefore  time.Time // 제외
+	UpdatedAfter   time.Time // 포함
+	UpdatedBefore  time.Time // 제외
+	PromptContains string    // 대소문자 구분 없이 프롬프트에 포함된 문자열
+	SortBy         string    // TaskSortCreated(기본값), TaskSortUpdated
+	Ascending      bool      // 기본값은 최신순
+	Limit          int       // 0 이하이면 DefaultTaskPageSize, 최대 MaxTaskPageSize
+	Cursor         string    // 이전 페이지의 TaskPage.NextCursor
+}
+
+// TaskPage는 Task 목록의 한 페이지입니다. NextCursor가 비어 있으면 마지막 페이지입니다.
+type TaskPage struct {
+	Tasks      []Task
+	NextCursor string
+}
+
+// taskCursor는 페이지의 마지막 Task 위치(정렬 기준 시각, task_id)입니다. 같은 정렬 조건에서만 유효합니다.
+type taskCursor struct {
+	sortBy    string
+	ascending bool
+	at        time.Time
+	taskID    string
+}
+
+func (c taskCursor) encode() string {
+	dir := "desc"
+	if c.ascending {
+		dir = "asc"
+	}
+	raw := strings.Join([]string{c.sortBy, dir, c.at.Format(time.RFC3339Nano), c.taskID}, "|")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTaskCursor(s string) (taskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return taskCursor{}, fmt.Errorf("storage: invalid task cursor: %w", err)
+	}
+	fields := strings.SplitN(string(raw), "|", 4)
+	if len(fields) != 4 {
+		return taskCursor{}, fmt.Errorf("storage: invalid task cursor")
+	}
+	at, err := time.Parse(time.RFC3339Nano, fields[2])
+	if err != nil {
+		return taskCursor{}, fmt.Errorf("storage: invalid task cursor: %w", err)
+	}
+	return taskCursor{sortBy: fields[0], ascending: fields[1] == "asc", at: at, taskID: fields[3]}, nil
+}
+
+// QueryTasks는 조건에 맞는 Task 목록을 q.SortBy 기준(created_at 또는 updated_at)으로 정렬하여 한 페이지씩 반환합니다.
+// 커서는 정렬 기준 시각과 task_id의 조합이므로 같은 시각의 Task도 순서가 고정되고, 페이지를 넘기는 사이에 Task가 추가되어도 중복되거나 빠지지 않습니다.
+// 단, updated_at 기준 정렬에서는 페이지를 넘기는 사이에 갱신된 Task의 위치가 바뀌므로 다시 나오거나 빠질 수 있습니다.
+func (r *Repository) QueryTasks(ctx context.Context, q TaskQuery) (*TaskPage, error) {
+	sortBy := q.SortBy
+	switch sortBy {
+	case "":
+		sortBy = TaskSortCreated
+	case TaskSortCreated, TaskSortUpdated:
+	default:
+		return nil, fmt.Errorf("storage: unknown task sort: %s", q.SortBy)
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultTaskPageSize
+	}
+	if limit > MaxTaskPageSize {
+		limit = MaxTaskPageSize
+	}
+
+	db := r.db.WithContext(ctx).Model(&Task{})
+	if q.TenantID != "" {
+		db = db.Where("tenant_id = ?", q.TenantID)
+	}
+	if q.AgentID != "" {
+		db = db.Where("agent_id = ?", q.AgentID)
+	}
+	if len(q.Statuses) > 0 {
+		db = db.Where("status IN ?", q.Statuses)
+	}
+	if !q.CreatedAfter.IsZero() {
+		db = db.Where("created_at >= ?", q.CreatedAfter)
+	}
+	if !q.CreatedBefore.IsZero() {
+		db = db.Where("created_at < ?", q.CreatedBefore)
+	}
+	if !q.UpdatedAfter.IsZero() {
+		db = db.Where("updated_at >= ?", q.UpdatedAfter)
+	}
+	if !q.UpdatedBefore.IsZero() {
+		db = db.Where("updated_at < ?", q.UpdatedBefore)
+	}
+	if q.PromptContains != "" {
+		db = db.Where("LOWER(prompt) LIKE ? ESCAPE '\\'", "%"+escapeLike(strings.ToLower(q.PromptContains))+"%")
+	}
+
+	dir, cmp := "DESC", "<"
+	if q.Ascending {
+		dir, cmp = "ASC", ">"
+	}
+	if q.Cursor != "" {
+		cursor, err := decodeTaskCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.sortBy != sortBy || cursor.ascending != q.Ascending {
+			return nil, fmt.Errorf("storage: task cursor does not match sort order")
+		}
+		db = db.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND task_id %[2]s ?))", sortBy, cmp),
+			cursor.at, cursor.at, cursor.taskID)
+	}
+
+	var tasks []Task
+	if err := db.
+		Order(fmt.Sprintf("%s %s, task_id %s", sortBy, dir, dir)).
+		Limit(limit + 1).
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	page := &TaskPage{Tasks: tasks}
+	if len(tasks) > limit {
+		page.Tasks = tasks[:limit]
+		last := page.Tasks[limit-1]
+		at := last.CreatedAt
+		if sortBy == TaskSortUpdated {
+			at = last.UpdatedAt
+		}
+		page.NextCursor = taskCursor{sortBy: sortBy, ascending: q.Ascending, at: at, taskID: last.TaskID}.encode()
+	}
+	return page, nil
+}
@@ -0,0 +1,128 @@
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryQueryTasks(t *testing.T) {
+	db := newMessageStoreDB(t, "query_tasks")
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	statuses := []string{storage.TaskStatusRunning, storage.TaskStatusWaiting, storage.TaskStatusCompleted}
+	for i := 0; i < 9; i++ {
+		agentID := "agent-a"
+		if i%3 == 2 {
+			agentID = "agent-b"
+		}
+		created := base.Add(time.Duration(i/2) * time.Hour) // 같은 생성 시각을 가진 Task 포함
+		require.NoError(t, repo.CreateTask(ctx, &storage.Task{
+			TaskID:    fmt.Sprintf("task-%d", i),
+			AgentID:   agentID,
+			Prompt:    fmt.Sprintf("Deploy service %d", i),
+			Status:    statuses[i%3],
+			CreatedAt: created,
+			UpdatedAt: created.Add(time.Duration(10-i) * time.Minute),
+		}))
+	}
+
+	ids := func(tasks []storage.Task) []string {
+		out := make([]string, len(tasks))
+		for i, task := range tasks {
+			out[i] = task.TaskID
+		}
+		return out
+	}
+
+	// 커서로 모든 페이지를 순회하면 중복 없이 최신순으로 모두 조회됨
+	var all []string
+	cursor := ""
+	pages := 0
+	for {
+		page, err := repo.QueryTasks(ctx, storage.TaskQuery{Limit: 4, Cursor: cursor})
+		require.NoError(t, err)
+		all = append(all, ids(page.Tasks)...)
+		pages++
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"task-8", "task-7", "task-6", "task-5", "task-4", "task-3", "task-2", "task-1", "task-0"}, all)
+
+	page, err := repo.QueryTasks(ctx, storage.TaskQuery{
+		Statuses:  []string{storage.TaskStatusRunning, storage.TaskStatusWaiting},
+		Ascending: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-0", "task-1", "task-3", "task-4", "task-6", "task-7"}, ids(page.Tasks))
+	assert.Empty(t, page.NextCursor)
+
+	page, err = repo.QueryTasks(ctx, storage.TaskQuery{AgentID: "agent-b"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-8", "task-5", "task-2"}, ids(page.Tasks))
+
+	page, err = repo.QueryTasks(ctx, storage.TaskQuery{
+		CreatedAfter:  base.Add(time.Hour),
+		CreatedBefore: base.Add(3 * time.Hour),
+		Ascending:     true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-2", "task-3", "task-4", "task-5"}, ids(page.Tasks))
+
+	page, err = repo.QueryTasks(ctx, storage.TaskQuery{SortBy: storage.TaskSortUpdated, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-8", "task-6"}, ids(page.Tasks))
+	page, err = repo.QueryTasks(ctx, storage.TaskQuery{SortBy: storage.TaskSortUpdated, Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-7", "task-4"}, ids(page.Tasks))
+
+	page, err = repo.QueryTasks(ctx, storage.TaskQuery{UpdatedAfter: base.Add(4*time.Hour + 2*time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-8"}, ids(page.Tasks))
+
+	page, err = repo.QueryTasks(ctx, storage.TaskQuery{PromptContains: "SERVICE 7"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-7"}, ids(page.Tasks))
+
+	// LIKE 와일드카드는 일반 문자로 취급
+	page, err = repo.QueryTasks(ctx, storage.TaskQuery{PromptContains: "%"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Tasks)
+
+	// updated_at이 같은 Task도 task_id로 순서가 고정되어 한 건씩 넘겨도 중복되거나 빠지지 않음
+	tied := base.Add(24 * time.Hour)
+	require.NoError(t, db.Model(&storage.Task{}).Where("task_id IN ?", []string{"task-0", "task-1", "task-2"}).
+		UpdateColumn("updated_at", tied).Error)
+	all, cursor = nil, ""
+	for {
+		page, err := repo.QueryTasks(ctx, storage.TaskQuery{SortBy: storage.TaskSortUpdated, Limit: 1, Cursor: cursor})
+		require.NoError(t, err)
+		all = append(all, ids(page.Tasks)...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"task-2", "task-1", "task-0", "task-8", "task-6", "task-7", "task-4", "task-5", "task-3"}, all)
+
+	// 정렬 조건이 다른 커서는 거부
+	page, err = repo.QueryTasks(ctx, storage.TaskQuery{Limit: 1})
+	require.NoError(t, err)
+	_, err = repo.QueryTasks(ctx, storage.TaskQuery{Limit: 1, Ascending: true, Cursor: page.NextCursor})
+	assert.Error(t, err)
+	_, err = repo.QueryTasks(ctx, storage.TaskQuery{Cursor: "not-a-cursor"})
+	assert.Error(t, err)
+	_, err = repo.QueryTasks(ctx, storage.TaskQuery{SortBy: "prompt"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// ErrAgentExists는 같은 테넌트에 같은 이름의 에이전트가 이미 있을 때 반환됩니다.
+// 다른 테넌트의 에이전트는 드러나지 않도록 이름 충돌만 알립니다.
+var ErrAgentExists = errors.New("agent already exists")
+
+// agentKeyHashLen은 기본 테넌트가 아닌 에이전트 식별자에 붙이는 해시 길이입니다.
+const agentKeyHashLen = 8
+
+// AgentKey는 테넌트와 이름으로 에이전트 내부 식별자(agent_id)를 만듭니다.
+// 기본 테넌트는 이전과 같이 이름을 그대로 쓰고, 다른 테넌트는 이름 뒤에 (테넌트, 이름)의 해시를 붙여
+// 여러 테넌트가 같은 이름을 써도 식별자와 작업 공간 디렉토리가 겹치지 않습니다.
+func AgentKey(tenantID, name string) string {
+	if tenantID == "" || tenantID == DefaultTenantID {
+		return name
+	}
+	sum := sha256.Sum256([]byte(tenantID + "/" + name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:agentKeyHashLen]
+
+	// agent_id 컬럼 길이(64)에 맞추어 이름을 문자 경계에서 자름
+	prefix := name
+	for len(prefix)+len(suffix) > 64 {
+		_, size := utf8.DecodeLastRuneInString(prefix)
+		prefix = prefix[:len(prefix)-size]
+	}
+	return prefix + suffix
+}
+
+// AgentScope는 테넌트 안의 사용자에게 보이는 에이전트 범위입니다.
+// 같은 테넌트의 guild 에이전트와 공용(소유자 없는) 에이전트, 사용자가 소유한 private 에이전트,
+// 모든 테넌트의 public 에이전트가 포함됩니다. Admin이면 테넌트의 모든 에이전트가 포함됩니다.
+type AgentScope struct {
+	TenantID string
+	Actor    string // 사용자 식별자 ({connector}:{id})
+	Admin    bool
+}
+
+// Visible은 에이전트가 범위 안에 있는지 확인합니다. ListVisibleAgents와 같은 규칙입니다.
+func (s AgentScope) Visible(agent *Agent) bool {
+	if agent.Visibility == AgentVisibilityPublic {
+		return true
+	}
+	if agent.TenantID != s.TenantID {
+		return false
+	}
+	return s.Admin || agent.Visibility != AgentVisibilityPrivate || agent.Owner == "" || agent.Owner == s.Actor
+}
+
+// migrateDefaultTenant는 테넌트가 도입되기 전의 에이전트와 Task를 기본 테넌트로 옮깁니다.
+// 기존 에이전트는 모든 Discord 서버에서 보이던 동작을 유지하도록 public으로 지정합니다.
+func migrateDefaultTenant(db *gorm.DB) error {
+	if err := db.Model(&Agent{}).
+		Where("tenant_id = ''").
+		UpdateColumns(map[string]interface{}{
+			"tenant_id":  DefaultTenantID,
+			"visibility": AgentVisibilityPublic,
+		}).Error; err != nil {
+		return fmt.Errorf("storage: migrate agent tenant: %w", err)
+	}
+	if err := db.Model(&Task{}).
+		Where("tenant_id = ''").
+		UpdateColumn("tenant_id", DefaultTenantID).Error; err != nil {
+		return fmt.Errorf("storage: migrate task tenant: %w", err)
+	}
+	return nil
+}
+
+// migrateAgentNames는 이름 컬럼이 도입되기 전의 에이전트 이름을 식별자로 채우고 (테넌트, 이름) 유일 색인을 만듭니다.
+// 기존 행의 이름이 모두 채워진 뒤에 색인을 만들어야 하므로 모델 태그 대신 여기서 만듭니다.
+func migrateAgentNames(db *gorm.DB) error {
+	if err := db.Model(&Agent{}).
+		Where("name = ''").
+		UpdateColumn("name", gorm.Expr("agent_id")).Error; err != nil {
+		return fmt.Errorf("storage: migrate agent names: %w", err)
+	}
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_agents_tenant_name ON agents (tenant_id, name)").Error; err != nil {
+		return fmt.Errorf("storage: create agent name index: %w", err)
+	}
+	return nil
+}
+
+// GetAgentByName은 테넌트 안에서 이름으로 에이전트를 조회합니다. 없으면 gorm.ErrRecordNotFound를 반환합니다.
+func (r *Repository) GetAgentByName(ctx context.Context, tenantID, name string) (*Agent, error) {
+	if name == "" {
+		return nil, fmt.Errorf("storage: empty agent name")
+	}
+	var agent Agent
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND name = ?", tenantID, name).
+		First(&agent).Error; err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// ListAgentsByName은 모든 테넌트에서 이름이 같은 에이전트를 반환합니다.
+func (r *Repository) ListAgentsByName(ctx context.Context, name string) ([]Agent, error) {
+	var agents []Agent
+	if err := r.db.WithContext(ctx).
+		Where("name = ?", name).
+		Order("created_at ASC").
+		Find(&agents).Error; err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// agentNameTaken은 테넌트에 같은 이름의 다른 에이전트가 있는지 확인합니다.
+func agentNameTaken(tx *gorm.DB, tenantID, name, exceptAgentID string) (bool, error) {
+	var count int64
+	if err := tx.Model(&Agent{}).
+		Where("tenant_id = ? AND name = ? AND agent_id <> ?", tenantID, name, exceptAgentID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListVisibleAgents는 범위 안의 에이전트 목록을 상태 필터를 적용해 반환합니다.
+func (r *Repository) ListVisibleAgents(ctx context.Context, scope AgentScope, statuses ...string) ([]Agent, error) {
+	q := r.db.WithContext(ctx).Model(&Agent{}).
+		Where("visibility = ? OR (tenant_id = ? AND (? OR visibility <> ? OR owner = '' OR owner = ?))",
+			AgentVisibilityPublic, scope.TenantID, scope.Admin, AgentVisibilityPrivate, scope.Actor)
+	if len(statuses) > 0 {
+		q = q.Where("status IN ?", statuses)
+	}
+	var agents []Agent
+	if err := q.Order("created_at ASC").Find(&agents).Error; err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// UpdateAgentOwnership은 에이전트의 소유 테넌트, 소유자, 공개 범위를 변경합니다.
+func (r *Repository) UpdateAgentOwnership(ctx context.Context, agentID, tenantID, owner, visibility string) error {
+	if agentID == "" {
+		return fmt.Errorf("storage: empty agentID")
+	}
+	if tenantID == "" {
+		return fmt.Errorf("storage: empty tenantID")
+	}
+	switch visibility {
+	case AgentVisibilityPrivate, AgentVisibilityGuild, AgentVisibilityPublic:
+	default:
+		return fmt.Errorf("storage: invalid agent visibility %q", visibility)
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var agent Agent
+		if err := tx.Where("agent_id = ?", agentID).First(&agent).Error; err != nil {
+			return err
+		}
+		taken, err := agentNameTaken(tx, tenantID, agent.Name, agentID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("%w: %s", ErrAgentExists, agent.Name)
+		}
+		return tx.Model(&Agent{}).
+			Where("agent_id = ?", agentID).
+			Updates(map[string]interface{}{
+				"tenant_id":  tenantID,
+				"owner":      owner,
+				"visibility": visibility,
+				"updated_at": time.Now(),
+			}).Error
+	})
+}
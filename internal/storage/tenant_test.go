@@ -0,0 +1,94 @@
+package storage_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/cnap-oss/app/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTenantMigration(t *testing.T) {
+	db := newMessageStoreDB(t, "tenant_migration")
+	ctx := context.Background()
+
+	// 테넌트 도입 이전 레코드
+	require.NoError(t, db.Exec(`INSERT INTO agents (agent_id, status, tenant_id, visibility, created_at, updated_at)
+		VALUES ('legacy', 'active', '', '', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO tasks (task_id, agent_id, status, tenant_id, created_at, updated_at)
+		VALUES ('legacy-task', 'legacy', 'completed', '', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`).Error)
+	require.NoError(t, storage.AutoMigrate(db))
+
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+	agent, err := repo.GetAgent(ctx, "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, storage.DefaultTenantID, agent.TenantID)
+	assert.Equal(t, storage.AgentVisibilityPublic, agent.Visibility)
+	task, err := repo.GetTask(ctx, "legacy-task")
+	require.NoError(t, err)
+	assert.Equal(t, storage.DefaultTenantID, task.TenantID)
+
+	// 새 에이전트는 기본 테넌트의 guild 공개 범위
+	require.NoError(t, repo.CreateAgent(ctx, &storage.Agent{AgentID: "fresh", Status: storage.AgentStatusActive}))
+	require.NoError(t, repo.CreateAgent(ctx, &storage.Agent{AgentID: "private", Status: storage.AgentStatusActive,
+		TenantID: "guild:a", Owner: "discord:1", Visibility: storage.AgentVisibilityPrivate}))
+	agent, err = repo.GetAgent(ctx, "fresh")
+	require.NoError(t, err)
+	assert.Equal(t, storage.AgentVisibilityGuild, agent.Visibility)
+
+	visible := func(scope storage.AgentScope) []string {
+		agents, err := repo.ListVisibleAgents(ctx, scope)
+		require.NoError(t, err)
+		var names []string
+		for _, a := range agents {
+			assert.True(t, scope.Visible(&a), a.AgentID)
+			names = append(names, a.AgentID)
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"legacy", "fresh"}, visible(storage.AgentScope{TenantID: storage.DefaultTenantID}))
+	assert.ElementsMatch(t, []string{"legacy", "private"}, visible(storage.AgentScope{TenantID: "guild:a", Actor: "discord:1"}))
+	assert.Equal(t, []string{"legacy"}, visible(storage.AgentScope{TenantID: "guild:a", Actor: "discord:2"}))
+	assert.Error(t, repo.UpdateAgentOwnership(ctx, "fresh", storage.DefaultTenantID, "", "everyone"))
+}
+
+func TestAgentNamesAreScopedByTenant(t *testing.T) {
+	db := newMessageStoreDB(t, "tenant_agent_names")
+	ctx := context.Background()
+	repo, err := storage.NewRepository(db)
+	require.NoError(t, err)
+
+	// 이름 컬럼 도입 이전 레코드는 식별자를 이름으로 사용
+	require.NoError(t, db.Exec(`INSERT INTO agents (agent_id, name, status, tenant_id, visibility, created_at, updated_at)
+		VALUES ('legacy', '', 'active', 'default', 'public', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`).Error)
+	require.NoError(t, storage.AutoMigrate(db))
+	legacy, err := repo.GetAgentByName(ctx, storage.DefaultTenantID, "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", legacy.AgentID)
+
+	private := &storage.Agent{Name: "helper", TenantID: "guild:a", Owner: "discord:1", Visibility: storage.AgentVisibilityPrivate}
+	require.NoError(t, repo.CreateAgent(ctx, private))
+	other := &storage.Agent{Name: "helper", TenantID: "guild:b"}
+	require.NoError(t, repo.CreateAgent(ctx, other))
+	fallback := &storage.Agent{Name: "helper"}
+	require.NoError(t, repo.CreateAgent(ctx, fallback))
+
+	assert.Equal(t, storage.AgentKey("guild:a", "helper"), private.AgentID)
+	assert.Equal(t, "helper", fallback.AgentID)
+	assert.NotEqual(t, private.AgentID, other.AgentID)
+	long := storage.AgentKey("guild:a", strings.Repeat("가", 40))
+	assert.LessOrEqual(t, len(long), 64)
+	assert.True(t, utf8.ValidString(long), long)
+
+	// 같은 테넌트의 이름 충돌만 같은 오류로 알림
+	err = repo.CreateAgent(ctx, &storage.Agent{Name: "helper", TenantID: "guild:a", Owner: "discord:2"})
+	assert.ErrorIs(t, err, storage.ErrAgentExists)
+	assert.Equal(t, "agent already exists: helper", err.Error())
+
+	// 같은 이름이 있는 테넌트로는 옮길 수 없음
+	assert.ErrorIs(t, repo.UpdateAgentOwnership(ctx, other.AgentID, "guild:a", "", storage.AgentVisibilityGuild), storage.ErrAgentExists)
+}
@@ -28,7 +28,7 @@ func (m *MockCallback) OnStarted(taskID string, sessionID string) error {
 	return nil
 }
 
-func (m *MockCallback) OnEvent(taskID string, event *opencode.Event) error {
+func (m *MockCallback) OnEvent(taskID string, msg *opencode.RunnerMessage) error {
 	return nil
 }
 